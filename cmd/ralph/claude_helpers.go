@@ -2,16 +2,40 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 )
 
+// defaultClaudeTimeout bounds one-shot Claude calls (the -timeout default
+// for add, fix and init).
+const defaultClaudeTimeout = 5 * time.Minute
+
+// claudeWaitDelay is how long a timed-out Claude call may take to release
+// its output pipes after being killed (e.g. when it left child processes).
+const claudeWaitDelay = 2 * time.Second
+
+// claudeTimeoutError reports a Claude (or other agent) call that hit its
+// deadline, with whatever it printed before being stopped.
+type claudeTimeoutError struct {
+	agent   string
+	timeout time.Duration
+	output  string
+}
+
+func (e *claudeTimeoutError) Error() string {
+	return fmt.Sprintf("%s analysis timed out after %s", e.agent, e.timeout)
+}
+
 func runClaudeOnce(prompt string) (string, error) {
-	return runClaudeOnceWithModel(prompt, "")
+	return runClaudeOnceWithModel(prompt, "", defaultClaudeTimeout)
 }
 
-func runClaudeOnceWithModel(prompt string, model string) (string, error) {
+// runClaudeOnceWithModel runs claude -p once. A timeout <= 0 waits forever.
+func runClaudeOnceWithModel(prompt string, model string, timeout time.Duration) (string, error) {
 	if _, err := exec.LookPath("claude"); err != nil {
 		return "", fmt.Errorf("claude not found in PATH. Install Claude Code: https://docs.anthropic.com/en/docs/claude-code")
 	}
@@ -20,17 +44,33 @@ func runClaudeOnceWithModel(prompt string, model string) (string, error) {
 		args = append(args, "--model", strings.TrimSpace(model))
 	}
 	args = append(args, "-p", prompt)
-	cmd := exec.Command("claude", args...)
+	return runAgentCommandOnce("Claude", "claude", args, timeout)
+}
+
+// runAgentCommandOnce runs binary with args and returns its stdout. agent
+// names it in the timeout error. A timeout <= 0 waits forever.
+func runAgentCommandOnce(agent, binary string, args []string, timeout time.Duration) (string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.WaitDelay = claudeWaitDelay
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	err := cmd.Run()
 	out := stdout.String()
 	if err != nil {
-		// Only include stderr when Claude fails (stderr frequently contains non-fatal warnings)
+		// Only include stderr when the agent fails (stderr frequently contains non-fatal warnings)
 		if stderr.Len() > 0 {
 			out += "\n--- STDERR ---\n" + stderr.String()
 		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return out, &claudeTimeoutError{agent: agent, timeout: timeout, output: out}
+		}
 		return out, err
 	}
 	// On success, return stdout only to keep JSON outputs clean.
@@ -45,9 +85,23 @@ func isClaudeRateLimitError(err error) bool {
 	return strings.Contains(s, "rate limit") || strings.Contains(s, "usage limit") || strings.Contains(s, "429")
 }
 
+// claudePartialOutputLines caps how much of a timed-out call's output is shown.
+const claudePartialOutputLines = 40
+
 func claudeActionableDetails(err error) string {
 	if err == nil {
 		return ""
 	}
-	return strings.TrimSpace(err.Error())
+	details := strings.TrimSpace(err.Error())
+	var timeoutErr *claudeTimeoutError
+	if errors.As(err, &timeoutErr) {
+		if partial := strings.TrimSpace(timeoutErr.output); partial != "" {
+			lines := strings.Split(partial, "\n")
+			if len(lines) > claudePartialOutputLines {
+				lines = append([]string{"..."}, lines[len(lines)-claudePartialOutputLines:]...)
+			}
+			details += "\n\nPartial output:\n" + strings.Join(lines, "\n")
+		}
+	}
+	return details
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunClaudeOnceTimesOut(t *testing.T) {
+	binDir := t.TempDir()
+	fakeClaude := "#!/bin/sh\necho 'Reading requirements...'\nexec sleep 30\n"
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte(fakeClaude), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	start := time.Now()
+	_, err := runClaudeOnceWithModel("prompt", "", 200*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("call took %s, expected it to stop at the deadline", elapsed)
+	}
+	var timeoutErr *claudeTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected claudeTimeoutError, got %v", err)
+	}
+	if err.Error() != "Claude analysis timed out after 200ms" {
+		t.Errorf("error = %q", err.Error())
+	}
+	details := claudeActionableDetails(err)
+	if !strings.Contains(details, "Partial output:\nReading requirements...") {
+		t.Errorf("details missing partial output:\n%s", details)
+	}
+	if isClaudeRateLimitError(err) {
+		t.Error("a timeout is not a rate limit")
+	}
+}
+
+func TestClaudeActionableDetailsTruncatesPartialOutput(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	err := &claudeTimeoutError{timeout: time.Minute, output: strings.Join(lines, "\n")}
+	details := claudeActionableDetails(err)
+	if strings.Contains(details, "line 60\n") || !strings.Contains(details, "...\nline 61\n") || !strings.HasSuffix(details, "line 100") {
+		t.Errorf("expected only the last %d lines:\n%s", claudePartialOutputLines, details)
+	}
+	if got := claudeActionableDetails(errors.New(" exit status 1 ")); got != "exit status 1" {
+		t.Errorf("plain error details = %q", got)
+	}
+}
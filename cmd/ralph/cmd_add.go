@@ -22,21 +22,35 @@ Usage:
   ralph add "description..."
   ralph add -file <file.md> [-model <model>]
   ralph add -desc "description..." [-model <model>]
+  <command> | ralph add -
 
 Flags:
-  -file   Path to markdown file with work description
-  -desc   Work description
-  -model  Claude model to use
+  -file      Path to markdown file with work description ("-" reads stdin)
+  -desc      Work description
+  -model     Claude model to use
+  -timeout   Give up on a Claude call after this long (default 5m)
+  -dry-run   Print the tasks that would be added without changing .ralph/
+  -position  Where new tasks go in prd.json: prepend or append (default prepend)
+  -after     Insert new tasks right after the task with this ID
+  -priority  Set every new task's priority: high, medium, or low
 
 Examples:
   ralph add ../work.md
   ralph add "Add an endpoint that returns the user's country based on IP"
   ralph add -file ../work.md -model sonnet
+  ralph add -dry-run "Split the settings page into tabs"
+  ralph add -after T004 -priority high "Fix the flaky login test"
+  gh issue view 5 --json body -q .body | ralph add -
 `)
 	}
 	description := fs.String("desc", "", "Work description")
 	filePath := fs.String("file", "", "Path to markdown file with work description")
 	model := fs.String("model", "", "Claude model to use")
+	timeout := fs.Duration("timeout", defaultClaudeTimeout, "Give up on a Claude call after this long")
+	dryRun := fs.Bool("dry-run", false, "Preview tasks without writing .ralph/prd.json")
+	position := fs.String("position", "prepend", "Where new tasks go: prepend or append")
+	after := fs.String("after", "", "Insert new tasks after the task with this ID")
+	priority := fs.String("priority", "", "Set every new task's priority")
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			fs.Usage()
@@ -47,27 +61,29 @@ Examples:
 		os.Exit(1)
 	}
 
-	pos := fs.Args()
-	if *filePath == "" && *description == "" && len(pos) > 0 {
-		if len(pos) == 1 {
-			if _, err := os.Stat(pos[0]); err == nil {
-				*filePath = pos[0]
-			} else {
-				*description = pos[0]
-			}
-		} else {
-			*description = strings.Join(pos, " ")
-		}
+	// Claude's rewritten prd.json keeps its own order unless placement was asked for.
+	setFlags := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	placementSet := setFlags["position"] || setFlags["after"]
+	placement := taskPlacement{Position: strings.ToLower(strings.TrimSpace(*position)), After: strings.TrimSpace(*after)}
+	if placement.Position != "prepend" && placement.Position != "append" {
+		fmt.Fprintf(os.Stderr, "Invalid -position %q. Must be prepend or append\n", *position)
+		os.Exit(1)
+	}
+	if placement.After != "" && setFlags["position"] {
+		fmt.Fprintln(os.Stderr, "-after cannot be combined with -position")
+		os.Exit(1)
+	}
+	prio := strings.ToLower(strings.TrimSpace(*priority))
+	if prio != "" && !containsString(validTaskPriorities, prio) {
+		fmt.Fprintf(os.Stderr, "Invalid -priority %q. Must be one of: %s\n", *priority, strings.Join(validTaskPriorities, ", "))
+		os.Exit(1)
 	}
 
-	workDesc := *description
-	if *filePath != "" {
-		data, err := os.ReadFile(*filePath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to read file %s: %v\n", *filePath, err)
-			os.Exit(1)
-		}
-		workDesc = string(data)
+	workDesc, err := resolveWorkDesc(*description, *filePath, fs.Args(), os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
 	if workDesc == "" {
@@ -76,6 +92,7 @@ Examples:
 		fmt.Fprintln(os.Stderr, "  ralph add \"description...\"")
 		fmt.Fprintln(os.Stderr, "  ralph add -file work.md")
 		fmt.Fprintln(os.Stderr, "  ralph add -desc \"description\"")
+		fmt.Fprintln(os.Stderr, "  echo \"description\" | ralph add -")
 		os.Exit(1)
 	}
 
@@ -85,8 +102,10 @@ Examples:
 	}
 
 	// Archive completed tasks and compact learnings before adding new work
-	archiveCompletedTasks()
-	compactLearnings(chosenModel)
+	if !*dryRun {
+		archiveCompletedTasks()
+		compactLearnings(chosenModel, *timeout)
+	}
 
 	prdPath := filepath.Join(".ralph", "prd.json")
 	prdBytes, err := os.ReadFile(prdPath)
@@ -94,6 +113,13 @@ Examples:
 		fmt.Fprintf(os.Stderr, "Could not read .ralph/prd.json - are you in a Ralph project? Error: %v\n", err)
 		os.Exit(1)
 	}
+	if placement.After != "" {
+		var current prdFile
+		if err := json.Unmarshal(prdBytes, &current); err == nil && findTask(&current, placement.After) == nil {
+			fmt.Fprintf(os.Stderr, "Task %q not found in .ralph/prd.json\n", placement.After)
+			os.Exit(1)
+		}
+	}
 	reqBytes, err := os.ReadFile(filepath.Join(".ralph", "requirements.md"))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Could not read .ralph/requirements.md - are you in a Ralph project? Error: %v\n", err)
@@ -108,7 +134,7 @@ Examples:
 	}
 
 	fmt.Printf("Calling Claude to translate into tasks (model: %s)...\n", chosenModel)
-	result, err := runClaudeOnceWithModel(prompt, chosenModel)
+	result, err := runClaudeOnceWithModel(prompt, chosenModel, *timeout)
 	if err != nil {
 		if isClaudeRateLimitError(err) {
 			fmt.Fprintln(os.Stderr, "Claude is unavailable (usage limit / rate limit).")
@@ -150,6 +176,23 @@ Examples:
 			}
 		}
 
+		if placementSet || prio != "" {
+			if added, err = placeGeneratedTasks(&after, added, placement, placementSet, prio); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			out, err := json.MarshalIndent(after, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to serialize updated .ralph/prd.json: %v\n", err)
+				os.Exit(1)
+			}
+			updatedPRD = string(out)
+		}
+
+		if *dryRun {
+			printDryRunTasks(added)
+			return
+		}
 		if err := os.WriteFile(prdPath, []byte(updatedPRD), 0644); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to update .ralph/prd.json: %v\n", err)
 			os.Exit(1)
@@ -158,15 +201,7 @@ Examples:
 		if len(added) == 0 {
 			fmt.Println("  (unable to determine added tasks; .ralph/prd.json was updated)")
 		} else {
-			for _, t := range added {
-				id := strings.TrimSpace(t.ID)
-				title := strings.TrimSpace(t.Title)
-				prio := strings.TrimSpace(t.Priority)
-				if prio == "" {
-					prio = "(no priority)"
-				}
-				fmt.Printf("  - [%s] %s (%s)\n", id, title, prio)
-			}
+			printTaskLines(os.Stdout, added)
 		}
 		fmt.Println("\nNext step:")
 		fmt.Println("  ralph run")
@@ -188,6 +223,7 @@ Examples:
 		fmt.Fprintln(os.Stderr, "No new tasks returned.")
 		os.Exit(1)
 	}
+	setTaskPriority(newTasks, prio)
 
 	var existing prdFile
 	if err := json.Unmarshal(prdBytes, &existing); err != nil {
@@ -198,7 +234,15 @@ Examples:
 		existing.Version = 1
 	}
 
-	existing.Tasks = append(newTasks, existing.Tasks...)
+	existing.Tasks, newTasks, err = placeNewTasks(existing.Tasks, newTasks, placement)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *dryRun {
+		printDryRunTasks(newTasks)
+		return
+	}
 
 	out, err := json.MarshalIndent(existing, "", "  ")
 	if err != nil {
@@ -211,17 +255,154 @@ Examples:
 	}
 
 	fmt.Println("New tasks:")
-	for _, t := range newTasks {
+	printTaskLines(os.Stdout, newTasks)
+	fmt.Println("\nNext step:")
+	fmt.Println("  ralph run")
+}
+
+// taskPlacement says where `ralph add` puts new tasks in prd.json.
+type taskPlacement struct {
+	Position string // "prepend" or "append"; ignored when After is set
+	After    string // Insert right after the task with this ID
+}
+
+// placeNewTasks merges added into existing at p and returns the merged list
+// along with the tasks actually added. A new task whose ID is already taken,
+// by an existing task or an earlier new one, is dropped.
+func placeNewTasks(existing, added []prdTask, p taskPlacement) ([]prdTask, []prdTask, error) {
+	seen := map[string]bool{}
+	for _, t := range existing {
+		if id := strings.TrimSpace(t.ID); id != "" {
+			seen[id] = true
+		}
+	}
+	var fresh []prdTask
+	for _, t := range added {
 		id := strings.TrimSpace(t.ID)
-		title := strings.TrimSpace(t.Title)
+		if id != "" && seen[id] {
+			continue
+		}
+		if id != "" {
+			seen[id] = true
+		}
+		fresh = append(fresh, t)
+	}
+
+	at := 0
+	switch {
+	case p.After != "":
+		at = -1
+		for i, t := range existing {
+			if strings.TrimSpace(t.ID) == p.After {
+				at = i + 1
+				break
+			}
+		}
+		if at < 0 {
+			return nil, nil, fmt.Errorf("task %q not found in .ralph/prd.json", p.After)
+		}
+	case p.Position == "append":
+		at = len(existing)
+	}
+
+	merged := make([]prdTask, 0, len(existing)+len(fresh))
+	merged = append(merged, existing[:at]...)
+	merged = append(merged, fresh...)
+	merged = append(merged, existing[at:]...)
+	return merged, fresh, nil
+}
+
+// placeGeneratedTasks applies the -priority and placement flags to a
+// prd.json Claude rewrote in full. The added tasks get priority; with reorder
+// they are also taken out of prd and put back at p among the other tasks.
+// Returns the added tasks as placed.
+func placeGeneratedTasks(prd *prdFile, added []prdTask, p taskPlacement, reorder bool, priority string) ([]prdTask, error) {
+	setTaskPriority(added, priority)
+	newIDs := map[string]bool{}
+	for _, t := range added {
+		newIDs[strings.TrimSpace(t.ID)] = true
+	}
+	var kept []prdTask
+	for i := range prd.Tasks {
+		if newIDs[strings.TrimSpace(prd.Tasks[i].ID)] {
+			setTaskPriority(prd.Tasks[i:i+1], priority)
+			continue
+		}
+		kept = append(kept, prd.Tasks[i])
+	}
+	if !reorder {
+		return added, nil
+	}
+	merged, placed, err := placeNewTasks(kept, added, p)
+	if err != nil {
+		return nil, err
+	}
+	prd.Tasks = merged
+	return placed, nil
+}
+
+// setTaskPriority sets every task's priority to priority, unless it is empty.
+func setTaskPriority(tasks []prdTask, priority string) {
+	if priority == "" {
+		return
+	}
+	for i := range tasks {
+		tasks[i].Priority = priority
+	}
+}
+
+// printTaskLines prints one "- [ID] title (priority)" line per task.
+func printTaskLines(w io.Writer, tasks []prdTask) {
+	for _, t := range tasks {
 		prio := strings.TrimSpace(t.Priority)
 		if prio == "" {
 			prio = "(no priority)"
 		}
-		fmt.Printf("  - [%s] %s (%s)\n", id, title, prio)
+		fmt.Fprintf(w, "  - [%s] %s (%s)\n", strings.TrimSpace(t.ID), strings.TrimSpace(t.Title), prio)
 	}
-	fmt.Println("\nNext step:")
-	fmt.Println("  ralph run")
+}
+
+// printDryRunTasks reports the tasks a -dry-run would have added.
+func printDryRunTasks(tasks []prdTask) {
+	fmt.Println("Tasks that would be added:")
+	if len(tasks) == 0 {
+		fmt.Println("  (none)")
+	} else {
+		printTaskLines(os.Stdout, tasks)
+	}
+	fmt.Println("\nDry run: .ralph/prd.json was not changed.")
+}
+
+// resolveWorkDesc picks the work description from -desc, -file, or the
+// positional args. A "-" file or lone "-" argument reads all of stdin.
+func resolveWorkDesc(description, filePath string, pos []string, stdin io.Reader) (string, error) {
+	if filePath == "" && description == "" && len(pos) > 0 {
+		if len(pos) == 1 {
+			if _, err := os.Stat(pos[0]); err == nil || pos[0] == "-" {
+				filePath = pos[0]
+			} else {
+				description = pos[0]
+			}
+		} else {
+			description = strings.Join(pos, " ")
+		}
+	}
+
+	switch filePath {
+	case "":
+		return description, nil
+	case "-":
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read work description from stdin: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	return string(data), nil
 }
 
 func parseNewTasks(response string) string {
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -41,3 +45,159 @@ func TestParseNewTasks(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveWorkDesc(t *testing.T) {
+	dir := t.TempDir()
+	workFile := filepath.Join(dir, "work.md")
+	if err := os.WriteFile(workFile, []byte("# From file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		desc  string
+		file  string
+		pos   []string
+		stdin string
+		want  string
+	}{
+		{name: "desc flag", desc: "Add login", want: "Add login"},
+		{name: "file flag", file: workFile, want: "# From file"},
+		{name: "positional file", pos: []string{workFile}, want: "# From file"},
+		{name: "positional words", pos: []string{"Add", "a", "logout", "button"}, want: "Add a logout button"},
+		{name: "dash positional reads stdin", pos: []string{"-"}, stdin: "Piped work\n", want: "Piped work"},
+		{name: "dash file reads stdin", file: "-", stdin: "Issue body\nwith details\n", want: "Issue body\nwith details"},
+		{name: "stdin ignored without dash", desc: "Flag wins", stdin: "unused", want: "Flag wins"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveWorkDesc(tt.desc, tt.file, tt.pos, strings.NewReader(tt.stdin))
+			if err != nil {
+				t.Fatalf("resolveWorkDesc() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveWorkDesc() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveWorkDescStdinBuildsPrompt(t *testing.T) {
+	stdin := strings.NewReader("Add rate limiting to the /login endpoint\n")
+	workDesc, err := resolveWorkDesc("", "", []string{"-"}, stdin)
+	if err != nil {
+		t.Fatalf("resolveWorkDesc() error: %v", err)
+	}
+
+	prompt, err := renderNewWorkPrompt("demo", "# Requirements", `{"version":1,"tasks":[]}`, workDesc)
+	if err != nil {
+		t.Fatalf("renderNewWorkPrompt() error: %v", err)
+	}
+	if !strings.Contains(prompt, "Add rate limiting to the /login endpoint") {
+		t.Errorf("prompt not built from stdin:\n%s", prompt)
+	}
+}
+
+func taskIDs(tasks []prdTask) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestPlaceNewTasks(t *testing.T) {
+	existing := []prdTask{
+		{ID: "T001", Title: "Schema", Status: "done"},
+		{ID: "T002", Title: "API", Status: "todo"},
+		{ID: "T003", Title: "UI", Status: "todo"},
+	}
+	added := []prdTask{
+		{ID: "T004", Title: "Auth"},
+		{ID: "T002", Title: "Duplicate of API"},
+		{ID: "T005", Title: "Docs"},
+		{ID: "T004", Title: "Auth again"},
+	}
+
+	tests := []struct {
+		name      string
+		placement taskPlacement
+		want      []string
+		wantErr   bool
+	}{
+		{"prepend", taskPlacement{Position: "prepend"}, []string{"T004", "T005", "T001", "T002", "T003"}, false},
+		{"append", taskPlacement{Position: "append"}, []string{"T001", "T002", "T003", "T004", "T005"}, false},
+		{"after middle task", taskPlacement{After: "T002"}, []string{"T001", "T002", "T004", "T005", "T003"}, false},
+		{"after last task", taskPlacement{After: "T003"}, []string{"T001", "T002", "T003", "T004", "T005"}, false},
+		{"after overrides position", taskPlacement{Position: "append", After: "T001"}, []string{"T001", "T004", "T005", "T002", "T003"}, false},
+		{"after unknown task", taskPlacement{After: "T999"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, placed, err := placeNewTasks(existing, added, tt.placement)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("placeNewTasks() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("placeNewTasks() error = %v", err)
+			}
+			if got := taskIDs(merged); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("merged = %v, want %v", got, tt.want)
+			}
+			if got := taskIDs(placed); !reflect.DeepEqual(got, []string{"T004", "T005"}) {
+				t.Errorf("placed = %v, want duplicates dropped", got)
+			}
+			for _, task := range merged {
+				if task.ID == "T002" && task.Title != "API" {
+					t.Errorf("existing T002 was replaced by %q", task.Title)
+				}
+				if task.ID == "T004" && task.Title != "Auth" {
+					t.Errorf("T004 = %q, want the first generated one", task.Title)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceGeneratedTasks(t *testing.T) {
+	// Claude returned the whole file with its new tasks first.
+	rewritten := func() *prdFile {
+		return &prdFile{Version: 1, Tasks: []prdTask{
+			{ID: "T003", Title: "Auth", Priority: "low"},
+			{ID: "T001", Title: "Schema", Priority: "high"},
+			{ID: "T002", Title: "API", Priority: "medium"},
+		}}
+	}
+	added := func() []prdTask { return []prdTask{{ID: "T003", Title: "Auth", Priority: "low"}} }
+
+	prd := rewritten()
+	placed, err := placeGeneratedTasks(prd, added(), taskPlacement{}, false, "high")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := taskIDs(prd.Tasks); !reflect.DeepEqual(got, []string{"T003", "T001", "T002"}) {
+		t.Errorf("without placement, order = %v, want Claude's order", got)
+	}
+	if prd.Tasks[0].Priority != "high" || placed[0].Priority != "high" {
+		t.Errorf("new task priority = %q/%q, want high", prd.Tasks[0].Priority, placed[0].Priority)
+	}
+	if prd.Tasks[2].Priority != "medium" {
+		t.Errorf("existing task priority changed to %q", prd.Tasks[2].Priority)
+	}
+
+	prd = rewritten()
+	if _, err := placeGeneratedTasks(prd, added(), taskPlacement{After: "T001"}, true, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := taskIDs(prd.Tasks); !reflect.DeepEqual(got, []string{"T001", "T003", "T002"}) {
+		t.Errorf("-after T001 order = %v, want [T001 T003 T002]", got)
+	}
+	if prd.Tasks[1].Priority != "low" {
+		t.Errorf("priority without -priority = %q, want Claude's low", prd.Tasks[1].Priority)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+// cleanTargets selects which groups of run artifacts ralph clean removes.
+type cleanTargets struct {
+	Logs    bool
+	State   bool
+	Metrics bool
+	Lock    bool
+}
+
+// cleanPaths returns the files and directories under dir for the selected
+// targets. Tasks, requirements, prompts, config and history are never listed.
+func cleanPaths(dir string, t cleanTargets) []string {
+	trk := tracker.NewWriter(dir)
+	var paths []string
+	if t.Logs {
+		paths = append(paths, filepath.Join(dir, "logs"))
+	}
+	if t.State {
+		paths = append(paths, trk.RunStatePath, trk.EventsPath, filepath.Join(dir, "circuit_state.json"))
+	}
+	if t.Metrics {
+		paths = append(paths, trk.MetricsPath, trk.TaskMetricsPath, trk.PrometheusPath)
+	}
+	if t.Lock {
+		paths = append(paths, trk.LockPath)
+	}
+	return paths
+}
+
+// cleanRalphDir removes the selected artifacts and returns the paths that
+// existed and were removed.
+func cleanRalphDir(dir string, t cleanTargets) ([]string, error) {
+	var removed []string
+	for _, p := range cleanPaths(dir, t) {
+		if _, err := os.Lstat(p); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return removed, err
+		}
+		if err := os.RemoveAll(p); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", p, err)
+		}
+		removed = append(removed, p)
+	}
+	return removed, nil
+}
+
+func cleanCmd(args []string) int {
+	fs := flag.NewFlagSet("clean", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`clean 🧹  Remove logs, run state and metrics from .ralph/
+
+Usage:
+  ralph clean [flags]
+
+With no flags, removes logs, state and metrics. prd.json, requirements.md,
+prompts, config and metrics history are always kept.
+
+Flags:
+  -logs      Remove .ralph/logs/
+  -state     Remove run_state.json, events.jsonl and circuit_state.json
+  -metrics   Remove run_metrics.json, task_metrics.json and metrics.prom
+  -all       Remove logs, state, metrics and the run lock
+  -force     Clean even if a run appears to be active
+
+Examples:
+  ralph clean
+  ralph clean -logs
+  ralph clean -all -force
+`)
+	}
+	logs := fs.Bool("logs", false, "Remove .ralph/logs/")
+	state := fs.Bool("state", false, "Remove run state files")
+	metrics := fs.Bool("metrics", false, "Remove run_metrics.json and task_metrics.json")
+	all := fs.Bool("all", false, "Remove logs, state, metrics and the run lock")
+	force := fs.Bool("force", false, "Clean even if a run appears to be active")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %v\n", fs.Args())
+		fs.Usage()
+		return 1
+	}
+
+	targets := cleanTargets{Logs: *logs, State: *state, Metrics: *metrics}
+	switch {
+	case *all:
+		targets = cleanTargets{Logs: true, State: true, Metrics: true, Lock: true}
+	case !*logs && !*state && !*metrics:
+		targets = cleanTargets{Logs: true, State: true, Metrics: true}
+	}
+
+	dir := ".ralph"
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, "No .ralph directory found. Run `ralph clean` from your project root.")
+		return 1
+	}
+
+	if pid, runID := findRunningLoop(tracker.NewWriter(dir)); pid != 0 {
+		if !*force {
+			fmt.Fprintf(os.Stderr, "A loop is running (pid %d, run_id=%s).\n", pid, runID)
+			fmt.Fprintln(os.Stderr, "Stop it with `ralph stop`, or re-run with -force to clean anyway.")
+			return 1
+		}
+		fmt.Printf("⚠️  Cleaning while a loop is running (pid %d)\n", pid)
+	}
+
+	removed, err := cleanRalphDir(dir, targets)
+	for _, p := range removed {
+		fmt.Printf("Removed %s\n", p)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(removed) == 0 {
+		fmt.Println("Nothing to clean")
+		return 0
+	}
+	fmt.Printf("✓ Cleaned %d item(s)\n", len(removed))
+	return 0
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+// writeRalphTree creates a .ralph directory with every file ralph clean
+// knows about, plus the files it must keep.
+func writeRalphTree(t *testing.T, root string) {
+	t.Helper()
+	files := []string{
+		"prd.json",
+		"requirements.md",
+		"config.json",
+		"prompts/LOOP_PROMPT.md",
+		"metrics_history.jsonl",
+		"logs/ralph.log",
+		"logs/claude_output.json",
+		"run_state.json",
+		"events.jsonl",
+		"circuit_state.json",
+		"run_metrics.json",
+		".ralph_lock",
+	}
+	for _, f := range files {
+		p := filepath.Join(root, ".ralph", f)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCleanCmd(t *testing.T) {
+	kept := []string{"prd.json", "requirements.md", "config.json", "prompts/LOOP_PROMPT.md", "metrics_history.jsonl"}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantRemoved []string
+		wantKept    []string
+	}{
+		{
+			name:        "default cleans logs, state and metrics",
+			wantRemoved: []string{"logs", "run_state.json", "events.jsonl", "circuit_state.json", "run_metrics.json"},
+			wantKept:    append([]string{".ralph_lock"}, kept...),
+		},
+		{
+			name:        "logs only",
+			args:        []string{"-logs"},
+			wantRemoved: []string{"logs"},
+			wantKept:    append([]string{"run_state.json", "run_metrics.json", ".ralph_lock"}, kept...),
+		},
+		{
+			name:        "state and metrics",
+			args:        []string{"-state", "-metrics"},
+			wantRemoved: []string{"run_state.json", "events.jsonl", "circuit_state.json", "run_metrics.json"},
+			wantKept:    append([]string{"logs/ralph.log", ".ralph_lock"}, kept...),
+		},
+		{
+			name:        "all removes the lock",
+			args:        []string{"-all"},
+			wantRemoved: []string{"logs", "run_state.json", "run_metrics.json", ".ralph_lock"},
+			wantKept:    kept,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeRalphTree(t, dir)
+			t.Chdir(dir)
+
+			if code := cleanCmd(tt.args); code != 0 {
+				t.Fatalf("cleanCmd(%v) = %d, want 0", tt.args, code)
+			}
+			for _, f := range tt.wantRemoved {
+				if _, err := os.Stat(filepath.Join(".ralph", f)); !os.IsNotExist(err) {
+					t.Errorf("expected .ralph/%s to be removed", f)
+				}
+			}
+			for _, f := range tt.wantKept {
+				if _, err := os.Stat(filepath.Join(".ralph", f)); err != nil {
+					t.Errorf("expected .ralph/%s to be kept: %v", f, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCleanRalphDirReportsRemoved(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".ralph")
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "run_metrics.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := cleanRalphDir(dir, cleanTargets{Logs: true, State: true, Metrics: true})
+	if err != nil {
+		t.Fatalf("cleanRalphDir: %v", err)
+	}
+	want := []string{filepath.Join(dir, "logs"), filepath.Join(dir, "run_metrics.json")}
+	if len(removed) != len(want) || removed[0] != want[0] || removed[1] != want[1] {
+		t.Errorf("removed = %v, want %v (missing files are not reported)", removed, want)
+	}
+
+	removed, err = cleanRalphDir(dir, cleanTargets{Logs: true})
+	if err != nil || len(removed) != 0 {
+		t.Errorf("second clean = %v, %v; want nothing removed", removed, err)
+	}
+}
+
+func TestCleanCmdRefusesActiveRun(t *testing.T) {
+	dir := t.TempDir()
+	writeRalphTree(t, dir)
+	t.Chdir(dir)
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("sleep not available: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	b, _ := json.Marshal(tracker.Lock{PID: cmd.Process.Pid, RunID: "run-1", StartedAt: time.Now()})
+	if err := os.WriteFile(filepath.Join(".ralph", ".ralph_lock"), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := cleanCmd(nil); code != 1 {
+		t.Fatalf("cleanCmd() with active run = %d, want 1", code)
+	}
+	if _, err := os.Stat(filepath.Join(".ralph", "run_state.json")); err != nil {
+		t.Fatalf("run_state.json removed despite active run: %v", err)
+	}
+
+	if code := cleanCmd([]string{"-force"}); code != 0 {
+		t.Fatalf("cleanCmd(-force) = %d, want 0", code)
+	}
+	if _, err := os.Stat(filepath.Join(".ralph", "run_state.json")); !os.IsNotExist(err) {
+		t.Error("expected run_state.json removed with -force")
+	}
+}
+
+func TestCleanCmdWithoutRalphDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if code := cleanCmd(nil); code != 1 {
+		t.Errorf("cleanCmd() without .ralph = %d, want 1", code)
+	}
+}
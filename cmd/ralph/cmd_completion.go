@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// completionFlag is a flag offered for a command.
+type completionFlag struct {
+	Name string
+	Desc string
+}
+
+// completionCommand is a top-level command and what can follow it.
+type completionCommand struct {
+	Name        string
+	Desc        string
+	Subcommands []string
+	Flags       []completionFlag
+}
+
+// completionCommands is the static list behind `ralph completion`. Keep it in
+// sync with the dispatch in main and each command's flag set.
+var completionCommands = []completionCommand{
+	{Name: "run", Desc: "Run the main loop", Flags: []completionFlag{
+		{"config", "Path to config file"},
+		{"model", "Claude model to use"},
+		{"once", "Run loop only once"},
+		{"budget", "Stop after spending this many dollars"},
+		{"max-loops", "Stop after this many iterations"},
+		{"log-format", "Run log format: text or json"},
+		{"step", "With -once, run only this step"},
+		{"no-redact", "Do not redact secrets in the run log"},
+		{"json-progress", "Write progress to stdout as JSON lines"},
+		{"wait-on-limit", "Wait for a Claude usage limit to reset and resume"},
+		{"confirm-each-step", "Ask before each step runs"},
+		{"plan", "Print the execution plan and exit"},
+		{"no-reset-failed", "Keep failed tasks failed at the start of the run"},
+		{"env-file", "Load environment variables from a dotenv file"},
+		{"env-override", "Let -env-file replace variables already set"},
+		{"prometheus", "Write .ralph/metrics.prom each loop"},
+		{"force", "Clear an unreadable or other-host lock"},
+	}},
+	{Name: "resume", Desc: "Continue after an interrupted run"},
+	{Name: "init", Desc: "Start a new Ralph project", Flags: []completionFlag{
+		{"requirements", "Path to requirements.md file"},
+		{"model", "Model to use"},
+		{"agent", "Agent CLI to set up: claude or aider"},
+		{"timeout", "Give up on an agent call after this long"},
+	}},
+	{Name: "add", Desc: "Add more work", Flags: []completionFlag{
+		{"file", "Path to markdown file with work description"},
+		{"desc", "Work description"},
+		{"model", "Claude model to use"},
+		{"timeout", "Give up on a Claude call after this long"},
+		{"dry-run", "Preview tasks without writing prd.json"},
+		{"position", "Where new tasks go: prepend or append"},
+		{"after", "Insert new tasks after this task ID"},
+		{"priority", "Set every new task's priority"},
+	}},
+	{Name: "fix", Desc: "Create tasks from a GitHub or GitLab issue", Flags: []completionFlag{
+		{"issue", "Issue number(s), comma-separated"},
+		{"repo", "Override repository"},
+		{"provider", "Issue provider: github or gitlab"},
+		{"model", "Claude model to use"},
+		{"timeout", "Give up on a Claude call after this long"},
+		{"dry-run", "Preview tasks without writing prd.json"},
+		{"force", "Add tasks for an already fixed issue"},
+	}},
+	{Name: "pr", Desc: "Push branch and open a pull request", Flags: []completionFlag{
+		{"title", "PR title"},
+		{"draft", "Create as draft PR"},
+		{"base", "Base branch"},
+	}},
+	{Name: "status", Desc: "Show the current or most recent run", Flags: []completionFlag{
+		{"json", "Print run state and metrics as JSON"},
+	}},
+	{Name: "stop", Desc: "Stop a running loop", Flags: []completionFlag{
+		{"timeout", "How long to wait for the loop to exit"},
+		{"force", "Send SIGKILL after the timeout"},
+	}},
+	{Name: "clean", Desc: "Remove logs, run state and metrics", Flags: []completionFlag{
+		{"logs", "Remove .ralph/logs/"},
+		{"state", "Remove run state files"},
+		{"metrics", "Remove run_metrics.json"},
+		{"all", "Remove logs, state, metrics and the lock"},
+		{"force", "Clean even if a run is active"},
+	}},
+	{Name: "metrics", Desc: "Summarize token usage and cost", Flags: []completionFlag{
+		{"n", "Number of recent runs to show"},
+		{"since", "Only show runs within a window (24h, 7d)"},
+		{"json", "Print metrics as JSON"},
+		{"csv", "Write the run history to a CSV file"},
+	}},
+	{Name: "logs", Desc: "Show, filter, or follow the run log", Flags: []completionFlag{
+		{"follow", "Keep printing new lines as they are written"},
+		{"level", "Minimum level to show"},
+		{"loop", "Only show lines from this loop"},
+		{"json", "Print JSON log lines unchanged"},
+		{"file", "Log file to read"},
+	}},
+	{Name: "tasks", Desc: "List tasks and their status", Subcommands: []string{"reorder"}, Flags: []completionFlag{
+		{"status", "Only show tasks with this status"},
+		{"priority", "Only show tasks with this priority"},
+		{"cost", "Show Claude calls, tokens and cost per task"},
+		{"json", "Print matching tasks as JSON"},
+	}},
+	{Name: "task", Desc: "Add, remove, or update a task", Subcommands: []string{"set-status", "retry", "add", "rm"}},
+	{Name: "config", Desc: "Validate loop configuration", Subcommands: []string{"validate"}},
+	{Name: "schema", Desc: "Print the JSON Schema for prd.json", Subcommands: []string{"prd"}},
+	{Name: "doctor", Desc: "Check that Claude, git, and the project are set up"},
+	{Name: "eval", Desc: "Run evaluation suites", Subcommands: []string{"list", "run", "compare", "report", "clean"}, Flags: []completionFlag{
+		{"approach", "Evaluation approach: ralph or oneshot"},
+		{"model", "Claude model to use"},
+		{"models", "Comma-separated models to run in turn"},
+		{"test-only", "Run tests only against an existing project"},
+		{"report", "Write a test report: junit"},
+		{"report-out", "Path for the test report"},
+		{"history", "Compare averages over every saved run"},
+		{"markdown", "Write the comparison as Markdown to a file"},
+		{"cleanup", "Remove the project directory after a successful run"},
+		{"keep", "Keep the project directory"},
+		{"timeout", "Time limit for generating the project, e.g. 90m"},
+		{"suites", "Comma-separated suites to run concurrently"},
+		{"max-parallel", "Most suites to run at once with --suites"},
+		{"port", "Port to start the suite's app on"},
+		{"older-than", "Remove project directories older than this"},
+		{"dir", "Directory holding eval project directories"},
+		{"dry-run", "List what would be removed"},
+		{"json", "Print the leaderboard as JSON"},
+	}},
+	{Name: "upgrade", Desc: "Check for updates and upgrade Ralph", Flags: []completionFlag{
+		{"yes", "Skip confirmation prompt"},
+	}},
+	{Name: "completion", Desc: "Generate a shell completion script", Subcommands: []string{"bash", "zsh", "fish"}},
+	{Name: "version", Desc: "Show the version", Flags: []completionFlag{
+		{"check", "Report whether a newer release is available"},
+	}},
+	{Name: "help", Desc: "Show help"},
+}
+
+func completionCmd(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printCompletionUsage()
+		if len(args) == 0 {
+			return 1
+		}
+		return 0
+	}
+	if len(args) > 1 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %v\n", args[1:])
+		return 1
+	}
+	if err := writeCompletion(os.Stdout, args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		printCompletionUsage()
+		return 1
+	}
+	return 0
+}
+
+func printCompletionUsage() {
+	fmt.Print(`completion ⌨️  Generate a shell completion script
+
+Usage:
+  ralph completion bash|zsh|fish
+
+Install:
+  bash   Add to ~/.bashrc:
+           source <(ralph completion bash)
+  zsh    Write the script to a directory on $fpath, then restart zsh:
+           ralph completion zsh > "${fpath[1]}/_ralph"
+         Or add to ~/.zshrc (after compinit):
+           source <(ralph completion zsh)
+  fish   Write it to fish's completions directory:
+           ralph completion fish > ~/.config/fish/completions/ralph.fish
+
+Completes commands, subcommands and flags. Anything else falls back to files.
+`)
+}
+
+// writeCompletion writes the completion script for shell to w.
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w)
+	case "zsh":
+		writeZshCompletion(w)
+	case "fish":
+		writeFishCompletion(w)
+	default:
+		return fmt.Errorf("unsupported shell %q (want bash, zsh or fish)", shell)
+	}
+	return nil
+}
+
+// completionWords returns a command's subcommands and -flags.
+func completionWords(c completionCommand) []string {
+	words := append([]string(nil), c.Subcommands...)
+	for _, f := range c.Flags {
+		words = append(words, "-"+f.Name)
+	}
+	return words
+}
+
+func writeBashCompletion(w io.Writer) {
+	names := make([]string, len(completionCommands))
+	for i, c := range completionCommands {
+		names[i] = c.Name
+	}
+
+	fmt.Fprint(w, "# bash completion for ralph\n")
+	fmt.Fprint(w, "_ralph() {\n")
+	fmt.Fprint(w, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" opts=\"\"\n")
+	fmt.Fprint(w, "    if [[ $COMP_CWORD -eq 1 ]]; then\n")
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(names, " "))
+	fmt.Fprint(w, "        return\n")
+	fmt.Fprint(w, "    fi\n")
+	fmt.Fprint(w, "    case \"${COMP_WORDS[1]}\" in\n")
+	for _, c := range completionCommands {
+		if words := completionWords(c); len(words) > 0 {
+			fmt.Fprintf(w, "        %s) opts=%q ;;\n", c.Name, strings.Join(words, " "))
+		}
+	}
+	fmt.Fprint(w, "    esac\n")
+	fmt.Fprint(w, "    COMPREPLY=($(compgen -W \"$opts\" -- \"$cur\"))\n")
+	fmt.Fprint(w, "}\n")
+	fmt.Fprint(w, "complete -o default -F _ralph ralph\n")
+}
+
+func writeZshCompletion(w io.Writer) {
+	fmt.Fprint(w, "#compdef ralph\n\n")
+	fmt.Fprint(w, "_ralph() {\n")
+	fmt.Fprint(w, "    local -a commands opts\n")
+	fmt.Fprint(w, "    commands=(\n")
+	for _, c := range completionCommands {
+		fmt.Fprintf(w, "        %s\n", shellQuote(c.Name+":"+c.Desc))
+	}
+	fmt.Fprint(w, "    )\n")
+	fmt.Fprint(w, "    if (( CURRENT == 2 )); then\n")
+	fmt.Fprint(w, "        _describe 'command' commands\n")
+	fmt.Fprint(w, "        return\n")
+	fmt.Fprint(w, "    fi\n")
+	fmt.Fprint(w, "    case $words[2] in\n")
+	for _, c := range completionCommands {
+		if words := completionWords(c); len(words) > 0 {
+			fmt.Fprintf(w, "        %s) opts=(%s) ;;\n", c.Name, strings.Join(words, " "))
+		}
+	}
+	fmt.Fprint(w, "    esac\n")
+	fmt.Fprint(w, "    compadd -a opts\n")
+	fmt.Fprint(w, "    _files\n")
+	fmt.Fprint(w, "}\n\n")
+	fmt.Fprint(w, "if [[ $zsh_eval_context[-1] == loadautofunc ]]; then\n")
+	fmt.Fprint(w, "    _ralph \"$@\"\n")
+	fmt.Fprint(w, "else\n")
+	fmt.Fprint(w, "    compdef _ralph ralph\n")
+	fmt.Fprint(w, "fi\n")
+}
+
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprint(w, "# fish completion for ralph\n")
+	for _, c := range completionCommands {
+		fmt.Fprintf(w, "complete -c ralph -f -n __fish_use_subcommand -a %s -d %s\n", c.Name, fishQuote(c.Desc))
+	}
+	for _, c := range completionCommands {
+		cond := fishQuote("__fish_seen_subcommand_from " + c.Name)
+		for _, sub := range c.Subcommands {
+			fmt.Fprintf(w, "complete -c ralph -f -n %s -a %s\n", cond, sub)
+		}
+		for _, f := range c.Flags {
+			fmt.Fprintf(w, "complete -c ralph -n %s -o %s -d %s\n", cond, f.Name, fishQuote(f.Desc))
+		}
+	}
+}
+
+// shellQuote wraps s in single quotes for bash and zsh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote wraps s in single quotes for fish, which escapes with backslashes.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\\`, `\\\\`, "'", `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	commands := []string{"run", "init", "add", "fix", "eval", "upgrade", "status", "version"}
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeCompletion(&buf, shell); err != nil {
+				t.Fatalf("writeCompletion(%s): %v", shell, err)
+			}
+			script := buf.String()
+			if strings.TrimSpace(script) == "" {
+				t.Fatal("empty completion script")
+			}
+			for _, c := range completionCommands {
+				if !strings.Contains(script, c.Name) {
+					t.Errorf("%s script missing command %q", shell, c.Name)
+				}
+			}
+			for _, c := range commands {
+				if !strings.Contains(script, c) {
+					t.Errorf("%s script missing command %q", shell, c)
+				}
+			}
+			for _, flag := range []string{"max-loops", "log-format", "issue", "requirements"} {
+				if !strings.Contains(script, flag) {
+					t.Errorf("%s script missing flag %q", shell, flag)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCompletionUnknownShell(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCompletion(&buf, "powershell"); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+func TestBashCompletionParses(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+	var buf bytes.Buffer
+	if err := writeCompletion(&buf, "bash"); err != nil {
+		t.Fatal(err)
+	}
+	// Source the script and complete "ralph run -max".
+	cmd := exec.Command(bash, "-c", buf.String()+`
+COMP_WORDS=(ralph run -max); COMP_CWORD=2; _ralph; echo "${COMPREPLY[@]}"`)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("bash failed: %v\n%s", err, out)
+	}
+	if got := strings.TrimSpace(string(out)); got != "-max-loops" {
+		t.Errorf("completion for 'ralph run -max' = %q, want -max-loops", got)
+	}
+}
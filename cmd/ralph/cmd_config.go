@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/loop"
+)
+
+func printConfigUsage() {
+	fmt.Print(`config ⚙️  Work with loop configuration
+
+Usage:
+  ralph config validate [path]
+
+Subcommands:
+  validate   Check a config file for errors (default: .ralph/config.json)
+
+Examples:
+  ralph config validate
+  ralph config validate .ralph/configs/custom.yaml
+`)
+}
+
+func configCmd(args []string) int {
+	if len(args) == 0 {
+		printConfigUsage()
+		return 1
+	}
+
+	switch args[0] {
+	case "validate":
+		if len(args) > 2 {
+			fmt.Fprintln(os.Stderr, "Usage: ralph config validate [path]")
+			return 1
+		}
+		path := defaultConfigPath()
+		if len(args) == 2 {
+			path = args[1]
+		}
+		return configValidate(path)
+	case "help", "-h", "--help":
+		printConfigUsage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown config subcommand: %s\n", args[0])
+		printConfigUsage()
+		return 1
+	}
+}
+
+func configValidate(path string) int {
+	registry := newStepRegistry()
+	loader := config.NewLoader(".ralph")
+	loader.SetStepValidator(registry.ValidateStepConfig)
+	loader.SetConditionValidator(loop.ValidateCondition)
+	cfg, err := loader.LoadAndValidate(path, registry.RegisteredTypes())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	enabled := 0
+	for _, s := range cfg.Steps {
+		if s.IsEnabled() {
+			enabled++
+		}
+	}
+	fmt.Printf("Config is valid (%d steps, %d enabled)\n", len(cfg.Steps), enabled)
+	return 0
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+	}{
+		{"valid", write("valid.json", `{"name": "ok", "steps": [
+			{"type": "agent", "name": "run-claude"},
+			{"type": "git-commit", "name": "commit", "enabled": false}
+		]}`), 0},
+		{"agent config typo", write("typo.json", `{"name": "bad", "steps": [
+			{"type": "agent", "name": "run-claude", "config": {"prd_fil": ".ralph/prd.json"}}
+		]}`), 1},
+		{"unknown step type", write("unknown.json", `{"name": "bad", "steps": [{"type": "deploy", "name": "ship"}]}`), 1},
+		{"invalid json", write("broken.json", `{"name": `), 1},
+		{"missing file", filepath.Join(dir, "nope.json"), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := configValidate(tt.path); got != tt.wantCode {
+				t.Errorf("configValidate(%s) = %d, want %d", tt.path, got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestConfigValidateDefaultTemplate(t *testing.T) {
+	content, err := renderDefaultLoopConfig(DefaultLoopConfigOptions{Model: "sonnet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := configValidate(path); got != 0 {
+		t.Errorf("default config failed validation (code %d)", got)
+	}
+}
+
+func TestRenderDefaultLoopConfigAgent(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       DefaultLoopConfigOptions
+		wantName   string
+		wantType   string
+		wantBinary string
+	}{
+		{"default is claude", DefaultLoopConfigOptions{}, "claude", "", ""},
+		{"explicit claude", DefaultLoopConfigOptions{AgentType: "claude", AgentBinary: "claude"}, "claude", "", ""},
+		{"aider", DefaultLoopConfigOptions{AgentType: "aider", AgentBinary: "aider"}, "aider", "aider", "aider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := renderDefaultLoopConfig(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cfg struct {
+				Steps []struct {
+					Name   string            `json:"name"`
+					Config map[string]string `json:"config"`
+				} `json:"steps"`
+			}
+			if err := json.Unmarshal([]byte(content), &cfg); err != nil {
+				t.Fatalf("rendered config is not valid JSON: %v\n%s", err, content)
+			}
+			step := cfg.Steps[0]
+			if step.Name != tt.wantName || step.Config["agent_type"] != tt.wantType || step.Config["binary"] != tt.wantBinary {
+				t.Errorf("agent step = %+v, want name %q, agent_type %q, binary %q", step, tt.wantName, tt.wantType, tt.wantBinary)
+			}
+
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if got := configValidate(path); got != 0 {
+				t.Errorf("rendered config failed validation (code %d)", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/loop"
+)
+
+// doctorCheck is the outcome of a single preflight check.
+type doctorCheck struct {
+	Name     string
+	Err      error
+	Hint     string
+	Optional bool // failure is reported but doesn't fail the command
+	Skipped  bool
+}
+
+func doctorCmd(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`doctor 🩺  Check that your environment is ready for Ralph
+
+Usage:
+  ralph doctor
+
+Checks Claude Code, git, and the GitHub CLI. When run inside a Ralph
+project, also checks the .ralph/ directory, config, and task list.
+
+Examples:
+  ralph doctor
+`)
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+
+	if !printDoctorReport(os.Stdout, runDoctorChecks()) {
+		return 1
+	}
+	return 0
+}
+
+func runDoctorChecks() []doctorCheck {
+	checks := []doctorCheck{
+		{Name: "Claude Code", Err: validateClaudePreflight()},
+		checkGit(),
+		{Name: "GitHub CLI (for fix/pr)", Err: checkGitHubAuth(), Optional: true},
+	}
+	return append(checks, runProjectChecks()...)
+}
+
+func checkGit() doctorCheck {
+	c := doctorCheck{Name: "git"}
+	if _, err := exec.LookPath("git"); err != nil {
+		c.Err = fmt.Errorf("git not found in PATH")
+		c.Hint = "Install git: https://git-scm.com/downloads"
+	}
+	return c
+}
+
+// runProjectChecks validates .ralph/ when present and skips otherwise.
+func runProjectChecks() []doctorCheck {
+	if st, err := os.Stat(".ralph"); err != nil || !st.IsDir() {
+		return []doctorCheck{{Name: "Ralph project (no .ralph/ here)", Skipped: true}}
+	}
+
+	configPath := defaultConfigPath()
+	checks := []doctorCheck{{
+		Name: "Project files",
+		Err:  validateRunPreflight(configPath),
+		Hint: "Re-run `ralph init` to recreate missing files",
+	}}
+
+	cfgCheck := doctorCheck{Name: "Config (" + configPath + ")", Hint: "Run `ralph config validate` for details"}
+	registry := newStepRegistry()
+	loader := config.NewLoader(".ralph")
+	loader.SetStepValidator(registry.ValidateStepConfig)
+	loader.SetConditionValidator(loop.ValidateCondition)
+	if _, err := loader.LoadAndValidate(configPath, registry.RegisteredTypes()); err != nil {
+		cfgCheck.Err = err
+	}
+	checks = append(checks, cfgCheck)
+
+	prdCheck := doctorCheck{Name: "Task list (.ralph/prd.json)"}
+	if _, _, err := agent.CheckPRDTasks(".ralph/prd.json"); err != nil {
+		prdCheck.Err = err
+		if errors.Is(err, agent.ErrPRDNoTasks) {
+			prdCheck.Hint = "Add tasks with `ralph add`"
+		}
+	}
+	checks = append(checks, prdCheck)
+	if warnings := agent.LoadPRDWarnings(".ralph/prd.json"); len(warnings) > 0 {
+		checks = append(checks, doctorCheck{
+			Name:     "Task priorities and statuses",
+			Optional: true,
+			Err:      errors.New(strings.Join(warnings, "; ")),
+			Hint:     "Unknown priorities sort last and unknown statuses count as in progress",
+		})
+	}
+	return checks
+}
+
+// printDoctorReport prints a checklist and reports whether all required checks passed.
+func printDoctorReport(w io.Writer, checks []doctorCheck) bool {
+	ok := true
+	for _, c := range checks {
+		switch {
+		case c.Skipped:
+			fmt.Fprintf(w, "–  %s: skipped\n", c.Name)
+			continue
+		case c.Err == nil:
+			fmt.Fprintf(w, "✓  %s\n", c.Name)
+			continue
+		}
+
+		label := ""
+		if c.Optional {
+			label = " (optional)"
+		} else {
+			ok = false
+		}
+		fmt.Fprintf(w, "✗  %s%s\n", c.Name, label)
+		for _, line := range strings.Split(strings.TrimSpace(c.Err.Error()), "\n") {
+			fmt.Fprintf(w, "     %s\n", line)
+		}
+		if c.Hint != "" {
+			fmt.Fprintf(w, "     → %s\n", c.Hint)
+		}
+	}
+
+	if ok {
+		fmt.Fprintln(w, "\nAll required checks passed.")
+	} else {
+		fmt.Fprintln(w, "\nSome required checks failed.")
+	}
+	return ok
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPrintDoctorReport(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []doctorCheck
+		wantOK bool
+		want   []string
+	}{
+		{
+			name:   "all pass",
+			checks: []doctorCheck{{Name: "git"}, {Name: "project", Skipped: true}},
+			wantOK: true,
+			want:   []string{"✓  git", "–  project: skipped", "All required checks passed."},
+		},
+		{
+			name:   "optional failure",
+			checks: []doctorCheck{{Name: "gh", Err: errors.New("not authenticated"), Optional: true}},
+			wantOK: true,
+			want:   []string{"✗  gh (optional)", "not authenticated"},
+		},
+		{
+			name:   "required failure with hint",
+			checks: []doctorCheck{{Name: "git", Err: errors.New("git not found"), Hint: "Install git"}},
+			wantOK: false,
+			want:   []string{"✗  git", "git not found", "→ Install git", "Some required checks failed."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if got := printDoctorReport(&buf, tt.checks); got != tt.wantOK {
+				t.Errorf("printDoctorReport() = %v, want %v", got, tt.wantOK)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunProjectChecks(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	checks := runProjectChecks()
+	if len(checks) != 1 || !checks[0].Skipped {
+		t.Fatalf("expected project checks to be skipped outside a project, got %+v", checks)
+	}
+
+	if err := os.MkdirAll(".ralph", 0755); err != nil {
+		t.Fatal(err)
+	}
+	checks = runProjectChecks()
+	if len(checks) != 3 {
+		t.Fatalf("expected 3 project checks, got %d", len(checks))
+	}
+	for _, c := range checks {
+		if c.Err == nil {
+			t.Errorf("expected %q to fail in an empty .ralph/", c.Name)
+		}
+	}
+}
@@ -1,14 +1,21 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/chr1sbest/wiggum/internal/eval"
+	"github.com/chr1sbest/wiggum/internal/tracker"
 )
 
 func evalCmd(args []string) int {
@@ -24,11 +31,15 @@ Subcommands:
   list         List available evaluation suites
   run          Run an evaluation suite
   compare      Compare ralph vs oneshot results
+  report       Rank saved results by tests passed per dollar
+  clean        Remove old eval project directories
 
 Examples:
   ralph eval list
   ralph eval run flask --approach ralph
   ralph eval compare flask
+  ralph eval report
+  ralph eval clean --older-than 7d
 
 Run 'ralph eval <subcommand> -h' for details.
 `)
@@ -60,6 +71,10 @@ Run 'ralph eval <subcommand> -h' for details.
 		return evalRunCmd(subArgs)
 	case "compare":
 		return evalCompareCmd(subArgs)
+	case "report":
+		return evalReportCmd(subArgs)
+	case "clean":
+		return evalCleanCmd(subArgs)
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown eval subcommand: %s\n", subcommand)
 		fs.Usage()
@@ -133,23 +148,52 @@ func evalRunCmd(args []string) int {
 	fs.SetOutput(io.Discard)
 	approach := fs.String("approach", "ralph", "Evaluation approach (ralph or oneshot)")
 	model := fs.String("model", "sonnet", "Claude model to use")
+	models := fs.String("models", "", "Comma-separated models to run one after another")
 	testOnly := fs.String("test-only", "", "Run tests only against existing project directory")
+	report := fs.String("report", "", "Write a test report in this format (junit)")
+	reportOut := fs.String("report-out", "results.xml", "Path for the --report file")
+	cleanup := fs.Bool("cleanup", false, "Remove the project directory after a successful run")
+	keep := fs.Bool("keep", false, "Keep the project directory (the default)")
+	timeout := fs.String("timeout", "", "Time limit for generating the project, e.g. 90m (default 45m)")
+	suites := fs.String("suites", "", "Comma-separated suites to run concurrently")
+	maxParallel := fs.Int("max-parallel", 2, "Most suites to run at once with --suites")
+	port := fs.Int("port", 0, "Port to start the suite's app on for testing (default: a free port)")
 
 	fs.Usage = func() {
 		fmt.Print(`eval run 🏃  Run an evaluation suite
 
 Usage:
   ralph eval run <suite> [flags]
+  ralph eval run --suites <a,b,c> [flags]
 
 Flags:
   --approach string    Evaluation approach: ralph or oneshot (default "ralph")
   --model string       Claude model to use (default "sonnet")
+  --models string      Comma-separated models to run in turn, e.g. sonnet,opus,haiku
   --test-only string   Run tests only against existing project directory
+  --report string      Write per-test results in this format: junit
+  --report-out string  Path for the report (default "results.xml")
+  --cleanup            Remove the project directory after a successful run,
+                       keeping only the result JSON in evals/results
+  --keep               Keep the project directory (the default)
+  --timeout duration   Time limit for generating the project (ralph init +
+                       ralph run, or the oneshot Claude call), e.g. 90m or 2h
+                       (default 45m)
+  --suites string      Comma-separated suites to run concurrently, each with
+                       its own project directory and app port
+  --max-parallel int   Most suites to run at once with --suites (default 2)
+  --port int           Port to start the suite's app on (default: a free
+                       port); with --suites, the first of consecutive ports
 
 Examples:
   ralph eval run flask --approach ralph
   ralph eval run logagg --approach oneshot --model opus
+  ralph eval run logagg --models sonnet,opus,haiku
   ralph eval run flask --test-only /path/to/existing/project
+  ralph eval run logagg --report junit --report-out results.xml
+  ralph eval run flask --cleanup
+  ralph eval run tasktracker --timeout 2h
+  ralph eval run --suites flask,tasktracker,logagg --max-parallel 3
 `)
 	}
 
@@ -165,7 +209,14 @@ Examples:
 				// Check if it's a known flag that takes a value
 				if args[i] == "-approach" || args[i] == "--approach" ||
 					args[i] == "-model" || args[i] == "--model" ||
-					args[i] == "-test-only" || args[i] == "--test-only" {
+					args[i] == "-models" || args[i] == "--models" ||
+					args[i] == "-test-only" || args[i] == "--test-only" ||
+					args[i] == "-report" || args[i] == "--report" ||
+					args[i] == "-report-out" || args[i] == "--report-out" ||
+					args[i] == "-timeout" || args[i] == "--timeout" ||
+					args[i] == "-suites" || args[i] == "--suites" ||
+					args[i] == "-max-parallel" || args[i] == "--max-parallel" ||
+					args[i] == "-port" || args[i] == "--port" {
 					i++
 					reordered = append(reordered, args[i])
 				}
@@ -186,17 +237,81 @@ Examples:
 		return 1
 	}
 
-	if fs.NArg() == 0 {
+	if *suites != "" && fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Error: give either a suite name or --suites, not both")
+		return 1
+	}
+	if *suites == "" && fs.NArg() == 0 {
 		fmt.Fprintln(os.Stderr, "Error: suite name required")
 		fs.Usage()
 		return 1
 	}
 
+	if *cleanup && *keep {
+		fmt.Fprintln(os.Stderr, "Error: --cleanup and --keep are mutually exclusive")
+		return 1
+	}
+	if *port < 0 || *port > 65535 {
+		fmt.Fprintf(os.Stderr, "Error: --port must be between 1 and 65535, or 0 for a free port, got %d\n", *port)
+		return 1
+	}
+
+	timeoutSeconds := eval.DefaultTimeoutSeconds
+	if *timeout != "" {
+		d, err := time.ParseDuration(*timeout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --timeout %q (use e.g. 90m or 2h)\n", *timeout)
+			return 1
+		}
+		if d < time.Second {
+			fmt.Fprintf(os.Stderr, "Error: --timeout must be at least 1s, got %s\n", *timeout)
+			return 1
+		}
+		timeoutSeconds = int(d / time.Second)
+	}
+
+	if *report != "" && *report != "junit" {
+		fmt.Fprintf(os.Stderr, "Invalid report format '%s'. Must be 'junit'.\n", *report)
+		return 1
+	}
+
+	if *suites != "" {
+		suiteList := eval.ParseSuites(*suites)
+		if len(suiteList) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: --suites needs at least one suite")
+			return 1
+		}
+		if *testOnly != "" {
+			fmt.Fprintln(os.Stderr, "Error: --test-only can't be combined with --suites")
+			return 1
+		}
+		if *maxParallel < 1 {
+			fmt.Fprintf(os.Stderr, "Error: --max-parallel must be at least 1, got %d\n", *maxParallel)
+			return 1
+		}
+		if m := eval.ParseModels(*models); len(m) > 1 {
+			fmt.Fprintln(os.Stderr, "Error: --models can't be combined with --suites; pick one model")
+			return 1
+		} else if len(m) == 1 {
+			*model = m[0]
+		}
+		if *approach != "ralph" && *approach != "oneshot" {
+			fmt.Fprintf(os.Stderr, "Invalid approach '%s'. Must be 'ralph' or 'oneshot'.\n", *approach)
+			return 1
+		}
+		for _, s := range suiteList {
+			if !evalSuiteExists(s) {
+				fmt.Fprintf(os.Stderr, "Suite '%s' not found. Run 'ralph eval list' to see available suites.\n", s)
+				return 1
+			}
+		}
+		return evalRunSuites(suiteList, *approach, *model, timeoutSeconds, *maxParallel, *port, *report, *reportOut, *cleanup)
+	}
+
 	suite := fs.Arg(0)
 
 	// Validate suite exists
-	suiteYaml := filepath.Join("evals", "suites", suite, "suite.yaml")
-	if _, err := os.Stat(suiteYaml); os.IsNotExist(err) {
+	if !evalSuiteExists(suite) {
 		fmt.Fprintf(os.Stderr, "Suite '%s' not found. Run 'ralph eval list' to see available suites.\n", suite)
 		return 1
 	}
@@ -210,13 +325,19 @@ Examples:
 			return 1
 		}
 
-		result, err := eval.RunSharedTests(*testOnly, suiteConfig, 8000)
+		result, err := eval.RunSharedTests(*testOnly, suiteConfig, *port)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Test execution failed: %v\n", err)
 			return 1
 		}
 
 		fmt.Printf("\nTests: %d/%d passed\n", result.Passed, result.Total)
+		if *report != "" {
+			if err := writeEvalReport(*reportOut, suite, result.Cases); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+				return 1
+			}
+		}
 		return 0
 	}
 
@@ -226,45 +347,188 @@ Examples:
 		return 1
 	}
 
+	if *models != "" {
+		modelList := eval.ParseModels(*models)
+		if len(modelList) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: --models needs at least one model")
+			return 1
+		}
+		if len(modelList) > 1 {
+			return evalRunMatrix(suite, *approach, modelList, timeoutSeconds, *report, *reportOut, *cleanup)
+		}
+		*model = modelList[0]
+	}
+
 	// Create config and run evaluation using Go implementation
 	config := eval.NewRunConfig(suite, *approach, *model)
+	config.TimeoutSeconds = timeoutSeconds
+	config.Port = *port
 
-	_, err := eval.Run(config)
+	result, err := eval.Run(config)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to run evaluation: %v\n", err)
 		return 1
 	}
+	if *cleanup {
+		cleanupEvalProject(result)
+	}
+	if *report != "" {
+		name := fmt.Sprintf("%s-%s-%s", suite, *approach, *model)
+		if err := writeEvalReport(*reportOut, name, result.SharedTestCases); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+			return 1
+		}
+	}
 
 	return 0
 }
 
+// evalRunMatrix runs the approach once per model and prints a combined
+// summary. A failing model does not stop the rest, but makes the exit code 1.
+func evalRunMatrix(suite, approach string, models []string, timeoutSeconds int, report, reportOut string, cleanup bool) int {
+	runs := eval.RunModels(suite, approach, models, timeoutSeconds)
+	eval.WriteMatrixSummary(os.Stdout, suite, approach, runs)
+	if cleanup {
+		for _, run := range runs {
+			if run.Err == nil && run.Result != nil {
+				cleanupEvalProject(run.Result)
+			}
+		}
+	}
+
+	code := 0
+	if eval.MatrixFailed(runs) {
+		code = 1
+	}
+	if report == "" {
+		return code
+	}
+	for _, run := range runs {
+		if run.Result == nil {
+			continue
+		}
+		name := fmt.Sprintf("%s-%s-%s", suite, approach, run.Model)
+		if err := writeEvalReport(modelReportPath(reportOut, run.Model), name, run.Result.SharedTestCases); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+			code = 1
+		}
+	}
+	return code
+}
+
+// evalSuiteExists reports whether evals/suites/<suite>/suite.yaml exists.
+func evalSuiteExists(suite string) bool {
+	_, err := os.Stat(filepath.Join("evals", "suites", suite, "suite.yaml"))
+	return !os.IsNotExist(err)
+}
+
+// evalRunSuites runs several suites concurrently and prints a combined
+// summary. Each suite runs as its own `ralph eval run` process on a distinct
+// port, with its output prefixed by the suite name. A failing suite does not
+// stop the rest, but makes the exit code 1.
+func evalRunSuites(suites []string, approach, model string, timeoutSeconds, maxParallel, basePort int, report, reportOut string, cleanup bool) int {
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to find the ralph executable: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Running %d suites, up to %d at a time\n", len(suites), maxParallel)
+	runs := eval.RunSuites(suites, maxParallel, basePort, os.Stdout, func(suite string, port int, out io.Writer) (*eval.EvalResult, error) {
+		args := []string{"eval", "run", suite,
+			"--approach", approach,
+			"--model", model,
+			"--timeout", (time.Duration(timeoutSeconds) * time.Second).String(),
+			"--port", strconv.Itoa(port),
+		}
+		if report != "" {
+			args = append(args, "--report", report, "--report-out", modelReportPath(reportOut, suite))
+		}
+		if cleanup {
+			args = append(args, "--cleanup")
+		}
+
+		started := time.Now()
+		cmd := exec.Command(exe, args...)
+		cmd.Stdout = out
+		cmd.Stderr = out
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("eval run exited with error: %w", err)
+		}
+		return eval.LatestResultSince(suite, approach, model, started)
+	})
+	eval.WriteSuitesSummary(os.Stdout, approach, model, runs)
+
+	if eval.SuitesFailed(runs) {
+		return 1
+	}
+	return 0
+}
+
+// cleanupEvalProject removes a finished run's project directory. The saved
+// result JSON is separate and stays.
+func cleanupEvalProject(result *eval.EvalResult) {
+	if err := eval.CleanupProjectDir(result.OutputDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to clean up project directory: %v\n", err)
+		return
+	}
+	fmt.Printf("Removed project directory %s\n", result.OutputDir)
+}
+
+// modelReportPath inserts model before the extension of path, so each model
+// in a matrix run gets its own report (results.xml -> results-opus.xml).
+func modelReportPath(path, model string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + model + ext
+}
+
+// writeEvalReport writes cases to path as a JUnit XML testsuite called name.
+func writeEvalReport(path, name string, cases []eval.CLITestResult) error {
+	var buf bytes.Buffer
+	if err := eval.WriteJUnitSuite(&buf, name, cases); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("JUnit report: %s\n", path)
+	return nil
+}
+
 func evalCompareCmd(args []string) int {
 	fs := flag.NewFlagSet("eval compare", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 	model := fs.String("model", "sonnet", "Claude model to compare (default \"sonnet\")")
+	history := fs.Bool("history", false, "Aggregate every saved run instead of only the latest")
+	markdown := fs.String("markdown", "", "Also write the comparison as Markdown to this file")
 
 	fs.Usage = func() {
 		fmt.Print(`eval compare 📊  Compare ralph vs oneshot results
 
 Usage:
-  ralph eval compare <suite> [--model <model>]
+  ralph eval compare <suite> [--model <model>] [--history | --markdown <file>]
 
 Flags:
   --model string       Claude model to compare (default "sonnet")
+  --history            Show average, best, and worst over every saved run
+  --markdown string    Also write the comparison as a Markdown table to this file
 
 Description:
   Compares the most recent ralph and oneshot evaluation results for the
-  specified suite, displaying metrics side-by-side.
+  specified suite, displaying metrics side-by-side. With --history, every
+  saved result for the suite and model is aggregated instead.
 
 Examples:
   ralph eval compare flask
   ralph eval compare workflow --model opus
+  ralph eval compare workflow --history
+  ralph eval compare workflow --markdown report.md
 `)
 	}
 
 	// Reorder args to put flags before positional arguments
 	// This allows: "compare workflow --model opus" to work like "compare --model opus workflow"
-	reorderedArgs := reorderArgsForFlags(args, []string{"model"})
+	reorderedArgs := reorderArgsForFlags(args, []string{"model", "markdown"})
 
 	if err := fs.Parse(reorderedArgs); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -297,17 +561,108 @@ Examples:
 		return 1
 	}
 
+	if *history && *markdown != "" {
+		fmt.Fprintln(os.Stderr, "Error: --markdown cannot be combined with --history")
+		return 1
+	}
+
+	if *history {
+		if err := eval.CompareHistory(suite, *model); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to compare evaluations: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
 	// Use Go implementation to compare results
 	if err := eval.Compare(suite, *model); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to compare evaluations: %v\n", err)
 		return 1
 	}
 
+	if *markdown != "" {
+		var buf bytes.Buffer
+		if err := eval.CompareMarkdown(&buf, suite, *model); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write Markdown report: %v\n", err)
+			return 1
+		}
+		if err := os.WriteFile(*markdown, buf.Bytes(), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write Markdown report: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Markdown report: %s\n", *markdown)
+	}
+
+	return 0
+}
+
+func evalReportCmd(args []string) int {
+	fs := flag.NewFlagSet("eval report", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	jsonOut := fs.Bool("json", false, "Print the leaderboard as JSON")
+
+	fs.Usage = func() {
+		fmt.Print(`eval report 🏆  Rank saved results by tests passed per dollar
+
+Usage:
+  ralph eval report [--json]
+
+Flags:
+  --json    Print the leaderboard as JSON
+
+Description:
+  Loads every result in evals/results/, groups them by suite, approach, and
+  model, and ranks each suite's groups by tests passed per dollar spent.
+  Tests and cost are summed over all runs of a group. Groups with no
+  recorded cost rank last.
+
+Examples:
+  ralph eval report
+  ralph eval report --json
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Println(err)
+		return 1
+	}
+
+	results, err := eval.LoadResultsDir(filepath.Join("evals", "results"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load results: %v\n", err)
+		return 1
+	}
+	entries := eval.BuildLeaderboard(results)
+
+	if *jsonOut {
+		if entries == nil {
+			entries = []eval.LeaderboardEntry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to serialize leaderboard: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+		return 0
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No results found. Run evaluations first with 'ralph eval run'.")
+		return 0
+	}
+	eval.WriteLeaderboard(os.Stdout, entries)
 	return 0
 }
 
 // reorderArgsForFlags reorders args so flags come before positional arguments
 // This allows "cmd arg --flag value" to work like "cmd --flag value arg"
+// flagNames lists the flags that take a value; any other "-x" argument is
+// moved as a boolean flag on its own.
 func reorderArgsForFlags(args []string, flagNames []string) []string {
 	var flags []string
 	var positional []string
@@ -333,6 +688,11 @@ func reorderArgsForFlags(args []string, flagNames []string) []string {
 				break
 			}
 		}
+		if !isFlag && len(arg) > 1 && arg[0] == '-' {
+			flags = append(flags, arg)
+			i++
+			isFlag = true
+		}
 		if !isFlag {
 			positional = append(positional, arg)
 			i++
@@ -341,3 +701,82 @@ func reorderArgsForFlags(args []string, flagNames []string) []string {
 
 	return append(flags, positional...)
 }
+
+func evalCleanCmd(args []string) int {
+	fs := flag.NewFlagSet("eval clean", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	olderThan := fs.String("older-than", "7d", "Remove project directories created longer ago than this")
+	dir := fs.String("dir", "", "Directory holding eval project directories (default: parent of the current directory)")
+	dryRun := fs.Bool("dry-run", false, "List the directories that would be removed")
+
+	fs.Usage = func() {
+		fmt.Print(`eval clean 🧹  Remove old eval project directories
+
+Usage:
+  ralph eval clean [--older-than <age>] [--dir <path>] [--dry-run]
+
+Flags:
+  --older-than string  Remove directories created longer ago than this, e.g. 90m, 24h, 7d (default "7d")
+  --dir string         Where eval-* project directories live (default: parent of the current directory)
+  --dry-run            List what would be removed without deleting anything
+
+Description:
+  Removes eval-<approach>-<suite>-<model>-<timestamp> directories left by
+  'ralph eval run', by the timestamp in their name (or their modification
+  time). Only directories named eval-* are touched; results in evals/results
+  are kept.
+
+Examples:
+  ralph eval clean
+  ralph eval clean --older-than 24h --dry-run
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Println(err)
+		fs.Usage()
+		return 1
+	}
+
+	age, err := tracker.ParseWindow(*olderThan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --older-than: %v\n", err)
+		return 1
+	}
+	root := *dir
+	if root == "" {
+		if root, err = eval.ProjectDirRoot(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	dirs, err := eval.FindOldProjectDirs(root, age, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to find eval project directories: %v\n", err)
+		return 1
+	}
+	if len(dirs) == 0 {
+		fmt.Printf("No eval project directories older than %s in %s\n", *olderThan, root)
+		return 0
+	}
+
+	code := 0
+	for _, d := range dirs {
+		if *dryRun {
+			fmt.Printf("Would remove %s\n", d)
+			continue
+		}
+		if err := eval.CleanupProjectDir(d); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+		fmt.Printf("Removed %s\n", d)
+	}
+	return code
+}
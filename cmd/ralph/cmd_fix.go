@@ -11,33 +11,49 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func fixCmd(args []string) {
 	fs := flag.NewFlagSet("fix", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 	fs.Usage = func() {
-		fmt.Print(`fix 🔧  Create tasks from a GitHub issue
+		fmt.Print(`fix 🔧  Create tasks from a GitHub or GitLab issue
 
 Usage:
-  ralph fix --issue <number>
-  ralph fix <github-issue-url>
+  ralph fix --issue <number>[,<number>...]
+  ralph fix <issue-url> [<issue-url>...]
+  <command> | ralph fix -
 
 Flags:
-  -issue    GitHub issue number (infers repo from git remote)
-  -repo     Override repository (owner/repo format)
-  -model    Claude model to use
+  -issue      Issue number(s), comma-separated (infers repo from git remote)
+  -repo       Override repository (owner/repo or group/project)
+  -provider   Issue provider: github or gitlab (default: from URL or git remote)
+  -model      Claude model to use
+  -timeout    Give up on a Claude call after this long (default 5m)
+  -dry-run    Print the tasks each issue would add without changing .ralph/
+  -force      Add tasks for an issue even if it was already fixed
 
 Examples:
   ralph fix --issue 42
+  ralph fix --issue 42 -dry-run
+  ralph fix --issue 42 -force
+  ralph fix --issue 12,15,22
   ralph fix https://github.com/owner/repo/issues/42
+  ralph fix https://gitlab.com/group/project/-/issues/42
   ralph fix --issue 42 --repo owner/repo
+  ralph fix --issue 42 --provider gitlab
+  gh issue list -l bug --json url -q '.[].url' | ralph fix -
 `)
 	}
 
-	issueNum := fs.Int("issue", 0, "GitHub issue number")
+	issueList := fs.String("issue", "", "Issue number(s), comma-separated")
 	repoOverride := fs.String("repo", "", "Repository (owner/repo)")
+	provider := fs.String("provider", "", "Issue provider (github or gitlab)")
 	model := fs.String("model", "", "Claude model to use")
+	timeout := fs.Duration("timeout", defaultClaudeTimeout, "Give up on a Claude call after this long")
+	dryRun := fs.Bool("dry-run", false, "Preview tasks without writing .ralph/prd.json")
+	force := fs.Bool("force", false, "Add tasks for an issue even if it was already fixed")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -49,25 +65,40 @@ Examples:
 		os.Exit(1)
 	}
 
-	// Handle positional arg (URL)
-	pos := fs.Args()
-	if *issueNum == 0 && len(pos) > 0 {
-		parsed := parseIssueURL(pos[0])
-		if parsed != nil {
-			*issueNum = parsed.Number
-			if *repoOverride == "" {
-				*repoOverride = parsed.Repo
-			}
-		}
+	// Collect issues from --issue and positional args (URLs or numbers)
+	positional, err := stdinIssueArgs(fs.Args(), os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-
-	if *issueNum == 0 {
+	targets, err := parseIssueTargets(*issueList, positional)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(targets) == 0 {
 		fmt.Fprintln(os.Stderr, "Issue number is required:")
 		fmt.Fprintln(os.Stderr, "  ralph fix --issue 42")
+		fmt.Fprintln(os.Stderr, "  ralph fix --issue 12,15,22")
 		fmt.Fprintln(os.Stderr, "  ralph fix https://github.com/owner/repo/issues/42")
 		os.Exit(1)
 	}
 
+	defaultProvider := *provider
+	if defaultProvider == "" {
+		if remote, err := getOriginRemote(); err == nil {
+			defaultProvider = providerFromRemote(remote)
+		}
+	}
+	for i := range targets {
+		if *provider != "" || targets[i].Provider == "" {
+			targets[i].Provider = defaultProvider
+		}
+		if *repoOverride != "" {
+			targets[i].Repo = *repoOverride
+		}
+	}
+
 	// Preflight checks
 	fmt.Println("Preflight checks...")
 
@@ -77,42 +108,45 @@ Examples:
 	}
 	fmt.Println("  ✓ Claude CLI available")
 
-	if err := checkGitHubAuth(); err != nil {
-		fmt.Fprintf(os.Stderr, "❌ GitHub: %v\n", err)
-		os.Exit(1)
-	}
-	fmt.Println("  ✓ GitHub CLI authenticated")
-
-	// Determine repo
-	repo := *repoOverride
-	if repo == "" {
-		var err error
-		repo, err = getGitHubRepo()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Could not detect GitHub repo: %v\n", err)
-			fmt.Fprintln(os.Stderr, "Use --repo owner/repo to specify manually")
-			os.Exit(1)
+	// Check auth and detect the repo once per provider
+	sources := map[string]issueSource{}
+	detectedRepos := map[string]string{}
+	for i, t := range targets {
+		src, ok := sources[t.Provider]
+		if !ok {
+			src, err = newIssueSource(t.Provider)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			authDesc, err := src.CheckAuth()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s: %v\n", src.Name(), err)
+				os.Exit(1)
+			}
+			fmt.Printf("  ✓ %s\n", authDesc)
+			sources[t.Provider] = src
 		}
-	}
-	fmt.Printf("  ✓ Repository: %s\n", repo)
-
-	// Fetch issue
-	fmt.Printf("\nFetching issue #%d from %s...\n", *issueNum, repo)
-	issue, err := fetchGitHubIssue(repo, *issueNum)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
-		os.Exit(1)
-	}
-	fmt.Printf("  ✓ %s\n", issue.Title)
-
-	if issue.State == "closed" {
-		fmt.Printf("  ⚠️  Issue is closed (state: %s)\n", issue.State)
+		if t.Repo != "" {
+			continue
+		}
+		repo, ok := detectedRepos[t.Provider]
+		if !ok {
+			repo, err = src.DetectRepo()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Could not detect %s repo: %v\n", src.Name(), err)
+				fmt.Fprintln(os.Stderr, "Use --repo owner/repo to specify manually")
+				os.Exit(1)
+			}
+			detectedRepos[t.Provider] = repo
+			fmt.Printf("  ✓ Repository: %s\n", repo)
+		}
+		targets[i].Repo = repo
 	}
 
 	// Check we're in a Ralph project
 	prdPath := filepath.Join(".ralph", "prd.json")
-	prdBytes, err := os.ReadFile(prdPath)
-	if err != nil {
+	if _, err := os.ReadFile(prdPath); err != nil {
 		fmt.Fprintf(os.Stderr, "Could not read .ralph/prd.json - are you in a Ralph project? Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -122,8 +156,12 @@ Examples:
 		os.Exit(1)
 	}
 
-	// Format issue as work description
-	workDesc := formatIssueAsWork(issue)
+	fixedPath := filepath.Join(".ralph", "fixed_issues.json")
+	fixed, err := loadFixedIssues(fixedPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
 
 	chosenModel := strings.TrimSpace(*model)
 	if chosenModel == "" {
@@ -131,35 +169,196 @@ Examples:
 	}
 
 	// Archive completed tasks and compact learnings before adding new work
-	archiveCompletedTasks()
-	compactLearnings(chosenModel)
+	if !*dryRun {
+		archiveCompletedTasks()
+		compactLearnings(chosenModel, *timeout)
+	}
+
+	var results []fixResult
+	exitCode := 0
+	for _, t := range targets {
+		res := fixResult{Target: t}
+
+		// Fetch issue
+		fmt.Printf("\nFetching issue #%d from %s...\n", t.Number, t.Repo)
+		issue, err := sources[t.Provider].FetchIssue(t.Repo, t.Number)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			res.Err = err
+			results = append(results, res)
+			continue
+		}
+		res.Issue = issue
+		fmt.Printf("  ✓ %s\n", issue.Title)
+
+		if issue.State == "closed" {
+			fmt.Printf("  ⚠️  Issue is closed (state: %s)\n", issue.State)
+		}
+
+		var current prdFile
+		if data, err := os.ReadFile(prdPath); err == nil {
+			_ = json.Unmarshal(data, &current)
+		}
+		if prior, err := checkRepeatFix(fixed, t, issue, current.Tasks, *force); prior != "" {
+			fmt.Printf("  ⚠️  %s\n", prior)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Issue #%d: skipped. Rerun with -force to add tasks for it again.\n", t.Number)
+				res.Err = err
+				results = append(results, res)
+				continue
+			}
+		}
+
+		added, err := addIssueTasks(prdPath, string(reqBytes), issue, chosenModel, *timeout, *dryRun)
+		if err != nil {
+			if isClaudeRateLimitError(err) {
+				fmt.Fprintln(os.Stderr, "Claude is unavailable (usage limit / rate limit).")
+				if details := claudeActionableDetails(err); details != "" {
+					fmt.Fprintf(os.Stderr, "\nDetails:\n%s\n", details)
+				}
+				res.Err = errors.New("Claude usage limit reached")
+				results = append(results, res)
+				exitCode = 2
+				break
+			}
+			fmt.Fprintf(os.Stderr, "❌ Issue #%d: %v\n", t.Number, err)
+			if details := claudeActionableDetails(err); details != "" && details != err.Error() {
+				fmt.Fprintf(os.Stderr, "\nDetails:\n%s\n", details)
+			}
+			res.Err = err
+			results = append(results, res)
+			continue
+		}
+		res.Added = added
+		results = append(results, res)
+		printAddedTasks(added, issue, *dryRun)
+
+		if !*dryRun {
+			fixed.record(t, issue, added, time.Now())
+			if err := fixed.save(fixedPath); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+		}
+	}
+
+	if len(results) > 1 {
+		printFixSummary(os.Stdout, results)
+	}
+	if exitCode == 0 && !anyFixSucceeded(results) {
+		exitCode = 1
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+	if *dryRun {
+		fmt.Println("\nDry run: .ralph/prd.json was not changed.")
+		return
+	}
+	fmt.Println("\nNext step:")
+	fmt.Println("  ralph run")
+}
+
+// issueTarget is one issue requested on the `ralph fix` command line.
+type issueTarget struct {
+	Provider string
+	Repo     string
+	Number   int
+}
+
+// fixResult records what `ralph fix` did for one issue.
+type fixResult struct {
+	Target issueTarget
+	Issue  *Issue
+	Added  []prdTask
+	Err    error
+}
+
+// parseIssueTargets combines --issue (comma- or space-separated numbers) and
+// positional args (issue URLs or bare numbers), dropping duplicates.
+func parseIssueTargets(issueFlag string, positional []string) ([]issueTarget, error) {
+	var out []issueTarget
+	seen := map[issueTarget]bool{}
+	add := func(t issueTarget) {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+
+	for _, f := range strings.FieldsFunc(issueFlag, func(r rune) bool { return r == ',' || r == ' ' }) {
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(f), "#"))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid issue number %q", f)
+		}
+		add(issueTarget{Number: n})
+	}
+	for _, arg := range positional {
+		if parsed := parseIssueURL(arg); parsed != nil {
+			add(issueTarget{Provider: parsed.Provider, Repo: parsed.Repo, Number: parsed.Number})
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(arg), "#"))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("not an issue URL or number: %s", arg)
+		}
+		add(issueTarget{Number: n})
+	}
+	return out, nil
+}
+
+// stdinIssueArgs replaces a "-" argument with the whitespace-separated issue
+// URLs or numbers read from stdin.
+func stdinIssueArgs(args []string, stdin io.Reader) ([]string, error) {
+	out := make([]string, 0, len(args))
+	readStdin := false
+	for _, arg := range args {
+		if arg != "-" {
+			out = append(out, arg)
+			continue
+		}
+		if readStdin {
+			continue
+		}
+		readStdin = true
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read issues from stdin: %w", err)
+		}
+		out = append(out, strings.Fields(string(data))...)
+	}
+	return out, nil
+}
+
+// addIssueTasks asks Claude to turn one issue into tasks and merges them into
+// prd.json, tagging each with the issue reference. It re-reads prd.json so
+// successive issues build on each other. With dryRun it returns the tasks
+// without writing prd.json.
+func addIssueTasks(prdPath, requirements string, issue *Issue, chosenModel string, timeout time.Duration, dryRun bool) ([]prdTask, error) {
+	prdBytes, err := os.ReadFile(prdPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read .ralph/prd.json: %w", err)
+	}
+
+	// Format issue as work description
+	workDesc := formatIssueAsWork(issue)
 
 	projectName := filepath.Base(mustGetwd())
-	prompt, err := renderNewWorkPrompt(projectName, string(reqBytes), string(prdBytes), workDesc)
+	prompt, err := renderNewWorkPrompt(projectName, requirements, string(prdBytes), workDesc)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to build Claude prompt: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to build Claude prompt: %w", err)
 	}
 
 	fmt.Printf("\nCalling Claude to create tasks (model: %s)...\n", chosenModel)
-	result, err := runClaudeOnceWithModel(prompt, chosenModel)
+	result, err := runClaudeOnceWithModel(prompt, chosenModel, timeout)
 	if err != nil {
 		if isClaudeRateLimitError(err) {
-			fmt.Fprintln(os.Stderr, "Claude is unavailable (usage limit / rate limit).")
-			details := claudeActionableDetails(err)
-			if details != "" {
-				fmt.Fprintf(os.Stderr, "\nDetails:\n%s\n", details)
-			}
-			os.Exit(2)
+			return nil, err
 		}
-		fmt.Fprintln(os.Stderr, "Claude analysis failed.")
-		details := claudeActionableDetails(err)
-		if details != "" {
-			fmt.Fprintf(os.Stderr, "\nDetails:\n%s\n", details)
-		}
-		os.Exit(1)
+		return nil, fmt.Errorf("Claude analysis failed: %w", err)
 	}
 
+	issueRef := &taskIssue{Number: issue.Number, URL: issue.URL}
+
 	// Try parsing as full PRD first (same logic as cmd_add.go)
 	updatedPRD := parseGeneratedPRD(result)
 	if updatedPRD != "" {
@@ -176,7 +375,6 @@ Examples:
 		_ = json.Unmarshal([]byte(updatedPRD), &after)
 
 		// Inject issue reference into new tasks
-		issueRef := &taskIssue{Number: issue.Number, URL: issue.URL}
 		added := make([]prdTask, 0)
 		for i, t := range after.Tasks {
 			id := strings.TrimSpace(t.ID)
@@ -188,96 +386,163 @@ Examples:
 				added = append(added, after.Tasks[i])
 			}
 		}
+		if dryRun {
+			return added, nil
+		}
 
 		// Re-serialize with issue fields added
 		out, err := json.MarshalIndent(after, "", "  ")
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to serialize .ralph/prd.json: %v\n", err)
-			os.Exit(1)
+			return nil, fmt.Errorf("failed to serialize .ralph/prd.json: %w", err)
 		}
 		if err := os.WriteFile(prdPath, out, 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to update .ralph/prd.json: %v\n", err)
-			os.Exit(1)
+			return nil, fmt.Errorf("failed to update .ralph/prd.json: %w", err)
 		}
-		printAddedTasks(added, issue)
-		return
+		return added, nil
 	}
 
 	// Parse as new tasks
 	newTasksJSON := parseNewTasks(result)
 	if newTasksJSON == "" {
-		fmt.Fprintln(os.Stderr, "Failed to parse new tasks from Claude's response.")
-		os.Exit(1)
+		return nil, errors.New("failed to parse new tasks from Claude's response")
 	}
 
 	var newTasks []prdTask
 	if err := json.Unmarshal([]byte(newTasksJSON), &newTasks); err != nil {
-		fmt.Fprintf(os.Stderr, "New tasks are not valid JSON: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("new tasks are not valid JSON: %w", err)
 	}
 	if len(newTasks) == 0 {
-		fmt.Fprintln(os.Stderr, "No new tasks returned.")
-		os.Exit(1)
-	}
-
-	// Inject issue reference into each task (don't rely on Claude to do it)
-	issueRef := &taskIssue{Number: issue.Number, URL: issue.URL}
-	for i := range newTasks {
-		newTasks[i].Issue = issueRef
+		return nil, errors.New("no new tasks returned")
 	}
 
 	var existing prdFile
 	if err := json.Unmarshal(prdBytes, &existing); err != nil {
-		fmt.Fprintf(os.Stderr, "Existing .ralph/prd.json is not valid JSON: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("existing .ralph/prd.json is not valid JSON: %w", err)
 	}
 	if existing.Version == 0 {
 		existing.Version = 1
 	}
 
+	// Inject issue reference into each task (don't rely on Claude to do it)
+	newTasks = dedupTaskIDs(newTasks, existing.Tasks)
+	for i := range newTasks {
+		newTasks[i].Issue = issueRef
+	}
+	if dryRun {
+		return newTasks, nil
+	}
+
 	existing.Tasks = append(newTasks, existing.Tasks...)
 
 	out, err := json.MarshalIndent(existing, "", "  ")
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to serialize updated .ralph/prd.json: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to serialize updated .ralph/prd.json: %w", err)
 	}
 	if err := os.WriteFile(prdPath, out, 0644); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to update .ralph/prd.json: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to update .ralph/prd.json: %w", err)
+	}
+	return newTasks, nil
+}
+
+var taskIDNumRe = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// dedupTaskIDs gives new tasks whose ID is empty or already taken (by an
+// existing task or an earlier new one) the next free ID, e.g. T007.
+func dedupTaskIDs(newTasks, existing []prdTask) []prdTask {
+	taken := map[string]bool{}
+	maxNum, width, prefix := 0, 3, "T"
+	note := func(id string) {
+		taken[id] = true
+		if m := taskIDNumRe.FindStringSubmatch(id); m != nil {
+			if n, err := strconv.Atoi(m[2]); err == nil && n > maxNum {
+				maxNum, prefix, width = n, m[1], len(m[2])
+			}
+		}
+	}
+	for _, t := range existing {
+		if id := strings.TrimSpace(t.ID); id != "" {
+			note(id)
+		}
 	}
 
-	printAddedTasks(newTasks, issue)
+	out := make([]prdTask, len(newTasks))
+	for i, t := range newTasks {
+		id := strings.TrimSpace(t.ID)
+		if id == "" || taken[id] {
+			maxNum++
+			id = fmt.Sprintf("%s%0*d", prefix, width, maxNum)
+		}
+		t.ID = id
+		note(id)
+		out[i] = t
+	}
+	return out
 }
 
-func printAddedTasks(tasks []prdTask, issue *GitHubIssue) {
-	fmt.Printf("\nTasks created for issue #%d:\n", issue.Number)
-	if len(tasks) == 0 {
-		fmt.Println("  (unable to determine added tasks; .ralph/prd.json was updated)")
+func printAddedTasks(tasks []prdTask, issue *Issue, dryRun bool) {
+	if dryRun {
+		fmt.Printf("\nTasks that would be added for issue #%d:\n", issue.Number)
 	} else {
-		for _, t := range tasks {
-			id := strings.TrimSpace(t.ID)
-			title := strings.TrimSpace(t.Title)
-			prio := strings.TrimSpace(t.Priority)
-			if prio == "" {
-				prio = "(no priority)"
-			}
-			fmt.Printf("  - [%s] %s (%s)\n", id, title, prio)
+		fmt.Printf("\nTasks created for issue #%d:\n", issue.Number)
+	}
+	switch {
+	case len(tasks) > 0:
+		printTaskLines(os.Stdout, tasks)
+	case dryRun:
+		fmt.Println("  (none)")
+	default:
+		fmt.Println("  (unable to determine added tasks; .ralph/prd.json was updated)")
+	}
+}
+
+func printFixSummary(w io.Writer, results []fixResult) {
+	fmt.Fprintln(w, "\nSummary:")
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(w, "  ✗ #%d: %v\n", r.Target.Number, r.Err)
+			continue
 		}
+		ids := make([]string, 0, len(r.Added))
+		for _, t := range r.Added {
+			ids = append(ids, strings.TrimSpace(t.ID))
+		}
+		line := fmt.Sprintf("  ✓ #%d %s: %d task(s)", r.Target.Number, strings.TrimSpace(r.Issue.Title), len(r.Added))
+		if len(ids) > 0 {
+			line += " (" + strings.Join(ids, ", ") + ")"
+		}
+		fmt.Fprintln(w, line)
 	}
-	fmt.Println("\nNext step:")
-	fmt.Println("  ralph run")
+}
+
+func anyFixSucceeded(results []fixResult) bool {
+	for _, r := range results {
+		if r.Err == nil {
+			return true
+		}
+	}
+	return false
 }
 
 type parsedIssueURL struct {
-	Repo   string
-	Number int
+	Provider string
+	Repo     string
+	Number   int
 }
 
-func parseIssueURL(url string) *parsedIssueURL {
+var (
 	// Match: https://github.com/owner/repo/issues/123
-	re := regexp.MustCompile(`github\.com/([^/]+/[^/]+)/issues/(\d+)`)
-	m := re.FindStringSubmatch(url)
+	githubIssueURLRe = regexp.MustCompile(`github\.com/([^/]+/[^/]+)/issues/(\d+)`)
+	// Match: https://gitlab.com/group/sub/project/-/issues/123 (the "/-" is optional)
+	gitlabIssueURLRe = regexp.MustCompile(`gitlab\.com/([^/]+(?:/[^/]+)+?)(?:/-)?/issues/(\d+)`)
+)
+
+func parseIssueURL(url string) *parsedIssueURL {
+	provider := providerGitHub
+	m := githubIssueURLRe.FindStringSubmatch(url)
+	if m == nil {
+		provider = providerGitLab
+		m = gitlabIssueURLRe.FindStringSubmatch(url)
+	}
 	if m == nil {
 		return nil
 	}
@@ -286,7 +551,8 @@ func parseIssueURL(url string) *parsedIssueURL {
 		return nil
 	}
 	return &parsedIssueURL{
-		Repo:   m[1],
-		Number: num,
+		Provider: provider,
+		Repo:     m[1],
+		Number:   num,
 	}
 }
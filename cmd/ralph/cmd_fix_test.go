@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIssueTargets(t *testing.T) {
+	tests := []struct {
+		name       string
+		issueFlag  string
+		positional []string
+		want       []issueTarget
+		wantErr    bool
+	}{
+		{
+			name:      "single number",
+			issueFlag: "42",
+			want:      []issueTarget{{Number: 42}},
+		},
+		{
+			name:      "comma-separated numbers",
+			issueFlag: "12,15, 22",
+			want:      []issueTarget{{Number: 12}, {Number: 15}, {Number: 22}},
+		},
+		{
+			name:      "duplicates dropped",
+			issueFlag: "12,#12,15",
+			want:      []issueTarget{{Number: 12}, {Number: 15}},
+		},
+		{
+			name: "multiple URLs",
+			positional: []string{
+				"https://github.com/owner/repo/issues/3",
+				"https://gitlab.com/group/project/-/issues/4",
+			},
+			want: []issueTarget{
+				{Provider: providerGitHub, Repo: "owner/repo", Number: 3},
+				{Provider: providerGitLab, Repo: "group/project", Number: 4},
+			},
+		},
+		{
+			name:       "flag and positional numbers",
+			issueFlag:  "1",
+			positional: []string{"2"},
+			want:       []issueTarget{{Number: 1}, {Number: 2}},
+		},
+		{
+			name:      "invalid number",
+			issueFlag: "12,abc",
+			wantErr:   true,
+		},
+		{
+			name:       "invalid positional",
+			positional: []string{"not-an-issue"},
+			wantErr:    true,
+		},
+		{
+			name: "nothing given",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseIssueTargets(tt.issueFlag, tt.positional)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseIssueTargets() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseIssueTargets() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseIssueTargets() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupTaskIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		newIDs   []string
+		existing []string
+		want     []string
+	}{
+		{
+			name:     "no collisions",
+			newIDs:   []string{"T004", "T005"},
+			existing: []string{"T001", "T002"},
+			want:     []string{"T004", "T005"},
+		},
+		{
+			name:     "collisions renumbered after highest",
+			newIDs:   []string{"T001", "T002"},
+			existing: []string{"T001", "T002", "T003"},
+			want:     []string{"T004", "T005"},
+		},
+		{
+			name:     "collision within new tasks",
+			newIDs:   []string{"T004", "T004"},
+			existing: []string{"T001"},
+			want:     []string{"T004", "T005"},
+		},
+		{
+			name:     "empty IDs assigned",
+			newIDs:   []string{"", " "},
+			existing: []string{"T009"},
+			want:     []string{"T010", "T011"},
+		},
+		{
+			name:   "empty PRD",
+			newIDs: []string{"", "T001"},
+			want:   []string{"T001", "T002"},
+		},
+	}
+
+	toTasks := func(ids []string) []prdTask {
+		tasks := make([]prdTask, len(ids))
+		for i, id := range ids {
+			tasks[i] = prdTask{ID: id}
+		}
+		return tasks
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dedupTaskIDs(toTasks(tt.newIDs), toTasks(tt.existing))
+			var gotIDs []string
+			for _, task := range got {
+				gotIDs = append(gotIDs, task.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.want) {
+				t.Errorf("dedupTaskIDs() = %v, want %v", gotIDs, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintFixSummary(t *testing.T) {
+	results := []fixResult{
+		{
+			Target: issueTarget{Number: 12},
+			Issue:  &Issue{Number: 12, Title: "Login broken"},
+			Added:  []prdTask{{ID: "T010"}, {ID: "T011"}},
+		},
+		{
+			Target: issueTarget{Number: 15},
+			Err:    errors.New("issue not found"),
+		},
+	}
+
+	var buf bytes.Buffer
+	printFixSummary(&buf, results)
+	out := buf.String()
+
+	for _, want := range []string{
+		"#12 Login broken: 2 task(s) (T010, T011)",
+		"#15: issue not found",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+	if !anyFixSucceeded(results) {
+		t.Errorf("anyFixSucceeded() = false, want true")
+	}
+	if anyFixSucceeded(results[1:]) {
+		t.Errorf("anyFixSucceeded() = true for only failures")
+	}
+}
+
+func TestStdinIssueArgs(t *testing.T) {
+	stdin := strings.NewReader("https://github.com/o/r/issues/3\n4\n #5\n")
+	got, err := stdinIssueArgs([]string{"12", "-", "-"}, stdin)
+	if err != nil {
+		t.Fatalf("stdinIssueArgs() error: %v", err)
+	}
+	want := []string{"12", "https://github.com/o/r/issues/3", "4", "#5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stdinIssueArgs() = %v, want %v", got, want)
+	}
+
+	targets, err := parseIssueTargets("", got)
+	if err != nil || len(targets) != 4 {
+		t.Errorf("parseIssueTargets(stdin args) = %v, %v; want 4 targets", targets, err)
+	}
+}
+
+func TestAddIssueTasksDryRunLeavesPRDUntouched(t *testing.T) {
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	fakeClaude := "#!/bin/sh\nprintf '%s\\n' '---NEW_TASKS---' '[{\"id\":\"T001\",\"title\":\"Fix crash\",\"priority\":\"high\"}]'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte(fakeClaude), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	prdPath := filepath.Join(dir, "prd.json")
+	original := `{"version":1,"tasks":[{"id":"T001","title":"Existing","status":"done"}]}`
+	if err := os.WriteFile(prdPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := &Issue{Number: 7, Title: "Crash on start", URL: "https://github.com/o/r/issues/7"}
+	added, err := addIssueTasks(prdPath, "# Requirements", issue, "default", defaultClaudeTimeout, true)
+	if err != nil {
+		t.Fatalf("addIssueTasks(dryRun) error: %v", err)
+	}
+	if len(added) != 1 || added[0].ID != "T002" || added[0].Issue == nil || added[0].Issue.Number != 7 {
+		t.Errorf("added = %+v, want one deduped task tagged with issue #7", added)
+	}
+	if data, _ := os.ReadFile(prdPath); string(data) != original {
+		t.Errorf("dry run modified prd.json:\n%s", data)
+	}
+}
@@ -6,10 +6,14 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/loop/steps"
 )
 
 // Static prd.json for explore mode - always a single completed task
@@ -36,19 +40,26 @@ func newProjectCmd(args []string) {
 Usage:
   ralph init                       (existing repo - Ralph explores and summarizes)
   ralph init <requirements.md>     (new project - generate tasks from requirements)
+  ralph init -                     (new project - read requirements from stdin)
 
 Flags:
-  -requirements   Path to requirements.md file
-  -model          Claude model to use
+  -requirements   Path to requirements.md file ("-" for stdin)
+  -model          Model to use
+  -agent          Agent CLI to set up: claude or aider (default "claude")
+  -timeout        Give up on an agent call after this long (default 5m)
 
 Examples:
   ralph init                              # existing repo
   ralph init requirements.md              # new project
   ralph init -requirements requirements.md -model sonnet
+  cat requirements.md | ralph init -
+  ralph init requirements.md -agent aider # use aider instead of Claude
 `)
 	}
 	reqFile := fs.String("requirements", "", "Path to requirements.md file")
-	model := fs.String("model", "", "Claude model to use")
+	model := fs.String("model", "", "Model to use")
+	agentName := fs.String("agent", steps.AgentTypeClaude, "Agent CLI to set up: claude or aider")
+	timeout := fs.Duration("timeout", defaultClaudeTimeout, "Give up on an agent call after this long")
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			fs.Usage()
@@ -59,6 +70,12 @@ Examples:
 		os.Exit(1)
 	}
 
+	agent, err := lookupInitAgent(*agentName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	pos := fs.Args()
 	if *reqFile == "" && len(pos) >= 1 {
 		*reqFile = pos[0]
@@ -81,11 +98,14 @@ Examples:
 
 	// No requirements file provided - check if existing repo
 	if *reqFile == "" {
-		if hasExistingCode() {
-			initExistingRepo(projectName, *model)
+		hasCode, why := detectExistingCode(".")
+		if hasCode {
+			fmt.Printf("Existing project detected (%s).\n", why)
+			initExistingRepo(projectName, *model, agent, *timeout)
 			return
 		}
-		fmt.Fprintln(os.Stderr, "This folder is empty, but it could be many things.")
+		fmt.Fprintf(os.Stderr, "No existing code found (%s).\n", why)
+		fmt.Fprintln(os.Stderr, "This folder has no code yet, but it could be many things.")
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Create a requirements.md describing what you want to build,")
 		fmt.Fprintln(os.Stderr, "then run:")
@@ -93,47 +113,38 @@ Examples:
 		os.Exit(1)
 	}
 
-	reqContent, err := os.ReadFile(*reqFile)
+	reqContent, err := readRequirements(*reqFile, os.Stdin)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to read requirements file: %v\n", err)
-		os.Exit(1)
-	}
-
-	if strings.TrimSpace(string(reqContent)) == "" {
-		fmt.Fprintln(os.Stderr, "Requirements file is empty.")
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	if err := validateClaudePreflight(); err != nil {
+	if err := agent.preflight(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
 	configModel := strings.TrimSpace(*model)
-	analysisModel := configModel
-	if analysisModel == "" {
-		analysisModel = "default"
-	}
 
-	fmt.Printf("Analyzing requirements with Claude...\n")
+	fmt.Printf("Analyzing requirements with %s...\n", agent.Name)
 
 	prompt, err := renderNewProjectPrompt(projectName, string(reqContent))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to build Claude prompt: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to build %s prompt: %v\n", agent.Name, err)
 		os.Exit(1)
 	}
 
-	result, err := runClaudeOnceWithModel(prompt, analysisModel)
+	result, err := agent.runOnce(prompt, configModel, *timeout)
 	if err != nil {
 		if isClaudeRateLimitError(err) {
-			fmt.Fprintln(os.Stderr, "Claude is unavailable (usage limit / rate limit).")
+			fmt.Fprintf(os.Stderr, "%s is unavailable (usage limit / rate limit).\n", agent.Name)
 			details := claudeActionableDetails(err)
 			if details != "" {
 				fmt.Fprintf(os.Stderr, "\nDetails:\n%s\n", details)
 			}
 			os.Exit(2)
 		}
-		fmt.Fprintln(os.Stderr, "Claude analysis failed.")
+		fmt.Fprintf(os.Stderr, "%s analysis failed.\n", agent.Name)
 		details := claudeActionableDetails(err)
 		if details != "" {
 			fmt.Fprintf(os.Stderr, "\nDetails:\n%s\n", details)
@@ -149,7 +160,7 @@ Examples:
 
 	prdContent := parseGeneratedPRD(result)
 	if prdContent == "" {
-		fmt.Fprintln(os.Stderr, "Failed to parse Claude's response.")
+		fmt.Fprintf(os.Stderr, "Failed to parse %s's response.\n", agent.Name)
 		os.Exit(1)
 	}
 
@@ -194,6 +205,8 @@ Examples:
 		PrdFile:           ".ralph/prd.json",
 		CommitMessageFile: "../.ralph/commit_message.txt",
 		Model:             configModel,
+		AgentType:         agent.Type,
+		AgentBinary:       agent.Binary,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to render default config: %v\n", err)
@@ -241,6 +254,30 @@ Examples:
 	fmt.Println("  ralph run")
 }
 
+// readRequirements reads the requirements file at path, or all of stdin when
+// path is "-". Empty requirements are an error.
+func readRequirements(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read requirements from stdin: %w", err)
+		}
+		if strings.TrimSpace(string(data)) == "" {
+			return nil, errors.New("No requirements on stdin (pipe them in, e.g. cat requirements.md | ralph init -).")
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read requirements file: %w", err)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, errors.New("Requirements file is empty.")
+	}
+	return data, nil
+}
+
 func parseGeneratedPRD(response string) string {
 	marker := "---FILE: prd.json---"
 	idx := strings.Index(response, marker)
@@ -333,40 +370,98 @@ func appendToGitignore(entry string) {
 	f.WriteString(entry + "\n")
 }
 
-// hasExistingCode checks if the current directory has code files
-func hasExistingCode() bool {
-	codeExtensions := map[string]bool{
-		".go": true, ".py": true, ".js": true, ".ts": true, ".tsx": true, ".jsx": true,
-		".rb": true, ".rs": true, ".java": true, ".c": true, ".cpp": true, ".h": true,
-		".cs": true, ".php": true, ".swift": true, ".kt": true, ".scala": true,
-		".html": true, ".css": true, ".scss": true, ".vue": true, ".svelte": true,
-	}
+// codeExtensions are file extensions that count as source code when
+// deciding whether `ralph init` is looking at an existing project.
+var codeExtensions = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".mjs": true, ".cjs": true, ".ts": true, ".tsx": true, ".jsx": true,
+	".rb": true, ".rs": true, ".java": true, ".c": true, ".cc": true, ".cpp": true, ".cxx": true, ".h": true, ".hpp": true,
+	".cs": true, ".fs": true, ".php": true, ".swift": true, ".m": true, ".mm": true, ".kt": true, ".kts": true, ".scala": true,
+	".groovy": true, ".dart": true, ".ex": true, ".exs": true, ".erl": true, ".hs": true, ".ml": true, ".clj": true,
+	".lua": true, ".pl": true, ".r": true, ".jl": true, ".zig": true, ".nim": true, ".sol": true, ".elm": true,
+	".sh": true, ".sql": true, ".proto": true, ".tf": true,
+	".html": true, ".css": true, ".scss": true, ".vue": true, ".svelte": true,
+}
+
+// projectManifests are build files that mark a project even before it has
+// any source files.
+var projectManifests = map[string]bool{
+	"go.mod": true, "package.json": true, "Cargo.toml": true, "pyproject.toml": true, "requirements.txt": true,
+	"Gemfile": true, "pom.xml": true, "build.gradle": true, "build.gradle.kts": true, "composer.json": true,
+	"CMakeLists.txt": true, "Package.swift": true, "mix.exs": true,
+}
 
-	entries, err := os.ReadDir(".")
+// skipCodeDirs are dependency and build output directories that say nothing
+// about whether the project itself has code. Hidden directories are skipped
+// too.
+var skipCodeDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "__pycache__": true, "venv": true,
+	"dist": true, "build": true, "target": true,
+}
+
+// maxCodeScanEntries bounds how much of a large tree detectExistingCode walks.
+const maxCodeScanEntries = 10000
+
+// detectExistingCode reports whether root holds source code, and why, so
+// `ralph init` can explain picking explore mode over a new project. It walks
+// the tree looking for a code file or project manifest, skipping hidden and
+// dependency directories and anything matched by root/.ralphignore.
+func detectExistingCode(root string) (bool, string) {
+	ignore, err := loadRalphIgnore(filepath.Join(root, ".ralphignore"))
 	if err != nil {
-		return false
+		return false, fmt.Sprintf("can't read .ralphignore: %v", err)
 	}
 
-	for _, e := range entries {
-		if e.IsDir() {
-			// Skip hidden dirs and common non-code dirs
-			name := e.Name()
-			if strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || name == "__pycache__" {
-				continue
+	found, reason, scanned := "", "", 0
+	walkErr := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		name := d.Name()
+		if d.IsDir() {
+			if strings.HasPrefix(name, ".") || skipCodeDirs[name] || ignore.Match(rel, true) {
+				return filepath.SkipDir
 			}
-			// Has a subdirectory - likely a project
-			return true
+			return nil
 		}
-		ext := strings.ToLower(filepath.Ext(e.Name()))
-		if codeExtensions[ext] {
-			return true
+		if scanned++; scanned > maxCodeScanEntries {
+			return filepath.SkipAll
 		}
+		if strings.HasPrefix(name, ".") || ignore.Match(rel, false) {
+			return nil
+		}
+		switch {
+		case projectManifests[name]:
+			found, reason = rel, "project file"
+		case codeExtensions[strings.ToLower(filepath.Ext(name))]:
+			found, reason = rel, "code file"
+		default:
+			return nil
+		}
+		return filepath.SkipAll
+	})
+	if walkErr != nil {
+		return false, fmt.Sprintf("can't read the directory: %v", walkErr)
+	}
+
+	if found != "" {
+		return true, fmt.Sprintf("found %s %s", reason, found)
 	}
-	return false
+	if scanned > maxCodeScanEntries {
+		return false, fmt.Sprintf("no code files in the first %d files", maxCodeScanEntries)
+	}
+	if len(ignore.patterns) > 0 {
+		return false, "no code files outside .ralphignore"
+	}
+	return false, "no code files"
 }
 
 // initExistingRepo handles ralph init in an existing codebase
-func initExistingRepo(projectName, model string) {
+func initExistingRepo(projectName, model string, agent initAgent, timeout time.Duration) {
 	// Check if .ralph already exists
 	if _, err := os.Stat(".ralph"); err == nil {
 		fmt.Fprintln(os.Stderr, "Ralph is already initialized here (.ralph/ exists).")
@@ -377,17 +472,12 @@ func initExistingRepo(projectName, model string) {
 
 	fmt.Println("Hi code! I live here now.")
 
-	if err := validateClaudePreflight(); err != nil {
+	if err := agent.preflight(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	analysisModel := strings.TrimSpace(model)
-	if analysisModel == "" {
-		analysisModel = "default"
-	}
-
-	fmt.Printf("Exploring codebase with Claude...\n")
+	fmt.Printf("Exploring codebase with %s...\n", agent.Name)
 
 	prompt, err := renderExploreRepoPrompt(projectName)
 	if err != nil {
@@ -395,13 +485,13 @@ func initExistingRepo(projectName, model string) {
 		os.Exit(1)
 	}
 
-	result, err := runClaudeOnceWithModel(prompt, analysisModel)
+	result, err := agent.runOnce(prompt, model, timeout)
 	if err != nil {
 		if isClaudeRateLimitError(err) {
-			fmt.Fprintln(os.Stderr, "Claude is unavailable (usage limit / rate limit).")
+			fmt.Fprintf(os.Stderr, "%s is unavailable (usage limit / rate limit).\n", agent.Name)
 			os.Exit(2)
 		}
-		fmt.Fprintln(os.Stderr, "Claude exploration failed.")
+		fmt.Fprintf(os.Stderr, "%s exploration failed.\n", agent.Name)
 		details := claudeActionableDetails(err)
 		if details != "" {
 			fmt.Fprintf(os.Stderr, "\nDetails:\n%s\n", details)
@@ -413,8 +503,8 @@ func initExistingRepo(projectName, model string) {
 	reqContent := parseExploreRequirements(result)
 
 	if reqContent == "" {
-		fmt.Fprintln(os.Stderr, "Failed to parse requirements from Claude's response.")
-		fmt.Fprintln(os.Stderr, "\nClaude's response (first 500 chars):")
+		fmt.Fprintf(os.Stderr, "Failed to parse requirements from %s's response.\n", agent.Name)
+		fmt.Fprintf(os.Stderr, "\n%s's response (first 500 chars):\n", agent.Name)
 		preview := result
 		if len(preview) > 500 {
 			preview = preview[:500]
@@ -447,6 +537,8 @@ func initExistingRepo(projectName, model string) {
 		PrdFile:           ".ralph/prd.json",
 		CommitMessageFile: "../.ralph/commit_message.txt",
 		Model:             model,
+		AgentType:         agent.Type,
+		AgentBinary:       agent.Binary,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to render default config: %v\n", err)
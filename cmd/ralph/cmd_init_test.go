@@ -1,6 +1,9 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -41,3 +44,115 @@ func TestParseGeneratedPRD(t *testing.T) {
 		})
 	}
 }
+
+func TestReadRequirements(t *testing.T) {
+	dir := t.TempDir()
+	reqPath := filepath.Join(dir, "requirements.md")
+	if err := os.WriteFile(reqPath, []byte("# From file\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	emptyPath := filepath.Join(dir, "empty.md")
+	if err := os.WriteFile(emptyPath, []byte("  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		stdin   string
+		want    string
+		wantErr string
+	}{
+		{name: "file", path: reqPath, stdin: "unused", want: "# From file\n"},
+		{name: "dash reads stdin", path: "-", stdin: "# Piped\nBuild a CLI\n", want: "# Piped\nBuild a CLI\n"},
+		{name: "empty stdin", path: "-", stdin: " \n\t", wantErr: "No requirements on stdin"},
+		{name: "empty file", path: emptyPath, wantErr: "Requirements file is empty"},
+		{name: "missing file", path: filepath.Join(dir, "missing.md"), wantErr: "Failed to read requirements file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readRequirements(tt.path, strings.NewReader(tt.stdin))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readRequirements() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readRequirements() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("readRequirements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRequirementsStdinBuildsPrompt(t *testing.T) {
+	stdin := strings.NewReader("Build a URL shortener with click stats\n")
+	req, err := readRequirements("-", stdin)
+	if err != nil {
+		t.Fatalf("readRequirements() error: %v", err)
+	}
+
+	prompt, err := renderNewProjectPrompt("demo", string(req))
+	if err != nil {
+		t.Fatalf("renderNewProjectPrompt() error: %v", err)
+	}
+	if !strings.Contains(prompt, "Build a URL shortener with click stats") {
+		t.Errorf("prompt not built from stdin:\n%s", prompt)
+	}
+}
+
+func TestDetectExistingCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		want       bool
+		wantReason string
+	}{
+		{"empty", nil, false, "no code files"},
+		{"code at root", map[string]string{"main.go": "package main"}, true, "found code file main.go"},
+		{"nested code", map[string]string{"README.md": "", "src/app/server.py": ""}, true, "found code file src/app/server.py"},
+		{"manifest only", map[string]string{"Cargo.toml": ""}, true, "found project file Cargo.toml"},
+		{"newer extension", map[string]string{"lib/parser.ex": ""}, true, "found code file lib/parser.ex"},
+		{"docs with empty src dir", map[string]string{"README.md": "", "docs/guide.md": "", "src/notes.txt": ""}, false, "no code files"},
+		{"only dependencies and hidden dirs", map[string]string{"node_modules/x/index.js": "", ".github/scripts/ci.sh": "", ".eslintrc.js": ""}, false, "no code files"},
+		{
+			name:       "ralphignore excludes examples",
+			files:      map[string]string{".ralphignore": "# sample code only\nexamples/\n*.html\n", "examples/demo.go": "", "site/index.html": "", "README.md": ""},
+			want:       false,
+			wantReason: "no code files outside .ralphignore",
+		},
+		{
+			name:       "anchored pattern leaves nested match",
+			files:      map[string]string{".ralphignore": "/scripts\n", "scripts/build.sh": "", "tools/scripts/run.sh": ""},
+			want:       true,
+			wantReason: "found code file tools/scripts/run.sh",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tt.files {
+				p := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, reason := detectExistingCode(dir)
+			if got != tt.want || reason != tt.wantReason {
+				t.Errorf("detectExistingCode() = %v, %q; want %v, %q", got, reason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
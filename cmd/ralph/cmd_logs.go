@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// logLevels ranks the level names written by internal/logger.
+var logLevels = map[string]int{"DEBUG": 0, "INFO": 1, "WARN": 2, "ERROR": 3}
+
+// logFollowPoll is how often -follow checks the log for new lines.
+const logFollowPoll = 250 * time.Millisecond
+
+func logsCmd(args []string) int {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`logs 📜  Show the run log from .ralph/logs
+
+Usage:
+  ralph logs [flags]
+
+Flags:
+  -follow   Keep printing new lines as they are written (like tail -f)
+  -level    Minimum level to show: debug, info, warn, error (default debug)
+  -loop     Only show lines from this loop iteration
+  -json     Read ralph.jsonl and print matching JSON lines unchanged
+  -file     Log file to read (default .ralph/logs/ralph.log, else ralph.jsonl)
+
+Examples:
+  ralph logs
+  ralph logs -level error
+  ralph logs -loop 3
+  ralph logs -follow -json | jq .message
+`)
+	}
+	follow := fs.Bool("follow", false, "Print new lines as they are written")
+	level := fs.String("level", "debug", "Minimum level to show")
+	loopNum := fs.Int("loop", 0, "Only show lines from this loop")
+	jsonOut := fs.Bool("json", false, "Print JSON lines unchanged")
+	file := fs.String("file", "", "Log file to read")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+
+	minLevel, ok := logLevels[strings.ToUpper(strings.TrimSpace(*level))]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Invalid level %q. Must be one of: debug, info, warn, error\n", *level)
+		return 1
+	}
+	if *loopNum < 0 {
+		fmt.Fprintln(os.Stderr, "-loop must be a positive loop number")
+		return 1
+	}
+
+	path := *file
+	if path == "" {
+		path = defaultLogPath(filepath.Join(".ralph", "logs"), *jsonOut)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not open %s - has `ralph run` written a log yet? Error: %v\n", path, err)
+		return 1
+	}
+	defer f.Close()
+
+	filter := &logFilter{
+		minLevel: minLevel,
+		loop:     *loopNum,
+		json:     strings.HasSuffix(path, ".jsonl"),
+		raw:      *jsonOut,
+	}
+	if filter.raw && !filter.json {
+		fmt.Fprintf(os.Stderr, "-json needs a JSON log, but %s is a text log (run with -log-format json)\n", path)
+		return 1
+	}
+
+	if !*follow {
+		if err := filter.Copy(os.Stdout, f); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", path, err)
+			return 1
+		}
+		return 0
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err := followLog(ctx, f, path, filter, os.Stdout, logFollowPoll); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to follow %s: %v\n", path, err)
+		return 1
+	}
+	return 0
+}
+
+// defaultLogPath picks the run log in dir: ralph.jsonl when JSON is wanted,
+// otherwise ralph.log, falling back to ralph.jsonl if only that exists.
+func defaultLogPath(dir string, wantJSON bool) string {
+	text := filepath.Join(dir, "ralph.log")
+	jsonl := filepath.Join(dir, "ralph.jsonl")
+	if wantJSON {
+		return jsonl
+	}
+	if _, err := os.Stat(text); err != nil {
+		if _, err := os.Stat(jsonl); err == nil {
+			return jsonl
+		}
+	}
+	return text
+}
+
+var (
+	textLogLineRe = regexp.MustCompile(`^\[[^\]]*\] (DEBUG|INFO|WARN|ERROR): `)
+	textLogLoopRe = regexp.MustCompile(` loop=(\d+)(?:\s|$)`)
+)
+
+// logFilter selects run log lines by level and loop. Lines belong to the
+// loop named by the most recent line carrying a loop field, so a filter
+// keeps state and should see the log in order. Text lines without a level
+// prefix (continuations of a multi-line message) follow the previous line.
+type logFilter struct {
+	minLevel int
+	loop     int  // 0 = every loop
+	json     bool // input is ralph.jsonl
+	raw      bool // print JSON lines unchanged instead of as text
+
+	current  int  // loop the log is currently in
+	lastKept bool // whether the previous entry was printed
+}
+
+// Match reports whether line passes the filter and returns it as it should
+// be printed.
+func (f *logFilter) Match(line string) (string, bool) {
+	if f.json {
+		return f.matchJSON(line)
+	}
+	m := textLogLineRe.FindStringSubmatch(line)
+	if m == nil {
+		return line, f.lastKept
+	}
+	if lm := textLogLoopRe.FindStringSubmatch(line[len(m[0]):]); lm != nil {
+		f.current, _ = strconv.Atoi(lm[1])
+	}
+	f.lastKept = f.keep(m[1])
+	return line, f.lastKept
+}
+
+func (f *logFilter) matchJSON(line string) (string, bool) {
+	var entry struct {
+		Timestamp string         `json:"timestamp"`
+		Level     string         `json:"level"`
+		Message   string         `json:"message"`
+		Fields    map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return line, false
+	}
+	if n, ok := entry.Fields["loop"].(float64); ok {
+		f.current = int(n)
+	}
+	if !f.keep(entry.Level) {
+		return "", false
+	}
+	if f.raw {
+		return line, true
+	}
+
+	keys := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	ts := entry.Timestamp
+	if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+		ts = t.Format("2006-01-02 15:04:05")
+	}
+	fmt.Fprintf(&sb, "[%s] %s: %s", ts, entry.Level, entry.Message)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, " %s=%v", k, entry.Fields[k])
+	}
+	return sb.String(), true
+}
+
+func (f *logFilter) keep(level string) bool {
+	rank, ok := logLevels[strings.ToUpper(level)]
+	if ok && rank < f.minLevel {
+		return false
+	}
+	return f.loop == 0 || f.current == f.loop
+}
+
+// Copy writes every matching line of r to w.
+func (f *logFilter) Copy(w io.Writer, r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		if out, ok := f.Match(sc.Text()); ok {
+			fmt.Fprintln(w, out)
+		}
+	}
+	return sc.Err()
+}
+
+// followLog prints matching lines of the open log file, then keeps polling
+// for new ones until ctx is done. If the file shrinks or is replaced (log
+// rotation), it reopens path and starts from the top.
+func followLog(ctx context.Context, f *os.File, path string, filter *logFilter, w io.Writer, poll time.Duration) error {
+	rd := bufio.NewReader(f)
+	var partial string
+	var offset int64
+	for {
+		chunk, err := rd.ReadString('\n')
+		offset += int64(len(chunk))
+		if err == nil {
+			if out, ok := filter.Match(strings.TrimRight(partial+chunk, "\r\n")); ok {
+				fmt.Fprintln(w, out)
+			}
+			partial = ""
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		partial += chunk
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(poll):
+		}
+
+		cur, statErr := f.Stat()
+		latest, pathErr := os.Stat(path)
+		if statErr == nil && pathErr == nil && (!os.SameFile(cur, latest) || latest.Size() < offset) {
+			nf, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			f.Close()
+			f = nf
+			rd.Reset(f)
+			partial, offset = "", 0
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const sampleTextLog = `[2026-01-02 10:00:00] INFO: Run started
+[2026-01-02 10:00:01] DEBUG: Starting loop iteration loop=1
+[2026-01-02 10:00:02] ERROR: Step failed step=agent error=exit status 1
+Output: line one
+line two
+[2026-01-02 10:00:03] DEBUG: Loop iteration complete loop=1 duration=2s
+[2026-01-02 10:00:04] DEBUG: Starting loop iteration loop=2
+[2026-01-02 10:00:05] WARN: Retrying step=agent
+[2026-01-02 10:00:06] INFO: Loop iteration complete loop=2 duration=1s
+`
+
+const sampleJSONLog = `{"timestamp":"2026-01-02T10:00:00Z","level":"INFO","message":"Run started"}
+{"timestamp":"2026-01-02T10:00:01Z","level":"DEBUG","message":"Starting loop iteration","fields":{"loop":1}}
+{"timestamp":"2026-01-02T10:00:02Z","level":"ERROR","message":"Step failed","fields":{"step":"agent","error":"exit status 1"}}
+{"timestamp":"2026-01-02T10:00:04Z","level":"DEBUG","message":"Starting loop iteration","fields":{"loop":2}}
+{"timestamp":"2026-01-02T10:00:05Z","level":"WARN","message":"Retrying","fields":{"step":"agent","attempt":1}}
+`
+
+func filterLog(t *testing.T, content string, f *logFilter) []string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := f.Copy(&out, strings.NewReader(content)); err != nil {
+		t.Fatal(err)
+	}
+	text := strings.TrimRight(out.String(), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+func TestLogFilterText(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int
+		loop  int
+		want  []string // substrings, one per expected line
+	}{
+		{"everything", 0, 0, []string{"Run started", "loop=1", "Step failed", "line one", "line two", "complete loop=1", "loop=2", "Retrying", "complete loop=2"}},
+		{"errors keep continuation lines", logLevels["ERROR"], 0, []string{"Step failed", "line one", "line two"}},
+		{"info and up", logLevels["INFO"], 0, []string{"Run started", "Step failed", "line one", "line two", "Retrying", "complete loop=2"}},
+		{"loop 1", 0, 1, []string{"Starting loop iteration loop=1", "Step failed", "line one", "line two", "complete loop=1"}},
+		{"loop 2 warnings", logLevels["WARN"], 2, []string{"Retrying"}},
+		{"missing loop", 0, 9, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterLog(t, sampleTextLog, &logFilter{minLevel: tt.level, loop: tt.loop})
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d:\n%s", len(got), len(tt.want), strings.Join(got, "\n"))
+			}
+			for i, sub := range tt.want {
+				if !strings.Contains(got[i], sub) {
+					t.Errorf("line %d = %q, want it to contain %q", i, got[i], sub)
+				}
+			}
+		})
+	}
+}
+
+func TestLogFilterJSON(t *testing.T) {
+	raw := filterLog(t, sampleJSONLog, &logFilter{minLevel: logLevels["WARN"], json: true, raw: true})
+	if len(raw) != 2 || raw[0] != strings.Split(sampleJSONLog, "\n")[2] {
+		t.Fatalf("raw JSON lines not passed through:\n%s", strings.Join(raw, "\n"))
+	}
+
+	loop2 := filterLog(t, sampleJSONLog, &logFilter{loop: 2, json: true})
+	want := []string{
+		"[2026-01-02 10:00:04] DEBUG: Starting loop iteration loop=2",
+		"[2026-01-02 10:00:05] WARN: Retrying attempt=1 step=agent",
+	}
+	if strings.Join(loop2, "\n") != strings.Join(want, "\n") {
+		t.Errorf("rendered loop 2:\n%s\nwant:\n%s", strings.Join(loop2, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDefaultLogPath(t *testing.T) {
+	dir := t.TempDir()
+	if got := defaultLogPath(dir, false); got != filepath.Join(dir, "ralph.log") {
+		t.Errorf("no logs: got %s, want ralph.log", got)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ralph.jsonl"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := defaultLogPath(dir, false); got != filepath.Join(dir, "ralph.jsonl") {
+		t.Errorf("only jsonl: got %s, want ralph.jsonl", got)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ralph.log"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := defaultLogPath(dir, false); got != filepath.Join(dir, "ralph.log") {
+		t.Errorf("both: got %s, want ralph.log", got)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the follower goroutine and the test.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestFollowLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ralph.log")
+	if err := os.WriteFile(path, []byte("[t] INFO: first\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() {
+		done <- followLog(ctx, f, path, &logFilter{minLevel: logLevels["INFO"]}, out, 5*time.Millisecond)
+	}()
+
+	appendLine := func(s string) {
+		af, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		af.WriteString(s)
+		af.Close()
+	}
+	waitFor := func(sub string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !strings.Contains(out.String(), sub) {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %q, got:\n%s", sub, out.String())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	waitFor("first")
+	appendLine("[t] DEBUG: hidden\n[t] ERROR: sec")
+	appendLine("ond\n")
+	waitFor("ERROR: second")
+
+	// Rotation: the file is replaced by a shorter one.
+	if err := os.WriteFile(path, []byte("[t] INFO: rotated\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("rotated")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("followLog: %v", err)
+	}
+	if strings.Contains(out.String(), "hidden") {
+		t.Errorf("debug line printed at info level:\n%s", out.String())
+	}
+}
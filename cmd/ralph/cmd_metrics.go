@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+type metricsReport struct {
+	Metrics   *tracker.RunMetrics     `json:"metrics,omitempty"`
+	Aggregate *runResult              `json:"aggregate,omitempty"`
+	History   []tracker.HistoryRecord `json:"history,omitempty"`
+	Window    string                  `json:"window,omitempty"` // -since, when history is filtered
+}
+
+func metricsCmd(args []string) int {
+	fs := flag.NewFlagSet("metrics", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`metrics 📊  Summarize token usage and cost
+
+Usage:
+  ralph metrics [flags]
+
+Flags:
+  -n       Number of recent runs to show from history (default 5, or every
+           run in the window with -since)
+  -since   Only show history runs completed within this window (e.g. 90m, 24h, 7d)
+  -json    Print metrics, aggregate, and history as JSON
+  -csv     Write every run in the history to this CSV file ("-" for stdout)
+
+Examples:
+  ralph metrics
+  ralph metrics -n 10
+  ralph metrics -since 24h
+  ralph metrics -json
+  ralph metrics -csv runs.csv
+`)
+	}
+	lastN := fs.Int("n", 5, "Number of recent runs to show")
+	since := fs.String("since", "", "Only show runs completed within this window")
+	jsonOut := fs.Bool("json", false, "Print metrics as JSON")
+	csvPath := fs.String("csv", "", "Write the run history to this CSV file")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+
+	var window time.Duration
+	if *since != "" {
+		var err error
+		if window, err = tracker.ParseWindow(*since); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -since: %v\n", err)
+			return 1
+		}
+	}
+
+	trk := tracker.NewWriter(".ralph")
+	if *csvPath != "" {
+		if err := exportMetricsCSV(trk, *csvPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to export metrics: %v\n", err)
+			return 1
+		}
+		if *csvPath != "-" {
+			fmt.Printf("Wrote run history to %s\n", *csvPath)
+		}
+		return 0
+	}
+
+	report, err := loadMetricsReport(trk, ".ralph/aggregate.json")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read metrics: %v\n", err)
+		return 1
+	}
+	if window > 0 {
+		report.History = tracker.FilterHistorySince(report.History, time.Now().Add(-window))
+		report.Window = *since
+	}
+	// -since shows the whole window, and its total, unless -n also asks for a cut.
+	nSet := false
+	fs.Visit(func(f *flag.Flag) { nSet = nSet || f.Name == "n" })
+	if (window == 0 || nSet) && *lastN >= 0 && len(report.History) > *lastN {
+		report.History = report.History[len(report.History)-*lastN:]
+	}
+
+	if *jsonOut {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to serialize metrics: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+		return 0
+	}
+
+	if report.Metrics == nil && report.Aggregate == nil && len(report.History) == 0 {
+		fmt.Println("No runs recorded.")
+		return 0
+	}
+	printMetricsReport(os.Stdout, report, time.Now())
+	return 0
+}
+
+// exportMetricsCSV writes the run history as CSV to path, or to stdout when
+// path is "-".
+func exportMetricsCSV(trk *tracker.Writer, path string) error {
+	if path == "-" {
+		return trk.ExportCSV(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := trk.ExportCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func loadMetricsReport(trk *tracker.Writer, aggregatePath string) (metricsReport, error) {
+	var r metricsReport
+	m, err := trk.LoadMetrics()
+	if err != nil {
+		return r, err
+	}
+	r.Metrics = m
+
+	if data, err := os.ReadFile(aggregatePath); err == nil {
+		var agg runResult
+		if err := json.Unmarshal(data, &agg); err == nil {
+			r.Aggregate = &agg
+		}
+	}
+
+	h, err := trk.LoadHistory()
+	if err != nil {
+		return r, err
+	}
+	r.History = h
+	return r, nil
+}
+
+func printMetricsReport(w io.Writer, r metricsReport, now time.Time) {
+	if m := r.Metrics; m != nil {
+		fmt.Fprintf(w, "Claude calls:  %d\n", m.TotalClaudeCalls)
+		fmt.Fprintf(w, "Tokens:        %d (in: %d, out: %d)\n", m.TotalTokens, m.InputTokens, m.OutputTokens)
+		fmt.Fprintf(w, "Cost:          $%.2f\n", m.TotalCostUSD)
+		end := now
+		if m.CompletedAt != nil {
+			end = *m.CompletedAt
+		} else if !m.UpdatedAt.IsZero() {
+			end = m.UpdatedAt
+		}
+		if !m.StartedAt.IsZero() && end.After(m.StartedAt) {
+			fmt.Fprintf(w, "Elapsed:       %s\n", end.Sub(m.StartedAt).Round(time.Second))
+		}
+	} else if a := r.Aggregate; a != nil {
+		fmt.Fprintf(w, "Claude calls:  %d\n", a.Metrics.TotalClaudeCalls)
+		fmt.Fprintf(w, "Tokens:        %d (in: %d, out: %d)\n", a.Metrics.TotalTokens, a.Metrics.InputTokens, a.Metrics.OutputTokens)
+		fmt.Fprintf(w, "Cost:          $%.2f\n", a.Metrics.TotalCostUSD)
+		fmt.Fprintf(w, "Elapsed:       %s\n", time.Duration(a.Metrics.ElapsedSec)*time.Second)
+	}
+	if a := r.Aggregate; a != nil && a.Model != "" {
+		fmt.Fprintf(w, "Model:         %s\n", a.Model)
+	}
+
+	if len(r.History) == 0 {
+		if r.Window != "" {
+			fmt.Fprintf(w, "\nNo runs in the last %s.\n", r.Window)
+		}
+		return
+	}
+	if r.Window != "" {
+		fmt.Fprintf(w, "\nRuns in the last %s:\n", r.Window)
+	} else {
+		fmt.Fprintf(w, "\nRecent runs:\n")
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN\tCOMPLETED\tELAPSED\tLOOPS\tTASKS\tTOKENS\tCOST")
+	var totalTokens int
+	var totalCost float64
+	for _, h := range r.History {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			h.RunID, h.CompletedAt.Local().Format("2006-01-02 15:04"), formatElapsed(time.Duration(h.ElapsedSec)*time.Second),
+			h.Loops, h.TasksCompleted, h.TotalTokens, formatCost(h.CostUSD))
+		totalTokens += h.TotalTokens
+		totalCost += h.CostUSD
+	}
+	tw.Flush()
+	if r.Window != "" {
+		fmt.Fprintf(w, "Total: %d run(s), %d tokens, %s\n", len(r.History), totalTokens, formatCost(totalCost))
+	}
+}
+
+// formatElapsed renders d compactly with its two largest units, e.g. "45s",
+// "3m12s", "2h05m" or "3d4h". Zero renders as "-".
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// formatCost renders a USD amount compactly: "$0.00", "<$0.01", "$3.27" or
+// "$1.2k" from a thousand dollars up.
+func formatCost(usd float64) string {
+	switch {
+	case usd > 0 && usd < 0.005:
+		return "<$0.01"
+	case usd >= 1000:
+		return fmt.Sprintf("$%.1fk", usd/1000)
+	default:
+		return fmt.Sprintf("$%.2f", usd)
+	}
+}
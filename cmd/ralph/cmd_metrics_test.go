@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+func TestLoadMetricsReport(t *testing.T) {
+	dir := t.TempDir()
+	trk := tracker.NewWriter(dir)
+	aggPath := filepath.Join(dir, "aggregate.json")
+
+	r, err := loadMetricsReport(trk, aggPath)
+	if err != nil {
+		t.Fatalf("loadMetricsReport failed: %v", err)
+	}
+	if r.Metrics != nil || r.Aggregate != nil || len(r.History) != 0 {
+		t.Fatalf("expected empty report, got %+v", r)
+	}
+
+	trk.AddUsage("run-1", tracker.UsageDelta{InputTokens: 10, OutputTokens: 5, TotalTokens: 15, CostUSD: 0.5})
+	agg, _ := json.Marshal(runResult{Version: "v1", Model: "opus"})
+	if err := os.WriteFile(aggPath, agg, 0644); err != nil {
+		t.Fatal(err)
+	}
+	line, _ := json.Marshal(tracker.HistoryRecord{RunID: "run-0", Loops: 3})
+	if err := os.WriteFile(trk.HistoryPath, append(line, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = loadMetricsReport(trk, aggPath)
+	if err != nil {
+		t.Fatalf("loadMetricsReport failed: %v", err)
+	}
+	if r.Metrics == nil || r.Metrics.TotalTokens != 15 {
+		t.Errorf("unexpected metrics: %+v", r.Metrics)
+	}
+	if r.Aggregate == nil || r.Aggregate.Model != "opus" {
+		t.Errorf("unexpected aggregate: %+v", r.Aggregate)
+	}
+	if len(r.History) != 1 || r.History[0].RunID != "run-0" {
+		t.Errorf("unexpected history: %+v", r.History)
+	}
+}
+
+func TestPrintMetricsReport(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	done := start.Add(90 * time.Second)
+	report := metricsReport{
+		Metrics: &tracker.RunMetrics{
+			StartedAt:        start,
+			CompletedAt:      &done,
+			TotalClaudeCalls: 4,
+			InputTokens:      300,
+			OutputTokens:     100,
+			TotalTokens:      400,
+			TotalCostUSD:     1.25,
+		},
+		Aggregate: &runResult{Model: "sonnet"},
+		History: []tracker.HistoryRecord{
+			{RunID: "abc", CompletedAt: done, Loops: 7, TasksCompleted: 2, TotalTokens: 400, CostUSD: 1.25},
+		},
+	}
+
+	var buf bytes.Buffer
+	printMetricsReport(&buf, report, start.Add(time.Hour))
+	out := buf.String()
+	for _, want := range []string{
+		"Claude calls:  4",
+		"Tokens:        400 (in: 300, out: 100)",
+		"Cost:          $1.25",
+		"Elapsed:       1m30s",
+		"Model:         sonnet",
+		"Recent runs:",
+		"abc",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintMetricsReportWindow(t *testing.T) {
+	done := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	report := metricsReport{
+		Window: "24h",
+		History: []tracker.HistoryRecord{
+			{RunID: "a", CompletedAt: done, ElapsedSec: 3725, TotalTokens: 400, CostUSD: 1.25},
+			{RunID: "b", CompletedAt: done, ElapsedSec: 42, TotalTokens: 100, CostUSD: 0.5},
+		},
+	}
+	var buf bytes.Buffer
+	printMetricsReport(&buf, report, done)
+	out := buf.String()
+	for _, want := range []string{"Runs in the last 24h:", "1h02m", "42s", "Total: 2 run(s), 500 tokens, $1.75"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printMetricsReport(&buf, metricsReport{Window: "7d"}, done)
+	if !strings.Contains(buf.String(), "No runs in the last 7d.") {
+		t.Errorf("expected empty window message, got %q", buf.String())
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "-"},
+		{45 * time.Second, "45s"},
+		{3*time.Minute + 5*time.Second, "3m05s"},
+		{2*time.Hour + 5*time.Minute + 30*time.Second, "2h05m"},
+		{76 * time.Hour, "3d4h"},
+	}
+	for _, tt := range tests {
+		if got := formatElapsed(tt.d); got != tt.want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCost(t *testing.T) {
+	tests := []struct {
+		usd  float64
+		want string
+	}{
+		{0, "$0.00"},
+		{0.001, "<$0.01"},
+		{3.268, "$3.27"},
+		{1234, "$1.2k"},
+	}
+	for _, tt := range tests {
+		if got := formatCost(tt.usd); got != tt.want {
+			t.Errorf("formatCost(%v) = %q, want %q", tt.usd, got, tt.want)
+		}
+	}
+}
+
+func TestMetricsCmdCSV(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	trk := tracker.NewWriter(".ralph")
+	if err := os.MkdirAll(".ralph", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := trk.AppendHistory(tracker.HistoryRecord{RunID: "run-1", Model: "sonnet", Loops: 2, TotalTokens: 30}); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := metricsCmd([]string{"-csv", "runs.csv"}); code != 0 {
+		t.Fatalf("metricsCmd(-csv) = %d, want 0", code)
+	}
+	data, err := os.ReadFile("runs.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "run_id,") || !strings.HasPrefix(lines[1], "run-1,,sonnet,2,") {
+		t.Errorf("runs.csv =\n%s", data)
+	}
+}
+
+func TestMetricsCmdSinceShowsWholeWindow(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	trk := tracker.NewWriter(".ralph")
+	if err := os.MkdirAll(".ralph", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 7; i++ {
+		rec := tracker.HistoryRecord{RunID: fmt.Sprintf("run-%d", i), CompletedAt: time.Now(), TotalTokens: 10}
+		if err := trk.AppendHistory(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{args: []string{"-json"}, want: 5},
+		{args: []string{"-json", "-since", "24h"}, want: 7},
+		{args: []string{"-json", "-since", "24h", "-n", "2"}, want: 2},
+	}
+	for _, tt := range tests {
+		out, err := os.Create(filepath.Join(dir, "stdout"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		realStdout := os.Stdout
+		os.Stdout = out
+		code := metricsCmd(tt.args)
+		os.Stdout = realStdout
+		out.Close()
+		if code != 0 {
+			t.Fatalf("metricsCmd(%q) = %d, want 0", tt.args, code)
+		}
+
+		data, err := os.ReadFile(out.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report metricsReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatalf("metricsCmd(%q) output is not JSON: %v\n%s", tt.args, err, data)
+		}
+		if len(report.History) != tt.want {
+			t.Errorf("metricsCmd(%q) history has %d runs, want %d", tt.args, len(report.History), tt.want)
+		}
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/config"
 )
 
 func prCmd(args []string) int {
@@ -60,15 +63,14 @@ Examples:
 	}
 
 	// Get current branch
-	branchOut, err := exec.Command("git", "branch", "--show-current").Output()
+	branch, err := currentBranch()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Failed to get current branch.")
 		return 1
 	}
-	branch := strings.TrimSpace(string(branchOut))
 
 	// Check we're not on main/master
-	if branch == "main" || branch == "master" || branch == *base {
+	if isBaseBranch(branch, *base) {
 		fmt.Fprintf(os.Stderr, "You're on '%s'. Create a feature branch first:\n", branch)
 		fmt.Fprintln(os.Stderr, "  git checkout -b feature/my-feature")
 		fmt.Fprintln(os.Stderr, "  ralph run")
@@ -86,25 +88,14 @@ Examples:
 
 	// Push branch to origin
 	fmt.Printf("Pushing branch '%s' to origin...\n", branch)
-	pushCmd := exec.Command("git", "push", "-u", "origin", branch)
-	pushCmd.Stdout = os.Stdout
-	pushCmd.Stderr = os.Stderr
-	if err := pushCmd.Run(); err != nil {
+	if err := pushBranch(branch, os.Stdout); err != nil {
 		fmt.Fprintln(os.Stderr, "Failed to push branch.")
 		return 1
 	}
 
 	// Create PR
 	fmt.Println("\nCreating pull request...")
-	ghArgs := []string{"pr", "create", "--base", *base, "--title", prTitle, "--body", prBody}
-	if *draft {
-		ghArgs = append(ghArgs, "--draft")
-	}
-
-	ghCmd := exec.Command("gh", ghArgs...)
-	ghCmd.Stdout = os.Stdout
-	ghCmd.Stderr = os.Stderr
-	if err := ghCmd.Run(); err != nil {
+	if err := createPR(*base, prTitle, prBody, *draft, os.Stdout); err != nil {
 		fmt.Fprintln(os.Stderr, "Failed to create PR. You may need to authenticate: gh auth login")
 		return 1
 	}
@@ -157,6 +148,13 @@ func generatePRBody() string {
 		return "Tasks completed by Ralph."
 	}
 
+	learnings, _ := os.ReadFile(filepath.Join(".ralph", "learnings.md"))
+	return buildPRBody(prd, string(learnings))
+}
+
+// buildPRBody renders the PR description: tasks grouped by status, then any
+// learnings (truncated) accumulated during the run.
+func buildPRBody(prd prdFile, learnings string) string {
 	var sb strings.Builder
 	sb.WriteString("## Summary\n\n")
 	sb.WriteString("Tasks completed by Ralph:\n\n")
@@ -200,7 +198,97 @@ func generatePRBody() string {
 		sb.WriteString("\n")
 	}
 
+	if notes := strings.TrimSpace(learnings); notes != "" {
+		if len(notes) > maxPRLearningsLen {
+			notes = notes[:maxPRLearningsLen] + "..."
+		}
+		sb.WriteString("### 📝 Learnings\n\n")
+		sb.WriteString(notes)
+		sb.WriteString("\n\n")
+	}
+
 	sb.WriteString("---\n*Generated by [Ralph](https://github.com/chr1sbest/wiggum)*")
 
 	return sb.String()
 }
+
+// maxPRLearningsLen caps how much of learnings.md goes into a PR body.
+const maxPRLearningsLen = 2000
+
+func currentBranch() (string, error) {
+	out, err := exec.Command("git", "branch", "--show-current").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// isBaseBranch reports whether branch is main, master, or the PR base.
+func isBaseBranch(branch, base string) bool {
+	return branch == "main" || branch == "master" || branch == base
+}
+
+func pushBranch(branch string, out io.Writer) error {
+	pushCmd := exec.Command("git", "push", "-u", "origin", branch)
+	pushCmd.Stdout = out
+	pushCmd.Stderr = os.Stderr
+	return pushCmd.Run()
+}
+
+func createPR(base, title, body string, draft bool, out io.Writer) error {
+	ghArgs := []string{"pr", "create", "--base", base, "--title", title, "--body", body}
+	if draft {
+		ghArgs = append(ghArgs, "--draft")
+	}
+
+	ghCmd := exec.Command("gh", ghArgs...)
+	ghCmd.Stdout = out
+	ghCmd.Stderr = os.Stderr
+	return ghCmd.Run()
+}
+
+// openPullRequestOnComplete is the pull_request hook: once a run has finished
+// every task, push the branch and open a PR. It never fails the run; anything
+// that gets in the way (not GitHub, no gh auth, on the base branch) is
+// reported and skipped. Progress goes to out.
+func openPullRequestOnComplete(cfg *config.Config, out io.Writer) {
+	if cfg == nil || cfg.PullRequest == nil || !cfg.PullRequest.Enabled {
+		return
+	}
+	if _, allComplete, err := agent.CheckPRDTasks(filepath.Join(".ralph", "prd.json")); err != nil || !allComplete {
+		return
+	}
+
+	if _, err := getGitHubRepo(); err != nil {
+		fmt.Fprintln(out, "\nSkipping pull request: not a GitHub repository")
+		return
+	}
+	if err := checkGitHubAuth(); err != nil {
+		fmt.Fprintf(out, "\nSkipping pull request: %v\n", err)
+		return
+	}
+
+	base := cfg.PullRequest.BaseBranch()
+	branch, err := currentBranch()
+	if err != nil || branch == "" {
+		fmt.Fprintln(out, "\nSkipping pull request: could not determine current branch")
+		return
+	}
+	if isBaseBranch(branch, base) {
+		fmt.Fprintf(out, "\nSkipping pull request: on '%s' (use branch_per_task or a feature branch)\n", branch)
+		return
+	}
+
+	fmt.Fprintf(out, "\nPushing branch '%s' to origin...\n", branch)
+	if err := pushBranch(branch, out); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to push branch: %v\n", err)
+		return
+	}
+
+	fmt.Fprintln(out, "Creating pull request...")
+	if err := createPR(base, generatePRTitle(), generatePRBody(), cfg.PullRequest.Draft, out); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to create PR: %v\n", err)
+		return
+	}
+	fmt.Fprintln(out, "✓ Pull request created!")
+}
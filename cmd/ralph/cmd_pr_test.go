@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuildPRBody(t *testing.T) {
+	const prdJSON = `{
+  "version": 1,
+  "tasks": [
+    {"id": "T001", "title": "Add login endpoint", "status": "done", "issue": {"number": 42, "url": "https://github.com/o/r/issues/42"}},
+    {"id": "T002", "title": "Hash passwords", "status": "done"},
+    {"id": "T003", "title": "Add logout", "status": "in_progress"},
+    {"id": "T004", "title": "Rate limit logins", "status": "todo"}
+  ]
+}`
+	var prd prdFile
+	if err := json.Unmarshal([]byte(prdJSON), &prd); err != nil {
+		t.Fatalf("failed to parse sample prd: %v", err)
+	}
+
+	body := buildPRBody(prd, "- bcrypt cost 12 keeps tests fast enough\n")
+
+	for _, want := range []string{
+		"### ✅ Completed",
+		"- [x] **T001** - Add login endpoint",
+		"  - Fixes #42",
+		"- [x] **T002** - Hash passwords",
+		"### 🔄 In Progress",
+		"- [ ] **T003** - Add logout",
+		"### 📋 Remaining",
+		"- [ ] **T004** - Rate limit logins",
+		"### 📝 Learnings",
+		"- bcrypt cost 12 keeps tests fast enough",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Count(body, "Fixes #") != 1 {
+		t.Errorf("expected exactly one Fixes line:\n%s", body)
+	}
+}
+
+func TestBuildPRBodyLearnings(t *testing.T) {
+	prd := prdFile{Tasks: []prdTask{{ID: "T001", Title: "Only task", Status: "done"}}}
+
+	if body := buildPRBody(prd, "  \n"); strings.Contains(body, "Learnings") {
+		t.Errorf("blank learnings should be omitted:\n%s", body)
+	}
+	if strings.Contains(buildPRBody(prd, ""), "### 🔄 In Progress") {
+		t.Errorf("empty sections should be omitted")
+	}
+
+	long := strings.Repeat("x", maxPRLearningsLen+100)
+	body := buildPRBody(prd, long)
+	if strings.Contains(body, long) || !strings.Contains(body, strings.Repeat("x", maxPRLearningsLen)+"...") {
+		t.Errorf("long learnings should be truncated to %d chars", maxPRLearningsLen)
+	}
+}
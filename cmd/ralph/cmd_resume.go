@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+// resumePlan describes whether `ralph resume` picks up after an interrupted run.
+type resumePlan struct {
+	Resume bool
+	Idle   bool // no work is left, so resume exits without starting a run
+	Reason string
+	State  *tracker.RunState
+}
+
+// planResume decides whether the last recorded run was interrupted with
+// actionable work left. A run is considered finished if it appears in the
+// metrics history, which only records clean completions. Work left means a
+// todo task that isn't blocked, a task still in progress, or one of the
+// resettable failed tasks ralph run would set back to todo.
+func planResume(rs *tracker.RunState, history []tracker.HistoryRecord, prd *agent.PRDStatus, resettable int) resumePlan {
+	if prd == nil {
+		return resumePlan{Idle: true, Reason: "no tasks in .ralph/prd.json", State: rs}
+	}
+	inProgress := prd.IncompleteTasks - prd.TodoTasks - prd.FailedTasks
+	if !prd.HasActionableTasks() && inProgress <= 0 && resettable <= 0 {
+		return resumePlan{Idle: true, Reason: "no actionable tasks remain", State: rs}
+	}
+	if rs == nil || strings.TrimSpace(rs.RunID) == "" {
+		return resumePlan{Reason: "no previous run state"}
+	}
+	for _, h := range history {
+		if h.RunID == rs.RunID {
+			return resumePlan{Reason: fmt.Sprintf("previous run %s completed", rs.RunID), State: rs}
+		}
+	}
+	return resumePlan{Resume: true, State: rs}
+}
+
+func resumeCmd(args []string) int {
+	for _, a := range args {
+		if a == "-h" || a == "--help" || a == "-help" {
+			fmt.Print(`resume ⏯️  Continue after an interrupted run
+
+Usage:
+  ralph resume [run flags]
+
+Reads .ralph/run_state.json, reports where the previous run stopped, and
+continues with a normal ralph run. If the previous run completed or no state
+exists, this starts a fresh run. If no todo, in-progress or resettable failed
+task is left, it exits without running. Accepts the same flags as ralph run.
+
+A lock left by the interrupted run is cleared automatically when its process
+is gone. Pass -force to clear one that can't be read or was taken on another
+host.
+
+Examples:
+  ralph resume
+  ralph resume -budget 5
+  ralph resume -force
+`)
+			return 0
+		}
+	}
+
+	trk := tracker.NewWriter(".ralph")
+	rs, _ := trk.LoadRunState()
+	history, _ := trk.LoadHistory()
+	prd, _ := agent.LoadPRDStatus(".ralph/prd.json")
+	resettable := 0
+	if prd != nil && prd.FailedTasks > 0 && !resumeKeepsFailed(args) {
+		resettable, _ = agent.ResettableFailedTasks(".ralph/prd.json", resumeResetFailedAfter(args))
+	}
+
+	p := planResume(rs, history, prd, resettable)
+	printResumePlan(os.Stdout, p)
+	if p.Idle {
+		return 0
+	}
+	return runCmd(args)
+}
+
+// resumeKeepsFailed reports whether the run flags include -no-reset-failed.
+func resumeKeepsFailed(args []string) bool {
+	v, ok := runFlagValue(args, "no-reset-failed", true)
+	keep, err := strconv.ParseBool(v)
+	return ok && err == nil && keep
+}
+
+// resumeResetFailedAfter returns reset_failed_after from the config ralph run
+// will load, or 0 if it can't be read.
+func resumeResetFailedAfter(args []string) time.Duration {
+	path, ok := runFlagValue(args, "config", false)
+	if !ok {
+		path = defaultConfigPath()
+	}
+	cfg, err := config.NewLoader(".ralph").LoadFile(path)
+	if err != nil {
+		return 0
+	}
+	return cfg.GetResetFailedAfter()
+}
+
+// runFlagValue finds the named flag in ralph run arguments, written as -name,
+// --name or -name=value. A flag that isn't boolean takes its value from the
+// next argument when it has no "=".
+func runFlagValue(args []string, name string, isBool bool) (string, bool) {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			break
+		}
+		flagArg := strings.TrimPrefix(strings.TrimPrefix(args[i], "-"), "-")
+		if flagArg == args[i] {
+			continue
+		}
+		key, value, hasValue := strings.Cut(flagArg, "=")
+		switch {
+		case key != name:
+			continue
+		case hasValue:
+			return value, true
+		case isBool:
+			return "true", true
+		case i+1 < len(args):
+			return args[i+1], true
+		}
+		return "", true
+	}
+	return "", false
+}
+
+func printResumePlan(w io.Writer, p resumePlan) {
+	if p.Idle {
+		fmt.Fprintf(w, "Nothing to resume (%s).\n", p.Reason)
+		return
+	}
+	if !p.Resume {
+		fmt.Fprintf(w, "Nothing to resume (%s); starting a fresh run.\n\n", p.Reason)
+		return
+	}
+	rs := p.State
+	fmt.Fprintf(w, "Resuming after run %s (stopped at loop %d, status: %s)\n", rs.RunID, rs.LoopNumber, rs.Status)
+	if rs.CurrentStep != "" {
+		fmt.Fprintf(w, "  Last step:  %s\n", rs.CurrentStep)
+	}
+	if rs.CurrentTaskID != "" || rs.CurrentTask != "" {
+		fmt.Fprintf(w, "  Task:       %s\n", strings.TrimSpace(fmt.Sprintf("[%s] %s", rs.CurrentTaskID, rs.CurrentTask)))
+	}
+	if rs.LastError != "" {
+		fmt.Fprintf(w, "  Last error: %s\n", rs.LastError)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+func TestPlanResume(t *testing.T) {
+	interrupted := &tracker.RunState{RunID: "run-2", LoopNumber: 4, Status: "error", CurrentStep: "claude"}
+	actionable := &agent.PRDStatus{TotalTasks: 3, CompletedTasks: 1, IncompleteTasks: 2, TodoTasks: 2}
+	done := &agent.PRDStatus{TotalTasks: 3, CompletedTasks: 3}
+	inProgress := &agent.PRDStatus{TotalTasks: 2, CompletedTasks: 1, IncompleteTasks: 1}
+	failed := &agent.PRDStatus{TotalTasks: 2, CompletedTasks: 1, IncompleteTasks: 1, FailedTasks: 1}
+
+	tests := []struct {
+		name       string
+		state      *tracker.RunState
+		history    []tracker.HistoryRecord
+		prd        *agent.PRDStatus
+		resettable int
+		wantResume bool
+		wantIdle   bool
+		wantReason string
+	}{
+		{name: "no state", prd: actionable, wantReason: "no previous run state"},
+		{name: "state without run id", state: &tracker.RunState{}, prd: actionable, wantReason: "no previous run state"},
+		{
+			name:       "previous run completed",
+			state:      interrupted,
+			history:    []tracker.HistoryRecord{{RunID: "run-1"}, {RunID: "run-2"}},
+			prd:        actionable,
+			wantReason: "completed",
+		},
+		{name: "all tasks done", state: interrupted, prd: done, wantIdle: true, wantReason: "no actionable tasks"},
+		{name: "all tasks done without state", prd: done, wantIdle: true, wantReason: "no actionable tasks"},
+		{name: "missing prd", state: interrupted, wantIdle: true, wantReason: "no tasks"},
+		{
+			name:       "interrupted with work left",
+			state:      interrupted,
+			history:    []tracker.HistoryRecord{{RunID: "run-1"}},
+			prd:        actionable,
+			wantResume: true,
+		},
+		{name: "task still in progress", state: interrupted, prd: inProgress, wantResume: true},
+		{name: "failed task ralph run resets", state: interrupted, prd: failed, resettable: 1, wantResume: true},
+		{name: "failed task kept failed", state: interrupted, prd: failed, wantIdle: true, wantReason: "no actionable tasks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planResume(tt.state, tt.history, tt.prd, tt.resettable)
+			if got.Resume != tt.wantResume || got.Idle != tt.wantIdle {
+				t.Errorf("Resume, Idle = %v, %v; want %v, %v (reason %q)", got.Resume, got.Idle, tt.wantResume, tt.wantIdle, got.Reason)
+			}
+			if !strings.Contains(got.Reason, tt.wantReason) {
+				t.Errorf("Reason = %q, want it to contain %q", got.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestResumeCmdExitsWhenNothingIsLeft(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(".ralph", 0755); err != nil {
+		t.Fatal(err)
+	}
+	prd := `{"version":1,"tasks":[{"id":"T1","title":"a","status":"done"},{"id":"T2","title":"b","status":"failed"}]}`
+	if err := os.WriteFile(".ralph/prd.json", []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	realStdout := os.Stdout
+	os.Stdout = out
+	// Without a config, ralph run would fail its preflight and return 1.
+	code := resumeCmd([]string{"-no-reset-failed"})
+	os.Stdout = realStdout
+	if code != 0 {
+		t.Fatalf("resumeCmd() = %d, want 0", code)
+	}
+
+	data, err := os.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, "Nothing to resume (no actionable tasks remain).") || strings.Contains(got, "fresh run") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+}
+
+func TestRunFlagValue(t *testing.T) {
+	args := []string{"-budget", "5", "--config=alt.yaml", "-no-reset-failed=false"}
+	if v, ok := runFlagValue(args, "config", false); !ok || v != "alt.yaml" {
+		t.Errorf("config = %q, %v; want alt.yaml", v, ok)
+	}
+	if v, ok := runFlagValue(args, "budget", false); !ok || v != "5" {
+		t.Errorf("budget = %q, %v; want 5", v, ok)
+	}
+	if resumeKeepsFailed(args) {
+		t.Error("-no-reset-failed=false should reset failed tasks")
+	}
+	if !resumeKeepsFailed([]string{"--no-reset-failed"}) {
+		t.Error("--no-reset-failed should keep failed tasks")
+	}
+	if _, ok := runFlagValue(args, "once", true); ok {
+		t.Error("once should not be found")
+	}
+}
+
+func TestPrintResumePlan(t *testing.T) {
+	var buf bytes.Buffer
+	printResumePlan(&buf, resumePlan{
+		Resume: true,
+		State: &tracker.RunState{
+			RunID:         "run-2",
+			LoopNumber:    4,
+			Status:        "error",
+			CurrentStep:   "claude",
+			CurrentTaskID: "T003",
+			CurrentTask:   "Add auth",
+			LastError:     "timeout after 15m",
+		},
+	})
+	out := buf.String()
+	for _, want := range []string{"run-2", "loop 4", "Last step:  claude", "[T003] Add auth", "timeout after 15m"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printResumePlan(&buf, resumePlan{Reason: "no previous run state"})
+	if !strings.Contains(buf.String(), "starting a fresh run") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -20,6 +21,7 @@ import (
 	"github.com/chr1sbest/wiggum/internal/logger"
 	"github.com/chr1sbest/wiggum/internal/loop"
 	"github.com/chr1sbest/wiggum/internal/loop/steps"
+	"github.com/chr1sbest/wiggum/internal/status"
 	"github.com/chr1sbest/wiggum/internal/tracker"
 )
 
@@ -30,20 +32,105 @@ func runCmd(args []string) int {
 		}
 	}
 	fs := flag.NewFlagSet("run", flag.ExitOnError)
-	configFile := fs.String("config", ".ralph/config.json", "Path to config file")
+	configFile := fs.String("config", ".ralph/config.json", "Path to config file (.json, .yaml or .yml)")
 	model := fs.String("model", "", "Claude model to use (overrides agent step config)")
 	once := fs.Bool("once", false, "Run loop only once")
+	budget := fs.Float64("budget", 0, "Stop the loop once this many dollars have been spent (0 = no limit)")
+	maxLoops := fs.Int("max-loops", 0, "Stop after this many loop iterations (0 = no limit)")
+	logFormat := fs.String("log-format", "text", "Run log format written under .ralph/logs: text or json")
+	stepName := fs.String("step", "", "With -once, run only the configured step with this name")
+	noRedact := fs.Bool("no-redact", false, "Write secrets (tokens, *_TOKEN/*_KEY/*_SECRET env values) to the run log unredacted")
+	forceLock := fs.Bool("force", false, "Clear a lock that can't be read or was taken on another host (dead local runs are cleared automatically)")
+	confirmEachStep := fs.Bool("confirm-each-step", false, "Ask on the terminal before each step runs (y/n/skip/quit)")
+	waitOnLimit := fs.Bool("wait-on-limit", false, "On a Claude usage limit, sleep until the quota resets (up to 6h) and resume instead of exiting")
+	jsonProgress := fs.Bool("json-progress", false, "Write progress to stdout as newline-delimited JSON instead of the banner and status display")
+	prometheus := fs.Bool("prometheus", false, "Write run metrics to .ralph/metrics.prom after each loop for a Prometheus textfile collector")
+	plan := fs.Bool("plan", false, "Print the steps, limits and task progress a run would use, then exit without running anything")
+	noResetFailed := fs.Bool("no-reset-failed", false, "Keep failed tasks failed instead of resetting them to todo when the run starts")
+	envFile := fs.String("env-file", "", "Load environment variables from this dotenv file before the config is read")
+	envOverride := fs.Bool("env-override", false, "With -env-file, replace variables that are already set in the environment")
 	fs.Parse(args)
 
-	if err := validateRunPreflight(*configFile); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+	configExplicit := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			configExplicit = true
+		}
+	})
+	if !configExplicit {
+		*configFile = defaultConfigPath()
+	}
+
+	if *budget < 0 {
+		fmt.Fprintln(os.Stderr, "-budget must be >= 0")
 		return 1
 	}
-	if err := validateClaudePreflight(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+	if *maxLoops < 0 {
+		fmt.Fprintln(os.Stderr, "-max-loops must be >= 0")
+		return 1
+	}
+	if *stepName != "" && !*once {
+		fmt.Fprintln(os.Stderr, "-step requires -once")
 		return 1
 	}
+	if *waitOnLimit && *once {
+		fmt.Fprintln(os.Stderr, "-wait-on-limit cannot be used with -once")
+		return 1
+	}
+	if *plan && *jsonProgress {
+		fmt.Fprintln(os.Stderr, "-plan cannot be used with -json-progress")
+		return 1
+	}
+	if *confirmEachStep && !*plan {
+		switch {
+		case *jsonProgress:
+			fmt.Fprintln(os.Stderr, "-confirm-each-step cannot be used with -json-progress")
+			return 1
+		case runningInCI():
+			fmt.Fprintln(os.Stderr, "-confirm-each-step needs someone at the terminal; it can't be used in CI")
+			return 1
+		case !stdinIsTerminal():
+			fmt.Fprintln(os.Stderr, "-confirm-each-step needs an interactive terminal on stdin")
+			return 1
+		}
+	}
+	if *logFormat != "text" && *logFormat != "json" {
+		fmt.Fprintf(os.Stderr, "-log-format must be text or json, got %q\n", *logFormat)
+		return 1
+	}
+	if *envOverride && *envFile == "" {
+		fmt.Fprintln(os.Stderr, "-env-override requires -env-file")
+		return 1
+	}
+
+	var progress *status.JSONWriter
+	out := io.Writer(os.Stdout)
+	if *jsonProgress {
+		// Progress records own stdout so a wrapper can parse it line by
+		// line; everything written for people goes to stderr instead.
+		progress = status.NewJSONWithWriter(os.Stdout)
+		out = os.Stderr
+	}
 
+	// Loaded before anything reads the config so ${VAR} expansion, hooks
+	// and steps all see the variables.
+	if *envFile != "" {
+		set, kept, err := config.LoadDotenv(*envFile, *envOverride)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load -env-file: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(out, "Loaded %d variable(s) from %s", len(set), *envFile)
+		if len(kept) > 0 {
+			fmt.Fprintf(out, " (kept %d already set: %s; use -env-override to replace them)", len(kept), strings.Join(kept, ", "))
+		}
+		fmt.Fprintln(out)
+	}
+
+	if err := validateRunPreflight(*configFile); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
 	hasTasks, allComplete, err := agent.CheckPRDTasks(".ralph/prd.json")
 	if err != nil {
 		if errors.Is(err, agent.ErrPRDNoTasks) {
@@ -57,81 +144,158 @@ func runCmd(args []string) int {
 		fmt.Fprintln(os.Stderr, ".ralph/prd.json contains no tasks. Add tasks (e.g. via `ralph add`) and re-run.")
 		return 1
 	}
-	if allComplete {
-		fmt.Println("All tasks are complete!")
-		fmt.Println("\nTo add more work:")
-		fmt.Println("  ralph add work.md")
-		fmt.Println("  ralph add \"your task description\"")
+	for _, w := range agent.LoadPRDWarnings(".ralph/prd.json") {
+		fmt.Fprintf(os.Stderr, "Warning: .ralph/prd.json: %s\n", w)
+	}
+	if allComplete && *stepName == "" && !*plan {
+		fmt.Fprintln(out, "All tasks are complete!")
+		fmt.Fprintln(out, "\nTo add more work:")
+		fmt.Fprintln(out, "  ralph add work.md")
+		fmt.Fprintln(out, "  ralph add \"your task description\"")
 		return 0
 	}
 
-	loopLogger := logger.NewNoopLogger()
-
-	registry := loop.NewStepRegistry()
-	registry.Register("command", func() loop.Step { return steps.NewCommandStep() })
-	registry.Register("noop", func() loop.Step { return steps.NewNoopStep() })
-	registry.Register("readme-check", func() loop.Step { return steps.NewReadmeCheckStep() })
-	registry.Register("agent", func() loop.Step { return steps.NewAgentStep() })
-	registry.Register("git-commit", func() loop.Step { return steps.NewGitCommitStep() })
+	registry := newStepRegistry()
+	if progress != nil {
+		registry.Register("agent", func() loop.Step {
+			s := steps.NewAgentStep()
+			s.DisableStatusRefresh()
+			return s
+		})
+		registry.Register("readme-check", func() loop.Step { return steps.NewReadmeCheckStepWithWriter(out) })
+	}
 
 	loader := config.NewLoader(".ralph")
+	loader.SetStepValidator(registry.ValidateStepConfig)
+	loader.SetConditionValidator(loop.ValidateCondition)
 	cfg, err := loader.LoadAndValidate(*configFile, registry.RegisteredTypes())
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
 
-	if strings.TrimSpace(*model) != "" {
-		for i := range cfg.Steps {
-			if cfg.Steps[i].Type != "agent" {
-				continue
-			}
-			var stepCfgMap map[string]any
-			if len(cfg.Steps[i].Config) > 0 {
-				if err := json.Unmarshal(cfg.Steps[i].Config, &stepCfgMap); err != nil {
-					fmt.Fprintf(os.Stderr, "Failed to parse agent step config for %s: %v\n", cfg.Steps[i].Name, err)
-					return 1
-				}
-			}
-			if stepCfgMap == nil {
-				stepCfgMap = map[string]any{}
-			}
-			stepCfgMap["model"] = strings.TrimSpace(*model)
-			b, err := json.Marshal(stepCfgMap)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to serialize agent step config for %s: %v\n", cfg.Steps[i].Name, err)
+	// -plan never calls an agent, so it works before the agent CLI is set up.
+	if !*plan {
+		agents, err := configuredAgents(cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, a := range agents {
+			if err := a.preflight(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
 				return 1
 			}
-			cfg.Steps[i].Config = b
 		}
 	}
 
-	if resetCount, err := agent.ResetFailedTasks(".ralph/prd.json"); err == nil && resetCount > 0 {
-		fmt.Printf("Reset %d failed task(s) to retry\n", resetCount)
+	if err := setAgentSessionNames(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if strings.TrimSpace(*model) != "" {
+		if err := setAgentStepOption(cfg, "model", strings.TrimSpace(*model)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if len(cfg.ModelAliases) > 0 {
+		if err := setAgentStepOption(cfg, "model_aliases", cfg.ModelAliases); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	budgetUSD := *budget
+	if budgetUSD == 0 {
+		budgetUSD = findBudgetFromConfig(cfg)
+	}
+
+	if *plan {
+		banner.New().Print(cfg)
+		prdStatus, _ := agent.LoadPRDStatus(".ralph/prd.json")
+		printRunPlan(out, runPlan{
+			Config:           cfg,
+			BudgetUSD:        budgetUSD,
+			MaxLoops:         *maxLoops,
+			Once:             *once,
+			PRD:              prdStatus,
+			Circuits:         loadCircuitSnapshots(filepath.Join(".ralph", "circuit_state.json")),
+			NoResetFailed:    *noResetFailed,
+			ResetFailedAfter: cfg.GetResetFailedAfter(),
+		})
+		return 0
+	}
+
+	if *stepName == "" && !*noResetFailed {
+		if resetCount, err := agent.ResetFailedTasks(".ralph/prd.json", cfg.GetResetFailedAfter()); err == nil && resetCount > 0 {
+			fmt.Fprintf(out, "Reset %d failed task(s) to retry\n", resetCount)
+		}
 	}
 
-	b := banner.New()
-	b.Print(cfg)
+	if progress == nil {
+		b := banner.New()
+		b.Print(cfg)
+	}
+
+	loopLogger, closeLog, err := newRunLogger(*logFormat, filepath.Join(".ralph", "logs"), cfg.Log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Run log disabled: %v\n", err)
+		loopLogger, closeLog = logger.NewNoopLogger(), func() {}
+	}
+	defer closeLog()
+	if !*noRedact {
+		loopLogger = logger.NewRedactingLogger(loopLogger)
+	}
 
 	mainLoop := loop.NewLoop(cfg, registry, loopLogger)
 	mainLoop.SetPRDPath(".ralph/prd.json")
+	mainLoop.SetOutput(out)
+	if progress != nil {
+		mainLoop.SetStatusDisplay(progress)
+	}
+	if cfg.StepDelay != "" {
+		mainLoop.SetStepDelay(cfg.GetStepDelay())
+	}
+
+	mainLoop.SetBudget(budgetUSD)
+	mainLoop.SetMaxLoops(*maxLoops)
 
 	trackerDir := ".ralph"
 	_ = os.MkdirAll(trackerDir, 0755)
 	trk := tracker.NewWriter(trackerDir)
 	runID := tracker.NewRunID()
-	releaseLock, err := trk.AcquireLock(runID)
+	releaseLock, err := acquireRunLock(trk, runID, *forceLock)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, describeLockError(err, trk, time.Now()))
 		return 1
 	}
 	defer func() { _ = releaseLock() }()
+
+	// A single step is a debugging aid: no run tracking, metrics or history.
+	if *stepName != "" {
+		return runSingleStep(mainLoop, *stepName, out)
+	}
 	mainLoop.EnableRunTracking(runID, trackerDir)
+	if *prometheus {
+		mainLoop.EnablePrometheus(resolveModel(cfg, *model))
+	}
+
+	circuitStatePath := filepath.Join(trackerDir, "circuit_state.json")
+	if err := mainLoop.RestoreCircuitState(circuitStatePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Ignoring %s: %v\n", circuitStatePath, err)
+	}
+	defer func() { _ = mainLoop.SaveCircuitState(circuitStatePath) }()
+	base := captureRunBaseline(trk, ".ralph/prd.json")
 	_, _ = trk.LoadOrInitMetrics(runID)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if *confirmEachStep {
+		mainLoop.SetStepConfirmer(newStepPrompter(os.Stdin, out, cancel))
+	}
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
@@ -140,18 +304,121 @@ func runCmd(args []string) int {
 		cancel()
 	}()
 
+	if err := runPreRunHooks(ctx, cfg.PreRun, out); err != nil {
+		fmt.Fprintf(os.Stderr, "Aborting run: %v\n", err)
+		return 1
+	}
+	defer runPostRunHooks(cfg.PostRun, out)
+
 	if *once {
-		return runOnce(ctx, mainLoop, trk, runID, cfg, *model)
+		return runOnce(ctx, mainLoop, trk, runID, cfg, *model, base, out)
 	}
-	return runContinuous(ctx, mainLoop, trk, runID, cfg, *model)
+	return runContinuous(ctx, mainLoop, trk, runID, cfg, *model, base, *waitOnLimit, out)
+}
+
+// runSingleStep executes one configured step and reports its StepResult.
+func runSingleStep(mainLoop *loop.Loop, name string, out io.Writer) int {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	result, err := mainLoop.RunStep(ctx, name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	printStepResult(out, result)
+	if result.Success || isAgentExit(result.Error) {
+		return 0
+	}
+	return 1
+}
+
+func printStepResult(w io.Writer, r loop.StepResult) {
+	fmt.Fprintln(w)
+	switch {
+	case r.CircuitOpen:
+		fmt.Fprintf(w, "Step %s skipped: circuit open\n", r.StepName)
+	case r.Success:
+		fmt.Fprintf(w, "✓ Step %s succeeded in %s\n", r.StepName, r.Duration.Round(time.Millisecond))
+	case isAgentExit(r.Error):
+		fmt.Fprintf(w, "✓ Step %s signaled completion in %s (%v)\n", r.StepName, r.Duration.Round(time.Millisecond), r.Error)
+		return
+	default:
+		fmt.Fprintf(w, "✗ Step %s failed in %s\n", r.StepName, r.Duration.Round(time.Millisecond))
+	}
+	if r.RetryAttempt > 0 {
+		fmt.Fprintf(w, "  Retries: %d\n", r.RetryAttempt)
+	}
+	if r.Error != nil {
+		fmt.Fprintf(w, "  Error: %v\n", r.Error)
+	}
+	if out := strings.TrimSpace(r.Output); out != "" {
+		fmt.Fprintf(w, "  Output:\n%s\n", out)
+	}
+}
+
+func isAgentExit(err error) bool {
+	_, ok := steps.IsAgentExitError(err)
+	return ok
+}
+
+// newRunLogger opens the run log in dir: ralph.log for text, ralph.jsonl for
+// json. logCfg (optional) enables size-based rotation.
+func newRunLogger(format, dir string, logCfg *config.LogConfig) (logger.Logger, func(), error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, err
+	}
+	var rot logger.RotationConfig
+	if logCfg != nil {
+		rot = logger.RotationConfig{MaxSizeBytes: logCfg.MaxSizeBytes, MaxBackups: logCfg.MaxBackups}
+	}
+	if format == "json" {
+		l, err := logger.NewRotatingJSONLogger(filepath.Join(dir, "ralph.jsonl"), logger.LevelDebug, rot)
+		if err != nil {
+			return nil, nil, err
+		}
+		return l, func() { _ = l.Close() }, nil
+	}
+	l, err := logger.NewRotatingFileLogger(filepath.Join(dir, "ralph.log"), logger.LevelDebug, rot)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, func() { _ = l.Close() }, nil
+}
+
+// newStepRegistry returns a registry with every built-in step type.
+func newStepRegistry() *loop.StepRegistry {
+	registry := loop.NewStepRegistry()
+	registry.Register("command", func() loop.Step { return steps.NewCommandStep() })
+	// One shared noop instance, so fail_times counts across loop iterations.
+	noop := steps.NewNoopStep()
+	registry.Register("noop", func() loop.Step { return noop })
+	registry.Register("readme-check", func() loop.Step { return steps.NewReadmeCheckStep() })
+	registry.Register("agent", func() loop.Step { return steps.NewAgentStep() })
+	registry.Register("git-commit", func() loop.Step { return steps.NewGitCommitStep() })
+	registry.Register("http-check", func() loop.Step { return steps.NewHTTPCheckStep() })
+	registry.Register("test-run", func() loop.Step { return steps.NewTestRunStep() })
+	return registry
 }
 
-func runOnce(ctx context.Context, mainLoop *loop.Loop, trk *tracker.Writer, runID string, cfg *config.Config, modelOverride string) int {
-	if err := mainLoop.RunOnce(ctx); err != nil && err != context.Canceled {
+func runOnce(ctx context.Context, mainLoop *loop.Loop, trk *tracker.Writer, runID string, cfg *config.Config, modelOverride string, base runBaseline, out io.Writer) int {
+	err := mainLoop.RunOnce(ctx)
+	err = interruptedErr(ctx, err)
+	// Deferred first so it runs last: the webhook sees the metrics saved by
+	// MarkComplete or recordInterruptedRun.
+	defer notifyRunEnd(cfg, trk, runID, err, os.Stderr)
+	defer func() {
+		if errors.Is(err, context.Canceled) {
+			recordInterruptedRun(mainLoop, trk, runID, cfg, modelOverride)
+		}
+	}()
+	if err != nil && err != context.Canceled {
 		if _, ok := steps.IsAgentExitError(err); ok {
 			trk.MarkComplete(runID)
 			_ = writeResultJSON(trk, cfg, modelOverride)
-			printRunMetrics(trk)
+			_ = appendRunHistory(trk, runID, cfg, modelOverride, base)
+			printRunMetrics(out, trk)
+			openPullRequestOnComplete(cfg, out)
 			return 0
 		}
 		var usageErr *steps.ClaudeUsageError
@@ -167,12 +434,72 @@ func runOnce(ctx context.Context, mainLoop *loop.Loop, trk *tracker.Writer, runI
 	return 0
 }
 
-func runContinuous(ctx context.Context, mainLoop *loop.Loop, trk *tracker.Writer, runID string, cfg *config.Config, modelOverride string) int {
-	if err := mainLoop.Run(ctx); err != nil && err != context.Canceled {
+// Bounds for ralph run -wait-on-limit. The wait is capped so a misparsed
+// reset time can't stall a run for days; if the limit is still in place
+// afterwards the run simply waits again.
+const (
+	usageLimitMaxWait = 6 * time.Hour
+	usageLimitMargin  = time.Minute
+)
+
+func runContinuous(ctx context.Context, mainLoop *loop.Loop, trk *tracker.Writer, runID string, cfg *config.Config, modelOverride string, base runBaseline, waitOnLimit bool, out io.Writer) int {
+	err := mainLoop.Run(ctx)
+	for waitOnLimit && err != nil {
+		resetAt, wait, ok := usageLimitWait(err, time.Now())
+		if !ok {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "\n⏸️  Claude usage limit reached, resets %s. Waiting %s before resuming...\n",
+			resetAt.Local().Format("Jan 2 15:04 MST"), wait.Round(time.Second))
+		notifyUsageLimitWait(cfg, trk, runID, err, resetAt, os.Stderr)
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(wait):
+			fmt.Fprintln(os.Stderr, "Resuming run")
+			err = mainLoop.Run(ctx)
+		}
+	}
+	err = interruptedErr(ctx, err)
+	// Deferred first so it runs last: the webhook sees the metrics saved by
+	// MarkComplete or recordInterruptedRun.
+	defer notifyRunEnd(cfg, trk, runID, err, os.Stderr)
+	defer func() {
+		if errors.Is(err, context.Canceled) {
+			recordInterruptedRun(mainLoop, trk, runID, cfg, modelOverride)
+		}
+	}()
+	if err != nil && err != context.Canceled {
+		if exitErr, ok := steps.IsAgentExitError(err); ok && exitErr.Reason == agent.ExitReasonTasksBlocked {
+			fmt.Fprintln(os.Stderr, "\nAll remaining tasks depend on failed tasks, stopping.")
+			fmt.Fprintln(os.Stderr, "Re-run to retry them (failed tasks are reset to todo): ralph run")
+			printRunMetrics(out, trk)
+			return 1
+		}
 		if _, ok := steps.IsAgentExitError(err); ok {
 			trk.MarkComplete(runID)
 			_ = writeResultJSON(trk, cfg, modelOverride)
-			printRunMetrics(trk)
+			_ = appendRunHistory(trk, runID, cfg, modelOverride, base)
+			printRunMetrics(out, trk)
+			openPullRequestOnComplete(cfg, out)
+			return 0
+		}
+		var budgetErr *loop.BudgetExceededError
+		if errors.As(err, &budgetErr) {
+			fmt.Fprintf(out, "\nBudget of $%.2f reached, stopping\n", budgetErr.BudgetUSD)
+			trk.MarkComplete(runID)
+			_ = writeResultJSON(trk, cfg, modelOverride)
+			_ = appendRunHistory(trk, runID, cfg, modelOverride, base)
+			printRunMetrics(out, trk)
+			return 0
+		}
+		var maxLoopsErr *loop.MaxLoopsReachedError
+		if errors.As(err, &maxLoopsErr) {
+			fmt.Fprintf(out, "\nReached -max-loops limit of %d, stopping\n", maxLoopsErr.MaxLoops)
+			trk.MarkComplete(runID)
+			_ = writeResultJSON(trk, cfg, modelOverride)
+			_ = appendRunHistory(trk, runID, cfg, modelOverride, base)
+			printRunMetrics(out, trk)
 			return 0
 		}
 		var usageErr *steps.ClaudeUsageError
@@ -188,18 +515,42 @@ func runContinuous(ctx context.Context, mainLoop *loop.Loop, trk *tracker.Writer
 	return 0
 }
 
-func printRunMetrics(trk *tracker.Writer) {
+// interruptedErr reports a run whose context was cancelled as
+// context.Canceled, even when the step it cut short returned its own error
+// (a killed child process, say). A run that completed stays complete.
+func interruptedErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil && !isAgentExit(err) {
+		return context.Canceled
+	}
+	return err
+}
+
+// recordInterruptedRun persists a run stopped by SIGINT/SIGTERM or a quit at
+// a step confirmation: run_state.json says "interrupted" and the metrics so
+// far are saved to aggregate.json. The run is not marked complete or added to
+// the history, so ralph resume still picks it up.
+func recordInterruptedRun(mainLoop *loop.Loop, trk *tracker.Writer, runID string, cfg *config.Config, modelOverride string) {
+	mainLoop.MarkInterrupted()
+	_, _ = trk.LoadOrInitMetrics(runID)
+	_ = writeResultJSON(trk, cfg, modelOverride)
+	fmt.Fprintln(os.Stderr, "\nInterrupted. Continue with: ralph resume")
+}
+
+func printRunMetrics(w io.Writer, trk *tracker.Writer) {
 	if m, _ := trk.LoadMetrics(); m != nil {
 		end := time.Now()
 		if m.CompletedAt != nil {
 			end = *m.CompletedAt
 		}
 		elapsed := end.Sub(m.StartedAt)
-		fmt.Printf("\nRun complete in %s\n", elapsed.Round(time.Second))
-		fmt.Printf("Total Claude calls: %d\n", m.TotalClaudeCalls)
-		fmt.Printf("Total tokens: %d (in: %d, out: %d)\n", m.TotalTokens, m.InputTokens, m.OutputTokens)
+		fmt.Fprintf(w, "\nRun complete in %s\n", elapsed.Round(time.Second))
+		fmt.Fprintf(w, "Total Claude calls: %d\n", m.TotalClaudeCalls)
+		fmt.Fprintf(w, "Total tokens: %d (in: %d, out: %d)\n", m.TotalTokens, m.InputTokens, m.OutputTokens)
+		if m.CacheCreationTokens > 0 || m.CacheReadTokens > 0 {
+			fmt.Fprintf(w, "Cache tokens: %d written, %d read (included in input)\n", m.CacheCreationTokens, m.CacheReadTokens)
+		}
 		if m.TotalCostUSD > 0 {
-			fmt.Printf("Estimated cost: $%.2f\n", m.TotalCostUSD)
+			fmt.Fprintf(w, "Estimated cost: $%.2f\n", m.TotalCostUSD)
 		}
 	}
 }
@@ -226,6 +577,17 @@ func printRunInstructions() {
 	fmt.Println("  See README.md for how to run/test the app")
 }
 
+// defaultConfigPath returns .ralph/config.json, or a YAML equivalent if only that exists.
+func defaultConfigPath() string {
+	candidates := []string{".ralph/config.json", ".ralph/config.yaml", ".ralph/config.yml"}
+	for _, p := range candidates {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return candidates[0]
+}
+
 func validateRunPreflight(configFile string) error {
 	required := []string{
 		".ralph/prd.json",
@@ -271,16 +633,18 @@ type runResult struct {
 }
 
 type runResultMetrics struct {
-	StartedAt        time.Time  `json:"started_at"`
-	UpdatedAt        time.Time  `json:"updated_at"`
-	CompletedAt      *time.Time `json:"completed_at,omitempty"`
-	TotalClaudeCalls int        `json:"total_claude_calls"`
-	InputTokens      int        `json:"input_tokens"`
-	OutputTokens     int        `json:"output_tokens"`
-	TotalTokens      int        `json:"total_tokens"`
-	TotalCostUSD     float64    `json:"total_cost_usd,omitempty"`
-	LastRunID        string     `json:"last_run_id,omitempty"`
-	ElapsedSec       int64      `json:"elapsed_sec,omitempty"`
+	StartedAt           time.Time  `json:"started_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	CompletedAt         *time.Time `json:"completed_at,omitempty"`
+	TotalClaudeCalls    int        `json:"total_claude_calls"`
+	InputTokens         int        `json:"input_tokens"`
+	OutputTokens        int        `json:"output_tokens"`
+	TotalTokens         int        `json:"total_tokens"`
+	CacheCreationTokens int        `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadTokens     int        `json:"cache_read_input_tokens,omitempty"`
+	TotalCostUSD        float64    `json:"total_cost_usd,omitempty"`
+	LastRunID           string     `json:"last_run_id,omitempty"`
+	ElapsedSec          int64      `json:"elapsed_sec,omitempty"`
 }
 
 func writeResultJSON(trk *tracker.Writer, cfg *config.Config, modelOverride string) error {
@@ -292,13 +656,7 @@ func writeResultJSON(trk *tracker.Writer, cfg *config.Config, modelOverride stri
 		return nil
 	}
 
-	model := strings.TrimSpace(modelOverride)
-	if model == "" {
-		model = strings.TrimSpace(findClaudeModelFromConfig(cfg))
-	}
-	if model == "" {
-		model = "sonnet"
-	}
+	model := resolveModel(cfg, modelOverride)
 
 	end := time.Now()
 	if m.CompletedAt != nil {
@@ -311,16 +669,18 @@ func writeResultJSON(trk *tracker.Writer, cfg *config.Config, modelOverride stri
 		Version: versionLine(),
 		Model:   model,
 		Metrics: runResultMetrics{
-			StartedAt:        m.StartedAt,
-			UpdatedAt:        m.UpdatedAt,
-			CompletedAt:      m.CompletedAt,
-			TotalClaudeCalls: m.TotalClaudeCalls,
-			InputTokens:      m.InputTokens,
-			OutputTokens:     m.OutputTokens,
-			TotalTokens:      m.TotalTokens,
-			TotalCostUSD:     m.TotalCostUSD,
-			LastRunID:        m.LastRunID,
-			ElapsedSec:       elapsedSec,
+			StartedAt:           m.StartedAt,
+			UpdatedAt:           m.UpdatedAt,
+			CompletedAt:         m.CompletedAt,
+			TotalClaudeCalls:    m.TotalClaudeCalls,
+			InputTokens:         m.InputTokens,
+			OutputTokens:        m.OutputTokens,
+			TotalTokens:         m.TotalTokens,
+			CacheCreationTokens: m.CacheCreationTokens,
+			CacheReadTokens:     m.CacheReadTokens,
+			TotalCostUSD:        m.TotalCostUSD,
+			LastRunID:           m.LastRunID,
+			ElapsedSec:          elapsedSec,
 		},
 	}
 
@@ -331,6 +691,163 @@ func writeResultJSON(trk *tracker.Writer, cfg *config.Config, modelOverride stri
 	return writeFileAtomic(".ralph/aggregate.json", data, 0644)
 }
 
+// runBaseline captures cumulative metrics and task progress when a run starts,
+// so the history record can report what this run alone contributed.
+type runBaseline struct {
+	StartedAt      time.Time
+	Metrics        tracker.RunMetrics
+	CompletedTasks int
+}
+
+func captureRunBaseline(trk *tracker.Writer, prdPath string) runBaseline {
+	base := runBaseline{StartedAt: time.Now()}
+	if m, err := trk.LoadMetrics(); err == nil && m != nil {
+		base.Metrics = *m
+	}
+	if st, err := agent.LoadPRDStatus(prdPath); err == nil && st != nil {
+		base.CompletedTasks = st.CompletedTasks
+	}
+	return base
+}
+
+// appendRunHistory records this run's totals in .ralph/metrics_history.jsonl.
+func appendRunHistory(trk *tracker.Writer, runID string, cfg *config.Config, modelOverride string, base runBaseline) error {
+	m, err := trk.LoadMetrics()
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		return nil
+	}
+
+	rec := tracker.HistoryRecord{
+		RunID:        runID,
+		CompletedAt:  time.Now(),
+		Model:        resolveModel(cfg, modelOverride),
+		InputTokens:  m.InputTokens - base.Metrics.InputTokens,
+		OutputTokens: m.OutputTokens - base.Metrics.OutputTokens,
+		TotalTokens:  m.TotalTokens - base.Metrics.TotalTokens,
+		CostUSD:      m.TotalCostUSD - base.Metrics.TotalCostUSD,
+	}
+	rec.ElapsedSec = int64(rec.CompletedAt.Sub(base.StartedAt).Round(time.Second) / time.Second)
+	if rs, err := trk.LoadRunState(); err == nil && rs != nil && rs.RunID == runID {
+		rec.Loops = rs.LoopNumber
+	}
+	if st, err := agent.LoadPRDStatus(".ralph/prd.json"); err == nil && st != nil {
+		if done := st.CompletedTasks - base.CompletedTasks; done > 0 {
+			rec.TasksCompleted = done
+		}
+	}
+	return trk.AppendHistory(rec)
+}
+
+// setAgentStepOption sets key to value in the config of every agent step.
+func setAgentStepOption(cfg *config.Config, key string, value any) error {
+	for i := range cfg.Steps {
+		if cfg.Steps[i].Type != "agent" {
+			continue
+		}
+		var stepCfgMap map[string]any
+		if len(cfg.Steps[i].Config) > 0 {
+			if err := json.Unmarshal(cfg.Steps[i].Config, &stepCfgMap); err != nil {
+				return fmt.Errorf("Failed to parse agent step config for %s: %v", cfg.Steps[i].Name, err)
+			}
+		}
+		if stepCfgMap == nil {
+			stepCfgMap = map[string]any{}
+		}
+		stepCfgMap[key] = value
+		b, err := json.Marshal(stepCfgMap)
+		if err != nil {
+			return fmt.Errorf("Failed to serialize agent step config for %s: %v", cfg.Steps[i].Name, err)
+		}
+		cfg.Steps[i].Config = b
+	}
+	return nil
+}
+
+// setAgentSessionNames gives each agent step its own session, keyed by step
+// name, when the config has more than one agent step. Steps that set
+// session_name or session_file themselves keep them. A single agent step
+// keeps the shared .ralph/.ralph_session file.
+func setAgentSessionNames(cfg *config.Config) error {
+	agents := 0
+	for _, s := range cfg.Steps {
+		if s.Type == "agent" {
+			agents++
+		}
+	}
+	if agents < 2 {
+		return nil
+	}
+	for i := range cfg.Steps {
+		if cfg.Steps[i].Type != "agent" {
+			continue
+		}
+		var stepCfgMap map[string]any
+		if len(cfg.Steps[i].Config) > 0 {
+			if err := json.Unmarshal(cfg.Steps[i].Config, &stepCfgMap); err != nil {
+				return fmt.Errorf("Failed to parse agent step config for %s: %v", cfg.Steps[i].Name, err)
+			}
+		}
+		if stepCfgMap == nil {
+			stepCfgMap = map[string]any{}
+		}
+		_, hasName := stepCfgMap["session_name"]
+		_, hasFile := stepCfgMap["session_file"]
+		if hasName || hasFile {
+			continue
+		}
+		stepCfgMap["session_name"] = cfg.Steps[i].Name
+		b, err := json.Marshal(stepCfgMap)
+		if err != nil {
+			return fmt.Errorf("Failed to serialize agent step config for %s: %v", cfg.Steps[i].Name, err)
+		}
+		cfg.Steps[i].Config = b
+	}
+	return nil
+}
+
+// usageLimitWait reports how long to sleep when err is a Claude usage limit
+// with a parseable reset time. ok is false for any other error, or when the
+// reset time is unknown and the run should exit as before.
+func usageLimitWait(err error, now time.Time) (resetAt time.Time, wait time.Duration, ok bool) {
+	var usageErr *steps.ClaudeUsageError
+	if !errors.As(err, &usageErr) {
+		return time.Time{}, 0, false
+	}
+	resetAt, ok = usageErr.ResetAt(now)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	wait = resetAt.Sub(now) + usageLimitMargin
+	if wait < usageLimitMargin {
+		wait = usageLimitMargin
+	}
+	if wait > usageLimitMaxWait {
+		wait = usageLimitMaxWait
+	}
+	return resetAt, wait, true
+}
+
+// resolveModel returns the model a run used: the -model override, the agent
+// step's configured model, or the "sonnet" default, with aliases resolved to
+// full model IDs.
+func resolveModel(cfg *config.Config, modelOverride string) string {
+	model := strings.TrimSpace(modelOverride)
+	if model == "" {
+		model = strings.TrimSpace(findClaudeModelFromConfig(cfg))
+	}
+	if model == "" {
+		model = "sonnet"
+	}
+	var aliases map[string]string
+	if cfg != nil {
+		aliases = cfg.ModelAliases
+	}
+	return config.ResolveModel(model, aliases)
+}
+
 func findClaudeModelFromConfig(cfg *config.Config) string {
 	if cfg == nil {
 		return ""
@@ -380,3 +897,23 @@ func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
 	}
 	return os.Rename(tmp, path)
 }
+
+// findBudgetFromConfig returns the first positive budget_usd set on an agent step.
+func findBudgetFromConfig(cfg *config.Config) float64 {
+	if cfg == nil {
+		return 0
+	}
+	for _, s := range cfg.Steps {
+		if s.Type != "agent" || len(s.Config) == 0 {
+			continue
+		}
+		var ac steps.AgentConfig
+		if err := json.Unmarshal(s.Config, &ac); err != nil {
+			continue
+		}
+		if ac.BudgetUSD > 0 {
+			return ac.BudgetUSD
+		}
+	}
+	return 0
+}
@@ -1,9 +1,23 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/logger"
+	"github.com/chr1sbest/wiggum/internal/loop"
+	"github.com/chr1sbest/wiggum/internal/loop/steps"
+	"github.com/chr1sbest/wiggum/internal/status"
+	"github.com/chr1sbest/wiggum/internal/tracker"
 )
 
 func TestValidateRunPreflight(t *testing.T) {
@@ -59,3 +73,556 @@ func TestMustGetwd(t *testing.T) {
 		t.Error("mustGetwd() returned empty string")
 	}
 }
+
+func TestFindBudgetFromConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		want float64
+	}{
+		{"nil config", nil, 0},
+		{"no budget", &config.Config{Steps: []config.StepConfig{
+			{Type: "agent", Config: json.RawMessage(`{"model": "sonnet"}`)},
+		}}, 0},
+		{"agent budget", &config.Config{Steps: []config.StepConfig{
+			{Type: "command", Config: json.RawMessage(`{"budget_usd": 3}`)},
+			{Type: "agent", Config: json.RawMessage(`{"budget_usd": 12.5}`)},
+		}}, 12.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findBudgetFromConfig(tt.cfg); got != tt.want {
+				t.Errorf("findBudgetFromConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetAgentSessionNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []config.StepConfig
+		want  map[string]string // step name -> session_name ("" = unset)
+	}{
+		{
+			name: "single agent keeps the shared session",
+			steps: []config.StepConfig{
+				{Type: "agent", Name: "agent", Config: json.RawMessage(`{"model":"sonnet"}`)},
+				{Type: "command", Name: "build", Config: json.RawMessage(`{"command":"make"}`)},
+			},
+			want: map[string]string{"agent": ""},
+		},
+		{
+			name: "two agents get sessions named after their steps",
+			steps: []config.StepConfig{
+				{Type: "agent", Name: "planner", Config: json.RawMessage(`{"prompt_file":"PLAN.md"}`)},
+				{Type: "agent", Name: "coder"},
+			},
+			want: map[string]string{"planner": "planner", "coder": "coder"},
+		},
+		{
+			name: "explicit session settings are kept",
+			steps: []config.StepConfig{
+				{Type: "agent", Name: "planner", Config: json.RawMessage(`{"session_name":"plan"}`)},
+				{Type: "agent", Name: "coder", Config: json.RawMessage(`{"session_file":".ralph/coder_session"}`)},
+				{Type: "agent", Name: "reviewer"},
+			},
+			want: map[string]string{"planner": "plan", "coder": "", "reviewer": "reviewer"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Steps: tt.steps}
+			if err := setAgentSessionNames(cfg); err != nil {
+				t.Fatalf("setAgentSessionNames: %v", err)
+			}
+			for _, s := range cfg.Steps {
+				want, ok := tt.want[s.Name]
+				if !ok {
+					continue
+				}
+				var ac steps.AgentConfig
+				if len(s.Config) > 0 {
+					if err := json.Unmarshal(s.Config, &ac); err != nil {
+						t.Fatalf("%s config: %v", s.Name, err)
+					}
+				}
+				if ac.SessionName != want {
+					t.Errorf("%s session_name = %q, want %q", s.Name, ac.SessionName, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintStepResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result loop.StepResult
+		want   []string
+	}{
+		{
+			name:   "success",
+			result: loop.StepResult{StepName: "readme-check", Success: true, Duration: 1500 * time.Millisecond},
+			want:   []string{"✓ Step readme-check succeeded in 1.5s"},
+		},
+		{
+			name:   "failure with retries",
+			result: loop.StepResult{StepName: "git-commit", Error: errors.New("git not found in PATH"), RetryAttempt: 2},
+			want:   []string{"✗ Step git-commit failed", "Retries: 2", "Error: git not found in PATH"},
+		},
+		{
+			name:   "agent exit",
+			result: loop.StepResult{StepName: "agent", Error: &steps.AgentExitError{Reason: "plan_complete"}},
+			want:   []string{"✓ Step agent signaled completion"},
+		},
+		{
+			name:   "circuit open",
+			result: loop.StepResult{StepName: "agent", CircuitOpen: true},
+			want:   []string{"Step agent skipped: circuit open"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printStepResult(&buf, tt.result)
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunJSONProgressStream(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte("#!/bin/sh\necho 1.0.0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	files := map[string]string{
+		".ralph/prd.json":                `{"version":1,"tasks":[{"id":"T001","title":"Do it","status":"todo"},{"id":"T002","title":"Retry","status":"failed"}]}`,
+		".ralph/requirements.md":         "# Requirements",
+		".ralph/prompts/SETUP_PROMPT.md": "Setup prompt",
+		".ralph/prompts/LOOP_PROMPT.md":  "Loop prompt",
+		".ralph/config.json":             `{"name":"noop","steps":[{"type":"noop","name":"first"},{"type":"noop","name":"second"}]}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	errOut, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer errOut.Close()
+	realStdout, realStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = out, errOut
+	code := runCmd([]string{"-once", "-json-progress"})
+	os.Stdout, os.Stderr = realStdout, realStderr
+	if code != 0 {
+		t.Fatalf("runCmd() = %d, want 0", code)
+	}
+	if human, _ := os.ReadFile(errOut.Name()); !strings.Contains(string(human), "Reset 1 failed task(s) to retry") {
+		t.Errorf("human output should go to stderr, got:\n%s", human)
+	}
+
+	data, err := os.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	type record struct {
+		Loop      int    `json:"loop"`
+		StepIndex int    `json:"step_index"`
+		StepTotal int    `json:"step_total"`
+		Step      string `json:"step"`
+		State     string `json:"state"`
+	}
+	want := []record{
+		{Loop: 1, StepIndex: 1, StepTotal: 2, Step: "first", State: "running"},
+		{Loop: 1, StepIndex: 2, StepTotal: 2, Step: "second", State: "running"},
+		{Loop: 1, StepTotal: 2, State: "complete"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d stdout lines, want %d:\n%s", len(lines), len(want), data)
+	}
+	for i, line := range lines {
+		var got record
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not JSON: %v\n%s", i+1, err, line)
+		}
+		if got != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i+1, got, want[i])
+		}
+	}
+}
+
+func TestRunPreflightChecksConfiguredAgent(t *testing.T) {
+	tests := []struct {
+		name     string
+		binaries []string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "aider on PATH without claude", binaries: []string{"aider"}, wantCode: 0},
+		{name: "aider missing", binaries: []string{"claude"}, wantCode: 1, wantErr: "`aider` was not found in PATH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+
+			binDir := filepath.Join(dir, "bin")
+			if err := os.MkdirAll(binDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			for _, b := range tt.binaries {
+				if err := os.WriteFile(filepath.Join(binDir, b), []byte("#!/bin/sh\necho 1.0.0\n"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("PATH", binDir)
+
+			files := map[string]string{
+				".ralph/prd.json":                `{"version":1,"tasks":[{"id":"T001","title":"Do it","status":"todo"}]}`,
+				".ralph/requirements.md":         "# Requirements",
+				".ralph/prompts/SETUP_PROMPT.md": "Setup prompt",
+				".ralph/prompts/LOOP_PROMPT.md":  "Loop prompt",
+				".ralph/config.json": `{"name":"aider","steps":[{"type":"agent","name":"work","config":{
+					"agent_type":"aider","prompt_file":".ralph/prompts/LOOP_PROMPT.md","prd_file":".ralph/prd.json","log_dir":""}}]}`,
+			}
+			for path, content := range files {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			errOut, err := os.Create(filepath.Join(dir, "stderr"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer errOut.Close()
+			realStderr := os.Stderr
+			os.Stderr = errOut
+			code := runCmd([]string{"-once"})
+			os.Stderr = realStderr
+			stderr, _ := os.ReadFile(errOut.Name())
+
+			if code != tt.wantCode {
+				t.Fatalf("runCmd(-once) = %d, want %d\nstderr:\n%s", code, tt.wantCode, stderr)
+			}
+			if tt.wantErr != "" && !strings.Contains(string(stderr), tt.wantErr) {
+				t.Errorf("stderr = %q, want it to mention %q", stderr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunPlanListsEnabledStepsInOrder(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	// No claude on PATH: -plan must not need it.
+	t.Setenv("PATH", filepath.Join(dir, "bin"))
+
+	files := map[string]string{
+		".ralph/prd.json":                `{"version":1,"tasks":[{"id":"T001","title":"Schema","status":"done"},{"id":"T002","title":"API","status":"todo"}]}`,
+		".ralph/requirements.md":         "# Requirements",
+		".ralph/prompts/SETUP_PROMPT.md": "Setup prompt",
+		".ralph/prompts/LOOP_PROMPT.md":  "Loop prompt",
+		".ralph/config.json": `{"name":"plan","steps":[
+			{"type":"noop","name":"verify","depends_on":["build"],"timeout":"2m"},
+			{"type":"noop","name":"lint","enabled":false},
+			{"type":"noop","name":"setup"},
+			{"type":"noop","name":"build","max_retries":2,"circuit_breaker":{"threshold":3,"reset_after":"1m"}}]}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	realStdout := os.Stdout
+	os.Stdout = out
+	code := runCmd([]string{"-plan"})
+	os.Stdout = realStdout
+	if code != 0 {
+		t.Fatalf("runCmd(-plan) = %d, want 0", code)
+	}
+
+	data, err := os.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+
+	// depends_on moves verify after build; array order breaks ties.
+	wantOrder := []string{"1. setup (noop)", "2. build (noop)", "3. verify (noop)"}
+	last := -1
+	for _, want := range wantOrder {
+		i := strings.Index(got, want)
+		if i < 0 {
+			t.Fatalf("plan missing %q:\n%s", want, got)
+		}
+		if i < last {
+			t.Errorf("%q listed out of order:\n%s", want, got)
+		}
+		last = i
+	}
+	for _, want := range []string{
+		"Execution plan (3 of 4 steps enabled)",
+		"retries: 2 (backoff 1s, max 30s) · circuit breaker: 3 failures, reset after 1m0s",
+		"timeout: 2m0s",
+		"Disabled: lint",
+		"Progress: 1/2 done",
+		"Next: T002 API",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("plan missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "lint (noop)") {
+		t.Errorf("disabled step listed as a plan step:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(".ralph", "events.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("-plan should not start a run, events.jsonl stat err = %v", err)
+	}
+}
+
+func TestUsageLimitWait(t *testing.T) {
+	now := time.Date(2026, 3, 10, 13, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		err      error
+		wantOK   bool
+		wantWait time.Duration
+	}{
+		{"reset in two hours", &steps.ClaudeUsageError{Details: "limit reached ∙ resets 3pm"}, true, 2*time.Hour + usageLimitMargin},
+		{"wrapped", errors.Join(errors.New("step agent"), &steps.ClaudeUsageError{Details: "resets 1:30pm"}), true, 30*time.Minute + usageLimitMargin},
+		{"capped", &steps.ClaudeUsageError{Details: "weekly limit ∙ resets Mar 14, 9am"}, true, usageLimitMaxWait},
+		{"reset already passed", &steps.ClaudeUsageError{Details: "usage limit reached|1000000000"}, true, usageLimitMargin},
+		{"no reset time", &steps.ClaudeUsageError{Details: "You are out of extra usage"}, false, 0},
+		{"other error", errors.New("exit code 1"), false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, wait, ok := usageLimitWait(tt.err, now)
+			if ok != tt.wantOK || wait != tt.wantWait {
+				t.Errorf("usageLimitWait() = %s, %v; want %s, %v", wait, ok, tt.wantWait, tt.wantOK)
+			}
+		})
+	}
+}
+
+// cancelStep cancels the run from inside a step, as a signal arriving
+// mid-loop would, and fails the way a killed child process does.
+type cancelStep struct{ cancel context.CancelFunc }
+
+func (s *cancelStep) Name() string { return "cancel" }
+func (s *cancelStep) Type() string { return "cancel" }
+func (s *cancelStep) Execute(ctx context.Context, _ json.RawMessage) error {
+	s.cancel()
+	<-ctx.Done()
+	return errors.New("signal: killed")
+}
+
+func TestRunContinuousPersistsInterruptedState(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(".ralph", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &config.Config{Name: "interrupt", Steps: []config.StepConfig{{Type: "cancel", Name: "work"}}}
+	registry := loop.NewStepRegistry()
+	registry.Register("cancel", func() loop.Step { return &cancelStep{cancel: cancel} })
+	mainLoop := loop.NewLoop(cfg, registry, logger.NewNoopLogger())
+	mainLoop.SetStepDelay(0)
+	mainLoop.SetStatusDisplay(status.NewWithWriter(io.Discard))
+	mainLoop.EnableRunTracking("run-1", ".ralph")
+
+	trk := tracker.NewWriter(".ralph")
+	trk.AddUsage("run-1", tracker.UsageDelta{TotalTokens: 100, CostUSD: 0.25})
+
+	if code := runContinuous(ctx, mainLoop, trk, "run-1", cfg, "", runBaseline{}, false, io.Discard); code != 0 {
+		t.Fatalf("runContinuous() = %d, want 0", code)
+	}
+
+	rs, err := trk.LoadRunState()
+	if err != nil || rs == nil {
+		t.Fatalf("LoadRunState() = %v, %v", rs, err)
+	}
+	if rs.Status != "interrupted" {
+		t.Errorf("run state status = %q, want interrupted", rs.Status)
+	}
+	if mainLoop.State().Status != loop.StatusInterrupted {
+		t.Errorf("loop status = %s, want %s", mainLoop.State().Status, loop.StatusInterrupted)
+	}
+
+	data, err := os.ReadFile(".ralph/aggregate.json")
+	if err != nil {
+		t.Fatalf("aggregate.json not written: %v", err)
+	}
+	var agg runResult
+	if err := json.Unmarshal(data, &agg); err != nil {
+		t.Fatal(err)
+	}
+	if agg.Metrics.TotalTokens != 100 || agg.Metrics.TotalCostUSD != 0.25 {
+		t.Errorf("aggregate metrics = %+v, want 100 tokens and $0.25", agg.Metrics)
+	}
+	if agg.Metrics.CompletedAt != nil {
+		t.Error("interrupted run should not be marked complete")
+	}
+	if history, _ := trk.LoadHistory(); len(history) != 0 {
+		t.Errorf("interrupted run added %d history record(s), want none", len(history))
+	}
+}
+
+func TestRunNoResetFailedKeepsFailedTasks(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		want   string
+	}{
+		{"default resets", []string{"-once"}, `{"name":"noop","steps":[{"type":"noop","name":"first"}]}`, "todo"},
+		{"flag keeps failed", []string{"-once", "-no-reset-failed"}, `{"name":"noop","steps":[{"type":"noop","name":"first"}]}`, "failed"},
+		{"recent failure kept by reset_failed_after", []string{"-once"}, `{"name":"noop","reset_failed_after":"24h","steps":[{"type":"noop","name":"first"}]}`, "failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+
+			binDir := filepath.Join(dir, "bin")
+			if err := os.MkdirAll(binDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte("#!/bin/sh\necho 1.0.0\n"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+			failedAt := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+			files := map[string]string{
+				".ralph/prd.json":                `{"version":1,"tasks":[{"id":"T001","title":"Impossible","status":"failed","failed_at":"` + failedAt + `"},{"id":"T002","title":"Do it","status":"todo"}]}`,
+				".ralph/requirements.md":         "# Requirements",
+				".ralph/prompts/SETUP_PROMPT.md": "Setup prompt",
+				".ralph/prompts/LOOP_PROMPT.md":  "Loop prompt",
+				".ralph/config.json":             tt.config,
+			}
+			for path, content := range files {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if code := runCmd(append(tt.args, "-json-progress")); code != 0 {
+				t.Fatalf("runCmd() = %d, want 0", code)
+			}
+
+			prd, err := loadPRDFile(".ralph/prd.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := prd.Tasks[0].Status; got != tt.want {
+				t.Errorf("T001 status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunEnvFile(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"keeps existing variables", []string{"-env-file", "secrets.env"}, "from-file from-env"},
+		{"override replaces them", []string{"-env-file", "secrets.env", "-env-override"}, "from-file from-file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+
+			binDir := filepath.Join(dir, "bin")
+			if err := os.MkdirAll(binDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(binDir, "claude"), []byte("#!/bin/sh\necho 1.0.0\n"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+			t.Setenv("RALPH_ENV_NEW", "")
+			os.Unsetenv("RALPH_ENV_NEW")
+			t.Setenv("RALPH_ENV_SET", "from-env")
+
+			files := map[string]string{
+				"secrets.env":                    "RALPH_ENV_NEW=from-file\nRALPH_ENV_SET=from-file\n",
+				".ralph/prd.json":                `{"version":1,"tasks":[{"id":"T001","title":"Do it","status":"todo"}]}`,
+				".ralph/requirements.md":         "# Requirements",
+				".ralph/prompts/SETUP_PROMPT.md": "Setup prompt",
+				".ralph/prompts/LOOP_PROMPT.md":  "Loop prompt",
+				".ralph/config.json":             `{"name":"noop","pre_run":["echo ${RALPH_ENV_NEW} $RALPH_ENV_SET > out.txt"],"steps":[{"type":"noop","name":"first"}]}`,
+			}
+			for path, content := range files {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if code := runCmd(append(tt.args, "-once", "-json-progress")); code != 0 {
+				t.Fatalf("runCmd() = %d, want 0", code)
+			}
+			out, err := os.ReadFile("out.txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(out)); got != tt.want {
+				t.Errorf("hook saw %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
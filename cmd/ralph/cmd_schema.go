@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+)
+
+func printSchemaUsage() {
+	fmt.Print(`schema 📐  Print JSON Schemas for Ralph's files
+
+Usage:
+  ralph schema prd
+
+Subcommands:
+  prd   JSON Schema for .ralph/prd.json
+
+Examples:
+  ralph schema prd > prd.schema.json
+`)
+}
+
+func schemaCmd(args []string) int {
+	if len(args) == 0 {
+		printSchemaUsage()
+		return 1
+	}
+
+	switch args[0] {
+	case "prd":
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "Usage: ralph schema prd")
+			return 1
+		}
+		writeSchema(os.Stdout, agent.PRDSchema())
+		return 0
+	case "help", "-h", "--help":
+		printSchemaUsage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown schema: %s\n", args[0])
+		printSchemaUsage()
+		return 1
+	}
+}
+
+func writeSchema(w io.Writer, schema []byte) {
+	w.Write(schema)
+	if len(schema) > 0 && schema[len(schema)-1] != '\n' {
+		fmt.Fprintln(w)
+	}
+}
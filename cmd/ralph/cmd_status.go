@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+type statusReport struct {
+	Live     bool                `json:"live"`
+	RunState *tracker.RunState   `json:"run_state"`
+	Metrics  *tracker.RunMetrics `json:"metrics,omitempty"`
+}
+
+func statusCmd(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`status 📟  Show the current or most recent run
+
+Usage:
+  ralph status [flags]
+
+Flags:
+  -json    Print raw run state and metrics as JSON
+
+Examples:
+  ralph status
+  ralph status -json
+`)
+	}
+	jsonOut := fs.Bool("json", false, "Print raw run state and metrics as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+
+	trk := tracker.NewWriter(".ralph")
+	rs, err := trk.LoadRunState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read .ralph/run_state.json: %v\n", err)
+		return 1
+	}
+	if rs == nil {
+		fmt.Println("No active or previous run.")
+		return 0
+	}
+	m, _ := trk.LoadMetrics()
+
+	report := statusReport{
+		Live:     tracker.ProcessAlive(rs.PID),
+		RunState: rs,
+		Metrics:  m,
+	}
+
+	if *jsonOut {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to serialize status: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+		return 0
+	}
+
+	printStatusReport(os.Stdout, report, time.Now())
+	return 0
+}
+
+func printStatusReport(w io.Writer, r statusReport, now time.Time) {
+	rs := r.RunState
+
+	state := "not running"
+	if r.Live {
+		state = "live"
+	}
+	fmt.Fprintf(w, "Run:      %s (%s)\n", rs.RunID, state)
+	fmt.Fprintf(w, "PID:      %d\n", rs.PID)
+	fmt.Fprintf(w, "Status:   %s\n", rs.Status)
+	fmt.Fprintf(w, "Loop:     #%d\n", rs.LoopNumber)
+	if rs.CurrentStep != "" {
+		fmt.Fprintf(w, "Step:     %s\n", rs.CurrentStep)
+	}
+	if rs.CurrentTaskID != "" {
+		fmt.Fprintf(w, "Task:     [%s] %s\n", rs.CurrentTaskID, strings.TrimSpace(rs.CurrentTask))
+	} else if rs.CurrentTask != "" {
+		fmt.Fprintf(w, "Task:     %s\n", strings.TrimSpace(rs.CurrentTask))
+	}
+	fmt.Fprintf(w, "Elapsed:  %s\n", runElapsed(rs, r.Live, now))
+	if rs.LastError != "" {
+		fmt.Fprintf(w, "Error:    %s\n", rs.LastError)
+	}
+
+	if m := r.Metrics; m != nil {
+		fmt.Fprintf(w, "Calls:    %d\n", m.TotalClaudeCalls)
+		fmt.Fprintf(w, "Tokens:   %d (in: %d, out: %d)\n", m.TotalTokens, m.InputTokens, m.OutputTokens)
+		if m.TotalCostUSD > 0 {
+			fmt.Fprintf(w, "Cost:     $%.2f\n", m.TotalCostUSD)
+		}
+	}
+}
+
+// runElapsed returns how long a run has been going (live) or how long it ran (finished).
+func runElapsed(rs *tracker.RunState, live bool, now time.Time) time.Duration {
+	if rs.StartedAt.IsZero() {
+		return 0
+	}
+	end := now
+	if !live && !rs.UpdatedAt.IsZero() {
+		end = rs.UpdatedAt
+	}
+	if end.Before(rs.StartedAt) {
+		return 0
+	}
+	return end.Sub(rs.StartedAt).Round(time.Second)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+func TestRunElapsed(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	updated := start.Add(5 * time.Minute)
+	now := start.Add(1 * time.Hour)
+	rs := &tracker.RunState{StartedAt: start, UpdatedAt: updated}
+
+	if got := runElapsed(rs, true, now); got != time.Hour {
+		t.Errorf("live runElapsed() = %v, want 1h", got)
+	}
+	if got := runElapsed(rs, false, now); got != 5*time.Minute {
+		t.Errorf("finished runElapsed() = %v, want 5m", got)
+	}
+	if got := runElapsed(&tracker.RunState{}, true, now); got != 0 {
+		t.Errorf("zero start runElapsed() = %v, want 0", got)
+	}
+}
+
+func TestPrintStatusReport(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	report := statusReport{
+		Live: false,
+		RunState: &tracker.RunState{
+			RunID:         "abc123",
+			PID:           4242,
+			StartedAt:     start,
+			UpdatedAt:     start.Add(90 * time.Second),
+			LoopNumber:    3,
+			CurrentStep:   "claude",
+			CurrentTaskID: "T002",
+			CurrentTask:   "Add login endpoint",
+			Status:        "running",
+		},
+		Metrics: &tracker.RunMetrics{TotalClaudeCalls: 2, TotalTokens: 300, InputTokens: 200, OutputTokens: 100},
+	}
+
+	var buf bytes.Buffer
+	printStatusReport(&buf, report, start.Add(time.Hour))
+	out := buf.String()
+
+	for _, want := range []string{
+		"abc123 (not running)",
+		"PID:      4242",
+		"Loop:     #3",
+		"Step:     claude",
+		"[T002] Add login endpoint",
+		"Elapsed:  1m30s",
+		"Tokens:   300 (in: 200, out: 100)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
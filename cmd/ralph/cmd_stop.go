@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+func stopCmd(args []string) int {
+	fs := flag.NewFlagSet("stop", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`stop 🛑  Stop a running loop
+
+Usage:
+  ralph stop [flags]
+
+Flags:
+  -timeout   How long to wait for the loop to exit (default 10s)
+  -force     Send SIGKILL if the loop hasn't exited after the timeout
+
+Examples:
+  ralph stop
+  ralph stop -timeout 30s
+  ralph stop -force
+`)
+	}
+	timeout := fs.Duration("timeout", 10*time.Second, "How long to wait for the loop to exit")
+	force := fs.Bool("force", false, "Send SIGKILL if the loop hasn't exited after the timeout")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+
+	trk := tracker.NewWriter(".ralph")
+	pid, runID := findRunningLoop(trk)
+	if pid == 0 {
+		fmt.Println("No running loop found")
+		return 0
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to find process %d: %v\n", pid, err)
+		return 1
+	}
+
+	fmt.Printf("Stopping loop (pid %d, run_id=%s)...\n", pid, runID)
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to signal process %d: %v\n", pid, err)
+		return 1
+	}
+
+	if waitForExit(pid, *timeout) {
+		fmt.Println("✓ Loop stopped")
+		return 0
+	}
+
+	if !*force {
+		fmt.Fprintf(os.Stderr, "Loop did not exit within %s.\n", *timeout)
+		fmt.Fprintln(os.Stderr, "Re-run with -force to send SIGKILL:")
+		fmt.Fprintln(os.Stderr, "  ralph stop -force")
+		return 1
+	}
+
+	fmt.Printf("Loop did not exit within %s, sending SIGKILL...\n", *timeout)
+	if err := proc.Signal(syscall.SIGKILL); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to kill process %d: %v\n", pid, err)
+		return 1
+	}
+	if !waitForExit(pid, 2*time.Second) {
+		fmt.Fprintf(os.Stderr, "Process %d is still running.\n", pid)
+		return 1
+	}
+	// The killed process can't release its own lock.
+	_ = os.Remove(trk.LockPath)
+	fmt.Println("✓ Loop killed")
+	return 0
+}
+
+// findRunningLoop returns the PID and run ID of a live loop, preferring the lock
+// file and falling back to run_state.json. Returns pid 0 if nothing is running.
+func findRunningLoop(trk *tracker.Writer) (int, string) {
+	if l, err := trk.LoadLock(); err == nil && l != nil {
+		if l.PID != os.Getpid() && tracker.ProcessAlive(l.PID) {
+			return l.PID, l.RunID
+		}
+		return 0, ""
+	}
+	if rs, err := trk.LoadRunState(); err == nil && rs != nil {
+		if rs.PID != os.Getpid() && tracker.ProcessAlive(rs.PID) && rs.Status == "running" {
+			return rs.PID, rs.RunID
+		}
+	}
+	return 0, ""
+}
+
+// waitForExit polls until the process exits or the timeout elapses.
+func waitForExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if !tracker.ProcessAlive(pid) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
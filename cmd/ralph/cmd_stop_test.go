@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+func TestFindRunningLoop(t *testing.T) {
+	dir := t.TempDir()
+	trk := tracker.NewWriter(dir)
+
+	if pid, _ := findRunningLoop(trk); pid != 0 {
+		t.Fatalf("expected no running loop without lock, got pid %d", pid)
+	}
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("sleep not available: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	writeLock := func(pid int) {
+		b, _ := json.Marshal(tracker.Lock{PID: pid, RunID: "run-1", StartedAt: time.Now()})
+		if err := os.WriteFile(filepath.Join(dir, ".ralph_lock"), b, 0644); err != nil {
+			t.Fatalf("write lock: %v", err)
+		}
+	}
+
+	writeLock(cmd.Process.Pid)
+	pid, runID := findRunningLoop(trk)
+	if pid != cmd.Process.Pid || runID != "run-1" {
+		t.Fatalf("findRunningLoop() = (%d, %q), want (%d, run-1)", pid, runID, cmd.Process.Pid)
+	}
+
+	writeLock(os.Getpid())
+	if pid, _ := findRunningLoop(trk); pid != 0 {
+		t.Fatalf("expected own pid to be ignored, got %d", pid)
+	}
+}
+
+func TestWaitForExit(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("sleep not available: %v", err)
+	}
+	pid := cmd.Process.Pid
+
+	if waitForExit(pid, 200*time.Millisecond) {
+		t.Fatal("waitForExit() = true for running process")
+	}
+
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	if !waitForExit(pid, time.Second) {
+		t.Fatal("waitForExit() = false after process exited")
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func printTaskUsage() {
+	fmt.Print(`task ✏️  Update a task in .ralph/prd.json
+
+Usage:
+  ralph task set-status <id> <status>
+  ralph task retry <id>
+  ralph task add -title "..." [flags]
+  ralph task rm <id>
+
+Subcommands:
+  set-status   Set a task's status (todo, in_progress, done, failed)
+  retry        Reset a failed task back to todo
+  add          Append a todo task with the next free ID (no Claude call)
+  rm           Remove a task
+
+Examples:
+  ralph task set-status T003 done
+  ralph task retry T004
+  ralph task add -title "Add /healthz endpoint" -priority high
+  ralph task rm T007
+`)
+}
+
+func taskCmd(args []string) int {
+	if len(args) == 0 {
+		printTaskUsage()
+		return 1
+	}
+
+	switch args[0] {
+	case "set-status":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Usage: ralph task set-status <id> <status>")
+			return 1
+		}
+		return updateTaskStatus(args[1], args[2], false)
+	case "retry":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Usage: ralph task retry <id>")
+			return 1
+		}
+		return updateTaskStatus(args[1], "todo", true)
+	case "add":
+		return taskAddCmd(args[1:])
+	case "rm":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Usage: ralph task rm <id>")
+			return 1
+		}
+		return removeTask(args[1])
+	case "help", "-h", "--help":
+		printTaskUsage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown task subcommand: %s\n", args[0])
+		printTaskUsage()
+		return 1
+	}
+}
+
+func updateTaskStatus(id, status string, onlyFailed bool) int {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if !containsString(validTaskStatuses, status) {
+		fmt.Fprintf(os.Stderr, "Invalid status %q. Must be one of: %s\n", status, strings.Join(validTaskStatuses, ", "))
+		return 1
+	}
+
+	prdPath := filepath.Join(".ralph", "prd.json")
+	prd, err := loadPRDFile(prdPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read .ralph/prd.json - are you in a Ralph project? Error: %v\n", err)
+		return 1
+	}
+
+	task := findTask(prd, id)
+	if task == nil {
+		fmt.Fprintf(os.Stderr, "Task %q not found in .ralph/prd.json\n", strings.TrimSpace(id))
+		return 1
+	}
+	prev := taskStatus(*task)
+	if onlyFailed && prev != "failed" {
+		fmt.Fprintf(os.Stderr, "Task %s is not failed (status: %s)\n", task.ID, prev)
+		return 1
+	}
+	task.Status = status
+	if status != "failed" {
+		task.FailedAt = ""
+	} else if prev != "failed" {
+		task.FailedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	if err := writePRDFile(prdPath, prd); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update .ralph/prd.json: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ [%s] %s: %s → %s\n", task.ID, strings.TrimSpace(task.Title), prev, status)
+	return 0
+}
+
+// findTask returns a pointer to the task with the given ID, or nil if absent.
+func findTask(prd *prdFile, id string) *prdTask {
+	id = strings.TrimSpace(id)
+	for i := range prd.Tasks {
+		if strings.TrimSpace(prd.Tasks[i].ID) == id {
+			return &prd.Tasks[i]
+		}
+	}
+	return nil
+}
+
+func taskAddCmd(args []string) int {
+	fs := flag.NewFlagSet("task add", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`task add ✏️  Append a task to .ralph/prd.json
+
+Usage:
+  ralph task add -title "..." [flags]
+
+Flags:
+  -title      Task title (required)
+  -priority   high, medium, or low (default: medium)
+  -details    Implementation details
+  -tests      How to verify the task is done
+
+Examples:
+  ralph task add -title "Add /healthz endpoint"
+  ralph task add -title "Fix login redirect" -priority high -tests "go test ./auth/..."
+`)
+	}
+	title := fs.String("title", "", "Task title")
+	priority := fs.String("priority", "medium", "Task priority")
+	details := fs.String("details", "", "Implementation details")
+	tests := fs.String("tests", "", "How to verify the task")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return 1
+	}
+
+	task := prdTask{
+		Title:    strings.TrimSpace(*title),
+		Details:  strings.TrimSpace(*details),
+		Priority: strings.ToLower(strings.TrimSpace(*priority)),
+		Status:   "todo",
+		Tests:    strings.TrimSpace(*tests),
+	}
+	if task.Title == "" {
+		fmt.Fprintln(os.Stderr, "A title is required: ralph task add -title \"...\"")
+		return 1
+	}
+	if !containsString(validTaskPriorities, task.Priority) {
+		fmt.Fprintf(os.Stderr, "Invalid priority %q. Must be one of: %s\n", *priority, strings.Join(validTaskPriorities, ", "))
+		return 1
+	}
+
+	prdPath := filepath.Join(".ralph", "prd.json")
+	prd, err := loadPRDFile(prdPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read .ralph/prd.json - are you in a Ralph project? Error: %v\n", err)
+		return 1
+	}
+
+	// An empty ID makes dedupTaskIDs assign the next free one after the existing tasks.
+	task = dedupTaskIDs([]prdTask{task}, prd.Tasks)[0]
+	prd.Tasks = append(prd.Tasks, task)
+
+	if err := writePRDFile(prdPath, prd); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update .ralph/prd.json: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Added [%s] %s (%s)\n", task.ID, task.Title, task.Priority)
+	return 0
+}
+
+func removeTask(id string) int {
+	prdPath := filepath.Join(".ralph", "prd.json")
+	prd, err := loadPRDFile(prdPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read .ralph/prd.json - are you in a Ralph project? Error: %v\n", err)
+		return 1
+	}
+
+	id = strings.TrimSpace(id)
+	idx := -1
+	for i := range prd.Tasks {
+		if strings.TrimSpace(prd.Tasks[i].ID) == id {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		fmt.Fprintf(os.Stderr, "Task %q not found in .ralph/prd.json\n", id)
+		return 1
+	}
+	removed := prd.Tasks[idx]
+	prd.Tasks = append(prd.Tasks[:idx], prd.Tasks[idx+1:]...)
+
+	if err := writePRDFile(prdPath, prd); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update .ralph/prd.json: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Removed [%s] %s\n", removed.ID, strings.TrimSpace(removed.Title))
+	for _, t := range prd.Tasks {
+		if containsString(t.DependsOn, id) {
+			fmt.Fprintf(os.Stderr, "Warning: [%s] still depends on %s\n", t.ID, id)
+		}
+	}
+	return 0
+}
+
+// writePRDFile serializes prd and atomically replaces path, after checking
+// that the output parses back to the same tasks.
+func writePRDFile(path string, prd *prdFile) error {
+	out, err := json.MarshalIndent(prd, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serialize: %w", err)
+	}
+	var check prdFile
+	if err := json.Unmarshal(out, &check); err != nil {
+		return fmt.Errorf("serialized prd.json does not parse: %w", err)
+	}
+	if len(check.Tasks) != len(prd.Tasks) {
+		return fmt.Errorf("serialized prd.json has %d tasks, want %d", len(check.Tasks), len(prd.Tasks))
+	}
+	return writeFileAtomic(path, out, 0644)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestPRD(t *testing.T, dir, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".ralph"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, ".ralph", "prd.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestUpdateTaskStatus(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := writeTestPRD(t, dir, `{"version": 1, "tasks": [
+		{"id": "T001", "title": "Setup", "status": "done"},
+		{"id": "T002", "title": "API", "status": "failed"},
+		{"id": "T003", "title": "Docs", "status": "todo"}
+	]}`)
+	t.Chdir(dir)
+
+	tests := []struct {
+		name       string
+		id         string
+		status     string
+		onlyFailed bool
+		wantCode   int
+		wantStatus string
+	}{
+		{"set status", "T003", "in_progress", false, 0, "in_progress"},
+		{"invalid status", "T003", "blocked", false, 1, "in_progress"},
+		{"missing id", "T999", "done", false, 1, ""},
+		{"retry non-failed", "T001", "todo", true, 1, "done"},
+		{"retry failed", "T002", "todo", true, 0, "todo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := updateTaskStatus(tt.id, tt.status, tt.onlyFailed); code != tt.wantCode {
+				t.Fatalf("updateTaskStatus() = %d, want %d", code, tt.wantCode)
+			}
+			if tt.wantStatus == "" {
+				return
+			}
+			prd, err := loadPRDFile(prdPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := findTask(prd, tt.id); got == nil || got.Status != tt.wantStatus {
+				t.Errorf("task %s status = %v, want %q", tt.id, got, tt.wantStatus)
+			}
+		})
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, ".ralph", "prd.json.tmp.*"))
+	if len(matches) != 0 {
+		t.Errorf("leftover temp files: %s", strings.Join(matches, ", "))
+	}
+}
+
+func TestTaskAddAssignsNextID(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := writeTestPRD(t, dir, `{"version": 1, "tasks": [
+		{"id": "T001", "title": "Setup", "status": "done"},
+		{"id": "T009", "title": "API", "status": "todo", "depends_on": ["T001"]},
+		{"id": "T003", "title": "Docs", "status": "todo"}
+	]}`)
+	t.Chdir(dir)
+
+	if code := taskAddCmd([]string{"-title", "Add healthz", "-priority", "high", "-tests", "curl /healthz"}); code != 0 {
+		t.Fatalf("taskAddCmd() = %d, want 0", code)
+	}
+	if code := taskAddCmd([]string{"-title", "Second"}); code != 0 {
+		t.Fatalf("taskAddCmd() = %d, want 0", code)
+	}
+
+	prd, err := loadPRDFile(prdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prd.Tasks) != 5 {
+		t.Fatalf("expected 5 tasks, got %d", len(prd.Tasks))
+	}
+	added := prd.Tasks[3]
+	if added.ID != "T010" || added.Title != "Add healthz" || added.Priority != "high" || added.Status != "todo" || added.Tests != "curl /healthz" {
+		t.Errorf("unexpected added task: %+v", added)
+	}
+	if got := prd.Tasks[4]; got.ID != "T011" || got.Priority != "medium" {
+		t.Errorf("unexpected second task: %+v", got)
+	}
+	if got := prd.Tasks[1].DependsOn; len(got) != 1 || got[0] != "T001" {
+		t.Errorf("existing depends_on not preserved: %v", got)
+	}
+}
+
+func TestTaskAddRejectsBadInput(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := writeTestPRD(t, dir, `{"version": 1, "tasks": []}`)
+	t.Chdir(dir)
+
+	for _, args := range [][]string{
+		{},
+		{"-title", "  "},
+		{"-title", "X", "-priority", "urgent"},
+		{"-title", "X", "extra"},
+	} {
+		if code := taskAddCmd(args); code != 1 {
+			t.Errorf("taskAddCmd(%q) = %d, want 1", args, code)
+		}
+	}
+
+	prd, err := loadPRDFile(prdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prd.Tasks) != 0 {
+		t.Errorf("expected no tasks added, got %+v", prd.Tasks)
+	}
+}
+
+func TestRemoveTask(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := writeTestPRD(t, dir, `{"version": 1, "tasks": [
+		{"id": "T001", "title": "Setup", "status": "done"},
+		{"id": "T002", "title": "API", "status": "todo"},
+		{"id": "T003", "title": "Docs", "status": "todo"}
+	]}`)
+	t.Chdir(dir)
+
+	if code := removeTask("T002"); code != 0 {
+		t.Fatalf("removeTask() = %d, want 0", code)
+	}
+	if code := removeTask("T002"); code != 1 {
+		t.Errorf("removeTask() of missing task = %d, want 1", code)
+	}
+
+	prd, err := loadPRDFile(prdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, task := range prd.Tasks {
+		ids = append(ids, task.ID)
+	}
+	if got := strings.Join(ids, ","); got != "T001,T003" {
+		t.Errorf("remaining tasks = %s, want T001,T003", got)
+	}
+
+	// A new task must not reuse an ID below the highest remaining one.
+	if code := taskAddCmd([]string{"-title", "New"}); code != 0 {
+		t.Fatalf("taskAddCmd() = %d, want 0", code)
+	}
+	prd, err = loadPRDFile(prdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prd.Tasks[len(prd.Tasks)-1].ID; got != "T004" {
+		t.Errorf("new task ID = %s, want T004", got)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, ".ralph", "prd.json.tmp.*"))
+	if len(matches) != 0 {
+		t.Errorf("leftover temp files: %s", strings.Join(matches, ", "))
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+var validTaskStatuses = []string{"todo", "in_progress", "done", "failed"}
+
+var validTaskPriorities = []string{"high", "medium", "low"}
+
+func tasksCmd(args []string) int {
+	if len(args) > 0 && args[0] == "reorder" {
+		return tasksReorderCmd(args[1:])
+	}
+
+	fs := flag.NewFlagSet("tasks", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`tasks 📋  List tasks from .ralph/prd.json
+
+Usage:
+  ralph tasks [flags]
+  ralph tasks reorder [-preserve-order]
+
+Flags:
+  -status     Only show tasks with this status (todo, in_progress, done, failed)
+  -priority   Only show tasks with this priority (high, medium, low)
+  -cost       Show Claude calls, tokens and cost per task
+  -json       Print matching tasks as JSON
+
+Examples:
+  ralph tasks
+  ralph tasks -status failed
+  ralph tasks -priority high -json
+  ralph tasks -cost
+  ralph tasks reorder
+`)
+	}
+	status := fs.String("status", "", "Filter by status")
+	priority := fs.String("priority", "", "Filter by priority")
+	cost := fs.Bool("cost", false, "Show Claude calls, tokens and cost per task")
+	jsonOut := fs.Bool("json", false, "Print matching tasks as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+
+	statusFilter := strings.ToLower(strings.TrimSpace(*status))
+	if statusFilter != "" && !containsString(validTaskStatuses, statusFilter) {
+		fmt.Fprintf(os.Stderr, "Invalid status %q. Must be one of: %s\n", *status, strings.Join(validTaskStatuses, ", "))
+		return 1
+	}
+	priorityFilter := strings.ToLower(strings.TrimSpace(*priority))
+	if priorityFilter != "" && !containsString(validTaskPriorities, priorityFilter) {
+		fmt.Fprintf(os.Stderr, "Invalid priority %q. Must be one of: %s\n", *priority, strings.Join(validTaskPriorities, ", "))
+		return 1
+	}
+
+	prdPath := filepath.Join(".ralph", "prd.json")
+	prd, err := loadPRDFile(prdPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read .ralph/prd.json - are you in a Ralph project? Error: %v\n", err)
+		return 1
+	}
+
+	tasks := filterTasks(prd.Tasks, statusFilter, priorityFilter)
+
+	if *cost {
+		metrics, err := tracker.NewWriter(".ralph").LoadTaskMetrics()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read .ralph/task_metrics.json: %v\n", err)
+			return 1
+		}
+		filtered := statusFilter != "" || priorityFilter != ""
+		return printTaskCosts(os.Stdout, taskCosts(tasks, metrics, filtered), *jsonOut)
+	}
+
+	if *jsonOut {
+		data, err := json.MarshalIndent(tasks, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to serialize tasks: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+		return 0
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("No matching tasks.")
+	} else {
+		printTaskTable(os.Stdout, tasks)
+	}
+
+	if st, err := agent.LoadPRDStatus(prdPath); err == nil && st != nil {
+		fmt.Printf("\n%s\n", taskSummaryLine(st))
+	}
+	return 0
+}
+
+func tasksReorderCmd(args []string) int {
+	fs := flag.NewFlagSet("tasks reorder", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`tasks reorder 📋  Sort .ralph/prd.json by priority
+
+Usage:
+  ralph tasks reorder [flags]
+
+Todo tasks are picked high > medium > low, then in file order. Reorder
+rewrites prd.json in that order so the file matches what runs next.
+
+Flags:
+  -preserve-order   Also set "preserve_order" so tasks are picked in
+                    file order from now on, ignoring priority
+
+Examples:
+  ralph tasks reorder
+  ralph tasks reorder -preserve-order
+`)
+	}
+	preserve := fs.Bool("preserve-order", false, "Pick tasks in file order from now on")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return 1
+	}
+
+	prdPath := filepath.Join(".ralph", "prd.json")
+	prd, err := loadPRDFile(prdPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read .ralph/prd.json - are you in a Ralph project? Error: %v\n", err)
+		return 1
+	}
+
+	moved := sortTasksByPriority(prd.Tasks)
+	if *preserve {
+		prd.PreserveOrder = true
+	}
+	if err := writePRDFile(prdPath, prd); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to update .ralph/prd.json: %v\n", err)
+		return 1
+	}
+
+	if moved == 0 {
+		fmt.Println("✓ Tasks already in priority order")
+	} else {
+		fmt.Printf("✓ Reordered %d task(s) by priority\n", moved)
+	}
+	if *preserve {
+		fmt.Println("Tasks will now be picked in file order (preserve_order)")
+	}
+	printTaskTable(os.Stdout, prd.Tasks)
+	return 0
+}
+
+// sortTasksByPriority stably sorts tasks high > medium > low and returns
+// how many tasks changed position.
+func sortTasksByPriority(tasks []prdTask) int {
+	before := make([]string, len(tasks))
+	for i, t := range tasks {
+		before[i] = t.ID
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return agent.PriorityRank(tasks[i].Priority) < agent.PriorityRank(tasks[j].Priority)
+	})
+	moved := 0
+	for i, t := range tasks {
+		if t.ID != before[i] {
+			moved++
+		}
+	}
+	return moved
+}
+
+// loadPRDFile reads and parses a prd.json file.
+func loadPRDFile(path string) (*prdFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var prd prdFile
+	if err := json.Unmarshal(data, &prd); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return &prd, nil
+}
+
+func filterTasks(tasks []prdTask, status, priority string) []prdTask {
+	out := make([]prdTask, 0, len(tasks))
+	for _, t := range tasks {
+		if status != "" && taskStatus(t) != status {
+			continue
+		}
+		if priority != "" && strings.ToLower(strings.TrimSpace(t.Priority)) != priority {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// taskStatus returns the normalized status of a task, treating empty as "todo".
+func taskStatus(t prdTask) string {
+	s := strings.ToLower(strings.TrimSpace(t.Status))
+	if s == "" {
+		return "todo"
+	}
+	return s
+}
+
+func printTaskTable(w io.Writer, tasks []prdTask) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tPRIORITY\tSTATUS")
+	for _, t := range tasks {
+		prio := strings.TrimSpace(t.Priority)
+		if prio == "" {
+			prio = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", strings.TrimSpace(t.ID), strings.TrimSpace(t.Title), prio, taskStatus(t))
+	}
+	tw.Flush()
+}
+
+// taskCost is one row of ralph tasks -cost.
+type taskCost struct {
+	ID     string `json:"id"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status,omitempty"`
+	tracker.TaskMetrics
+}
+
+// taskCosts joins tasks with their recorded usage, most expensive first.
+// Tasks without usage are left out. Unless filtered, usage for task IDs no
+// longer in prd.json and for calls with no current task is listed too.
+func taskCosts(tasks []prdTask, metrics map[string]tracker.TaskMetrics, filtered bool) []taskCost {
+	var rows []taskCost
+	seen := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		id := strings.TrimSpace(t.ID)
+		seen[id] = true
+		if m, ok := metrics[id]; ok && id != "" {
+			rows = append(rows, taskCost{ID: id, Title: strings.TrimSpace(t.Title), Status: taskStatus(t), TaskMetrics: m})
+		}
+	}
+	if !filtered {
+		for id, m := range metrics {
+			if !seen[id] {
+				rows = append(rows, taskCost{ID: id, TaskMetrics: m})
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].CostUSD != rows[j].CostUSD {
+			return rows[i].CostUSD > rows[j].CostUSD
+		}
+		if rows[i].TotalTokens != rows[j].TotalTokens {
+			return rows[i].TotalTokens > rows[j].TotalTokens
+		}
+		return rows[i].ID < rows[j].ID
+	})
+	return rows
+}
+
+func printTaskCosts(w io.Writer, rows []taskCost, jsonOut bool) int {
+	if jsonOut {
+		if rows == nil {
+			rows = []taskCost{}
+		}
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to serialize task costs: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(w, string(data))
+		return 0
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No usage recorded per task yet (.ralph/task_metrics.json).")
+		return 0
+	}
+
+	var total tracker.TaskMetrics
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tSTATUS\tCALLS\tTOKENS\tCOST")
+	for _, r := range rows {
+		id, title, status := r.ID, r.Title, r.Status
+		if id == tracker.UnattributedTaskID {
+			id, title = "-", "(no current task)"
+		} else if title == "" {
+			title = "(not in prd.json)"
+		}
+		if status == "" {
+			status = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t$%.2f\n", id, title, status, r.ClaudeCalls, r.TotalTokens, r.CostUSD)
+		total.ClaudeCalls += r.ClaudeCalls
+		total.TotalTokens += r.TotalTokens
+		total.CostUSD += r.CostUSD
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nTotal: %d calls, %d tokens, $%.2f\n", total.ClaudeCalls, total.TotalTokens, total.CostUSD)
+	return 0
+}
+
+func taskSummaryLine(st *agent.PRDStatus) string {
+	line := fmt.Sprintf("%s complete", st.Progress())
+	if st.FailedTasks > 0 {
+		line += fmt.Sprintf(", %d failed", st.FailedTasks)
+	}
+	return line
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
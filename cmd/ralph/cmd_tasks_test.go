@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+func TestFilterTasks(t *testing.T) {
+	tasks := []prdTask{
+		{ID: "T001", Title: "Setup", Priority: "high", Status: "done"},
+		{ID: "T002", Title: "API", Priority: "High", Status: "failed"},
+		{ID: "T003", Title: "Docs", Priority: "low"},
+		{ID: "T004", Title: "UI", Priority: "medium", Status: "todo"},
+	}
+
+	tests := []struct {
+		name     string
+		status   string
+		priority string
+		want     []string
+	}{
+		{"no filters", "", "", []string{"T001", "T002", "T003", "T004"}},
+		{"status failed", "failed", "", []string{"T002"}},
+		{"empty status is todo", "todo", "", []string{"T003", "T004"}},
+		{"priority high", "", "high", []string{"T001", "T002"}},
+		{"both", "done", "high", []string{"T001"}},
+		{"no match", "in_progress", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterTasks(tasks, tt.status, tt.priority)
+			var ids []string
+			for _, task := range got {
+				ids = append(ids, task.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("filterTasks(%q, %q) = %v, want %v", tt.status, tt.priority, ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintTaskTable(t *testing.T) {
+	var buf bytes.Buffer
+	printTaskTable(&buf, []prdTask{
+		{ID: "T001", Title: "Setup project", Priority: "high", Status: "done"},
+		{ID: "T002", Title: "Add API"},
+	})
+	out := buf.String()
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d lines:\n%s", len(lines), out)
+	}
+	if !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[0], "STATUS") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if fields := strings.Fields(lines[2]); fields[len(fields)-2] != "-" || fields[len(fields)-1] != "todo" {
+		t.Errorf("expected missing priority '-' and status 'todo', got %q", lines[2])
+	}
+}
+
+func TestTaskSummaryLine(t *testing.T) {
+	if got := taskSummaryLine(&agent.PRDStatus{TotalTasks: 10, CompletedTasks: 3, FailedTasks: 1}); got != "3/10 complete, 1 failed" {
+		t.Errorf("taskSummaryLine() = %q", got)
+	}
+	if got := taskSummaryLine(&agent.PRDStatus{TotalTasks: 2, CompletedTasks: 2}); got != "2/2 complete" {
+		t.Errorf("taskSummaryLine() = %q", got)
+	}
+}
+
+func TestTasksReorder(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := writeTestPRD(t, dir, `{"version": 1, "tasks": [
+		{"id": "T001", "title": "Docs", "priority": "low", "status": "todo"},
+		{"id": "T002", "title": "API", "priority": "high", "status": "todo"},
+		{"id": "T003", "title": "UI", "status": "todo"},
+		{"id": "T004", "title": "Auth", "priority": "high", "status": "todo"}
+	]}`)
+	t.Chdir(dir)
+
+	if code := tasksCmd([]string{"reorder"}); code != 0 {
+		t.Fatalf("tasks reorder = %d, want 0", code)
+	}
+	prd, err := loadPRDFile(prdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, task := range prd.Tasks {
+		ids = append(ids, task.ID)
+	}
+	if got := strings.Join(ids, ","); got != "T002,T004,T003,T001" {
+		t.Errorf("order = %s, want T002,T004,T003,T001", got)
+	}
+	if prd.PreserveOrder {
+		t.Error("expected preserve_order to stay unset")
+	}
+
+	if code := tasksCmd([]string{"reorder", "-preserve-order"}); code != 0 {
+		t.Fatalf("tasks reorder -preserve-order = %d, want 0", code)
+	}
+	st, err := agent.LoadPRDStatus(prdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CurrentTaskID != "T002" {
+		t.Errorf("CurrentTaskID = %s, want T002", st.CurrentTaskID)
+	}
+	if prd, err = loadPRDFile(prdPath); err != nil || !prd.PreserveOrder {
+		t.Errorf("expected preserve_order to be set, got %+v (err %v)", prd, err)
+	}
+}
+
+func TestTaskCosts(t *testing.T) {
+	tasks := []prdTask{
+		{ID: "T001", Title: "Setup", Priority: "high", Status: "done"},
+		{ID: "T002", Title: "API", Priority: "low", Status: "in_progress"},
+		{ID: "T003", Title: "Docs"},
+		{Title: "No ID"},
+	}
+	metrics := map[string]tracker.TaskMetrics{
+		"T001":                     {ClaudeCalls: 1, TotalTokens: 100, CostUSD: 0.10},
+		"T002":                     {ClaudeCalls: 3, TotalTokens: 900, CostUSD: 1.20},
+		"T009":                     {ClaudeCalls: 1, TotalTokens: 50, CostUSD: 0.05},
+		tracker.UnattributedTaskID: {ClaudeCalls: 2, TotalTokens: 10},
+	}
+
+	ids := func(rows []taskCost) string {
+		var out []string
+		for _, r := range rows {
+			out = append(out, r.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	rows := taskCosts(tasks, metrics, false)
+	if got, want := ids(rows), "T002,T001,T009,-"; got != want {
+		t.Errorf("taskCosts() order = %s, want %s", got, want)
+	}
+	if rows[0].Title != "API" || rows[0].Status != "in_progress" {
+		t.Errorf("T002 row = %+v, want title and status from prd.json", rows[0])
+	}
+
+	if got, want := ids(taskCosts(filterTasks(tasks, "done", ""), metrics, true)), "T001"; got != want {
+		t.Errorf("filtered taskCosts() = %s, want %s", got, want)
+	}
+
+	var buf bytes.Buffer
+	printTaskCosts(&buf, rows, false)
+	out := buf.String()
+	for _, want := range []string{"CALLS", "$1.20", "(not in prd.json)", "(no current task)", "Total: 7 calls, 1060 tokens, $1.35"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("cost table missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printTaskCosts(&buf, nil, false)
+	if !strings.Contains(buf.String(), "No usage recorded") {
+		t.Errorf("expected empty message, got %q", buf.String())
+	}
+
+	buf.Reset()
+	printTaskCosts(&buf, rows[:1], true)
+	if !strings.Contains(buf.String(), `"id": "T002"`) || !strings.Contains(buf.String(), `"claude_calls": 3`) {
+		t.Errorf("unexpected JSON:\n%s", buf.String())
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
 )
 
@@ -84,10 +86,14 @@ func upgradeCmd(args []string) int {
 		printManualUpgradeInstructions()
 		return 0
 	}
-	if err := checkWritable(exePath); err != nil {
-		fmt.Printf("Current binary is not writable (%v).\n", err)
-		printManualUpgradeInstructions()
-		return 0
+	// Windows can't open a running .exe for writing; upgradeOnWindows moves
+	// it aside instead.
+	if runtime.GOOS != "windows" {
+		if err := checkWritable(exePath); err != nil {
+			fmt.Printf("Current binary is not writable (%v).\n", err)
+			printManualUpgradeInstructions()
+			return 0
+		}
 	}
 	if _, err := exec.LookPath("go"); err != nil {
 		fmt.Println("`go` not found in PATH.")
@@ -95,8 +101,21 @@ func upgradeCmd(args []string) int {
 		return 0
 	}
 
+	if runtime.GOOS == "windows" {
+		return upgradeOnWindows(exePath)
+	}
+	if !goInstallLatest(exeDir) {
+		return 1
+	}
+	fmt.Printf("Upgraded to latest. Run: ralph version\n")
+	return 0
+}
+
+// goInstallLatest runs `go install ...@latest` with GOBIN set to gobin and
+// prints go's output. On failure it prints why and how to upgrade by hand.
+func goInstallLatest(gobin string) bool {
 	cmd := exec.Command("go", "install", "github.com/chr1sbest/wiggum/cmd/ralph@latest")
-	cmd.Env = append(os.Environ(), "GOBIN="+exeDir)
+	cmd.Env = append(os.Environ(), "GOBIN="+gobin)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Upgrade failed: %v\n", err)
@@ -109,15 +128,59 @@ func upgradeCmd(args []string) int {
 			}
 		}
 		printManualUpgradeInstructions()
-		return 1
+		return false
 	}
 	if strings.TrimSpace(string(out)) != "" {
 		fmt.Println(string(out))
 	}
+	return true
+}
+
+// upgradeOnWindows installs the latest ralph.exe into a temporary directory
+// next to the running binary, then swaps it in. Windows won't overwrite a
+// running .exe but does allow renaming it, so the current binary is moved
+// aside to ralph.exe.old first.
+func upgradeOnWindows(exePath string) int {
+	tmpBin, err := os.MkdirTemp(filepath.Dir(exePath), ".ralph-upgrade-")
+	if err != nil {
+		fmt.Printf("Cannot write next to %s (%v).\n", exePath, err)
+		printManualUpgradeInstructions()
+		return 0
+	}
+	if !goInstallLatest(tmpBin) {
+		_ = os.RemoveAll(tmpBin)
+		return 1
+	}
+
+	built := filepath.Join(tmpBin, "ralph.exe")
+	backup, err := replaceRunningBinary(exePath, built)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not replace %s: %v\n", exePath, err)
+		fmt.Fprintf(os.Stderr, "The new version is at %s. After ralph exits, move it over %s.\n", built, exePath)
+		return 1
+	}
+	_ = os.RemoveAll(tmpBin)
 	fmt.Printf("Upgraded to latest. Run: ralph version\n")
+	fmt.Printf("The previous binary was kept as %s and is removed on the next upgrade.\n", backup)
 	return 0
 }
 
+// replaceRunningBinary moves exePath to exePath+".old" and newPath to
+// exePath, restoring the original if the second move fails. A backup left by
+// an earlier upgrade is removed first. Returns the backup's path.
+func replaceRunningBinary(exePath, newPath string) (string, error) {
+	backup := exePath + ".old"
+	_ = os.Remove(backup)
+	if err := os.Rename(exePath, backup); err != nil {
+		return "", fmt.Errorf("move current binary aside: %w", err)
+	}
+	if err := os.Rename(newPath, exePath); err != nil {
+		_ = os.Rename(backup, exePath)
+		return "", fmt.Errorf("move new binary into place: %w", err)
+	}
+	return backup, nil
+}
+
 func fetchLatestVersion() (string, error) {
 	url := "https://api.github.com/repos/chr1sbest/wiggum/releases/latest"
 	req, err := http.NewRequest("GET", url, nil)
@@ -250,35 +313,58 @@ func looksLikeHomebrewInstall(exePath string) bool {
 }
 
 func looksLikeGoInstall(exeDir string) bool {
-	if gobin := strings.TrimSpace(os.Getenv("GOBIN")); gobin != "" {
-		if samePath(exeDir, gobin) {
+	home, _ := os.UserHomeDir()
+	return looksLikeGoInstallOn(runtime.GOOS, exeDir, os.Getenv("GOBIN"), os.Getenv("GOPATH"), home)
+}
+
+// looksLikeGoInstallOn reports whether exeDir is a directory `go install`
+// writes to on goos: GOBIN, each GOPATH entry's bin, or ~/go/bin when GOPATH
+// is unset. Windows paths compare case-insensitively with either slash, and
+// GOPATH entries there are separated by ';' since ':' follows drive letters.
+func looksLikeGoInstallOn(goos, exeDir, gobin, gopath, home string) bool {
+	same := samePath
+	listSep := string(os.PathListSeparator)
+	join := filepath.Join
+	if goos == "windows" {
+		same = sameWindowsPath
+		listSep = ";"
+		join = func(elem ...string) string { return strings.Join(elem, `\`) }
+	}
+
+	if gobin = strings.TrimSpace(gobin); gobin != "" {
+		if same(exeDir, gobin) {
 			return true
 		}
 	}
-	gopath := strings.TrimSpace(os.Getenv("GOPATH"))
+	gopath = strings.TrimSpace(gopath)
 	if gopath == "" {
 		// Check default ~/go/bin
-		if home, err := os.UserHomeDir(); err == nil {
-			defaultBin := filepath.Join(home, "go", "bin")
-			if samePath(exeDir, defaultBin) {
-				return true
-			}
+		if home != "" && same(exeDir, join(home, "go", "bin")) {
+			return true
 		}
 		return false
 	}
-	for _, p := range strings.Split(gopath, string(os.PathListSeparator)) {
+	for _, p := range strings.Split(gopath, listSep) {
 		p = strings.TrimSpace(p)
 		if p == "" {
 			continue
 		}
-		binDir := filepath.Join(p, "bin")
-		if samePath(exeDir, binDir) {
+		if same(exeDir, join(p, "bin")) {
 			return true
 		}
 	}
 	return false
 }
 
+// sameWindowsPath compares Windows paths without touching the filesystem:
+// case-insensitively, with / and \ treated alike and trailing slashes ignored.
+func sameWindowsPath(a, b string) bool {
+	norm := func(p string) string {
+		return path.Clean(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(p), `\`, "/")))
+	}
+	return norm(a) == norm(b)
+}
+
 func samePath(a, b string) bool {
 	aa := filepath.Clean(a)
 	bb := filepath.Clean(b)
@@ -57,6 +57,66 @@ func TestLooksLikeGoInstall(t *testing.T) {
 	}
 }
 
+func TestLooksLikeGoInstallOnWindows(t *testing.T) {
+	tests := []struct {
+		name   string
+		exeDir string
+		gobin  string
+		gopath string
+		home   string
+		want   bool
+	}{
+		{"matches GOBIN ignoring case and slashes", `c:/users/me/BIN/`, `C:\Users\me\bin`, "", `C:\Users\me`, true},
+		{"matches GOPATH bin", `C:\Users\me\go\bin`, "", `C:\Users\me\go`, `C:\Users\me`, true},
+		{"matches a later GOPATH entry", `D:\tools\bin`, "", `C:\Users\me\go;D:\tools`, `C:\Users\me`, true},
+		{"matches default GOPATH", `C:\Users\me\go\bin`, "", "", `C:\Users\me`, true},
+		{"other directory", `C:\Program Files\ralph`, "", `C:\Users\me\go`, `C:\Users\me`, false},
+		{"GOPATH without bin", `C:\Users\me\go`, "", `C:\Users\me\go`, `C:\Users\me`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := looksLikeGoInstallOn("windows", tt.exeDir, tt.gobin, tt.gopath, tt.home)
+			if got != tt.want {
+				t.Errorf("looksLikeGoInstallOn(windows, %q) = %v, want %v", tt.exeDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceRunningBinary(t *testing.T) {
+	dir := t.TempDir()
+	exePath := filepath.Join(dir, "ralph.exe")
+	newPath := filepath.Join(dir, "new", "ralph.exe")
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for p, content := range map[string]string{exePath: "old", newPath: "new", exePath + ".old": "older"} {
+		if err := os.WriteFile(p, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backup, err := replaceRunningBinary(exePath, newPath)
+	if err != nil {
+		t.Fatalf("replaceRunningBinary() error = %v", err)
+	}
+	if got, _ := os.ReadFile(exePath); string(got) != "new" {
+		t.Errorf("%s = %q, want the new binary", exePath, got)
+	}
+	if got, _ := os.ReadFile(backup); string(got) != "old" {
+		t.Errorf("backup %s = %q, want the previous binary", backup, got)
+	}
+
+	// A missing new binary leaves the current one in place.
+	if _, err := replaceRunningBinary(exePath, filepath.Join(dir, "missing.exe")); err == nil {
+		t.Fatal("replaceRunningBinary() with a missing new binary should fail")
+	}
+	if got, _ := os.ReadFile(exePath); string(got) != "new" {
+		t.Errorf("%s = %q after a failed swap, want it restored", exePath, got)
+	}
+}
+
 func TestSamePath(t *testing.T) {
 	tests := []struct {
 		a, b string
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// exitUpgradeAvailable is what `ralph version -check` returns when a newer
+// release exists, so scripts can tell it apart from success and from errors.
+const exitUpgradeAvailable = 3
+
+func versionCmd(args []string) int {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Print(`version 🏷️  Show Ralph's version number
+
+Usage:
+  ralph version [flags]
+
+Flags:
+  -check   Also check whether a newer release is available (doesn't upgrade)
+
+Exit codes with -check:
+  0   Up to date
+  1   The latest version couldn't be fetched
+  3   A newer version is available
+
+Examples:
+  ralph version
+  ralph version -check
+`)
+	}
+	check := fs.Bool("check", false, "Check whether a newer release is available")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 1
+	}
+
+	fmt.Println(versionLine())
+	if !*check {
+		return 0
+	}
+	return checkForUpgrade(os.Stdout, os.Stderr, version, fetchLatestVersion)
+}
+
+// checkForUpgrade compares current with the latest release reported by fetch
+// and says whether an upgrade is available, without installing anything.
+func checkForUpgrade(w, errW io.Writer, current string, fetch func() (string, error)) int {
+	latest, err := fetch()
+	if err != nil {
+		fmt.Fprintf(errW, "Failed to check latest version: %v\n", err)
+		return 1
+	}
+	if latest == "" {
+		fmt.Fprintln(errW, "Failed to determine latest version.")
+		return 1
+	}
+	if current == "dev" {
+		fmt.Fprintf(w, "Development build; the latest release is %s\n", latest)
+		return 0
+	}
+	if latest == current || compareSemver(latest, current) <= 0 {
+		fmt.Fprintf(w, "ralph is up to date (%s)\n", current)
+		return 0
+	}
+	fmt.Fprintf(w, "Newer version %s available (current %s). Run: ralph upgrade\n", latest, current)
+	return exitUpgradeAvailable
+}
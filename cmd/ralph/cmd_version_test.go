@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckForUpgrade(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		latest   string
+		fetchErr error
+		wantCode int
+		wantOut  string
+		wantErr  string
+	}{
+		{"up to date", "v1.4.0", "v1.4.0", nil, 0, "ralph is up to date (v1.4.0)", ""},
+		{"ahead of latest", "v1.5.0", "v1.4.0", nil, 0, "ralph is up to date (v1.5.0)", ""},
+		{"upgrade available", "v1.3.2", "v1.4.0", nil, exitUpgradeAvailable, "Newer version v1.4.0 available (current v1.3.2). Run: ralph upgrade", ""},
+		{"dev build", "dev", "v1.4.0", nil, 0, "Development build; the latest release is v1.4.0", ""},
+		{"fetch failure", "v1.3.2", "", errors.New("rate limited"), 1, "", "Failed to check latest version: rate limited"},
+		{"empty latest", "v1.3.2", "", nil, 1, "", "Failed to determine latest version."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			fetch := func() (string, error) { return tt.latest, tt.fetchErr }
+
+			code := checkForUpgrade(&out, &errOut, tt.current, fetch)
+			if code != tt.wantCode {
+				t.Errorf("checkForUpgrade() = %d, want %d", code, tt.wantCode)
+			}
+			if got := strings.TrimSpace(out.String()); got != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", got, tt.wantOut)
+			}
+			if got := strings.TrimSpace(errOut.String()); got != tt.wantErr {
+				t.Errorf("stderr = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
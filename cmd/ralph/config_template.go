@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"text/template"
+
+	"github.com/chr1sbest/wiggum/internal/loop/steps"
 )
 
 type defaultConfigTemplateData struct {
@@ -15,6 +17,9 @@ type defaultConfigTemplateData struct {
 	PrdFile           string
 	CommitMessageFile string
 	Model             string
+	StepName          string
+	AgentType         string
+	AgentBinary       string
 }
 
 type DefaultLoopConfigOptions struct {
@@ -26,6 +31,11 @@ type DefaultLoopConfigOptions struct {
 	PrdFile           string
 	CommitMessageFile string
 	Model             string
+	// AgentType is the agent step's agent_type; "" or "claude" writes the
+	// Claude config without agent_type or binary
+	AgentType string
+	// AgentBinary is written as the agent step's binary for other agents
+	AgentBinary string
 }
 
 const defaultLoopConfigTemplate = `{
@@ -34,8 +44,14 @@ const defaultLoopConfigTemplate = `{
   "steps": [
     {
       "type": "agent",
-      "name": "claude",
+      "name": "{{.StepName}}",
       "config": {
+{{- if .AgentType }}
+        "agent_type": "{{.AgentType}}",
+{{- end }}
+{{- if .AgentBinary }}
+        "binary": "{{.AgentBinary}}",
+{{- end }}
         "prompt_file": "{{.LoopPromptFile}}",
         "prd_file": "{{.PrdFile}}",
 {{- if .Model }}
@@ -71,6 +87,13 @@ func renderDefaultLoopConfig(opts DefaultLoopConfigOptions) (string, error) {
 	if opts.CommitMessageFile == "" {
 		opts.CommitMessageFile = "../.ralph/commit_message.txt"
 	}
+	stepName := steps.AgentTypeClaude
+	if opts.AgentType == steps.AgentTypeClaude {
+		opts.AgentType, opts.AgentBinary = "", ""
+	}
+	if opts.AgentType != "" {
+		stepName = opts.AgentType
+	}
 
 	data := defaultConfigTemplateData{
 		RepoDir:           opts.RepoDir,
@@ -81,6 +104,9 @@ func renderDefaultLoopConfig(opts DefaultLoopConfigOptions) (string, error) {
 		PrdFile:           opts.PrdFile,
 		CommitMessageFile: opts.CommitMessageFile,
 		Model:             opts.Model,
+		StepName:          stepName,
+		AgentType:         opts.AgentType,
+		AgentBinary:       opts.AgentBinary,
 	}
 
 	tmpl, err := template.New("default_config").Option("missingkey=error").Parse(defaultLoopConfigTemplate)
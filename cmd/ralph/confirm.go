@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/chr1sbest/wiggum/internal/loop"
+)
+
+// stepPrompter implements loop.StepConfirmer for ralph run -confirm-each-step
+// by asking on out and reading answers from in.
+type stepPrompter struct {
+	out    io.Writer
+	cancel context.CancelFunc
+	lines  chan promptLine
+}
+
+type promptLine struct {
+	text string
+	err  error
+}
+
+// newStepPrompter reads answers from in in the background, so a pending
+// prompt doesn't keep Ctrl-C from stopping the run. "quit" calls cancel.
+func newStepPrompter(in io.Reader, out io.Writer, cancel context.CancelFunc) *stepPrompter {
+	p := &stepPrompter{out: out, cancel: cancel, lines: make(chan promptLine)}
+	go func() {
+		r := bufio.NewReader(in)
+		for {
+			text, err := r.ReadString('\n')
+			p.lines <- promptLine{text: text, err: err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func (p *stepPrompter) ConfirmStep(ctx context.Context, sp loop.StepPrompt) loop.StepDecision {
+	fmt.Fprintf(p.out, "\n▶ Loop %d, step %d/%d: %s (%s)\n", sp.LoopNumber, sp.StepNumber, sp.TotalSteps, sp.Step.Name, sp.Step.Type)
+	if sp.TaskID != "" {
+		fmt.Fprintf(p.out, "  Current task: [%s] %s\n", sp.TaskID, sp.TaskTitle)
+	}
+	for {
+		fmt.Fprint(p.out, "Run this step? [y]es, [n]o/[s]kip, [q]uit: ")
+		var line promptLine
+		select {
+		case <-ctx.Done():
+			return loop.StepQuit
+		case line = <-p.lines:
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line.text)) {
+		case "y", "yes":
+			return loop.StepRun
+		case "n", "no", "s", "skip":
+			fmt.Fprintf(p.out, "Skipping %s this iteration\n", sp.Step.Name)
+			return loop.StepSkip
+		case "q", "quit":
+			p.cancel()
+			return loop.StepQuit
+		}
+		if line.err != nil {
+			// stdin closed: nobody is left to approve steps.
+			fmt.Fprintln(p.out, "\nNo more input, stopping")
+			p.cancel()
+			return loop.StepQuit
+		}
+		fmt.Fprintln(p.out, "Please answer y, n, skip or quit.")
+	}
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal.
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// runningInCI reports whether a CI environment variable is set.
+func runningInCI() bool {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv("CI")))
+	return v != "" && v != "false" && v != "0"
+}
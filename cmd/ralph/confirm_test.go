@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/loop"
+)
+
+func TestStepPrompterConfirmStep(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       loop.StepDecision
+		wantCancel bool
+	}{
+		{"yes", "y\n", loop.StepRun, false},
+		{"yes word", " Yes \n", loop.StepRun, false},
+		{"no", "n\n", loop.StepSkip, false},
+		{"skip", "skip\n", loop.StepSkip, false},
+		{"quit", "quit\n", loop.StepQuit, true},
+		{"invalid then yes", "maybe\n\ny\n", loop.StepRun, false},
+		{"answer without newline", "s", loop.StepSkip, false},
+		{"eof", "", loop.StepQuit, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cancelled := false
+			var out bytes.Buffer
+			p := newStepPrompter(strings.NewReader(tt.input), &out, func() { cancelled = true })
+			got := p.ConfirmStep(context.Background(), loop.StepPrompt{
+				LoopNumber: 2,
+				StepNumber: 1,
+				TotalSteps: 3,
+				Step:       config.StepConfig{Name: "work", Type: "agent"},
+				TaskID:     "T-3",
+				TaskTitle:  "Add login",
+			})
+			if got != tt.want {
+				t.Errorf("ConfirmStep() = %v, want %v", got, tt.want)
+			}
+			if cancelled != tt.wantCancel {
+				t.Errorf("cancel called = %v, want %v", cancelled, tt.wantCancel)
+			}
+			text := out.String()
+			if !strings.Contains(text, "step 1/3: work (agent)") || !strings.Contains(text, "[T-3] Add login") {
+				t.Errorf("prompt missing step or task:\n%s", text)
+			}
+		})
+	}
+}
+
+func TestStepPrompterStopsOnCancelledContext(t *testing.T) {
+	// An open stdin that never answers must not block shutdown.
+	in, _ := io.Pipe()
+	p := newStepPrompter(in, io.Discard, func() {})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := p.ConfirmStep(ctx, loop.StepPrompt{Step: config.StepConfig{Name: "work"}}); got != loop.StepQuit {
+		t.Errorf("ConfirmStep() = %v, want StepQuit", got)
+	}
+}
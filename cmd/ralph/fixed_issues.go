@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// errIssueAlreadyFixed is reported for an issue `ralph fix` has handled before
+// when -force isn't given.
+var errIssueAlreadyFixed = errors.New("already fixed (use -force to add tasks again)")
+
+// fixedIssue records one issue `ralph fix` turned into tasks.
+type fixedIssue struct {
+	Provider string   `json:"provider,omitempty"`
+	Repo     string   `json:"repo,omitempty"`
+	Number   int      `json:"number"`
+	Hash     string   `json:"hash"` // issueContentHash of the title and body
+	TaskIDs  []string `json:"task_ids,omitempty"`
+	FixedAt  string   `json:"fixed_at"` // RFC 3339
+}
+
+// fixedIssues is the contents of .ralph/fixed_issues.json.
+type fixedIssues struct {
+	Issues []fixedIssue `json:"issues"`
+}
+
+// loadFixedIssues reads path. A missing file means no issue was fixed yet.
+func loadFixedIssues(path string) (*fixedIssues, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &fixedIssues{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+	var f fixedIssues
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s is not valid JSON: %w", path, err)
+	}
+	return &f, nil
+}
+
+func (f *fixedIssues) save(path string) error {
+	out, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	return nil
+}
+
+func (f *fixedIssues) find(t issueTarget) *fixedIssue {
+	for i := range f.Issues {
+		r := &f.Issues[i]
+		if r.Number == t.Number && r.Repo == t.Repo && r.Provider == t.Provider {
+			return r
+		}
+	}
+	return nil
+}
+
+// record notes that issue was fixed with tasks, replacing any earlier record
+// for the same issue.
+func (f *fixedIssues) record(t issueTarget, issue *Issue, tasks []prdTask, now time.Time) {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, strings.TrimSpace(task.ID))
+	}
+	rec := fixedIssue{
+		Provider: t.Provider,
+		Repo:     t.Repo,
+		Number:   t.Number,
+		Hash:     issueContentHash(issue),
+		TaskIDs:  ids,
+		FixedAt:  now.UTC().Format(time.RFC3339),
+	}
+	if existing := f.find(t); existing != nil {
+		*existing = rec
+		return
+	}
+	f.Issues = append(f.Issues, rec)
+}
+
+// issueContentHash fingerprints an issue's title and body so a repeat fix can
+// tell whether the issue was edited since.
+func issueContentHash(issue *Issue) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(issue.Title) + "\n" + strings.TrimSpace(issue.Body)))
+	return hex.EncodeToString(sum[:])
+}
+
+// priorFix describes how issue was already handled: a fixed_issues.json record
+// or tasks in prd.json tagged with the issue. It returns "" for a new issue.
+func (f *fixedIssues) priorFix(t issueTarget, issue *Issue, tasks []prdTask) string {
+	if rec := f.find(t); rec != nil {
+		msg := fmt.Sprintf("Issue #%d was already fixed", t.Number)
+		if fixedAt, err := time.Parse(time.RFC3339, rec.FixedAt); err == nil {
+			msg += " on " + fixedAt.Local().Format("2006-01-02")
+		}
+		if len(rec.TaskIDs) > 0 {
+			msg += " (" + strings.Join(rec.TaskIDs, ", ") + ")"
+		}
+		if rec.Hash == issueContentHash(issue) {
+			return msg + " and hasn't changed since"
+		}
+		return msg + "; it has been edited since"
+	}
+
+	var ids []string
+	for _, task := range tasks {
+		if taskFromIssue(task, issue) {
+			ids = append(ids, strings.TrimSpace(task.ID))
+		}
+	}
+	if len(ids) > 0 {
+		return fmt.Sprintf("Issue #%d already has tasks in .ralph/prd.json (%s)", issue.Number, strings.Join(ids, ", "))
+	}
+	return ""
+}
+
+// taskFromIssue reports whether task was tagged with issue. The URL only
+// decides when both sides have one, since issue numbers repeat across repos.
+func taskFromIssue(task prdTask, issue *Issue) bool {
+	if task.Issue == nil || task.Issue.Number != issue.Number {
+		return false
+	}
+	return task.Issue.URL == "" || issue.URL == "" || task.Issue.URL == issue.URL
+}
+
+// checkRepeatFix returns the warning to print for an issue that was already
+// handled, and errIssueAlreadyFixed unless force is set.
+func checkRepeatFix(fixed *fixedIssues, t issueTarget, issue *Issue, tasks []prdTask, force bool) (string, error) {
+	prior := fixed.priorFix(t, issue, tasks)
+	if prior == "" || force {
+		return prior, nil
+	}
+	return prior, errIssueAlreadyFixed
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckRepeatFix(t *testing.T) {
+	target := issueTarget{Provider: providerGitHub, Repo: "o/r", Number: 42}
+	issue := &Issue{Number: 42, Title: "Login broken", Body: "Steps to reproduce", URL: "https://github.com/o/r/issues/42"}
+	edited := &Issue{Number: 42, Title: "Login broken", Body: "Steps to reproduce, updated", URL: issue.URL}
+
+	recorded := &fixedIssues{}
+	recorded.record(target, issue, []prdTask{{ID: "T004"}, {ID: "T005"}}, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+
+	tagged := []prdTask{
+		{ID: "T001", Title: "Unrelated"},
+		{ID: "T002", Title: "Fix login", Issue: &taskIssue{Number: 42, URL: issue.URL}},
+		{ID: "T003", Title: "Other repo", Issue: &taskIssue{Number: 42, URL: "https://github.com/x/y/issues/42"}},
+	}
+
+	tests := []struct {
+		name       string
+		fixed      *fixedIssues
+		target     issueTarget
+		issue      *Issue
+		tasks      []prdTask
+		force      bool
+		wantPrior  string
+		wantForbid bool
+	}{
+		{"new issue", &fixedIssues{}, target, issue, tagged[:1], false, "", false},
+		{"recorded and unchanged", recorded, target, issue, nil, false, "(T004, T005) and hasn't changed since", true},
+		{"recorded and edited", recorded, target, edited, nil, false, "has been edited since", true},
+		{"same number in another repo", recorded, issueTarget{Provider: providerGitHub, Repo: "x/y", Number: 42}, issue, nil, false, "", false},
+		{"tagged in prd.json", &fixedIssues{}, target, issue, tagged, false, "already has tasks in .ralph/prd.json (T002)", true},
+		{"force overrides record", recorded, target, issue, nil, true, "hasn't changed since", false},
+		{"force overrides tags", &fixedIssues{}, target, issue, tagged, true, "(T002)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prior, err := checkRepeatFix(tt.fixed, tt.target, tt.issue, tt.tasks, tt.force)
+			if tt.wantPrior == "" && prior != "" {
+				t.Errorf("prior = %q, want none", prior)
+			}
+			if !strings.Contains(prior, tt.wantPrior) {
+				t.Errorf("prior = %q, want it to contain %q", prior, tt.wantPrior)
+			}
+			if got := errors.Is(err, errIssueAlreadyFixed); got != tt.wantForbid {
+				t.Errorf("checkRepeatFix() error = %v, want already fixed: %v", err, tt.wantForbid)
+			}
+		})
+	}
+}
+
+func TestFixedIssuesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixed_issues.json")
+
+	fixed, err := loadFixedIssues(path)
+	if err != nil || len(fixed.Issues) != 0 {
+		t.Fatalf("loadFixedIssues(missing) = %+v, %v; want empty", fixed, err)
+	}
+
+	target := issueTarget{Provider: providerGitHub, Repo: "o/r", Number: 7}
+	issue := &Issue{Number: 7, Title: "Crash", Body: "Stack trace"}
+	fixed.record(target, issue, []prdTask{{ID: "T001"}}, time.Now())
+	fixed.record(target, issue, []prdTask{{ID: "T002"}, {ID: "T003"}}, time.Now())
+	if err := fixed.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadFixedIssues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Issues) != 1 {
+		t.Fatalf("loaded %d records, want a re-fix to replace the first: %+v", len(loaded.Issues), loaded.Issues)
+	}
+	rec := loaded.Issues[0]
+	if rec.Repo != "o/r" || rec.Number != 7 || rec.Hash != issueContentHash(issue) || !reflect.DeepEqual(rec.TaskIDs, []string{"T002", "T003"}) {
+		t.Errorf("record = %+v", rec)
+	}
+}
@@ -9,16 +9,16 @@ import (
 	"strings"
 )
 
-type GitHubIssue struct {
-	Number int      `json:"number"`
-	Title  string   `json:"title"`
-	Body   string   `json:"body"`
-	State  string   `json:"state"`
-	Labels []string `json:"labels"`
-	URL    string   `json:"url"`
+func getGitHubRepo() (string, error) {
+	remote, err := getOriginRemote()
+	if err != nil {
+		return "", err
+	}
+	return parseGitHubRepo(remote)
 }
 
-func getGitHubRepo() (string, error) {
+// getOriginRemote returns the URL of the origin remote.
+func getOriginRemote() (string, error) {
 	if _, err := exec.LookPath("git"); err != nil {
 		return "", fmt.Errorf("git not found in PATH")
 	}
@@ -31,8 +31,7 @@ func getGitHubRepo() (string, error) {
 		return "", fmt.Errorf("failed to get git remote: %v", err)
 	}
 
-	remote := strings.TrimSpace(stdout.String())
-	return parseGitHubRepo(remote)
+	return strings.TrimSpace(stdout.String()), nil
 }
 
 func parseGitHubRepo(remote string) (string, error) {
@@ -78,7 +77,7 @@ func checkClaudeAvailable() error {
 	return nil
 }
 
-func fetchGitHubIssue(repo string, issueNum int) (*GitHubIssue, error) {
+func fetchGitHubIssue(repo string, issueNum int) (*Issue, error) {
 	cmd := exec.Command("gh", "issue", "view", fmt.Sprintf("%d", issueNum),
 		"--repo", repo,
 		"--json", "number,title,body,state,labels,url")
@@ -115,19 +114,20 @@ func fetchGitHubIssue(repo string, issueNum int) (*GitHubIssue, error) {
 		labels[i] = l.Name
 	}
 
-	return &GitHubIssue{
-		Number: raw.Number,
-		Title:  raw.Title,
-		Body:   raw.Body,
-		State:  raw.State,
-		Labels: labels,
-		URL:    raw.URL,
+	return &Issue{
+		Provider: providerGitHub,
+		Number:   raw.Number,
+		Title:    raw.Title,
+		Body:     raw.Body,
+		State:    raw.State,
+		Labels:   labels,
+		URL:      raw.URL,
 	}, nil
 }
 
-func formatIssueAsWork(issue *GitHubIssue) string {
+func formatIssueAsWork(issue *Issue) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("# GitHub Issue #%d: %s\n\n", issue.Number, issue.Title))
+	sb.WriteString(fmt.Sprintf("# %s Issue #%d: %s\n\n", issueSourceLabel(issue.Provider), issue.Number, issue.Title))
 	sb.WriteString(fmt.Sprintf("**URL:** %s\n", issue.URL))
 	if len(issue.Labels) > 0 {
 		sb.WriteString(fmt.Sprintf("**Labels:** %s\n", strings.Join(issue.Labels, ", ")))
@@ -65,11 +65,12 @@ func TestParseGitHubRepo(t *testing.T) {
 
 func TestParseIssueURL(t *testing.T) {
 	tests := []struct {
-		name       string
-		url        string
-		wantRepo   string
-		wantNumber int
-		wantNil    bool
+		name         string
+		url          string
+		wantRepo     string
+		wantNumber   int
+		wantProvider string
+		wantNil      bool
 	}{
 		{
 			name:       "valid issue URL",
@@ -94,8 +95,22 @@ func TestParseIssueURL(t *testing.T) {
 			wantNil: true,
 		},
 		{
-			name:    "non-GitHub URL",
-			url:     "https://gitlab.com/owner/repo/issues/42",
+			name:         "GitLab issue URL",
+			url:          "https://gitlab.com/group/project/-/issues/42",
+			wantRepo:     "group/project",
+			wantNumber:   42,
+			wantProvider: providerGitLab,
+		},
+		{
+			name:         "GitLab subgroup URL without dash",
+			url:          "https://gitlab.com/group/sub/project/issues/7",
+			wantRepo:     "group/sub/project",
+			wantNumber:   7,
+			wantProvider: providerGitLab,
+		},
+		{
+			name:    "unknown host",
+			url:     "https://bitbucket.org/owner/repo/issues/42",
 			wantNil: true,
 		},
 		{
@@ -124,12 +139,19 @@ func TestParseIssueURL(t *testing.T) {
 			if got.Number != tt.wantNumber {
 				t.Errorf("parseIssueURL().Number = %d, want %d", got.Number, tt.wantNumber)
 			}
+			wantProvider := tt.wantProvider
+			if wantProvider == "" {
+				wantProvider = providerGitHub
+			}
+			if got.Provider != wantProvider {
+				t.Errorf("parseIssueURL().Provider = %q, want %q", got.Provider, wantProvider)
+			}
 		})
 	}
 }
 
 func TestFormatIssueAsWork(t *testing.T) {
-	issue := &GitHubIssue{
+	issue := &Issue{
 		Number: 42,
 		Title:  "Fix the bug",
 		Body:   "This is the bug description",
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// gitlabAPIBase is the GitLab REST endpoint used when glab isn't installed.
+var gitlabAPIBase = "https://gitlab.com/api/v4"
+
+type gitlabIssueSource struct{}
+
+func (gitlabIssueSource) Name() string { return "GitLab" }
+
+func (gitlabIssueSource) CheckAuth() (string, error) {
+	if _, err := exec.LookPath("glab"); err == nil {
+		if err := exec.Command("glab", "auth", "status").Run(); err != nil {
+			return "", fmt.Errorf("glab CLI not authenticated: run 'glab auth login'")
+		}
+		return "GitLab CLI authenticated", nil
+	}
+	if strings.TrimSpace(os.Getenv("GITLAB_TOKEN")) != "" {
+		return "GITLAB_TOKEN set", nil
+	}
+	return "", fmt.Errorf("glab CLI not found in PATH and GITLAB_TOKEN not set (install: https://gitlab.com/gitlab-org/cli)")
+}
+
+func (gitlabIssueSource) DetectRepo() (string, error) {
+	remote, err := getOriginRemote()
+	if err != nil {
+		return "", err
+	}
+	return parseGitLabRepo(remote)
+}
+
+func (gitlabIssueSource) FetchIssue(repo string, number int) (*Issue, error) {
+	if _, err := exec.LookPath("glab"); err == nil {
+		return fetchGitLabIssueCLI(repo, number)
+	}
+	return fetchGitLabIssueAPI(gitlabAPIBase, os.Getenv("GITLAB_TOKEN"), repo, number)
+}
+
+func parseGitLabRepo(remote string) (string, error) {
+	// Handle SSH: git@gitlab.com:group/subgroup/repo.git
+	sshRe := regexp.MustCompile(`git@gitlab\.com:(.+?)(\.git)?$`)
+	if m := sshRe.FindStringSubmatch(remote); m != nil && strings.Contains(m[1], "/") {
+		return strings.TrimSuffix(m[1], ".git"), nil
+	}
+
+	// Handle HTTPS: https://gitlab.com/group/subgroup/repo.git
+	httpsRe := regexp.MustCompile(`https://gitlab\.com/(.+?)(\.git)?$`)
+	if m := httpsRe.FindStringSubmatch(remote); m != nil && strings.Contains(m[1], "/") {
+		return strings.TrimSuffix(m[1], ".git"), nil
+	}
+
+	return "", fmt.Errorf("could not parse GitLab project from remote: %s", remote)
+}
+
+func fetchGitLabIssueCLI(repo string, issueNum int) (*Issue, error) {
+	cmd := exec.Command("glab", "issue", "view", fmt.Sprintf("%d", issueNum),
+		"--repo", repo,
+		"--output", "json")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg == "" {
+			errMsg = err.Error()
+		}
+		return nil, fmt.Errorf("failed to fetch issue #%d: %s", issueNum, errMsg)
+	}
+	return parseGitLabIssue(stdout.Bytes())
+}
+
+func fetchGitLabIssueAPI(base, token, repo string, issueNum int) (*Issue, error) {
+	endpoint := fmt.Sprintf("%s/projects/%s/issues/%d", strings.TrimRight(base, "/"), url.PathEscape(repo), issueNum)
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(token) != "" {
+		req.Header.Set("PRIVATE-TOKEN", strings.TrimSpace(token))
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue #%d: %v", issueNum, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue #%d: %v", issueNum, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch issue #%d: %s", issueNum, resp.Status)
+	}
+	return parseGitLabIssue(body)
+}
+
+// parseGitLabIssue converts a GitLab issue (API or glab JSON) into an Issue.
+func parseGitLabIssue(data []byte) (*Issue, error) {
+	var raw struct {
+		IID         int      `json:"iid"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		State       string   `json:"state"`
+		Labels      []string `json:"labels"`
+		WebURL      string   `json:"web_url"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse issue JSON: %v", err)
+	}
+
+	// GitLab reports open issues as "opened".
+	state := raw.State
+	if state == "opened" {
+		state = "open"
+	}
+
+	return &Issue{
+		Provider: providerGitLab,
+		Number:   raw.IID,
+		Title:    raw.Title,
+		Body:     raw.Description,
+		State:    state,
+		Labels:   raw.Labels,
+		URL:      raw.WebURL,
+	}, nil
+}
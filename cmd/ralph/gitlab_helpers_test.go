@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseGitLabRepo(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		want    string
+		wantErr bool
+	}{
+		{name: "SSH format", remote: "git@gitlab.com:group/project.git", want: "group/project"},
+		{name: "SSH subgroup", remote: "git@gitlab.com:group/sub/project.git", want: "group/sub/project"},
+		{name: "HTTPS format", remote: "https://gitlab.com/group/project.git", want: "group/project"},
+		{name: "HTTPS without .git", remote: "https://gitlab.com/group/sub/project", want: "group/sub/project"},
+		{name: "GitHub remote", remote: "git@github.com:owner/repo.git", wantErr: true},
+		{name: "no project path", remote: "https://gitlab.com/group", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGitLabRepo(tt.remote)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGitLabRepo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseGitLabRepo() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchGitLabIssueAPI(t *testing.T) {
+	var gotPath, gotToken string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotToken = r.Header.Get("PRIVATE-TOKEN")
+		if !strings.HasSuffix(gotPath, "/issues/42") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"iid":42,"title":"Fix login","description":"Steps...","state":"opened","labels":["bug"],"web_url":"https://gitlab.com/group/sub/project/-/issues/42"}`))
+	}))
+	defer srv.Close()
+
+	issue, err := fetchGitLabIssueAPI(srv.URL, "secret", "group/sub/project", 42)
+	if err != nil {
+		t.Fatalf("fetchGitLabIssueAPI() error = %v", err)
+	}
+	if gotPath != "/projects/group%2Fsub%2Fproject/issues/42" {
+		t.Errorf("request path = %q", gotPath)
+	}
+	if gotToken != "secret" {
+		t.Errorf("PRIVATE-TOKEN = %q, want secret", gotToken)
+	}
+	if issue.Number != 42 || issue.Title != "Fix login" || issue.Body != "Steps..." || issue.State != "open" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+	if issue.Provider != providerGitLab || len(issue.Labels) != 1 || issue.Labels[0] != "bug" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+
+	if _, err := fetchGitLabIssueAPI(srv.URL, "", "group/project", 7); err == nil {
+		t.Error("expected error for missing issue")
+	}
+}
+
+func TestProviderFromRemote(t *testing.T) {
+	tests := map[string]string{
+		"git@gitlab.com:group/project.git":       providerGitLab,
+		"https://gitlab.example.com/group/p.git": providerGitLab,
+		"git@github.com:owner/repo.git":          providerGitHub,
+		"":                                       providerGitHub,
+	}
+	for remote, want := range tests {
+		if got := providerFromRemote(remote); got != want {
+			t.Errorf("providerFromRemote(%q) = %q, want %q", remote, got, want)
+		}
+	}
+}
+
+func TestFormatIssueAsWorkGitLab(t *testing.T) {
+	got := formatIssueAsWork(&Issue{Provider: providerGitLab, Number: 3, Title: "Crash"})
+	if !strings.HasPrefix(got, "# GitLab Issue #3: Crash") {
+		t.Errorf("unexpected header: %q", got)
+	}
+}
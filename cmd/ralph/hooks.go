@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/config"
+)
+
+// runHook executes one pre_run/post_run command with sh -c, enforcing the
+// hook's timeout. The config loader has already expanded ${VAR} references.
+func runHook(ctx context.Context, h config.HookConfig) error {
+	timeout := h.GetTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	// Don't wait on children of sh that still hold the output pipe.
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	if out := strings.TrimSpace(string(output)); out != "" {
+		return fmt.Errorf("%w\nOutput: %s", err, out)
+	}
+	return err
+}
+
+// runPreRunHooks runs hooks in order and stops at the first failure.
+func runPreRunHooks(ctx context.Context, hooks []config.HookConfig, w io.Writer) error {
+	for _, h := range hooks {
+		fmt.Fprintf(w, "▶ pre_run: %s\n", h.DisplayName())
+		if err := runHook(ctx, h); err != nil {
+			return fmt.Errorf("pre_run hook %q failed: %w", h.DisplayName(), err)
+		}
+	}
+	return nil
+}
+
+// runPostRunHooks runs every hook in order, warning on failures without
+// stopping. It uses a fresh context so hooks still run after a cancel.
+func runPostRunHooks(hooks []config.HookConfig, w io.Writer) {
+	for _, h := range hooks {
+		fmt.Fprintf(w, "▶ post_run: %s\n", h.DisplayName())
+		if err := runHook(context.Background(), h); err != nil {
+			fmt.Fprintf(w, "⚠️  post_run hook %q failed: %v\n", h.DisplayName(), err)
+		}
+	}
+}
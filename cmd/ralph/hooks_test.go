@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chr1sbest/wiggum/internal/config"
+)
+
+func TestRunPreRunHooksOrderAndFailure(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "order.log")
+	t.Setenv("HOOK_LOG", logPath)
+
+	hooks := []config.HookConfig{
+		{Name: "first", Command: "echo first >> ${HOOK_LOG}"},
+		{Name: "second", Command: "echo second >> ${HOOK_LOG}"},
+		{Name: "broken", Command: "echo boom; exit 3"},
+		{Name: "never", Command: "echo never >> ${HOOK_LOG}"},
+	}
+
+	var out bytes.Buffer
+	err := runPreRunHooks(context.Background(), hooks, &out)
+	if err == nil {
+		t.Fatal("expected pre_run failure")
+	}
+	for _, want := range []string{`pre_run hook "broken" failed`, "exit status 3", "boom"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+
+	data, _ := os.ReadFile(logPath)
+	if got := string(data); got != "first\nsecond\n" {
+		t.Errorf("hooks ran as %q, want first then second and nothing after the failure", got)
+	}
+	if strings.Contains(out.String(), "never") {
+		t.Errorf("hook after the failure was started:\n%s", out.String())
+	}
+}
+
+func TestRunPostRunHooksWarnAndContinue(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "order.log")
+	t.Setenv("HOOK_LOG", logPath)
+
+	hooks := []config.HookConfig{
+		{Command: "exit 1"},
+		{Name: "cleanup", Command: "echo cleanup >> ${HOOK_LOG}"},
+	}
+
+	var out bytes.Buffer
+	runPostRunHooks(hooks, &out)
+
+	if !strings.Contains(out.String(), `post_run hook "exit 1" failed`) {
+		t.Errorf("expected a warning for the failing hook, got:\n%s", out.String())
+	}
+	data, _ := os.ReadFile(logPath)
+	if string(data) != "cleanup\n" {
+		t.Errorf("later post_run hook did not run after a failure: %q", data)
+	}
+}
+
+func TestRunHookTimeout(t *testing.T) {
+	err := runHook(context.Background(), config.HookConfig{Command: "sleep 5", Timeout: "50ms"})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestRunHooksAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := runPreRunHooks(ctx, []config.HookConfig{{Command: "true"}}, &bytes.Buffer{}); err == nil {
+		t.Error("expected pre_run hooks to fail once the run is cancelled")
+	}
+
+	// post_run hooks ignore the run's context, so teardown still happens.
+	logPath := filepath.Join(t.TempDir(), "post.log")
+	t.Setenv("HOOK_LOG", logPath)
+	runPostRunHooks([]config.HookConfig{{Command: "echo done > ${HOOK_LOG}"}}, &bytes.Buffer{})
+	if data, _ := os.ReadFile(logPath); string(data) != "done\n" {
+		t.Errorf("post_run hook did not run: %q", data)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/loop/steps"
+)
+
+// initAgent is an agent CLI that `ralph init -agent` can set a project up
+// for. The same agent analyzes the requirements during init and runs the
+// loop's agent step afterwards.
+type initAgent struct {
+	// Type is the agent step's agent_type.
+	Type string
+	// Name is how messages refer to the agent.
+	Name string
+	// Binary is the executable looked up on PATH and written to the config.
+	Binary string
+	// InstallHint tells the user how to get Binary.
+	InstallHint string
+}
+
+var initAgents = map[string]initAgent{
+	steps.AgentTypeClaude: {
+		Type:        steps.AgentTypeClaude,
+		Name:        "Claude",
+		Binary:      "claude",
+		InstallHint: "https://code.claude.com/docs/en/setup",
+	},
+	steps.AgentTypeAider: {
+		Type:        steps.AgentTypeAider,
+		Name:        "aider",
+		Binary:      "aider",
+		InstallHint: "python -m pip install aider-install && aider-install",
+	},
+}
+
+// lookupInitAgent returns the agent named by -agent, case-insensitively.
+func lookupInitAgent(name string) (initAgent, error) {
+	a, ok := initAgents[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return initAgent{}, fmt.Errorf("unknown agent %q (supported: %s)", name, strings.Join(slices.Sorted(maps.Keys(initAgents)), ", "))
+	}
+	return a, nil
+}
+
+// isClaude reports whether a is the default Claude agent.
+func (a initAgent) isClaude() bool {
+	return a.Type == steps.AgentTypeClaude
+}
+
+// preflight checks that the agent's binary is installed and runs.
+func (a initAgent) preflight() error {
+	if a.isClaude() && a.Binary == "claude" {
+		return validateClaudePreflight()
+	}
+	if _, err := exec.LookPath(a.Binary); err != nil {
+		return fmt.Errorf("%s is required but `%s` was not found in PATH.\n\nFix:\n  - Install it: %s\n  - Confirm it works: %s --version", a.Name, a.Binary, a.InstallHint, a.Binary)
+	}
+	if out, err := exec.Command(a.Binary, "--version").CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("%s appears to be installed, but is not working:\n%s\n\nFix:\n  - Run `%s --version` and fix any setup errors\n  - Then retry", a.Name, msg, a.Binary)
+	}
+	return nil
+}
+
+// runOnce sends prompt to the agent and returns its answer. Claude gets
+// "default" when model is empty; aider runs in ask mode without git, so it
+// answers without editing files.
+func (a initAgent) runOnce(prompt, model string, timeout time.Duration) (string, error) {
+	model = strings.TrimSpace(model)
+	if a.isClaude() {
+		if model == "" {
+			model = "default"
+		}
+		return runClaudeOnceWithModel(prompt, model, timeout)
+	}
+	args := []string{"--chat-mode", "ask", "--yes-always", "--no-git", "--no-pretty", "--no-stream"}
+	if model != "" {
+		args = append(args, "--model", model)
+	}
+	args = append(args, "--message", prompt)
+	return runAgentCommandOnce(a.Name, a.Binary, args, timeout)
+}
+
+// configuredAgents returns the agents run by the enabled agent steps of cfg,
+// one per binary, so `ralph run` checks the CLIs it will actually call.
+func configuredAgents(cfg *config.Config) ([]initAgent, error) {
+	var agents []initAgent
+	seen := map[string]bool{}
+	for _, s := range cfg.Steps {
+		if s.Type != "agent" || !s.IsEnabled() {
+			continue
+		}
+		agentType, binary, err := steps.AgentBinary(s.Config)
+		if err != nil {
+			return nil, fmt.Errorf("agent step %s: %w", s.Name, err)
+		}
+		if seen[binary] {
+			continue
+		}
+		seen[binary] = true
+		// Config validation has already rejected unknown agent types.
+		a := initAgents[agentType]
+		a.Binary = binary
+		agents = append(agents, a)
+	}
+	return agents, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupInitAgent(t *testing.T) {
+	for _, name := range []string{"claude", "Aider", " aider "} {
+		if _, err := lookupInitAgent(name); err != nil {
+			t.Errorf("lookupInitAgent(%q) error = %v", name, err)
+		}
+	}
+	_, err := lookupInitAgent("cursor")
+	if err == nil || !strings.Contains(err.Error(), `unknown agent "cursor" (supported: aider, claude)`) {
+		t.Errorf("lookupInitAgent(cursor) error = %v", err)
+	}
+}
+
+func TestInitAgentPreflightChecksBinary(t *testing.T) {
+	binDir := t.TempDir()
+	t.Setenv("PATH", binDir)
+	aider, _ := lookupInitAgent("aider")
+
+	if err := aider.preflight(); err == nil || !strings.Contains(err.Error(), "`aider` was not found in PATH") {
+		t.Errorf("preflight() without aider = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(binDir, "aider"), []byte("#!/bin/sh\necho 'aider 0.86.1'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := aider.preflight(); err != nil {
+		t.Errorf("preflight() with aider on PATH = %v; claude should not be needed", err)
+	}
+}
+
+func TestInitAgentRunOnceAider(t *testing.T) {
+	binDir := t.TempDir()
+	fakeAider := "#!/bin/sh\nfor a in \"$@\"; do echo \"arg: $a\"; done\n"
+	if err := os.WriteFile(filepath.Join(binDir, "aider"), []byte(fakeAider), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	aider, _ := lookupInitAgent("aider")
+
+	out, err := aider.runOnce("Plan the tasks", "gpt-4o", time.Minute)
+	if err != nil {
+		t.Fatalf("runOnce() error = %v", err)
+	}
+	for _, want := range []string{"arg: --chat-mode\narg: ask\n", "arg: --no-git\n", "arg: --model\narg: gpt-4o\n", "arg: --message\narg: Plan the tasks\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("aider args missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = aider.runOnce("Plan the tasks", "", time.Minute)
+	if err != nil || strings.Contains(out, "--model") {
+		t.Errorf("runOnce() without a model passed --model: %v\n%s", err, out)
+	}
+}
+
+func TestNewProjectCmdAgentAider(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	binDir := t.TempDir()
+	prd := `{"version":1,"tasks":[{"id":"T001","title":"Scaffold","priority":"high","status":"todo"}]}`
+	fakeAider := "#!/bin/sh\n[ \"$1\" = --version ] && { echo 'aider 0.86.1'; exit 0; }\necho 'Aider v0.86.1'\necho '---FILE: prd.json---'\necho '" + prd + "'\n"
+	fakeClaude := "#!/bin/sh\ntouch \"" + filepath.Join(dir, "claude-called") + "\"\nexit 1\n"
+	for name, script := range map[string]string{"aider": fakeAider, "claude": fakeClaude} {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if err := os.WriteFile("requirements.md", []byte("# Todo app\nA CLI todo list.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newProjectCmd([]string{"-agent", "aider", "-model", "sonnet", "requirements.md"})
+
+	if _, err := os.Stat("claude-called"); err == nil {
+		t.Error("ralph init -agent aider called claude")
+	}
+	data, err := os.ReadFile(filepath.Join(".ralph", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct {
+		Steps []struct {
+			Type   string            `json:"type"`
+			Name   string            `json:"name"`
+			Config map[string]string `json:"config"`
+		} `json:"steps"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("config.json is not valid JSON: %v\n%s", err, data)
+	}
+	if len(cfg.Steps) != 1 {
+		t.Fatalf("config.json has %d steps, want 1", len(cfg.Steps))
+	}
+	step := cfg.Steps[0]
+	if step.Type != "agent" || step.Name != "aider" || step.Config["agent_type"] != "aider" || step.Config["binary"] != "aider" || step.Config["model"] != "sonnet" {
+		t.Errorf("agent step = %+v, want an aider agent step with model sonnet", step)
+	}
+	if got := configValidate(filepath.Join(".ralph", "config.json")); got != 0 {
+		t.Errorf("generated config failed validation (code %d)", got)
+	}
+}
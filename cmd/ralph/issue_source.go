@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	providerGitHub = "github"
+	providerGitLab = "gitlab"
+)
+
+// Issue is a tracker issue turned into work by `ralph fix`.
+type Issue struct {
+	Provider string   `json:"provider,omitempty"`
+	Number   int      `json:"number"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	State    string   `json:"state"`
+	Labels   []string `json:"labels"`
+	URL      string   `json:"url"`
+}
+
+// issueSource fetches issues from one hosting provider.
+type issueSource interface {
+	// Name is the provider's display name, e.g. "GitHub".
+	Name() string
+	// CheckAuth verifies credentials and describes what was found.
+	CheckAuth() (string, error)
+	// DetectRepo infers the project path from the origin remote.
+	DetectRepo() (string, error)
+	FetchIssue(repo string, number int) (*Issue, error)
+}
+
+type githubIssueSource struct{}
+
+func (githubIssueSource) Name() string { return "GitHub" }
+
+func (githubIssueSource) CheckAuth() (string, error) {
+	if err := checkGitHubAuth(); err != nil {
+		return "", err
+	}
+	return "GitHub CLI authenticated", nil
+}
+
+func (githubIssueSource) DetectRepo() (string, error) { return getGitHubRepo() }
+
+func (githubIssueSource) FetchIssue(repo string, number int) (*Issue, error) {
+	return fetchGitHubIssue(repo, number)
+}
+
+// newIssueSource returns the source for provider ("github" or "gitlab").
+func newIssueSource(provider string) (issueSource, error) {
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case providerGitHub, "":
+		return githubIssueSource{}, nil
+	case providerGitLab:
+		return gitlabIssueSource{}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q (use github or gitlab)", provider)
+	}
+}
+
+// providerFromRemote guesses the provider from a git remote URL, defaulting to GitHub.
+func providerFromRemote(remote string) string {
+	if strings.Contains(strings.ToLower(remote), "gitlab") {
+		return providerGitLab
+	}
+	return providerGitHub
+}
+
+func issueSourceLabel(provider string) string {
+	if provider == providerGitLab {
+		return "GitLab"
+	}
+	return "GitHub"
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+// acquireRunLock takes the project lock for runID. AcquireLock already clears
+// a lock left by a dead process on this host. With force, an unreadable lock
+// file or one taken on another host is cleared too; a live local run's lock
+// never is.
+func acquireRunLock(trk *tracker.Writer, runID string, force bool) (func() error, error) {
+	release, err := trk.AcquireLock(runID)
+	var held *tracker.LockHeldError
+	if force && errors.As(err, &held) && (held.Stale || held.OtherHost) {
+		if err := trk.ClearStaleLock(); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Cleared stale lock %s\n", trk.LockPath)
+		return trk.AcquireLock(runID)
+	}
+	return release, err
+}
+
+// describeLockError explains a failed lock acquisition, using the holder's
+// run state when it belongs to the same run. Other errors print as is.
+func describeLockError(err error, trk *tracker.Writer, now time.Time) string {
+	var held *tracker.LockHeldError
+	if !errors.As(err, &held) {
+		return err.Error()
+	}
+	if held.Lock == nil && !held.Stale {
+		return fmt.Sprintf("The lock file %s can't be read yet; another ralph run may be starting.\nTry again in a few seconds.", trk.LockPath)
+	}
+	if held.Lock == nil {
+		return fmt.Sprintf("The lock file %s exists but can't be read.\nIf no ralph run is active, rerun with -force to clear it.", trk.LockPath)
+	}
+
+	l := held.Lock
+	var b strings.Builder
+	switch {
+	case held.OtherHost:
+		fmt.Fprintf(&b, "A ralph run on another host holds this project's lock:\n")
+	case held.Stale:
+		fmt.Fprintf(&b, "A previous ralph run left its lock behind, but its process is no longer running:\n")
+	default:
+		fmt.Fprintf(&b, "Another ralph run is active in this project:\n")
+	}
+	fmt.Fprintf(&b, "  PID:      %d\n", l.PID)
+	if l.Host != "" {
+		fmt.Fprintf(&b, "  Host:     %s\n", l.Host)
+	}
+	if l.RunID != "" {
+		fmt.Fprintf(&b, "  Run:      %s\n", l.RunID)
+	}
+	if !l.StartedAt.IsZero() {
+		fmt.Fprintf(&b, "  Started:  %s (%s ago)\n", l.StartedAt.Local().Format("2006-01-02 15:04:05"), formatElapsed(now.Sub(l.StartedAt)))
+	}
+	if rs, _ := trk.LoadRunState(); rs != nil && (rs.RunID == l.RunID || rs.PID == l.PID) {
+		if rs.CurrentStep != "" {
+			fmt.Fprintf(&b, "  Step:     %s (loop %d)\n", rs.CurrentStep, rs.LoopNumber)
+		}
+		if rs.CurrentTaskID != "" {
+			fmt.Fprintf(&b, "  Task:     [%s] %s\n", rs.CurrentTaskID, rs.CurrentTask)
+		}
+	}
+	switch {
+	case held.OtherHost:
+		b.WriteString("If that run has stopped, rerun with -force to clear the lock.")
+	case held.Stale:
+		b.WriteString("Rerun with -force to clear the stale lock.")
+	default:
+		b.WriteString("Stop it with `ralph stop`, or watch it with `ralph status`.")
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+func TestDescribeLockError(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-35 * time.Minute)
+	trk := tracker.NewWriter(t.TempDir())
+	rs := tracker.RunState{RunID: "run-7", PID: 4242, CurrentStep: "agent", LoopNumber: 3, CurrentTaskID: "T-4", CurrentTask: "Add login"}
+	data, _ := json.Marshal(rs)
+	if err := os.WriteFile(trk.RunStatePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	live := &tracker.LockHeldError{Lock: &tracker.Lock{PID: 4242, RunID: "run-7", StartedAt: started}}
+	out := describeLockError(live, trk, now)
+	for _, want := range []string{"Another ralph run is active", "PID:      4242", "Run:      run-7", "(35m00s ago)", "Step:     agent (loop 3)", "[T-4] Add login", "ralph stop"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("live lock message missing %q:\n%s", want, out)
+		}
+	}
+
+	stale := &tracker.LockHeldError{Lock: &tracker.Lock{PID: 99, RunID: "old"}, Stale: true}
+	out = describeLockError(stale, trk, now)
+	if !strings.Contains(out, "no longer running") || !strings.Contains(out, "-force") || strings.Contains(out, "Step:") {
+		t.Errorf("unexpected stale lock message:\n%s", out)
+	}
+
+	remote := &tracker.LockHeldError{Lock: &tracker.Lock{PID: 77, RunID: "remote", Host: "build-2"}, OtherHost: true}
+	out = describeLockError(remote, trk, now)
+	if !strings.Contains(out, "another host") || !strings.Contains(out, "Host:     build-2") || !strings.Contains(out, "-force") {
+		t.Errorf("unexpected other-host lock message:\n%s", out)
+	}
+
+	if out := describeLockError(&tracker.LockHeldError{Stale: true}, trk, now); !strings.Contains(out, "can't be read") {
+		t.Errorf("unexpected unreadable lock message:\n%s", out)
+	}
+	if out := describeLockError(&tracker.LockHeldError{}, trk, now); !strings.Contains(out, "may be starting") || strings.Contains(out, "-force") {
+		t.Errorf("unexpected message for a lock still being written:\n%s", out)
+	}
+	if out := describeLockError(errors.New("disk full"), trk, now); out != "disk full" {
+		t.Errorf("other errors should print as is, got %q", out)
+	}
+}
+
+func TestAcquireRunLockForceClearsStaleLock(t *testing.T) {
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot start a process: %v", err)
+	}
+	trk := tracker.NewWriter(t.TempDir())
+	data, _ := json.Marshal(tracker.Lock{PID: cmd.ProcessState.Pid(), RunID: "crashed", Host: "other-host.invalid"})
+	if err := os.WriteFile(trk.LockPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := acquireRunLock(trk, "next", false); !errors.Is(err, tracker.ErrLockHeld) {
+		t.Fatalf("without -force expected ErrLockHeld for another host's lock, got %v", err)
+	}
+	release, err := acquireRunLock(trk, "next", true)
+	if err != nil {
+		t.Fatalf("with -force: %v", err)
+	}
+	defer func() { _ = release() }()
+	if l, _ := trk.LoadLock(); l == nil || l.RunID != "next" {
+		t.Errorf("lock = %+v, want run next", l)
+	}
+
+	// A live holder keeps its lock even with -force.
+	if _, err := acquireRunLock(trk, "third", true); !errors.Is(err, tracker.ErrLockHeld) {
+		t.Errorf("expected live lock to be kept, got %v", err)
+	}
+}
@@ -22,6 +22,8 @@ func main() {
 	switch os.Args[1] {
 	case "run":
 		os.Exit(runCmd(os.Args[2:]))
+	case "resume":
+		os.Exit(resumeCmd(os.Args[2:]))
 	case "init", "new-project":
 		newProjectCmd(os.Args[2:])
 	case "add", "new-work":
@@ -30,12 +32,34 @@ func main() {
 		fixCmd(os.Args[2:])
 	case "pr":
 		os.Exit(prCmd(os.Args[2:]))
+	case "status":
+		os.Exit(statusCmd(os.Args[2:]))
+	case "stop":
+		os.Exit(stopCmd(os.Args[2:]))
+	case "clean":
+		os.Exit(cleanCmd(os.Args[2:]))
+	case "tasks":
+		os.Exit(tasksCmd(os.Args[2:]))
+	case "task":
+		os.Exit(taskCmd(os.Args[2:]))
+	case "config":
+		os.Exit(configCmd(os.Args[2:]))
+	case "schema":
+		os.Exit(schemaCmd(os.Args[2:]))
+	case "doctor":
+		os.Exit(doctorCmd(os.Args[2:]))
+	case "logs":
+		os.Exit(logsCmd(os.Args[2:]))
+	case "metrics":
+		os.Exit(metricsCmd(os.Args[2:]))
 	case "upgrade":
 		os.Exit(upgradeCmd(os.Args[2:]))
 	case "eval":
 		os.Exit(evalCmd(os.Args[2:]))
+	case "completion":
+		os.Exit(completionCmd(os.Args[2:]))
 	case "version":
-		fmt.Println(versionLine())
+		os.Exit(versionCmd(os.Args[2:]))
 	case "help", "-h", "--help":
 		printUsage()
 	default:
@@ -56,12 +80,24 @@ Usage:
 
 Commands:
   run          Run the main loop (Ralph does the work)
+  resume       Continue after an interrupted run
   init         Start a new Ralph project (fresh crayons!)
   add          Add more work for Ralph to think about
-  fix          Create tasks from a GitHub issue
+  fix          Create tasks from a GitHub or GitLab issue
   pr           Push branch and open a pull request
+  status       Show the current or most recent run
+  stop         Stop a running loop
+  clean        Remove logs, run state and metrics (keeps tasks)
+  metrics      Summarize token usage and cost across runs
+  logs         Show, filter, or follow the run log
+  tasks        List tasks and their status
+  task         Add, remove, or update a task (status, retry)
+  config       Validate loop configuration
+  schema       Print the JSON Schema for prd.json
+  doctor       Check that Claude, git, and your project are set up
   eval         Run evaluation suites against ralph and oneshot approaches
   upgrade      Check for updates and upgrade Ralph
+  completion   Generate a bash, zsh or fish completion script
   version      Show Ralph's version number
   help         Show this message again (I like explaining)
 
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/loop"
+	"github.com/chr1sbest/wiggum/internal/loop/steps"
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+// webhookTimeout bounds the notify POST so a slow endpoint can't hold up exit.
+const webhookTimeout = 10 * time.Second
+
+// webhookPayload is the JSON body POSTed to notify.webhook_url.
+type webhookPayload struct {
+	Event    string              `json:"event"` // complete, error, blocked or usage_limit
+	Config   string              `json:"config"`
+	RunID    string              `json:"run_id"`
+	Reason   string              `json:"reason,omitempty"`
+	ResetsAt *time.Time          `json:"resets_at,omitempty"` // usage_limit only
+	Time     time.Time           `json:"time"`
+	Metrics  *tracker.RunMetrics `json:"metrics,omitempty"`
+}
+
+// runEndEvent maps how the loop ended to a notify event. It returns "" for an
+// interrupted run, which isn't reported.
+func runEndEvent(err error) string {
+	if err == nil {
+		return config.NotifyEventComplete
+	}
+	if errors.Is(err, context.Canceled) {
+		return ""
+	}
+	if exitErr, ok := steps.IsAgentExitError(err); ok {
+		if exitErr.Reason == agent.ExitReasonTasksBlocked {
+			return config.NotifyEventBlocked
+		}
+		return config.NotifyEventComplete
+	}
+	var budgetErr *loop.BudgetExceededError
+	var maxLoopsErr *loop.MaxLoopsReachedError
+	if errors.As(err, &budgetErr) || errors.As(err, &maxLoopsErr) {
+		return config.NotifyEventComplete
+	}
+	return config.NotifyEventError
+}
+
+// notifyRunEnd posts the run outcome to the configured webhook if the event
+// is selected. It is best-effort: failures are printed to w and never change
+// the exit code.
+func notifyRunEnd(cfg *config.Config, trk *tracker.Writer, runID string, runErr error, w io.Writer) {
+	payload := webhookPayload{Event: runEndEvent(runErr)}
+	if runErr != nil {
+		payload.Reason = runErr.Error()
+	}
+	sendNotify(cfg, trk, runID, payload, w)
+}
+
+// notifyUsageLimitWait tells the webhook that the run is pausing until the
+// Claude usage limit resets at resetAt.
+func notifyUsageLimitWait(cfg *config.Config, trk *tracker.Writer, runID string, usageErr error, resetAt time.Time, w io.Writer) {
+	resetAt = resetAt.UTC()
+	sendNotify(cfg, trk, runID, webhookPayload{
+		Event:    config.NotifyEventUsageLimit,
+		Reason:   usageErr.Error(),
+		ResetsAt: &resetAt,
+	}, w)
+}
+
+// sendNotify fills in the common payload fields and posts it if the event
+// is selected.
+func sendNotify(cfg *config.Config, trk *tracker.Writer, runID string, payload webhookPayload, w io.Writer) {
+	if payload.Event == "" || !cfg.Notify.Wants(payload.Event) {
+		return
+	}
+	payload.Config = cfg.Name
+	payload.RunID = runID
+	payload.Time = time.Now().UTC()
+	if m, _ := trk.LoadMetrics(); m != nil {
+		payload.Metrics = m
+	}
+	if err := postWebhook(cfg.Notify.WebhookURL, payload); err != nil {
+		fmt.Fprintf(w, "⚠️  notify webhook failed: %v\n", err)
+	}
+}
+
+// postWebhook sends payload as JSON and treats any non-2xx reply as an error.
+func postWebhook(url string, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ralph/"+version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/loop"
+	"github.com/chr1sbest/wiggum/internal/loop/steps"
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+func TestRunEndEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"once finished", nil, config.NotifyEventComplete},
+		{"plan complete", &steps.AgentExitError{Reason: agent.ExitReasonPlanComplete}, config.NotifyEventComplete},
+		{"blocked", &steps.AgentExitError{Reason: agent.ExitReasonTasksBlocked}, config.NotifyEventBlocked},
+		{"budget", &loop.BudgetExceededError{BudgetUSD: 1, SpentUSD: 1.2}, config.NotifyEventComplete},
+		{"max loops", fmt.Errorf("wrapped: %w", &loop.MaxLoopsReachedError{MaxLoops: 3}), config.NotifyEventComplete},
+		{"usage limit", &steps.ClaudeUsageError{Details: "quota"}, config.NotifyEventError},
+		{"failure", errors.New("step failed"), config.NotifyEventError},
+		{"interrupted", context.Canceled, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runEndEvent(tt.err); got != tt.want {
+				t.Errorf("runEndEvent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyWants(t *testing.T) {
+	all := &config.NotifyConfig{WebhookURL: "http://x"}
+	onlyError := &config.NotifyConfig{WebhookURL: "http://x", OnError: true}
+	for _, event := range []string{config.NotifyEventComplete, config.NotifyEventError, config.NotifyEventBlocked, config.NotifyEventUsageLimit} {
+		if !all.Wants(event) {
+			t.Errorf("no selection should send %q", event)
+		}
+		if got, want := onlyError.Wants(event), event == config.NotifyEventError; got != want {
+			t.Errorf("on_error only: Wants(%q) = %v, want %v", event, got, want)
+		}
+	}
+	var unset *config.NotifyConfig
+	if unset.Wants(config.NotifyEventError) {
+		t.Error("nil notify config should send nothing")
+	}
+}
+
+func TestNotifyRunEndPostsPayload(t *testing.T) {
+	var got webhookPayload
+	var contentType string
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		contentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("invalid JSON payload: %v\n%s", err, body)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	trk := tracker.NewWriter(t.TempDir())
+	if _, err := trk.LoadOrInitMetrics("run-1"); err != nil {
+		t.Fatal(err)
+	}
+	trk.AddUsage("run-1", tracker.UsageDelta{InputTokens: 100, OutputTokens: 20, TotalTokens: 120, CostUSD: 0.5})
+
+	cfg := &config.Config{Name: "demo", Notify: &config.NotifyConfig{WebhookURL: srv.URL, OnBlocked: true}}
+	var out bytes.Buffer
+
+	// Not selected: no request.
+	notifyRunEnd(cfg, trk, "run-1", errors.New("boom"), &out)
+	if calls != 0 {
+		t.Fatalf("expected no webhook for unselected event, got %d calls", calls)
+	}
+
+	notifyRunEnd(cfg, trk, "run-1", &steps.AgentExitError{Reason: agent.ExitReasonTasksBlocked}, &out)
+	if calls != 1 {
+		t.Fatalf("expected 1 webhook call, got %d", calls)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+	if got.Event != "blocked" || got.Config != "demo" || got.RunID != "run-1" || !strings.Contains(got.Reason, "tasks_blocked") {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+	if got.Metrics == nil || got.Metrics.TotalTokens != 120 || got.Metrics.TotalCostUSD != 0.5 {
+		t.Errorf("payload metrics = %+v, want 120 tokens and $0.50", got.Metrics)
+	}
+	if got.Time.IsZero() {
+		t.Error("payload time not set")
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestNotifyRunEndReportsFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Name: "demo", Notify: &config.NotifyConfig{WebhookURL: srv.URL}}
+	var out bytes.Buffer
+	notifyRunEnd(cfg, tracker.NewWriter(t.TempDir()), "run-1", nil, &out)
+	if !strings.Contains(out.String(), "notify webhook failed") || !strings.Contains(out.String(), "500") {
+		t.Errorf("expected failure warning, got %q", out.String())
+	}
+}
+
+func TestNotifyUsageLimitWaitPostsResetTime(t *testing.T) {
+	var got webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("invalid JSON payload: %v\n%s", err, body)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Name: "demo", Notify: &config.NotifyConfig{WebhookURL: srv.URL, OnUsageLimit: true}}
+	resetAt := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	notifyUsageLimitWait(cfg, tracker.NewWriter(t.TempDir()), "run-1", &steps.ClaudeUsageError{Details: "resets 3pm"}, resetAt, &out)
+	if got.Event != config.NotifyEventUsageLimit || got.RunID != "run-1" || !strings.Contains(got.Reason, "resets 3pm") {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+	if got.ResetsAt == nil || !got.ResetsAt.Equal(resetAt) {
+		t.Errorf("resets_at = %v, want %s", got.ResetsAt, resetAt)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
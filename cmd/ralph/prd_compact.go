@@ -77,7 +77,7 @@ type prdArchive struct {
 }
 
 // compactLearnings summarizes learnings.md if it gets too large
-func compactLearnings(model string) {
+func compactLearnings(model string, timeout time.Duration) {
 	learningsPath := filepath.Join(".ralph", "learnings.md")
 
 	content, err := os.ReadFile(learningsPath)
@@ -102,7 +102,7 @@ Output ONLY the summarized markdown content, no explanations.
 %s
 ---END LEARNINGS---`, string(content))
 
-	result, err := runClaudeOnceWithModel(prompt, model)
+	result, err := runClaudeOnceWithModel(prompt, model, timeout)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to compact learnings: %v\n", err)
 		return
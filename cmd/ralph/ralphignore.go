@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// ralphIgnore holds the patterns from a .ralphignore file. The syntax is a
+// subset of .gitignore: one glob per line, # comments, a trailing "/" to
+// match only directories, and a leading "/" or an inner "/" to anchor the
+// pattern to the project root. Unanchored patterns match a file or directory
+// name at any depth. Negation ("!") isn't supported.
+type ralphIgnore struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	glob     string
+	dirOnly  bool
+	anchored bool
+}
+
+// loadRalphIgnore reads the .ralphignore at path. A missing file ignores
+// nothing.
+func loadRalphIgnore(path string) (*ralphIgnore, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ralphIgnore{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseRalphIgnore(f)
+}
+
+func parseRalphIgnore(r io.Reader) (*ralphIgnore, error) {
+	ig := &ralphIgnore{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p := ignorePattern{}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.HasPrefix(line, "/") {
+			p.anchored = true
+			line = strings.TrimLeft(line, "/")
+		} else if strings.Contains(line, "/") {
+			p.anchored = true
+		}
+		if line == "" {
+			continue
+		}
+		if _, err := path.Match(line, ""); err != nil {
+			return nil, err
+		}
+		p.glob = line
+		ig.patterns = append(ig.patterns, p)
+	}
+	return ig, sc.Err()
+}
+
+// Match reports whether rel, a slash-separated path relative to the project
+// root, is ignored. Callers walking a tree skip ignored directories, so
+// their contents never need matching.
+func (ig *ralphIgnore) Match(rel string, isDir bool) bool {
+	for _, p := range ig.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		target := rel
+		if !p.anchored {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(p.glob, target); ok {
+			return true
+		}
+	}
+	return false
+}
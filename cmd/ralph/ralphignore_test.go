@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRalphIgnoreMatch(t *testing.T) {
+	ig, err := parseRalphIgnore(strings.NewReader(`
+# comments and blank lines are skipped
+
+docs/
+/build.sh
+generated/*.go
+*.min.js
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"docs", true, true},
+		{"site/docs", true, true},
+		{"docs", false, false}, // dir-only pattern
+		{"build.sh", false, true},
+		{"scripts/build.sh", false, false}, // anchored to the root
+		{"generated/api.go", false, true},
+		{"pkg/generated/api.go", false, false},
+		{"web/vendor.min.js", false, true},
+		{"web/app.js", false, false},
+	}
+	for _, tt := range tests {
+		if got := ig.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	if _, err := parseRalphIgnore(strings.NewReader("[unclosed\n")); err == nil {
+		t.Error("parseRalphIgnore() should reject a malformed glob")
+	}
+}
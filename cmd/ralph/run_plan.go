@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/resilience"
+)
+
+// runPlan is what ralph run -plan reports: the loaded config plus the
+// run-level limits and saved state a real run would start from.
+type runPlan struct {
+	Config    *config.Config
+	BudgetUSD float64
+	MaxLoops  int
+	Once      bool
+	PRD       *agent.PRDStatus
+	Circuits  map[string]resilience.CircuitSnapshot // From circuit_state.json
+
+	NoResetFailed    bool          // -no-reset-failed
+	ResetFailedAfter time.Duration // reset_failed_after
+}
+
+// loadCircuitSnapshots reads breaker state saved by a previous run. A missing
+// or unreadable file reports no saved state.
+func loadCircuitSnapshots(path string) map[string]resilience.CircuitSnapshot {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var snapshots map[string]resilience.CircuitSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil
+	}
+	return snapshots
+}
+
+// printRunPlan writes the steps a run would execute, in execution order, with
+// their timeout, retry and circuit breaker settings, followed by task progress.
+func printRunPlan(w io.Writer, p runPlan) {
+	cfg := p.Config
+	ordered := cfg.Steps
+	if config.HasDependencies(cfg.Steps) {
+		if steps, err := config.OrderSteps(cfg.Steps); err == nil {
+			ordered = steps
+		}
+	}
+
+	var enabled []config.StepConfig
+	var disabled []string
+	for _, s := range ordered {
+		if s.IsEnabled() {
+			enabled = append(enabled, s)
+		} else {
+			disabled = append(disabled, s.Name)
+		}
+	}
+
+	fmt.Fprintf(w, "Execution plan (%d of %d steps enabled):\n\n", len(enabled), len(cfg.Steps))
+	for i, s := range enabled {
+		fmt.Fprintf(w, "  %d. %s (%s)\n", i+1, s.Name, s.Type)
+		fmt.Fprintf(w, "     timeout: %s · retries: %s · circuit breaker: %s\n",
+			planTimeout(s), planRetries(s), planCircuitBreaker(s, p.Circuits[s.Name]))
+		if len(s.DependsOn) > 0 {
+			fmt.Fprintf(w, "     depends on: %s\n", strings.Join(s.DependsOn, ", "))
+		}
+		if when := strings.TrimSpace(s.When); when != "" {
+			fmt.Fprintf(w, "     when: %s\n", when)
+		}
+		if s.ContinueOnError {
+			fmt.Fprintln(w, "     continue on error")
+		}
+	}
+	if len(disabled) > 0 {
+		fmt.Fprintf(w, "\n  Disabled: %s\n", strings.Join(disabled, ", "))
+	}
+
+	fmt.Fprintln(w, "\nLoop:")
+	mode := "until all tasks are done"
+	if p.Once {
+		mode = "once"
+	}
+	fmt.Fprintf(w, "  Runs: %s\n", mode)
+	fmt.Fprintf(w, "  Step delay: %s\n", cfg.GetStepDelay())
+	if p.MaxLoops > 0 {
+		fmt.Fprintf(w, "  Max loops: %d\n", p.MaxLoops)
+	}
+	if p.BudgetUSD > 0 {
+		fmt.Fprintf(w, "  Budget: $%.2f\n", p.BudgetUSD)
+	}
+	if cfg.MaxLoopsPerTask > 0 {
+		fmt.Fprintf(w, "  Max loops per task: %d\n", cfg.MaxLoopsPerTask)
+	}
+	if timeout := cfg.GetTaskTimeout(); timeout > 0 {
+		fmt.Fprintf(w, "  Task timeout: %s\n", timeout)
+	}
+	if cfg.StallThreshold > 0 {
+		fmt.Fprintf(w, "  Stall threshold: %d loops\n", cfg.StallThreshold)
+	}
+
+	if p.PRD != nil {
+		fmt.Fprintln(w, "\nTasks:")
+		fmt.Fprintf(w, "  Progress: %s done (%d todo, %d failed, %d blocked)\n",
+			p.PRD.Progress(), p.PRD.TodoTasks, p.PRD.FailedTasks, p.PRD.BlockedTasks)
+		if p.PRD.IsComplete() {
+			fmt.Fprintln(w, "  All tasks are complete; a run would exit immediately")
+		} else if p.PRD.CurrentTaskID != "" {
+			fmt.Fprintf(w, "  Next: %s %s\n", p.PRD.CurrentTaskID, p.PRD.CurrentTask)
+		}
+		if p.PRD.FailedTasks > 0 {
+			switch {
+			case p.NoResetFailed:
+				fmt.Fprintf(w, "  %d failed task(s) would stay failed (-no-reset-failed)\n", p.PRD.FailedTasks)
+			case p.ResetFailedAfter > 0:
+				fmt.Fprintf(w, "  %d failed task(s) would be reset to todo once failed for %s\n", p.PRD.FailedTasks, p.ResetFailedAfter)
+			default:
+				fmt.Fprintf(w, "  %d failed task(s) would be reset to todo\n", p.PRD.FailedTasks)
+			}
+		}
+	}
+}
+
+func planTimeout(s config.StepConfig) string {
+	if d := s.GetTimeout(); d > 0 {
+		return d.String()
+	}
+	return "none"
+}
+
+func planRetries(s config.StepConfig) string {
+	if s.MaxRetries <= 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d (backoff %s, max %s)", s.MaxRetries, s.GetRetryDelay(), s.GetMaxRetryDelay())
+}
+
+// planCircuitBreaker describes the breaker the loop would build for s, and
+// its saved state when a previous run left it open or half-open.
+func planCircuitBreaker(s config.StepConfig, saved resilience.CircuitSnapshot) string {
+	cb := resilience.DefaultCircuitBreakerConfig()
+	desc := fmt.Sprintf("%d failures, reset after %s (default)", cb.Threshold, cb.ResetAfter)
+	if s.CircuitBreaker != nil {
+		cb = resilience.CircuitBreakerConfig{Threshold: s.CircuitBreaker.Threshold, ResetAfter: s.GetCircuitBreakerResetAfter()}
+		desc = fmt.Sprintf("%d failures, reset after %s", cb.Threshold, cb.ResetAfter)
+	}
+	switch saved.State {
+	case resilience.CircuitOpen.String():
+		if time.Since(saved.LastFailure) < cb.ResetAfter {
+			desc += ", currently open"
+		} else {
+			desc += ", currently half-open"
+		}
+	case resilience.CircuitHalfOpen.String():
+		desc += ", currently half-open"
+	}
+	return desc
+}
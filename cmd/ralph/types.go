@@ -1,13 +1,16 @@
 package main
 
 import (
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
 
 type prdFile struct {
-	Version jsonInt   `json:"version"`
-	Tasks   []prdTask `json:"tasks"`
+	Version       jsonInt   `json:"version"`
+	PreserveOrder bool      `json:"preserve_order,omitempty"` // Pick todo tasks in file order, ignoring priority
+	Tasks         []prdTask `json:"tasks"`
 }
 
 type jsonInt int
@@ -27,20 +30,27 @@ func (i *jsonInt) UnmarshalJSON(b []byte) error {
 	}
 	n, err := strconv.Atoi(s)
 	if err != nil {
-		return err
+		// Claude sometimes writes whole numbers as floats, e.g. 1.0 or 1e0.
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt32 {
+			return fmt.Errorf("invalid integer %s", string(b))
+		}
+		n = int(f)
 	}
 	*i = jsonInt(n)
 	return nil
 }
 
 type prdTask struct {
-	ID       string     `json:"id"`
-	Title    string     `json:"title"`
-	Details  string     `json:"details,omitempty"`
-	Priority string     `json:"priority,omitempty"`
-	Status   string     `json:"status,omitempty"`
-	Tests    string     `json:"tests,omitempty"`
-	Issue    *taskIssue `json:"issue,omitempty"`
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Details   string     `json:"details,omitempty"`
+	Priority  string     `json:"priority,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	Tests     string     `json:"tests,omitempty"`
+	DependsOn []string   `json:"depends_on,omitempty"` // Task IDs that must be done first
+	Issue     *taskIssue `json:"issue,omitempty"`
+	FailedAt  string     `json:"failed_at,omitempty"` // RFC 3339 time the task was marked failed
 }
 
 type taskIssue struct {
@@ -48,22 +58,38 @@ type taskIssue struct {
 	URL    string `json:"url"`
 }
 
+// stripJSONFences returns the contents of the first ``` fenced block in s,
+// whatever its language tag, dropping any prose around it. Nested fences
+// (a block wrapped in a second fence) are unwrapped too. Input that has no
+// fence, or already starts with bare JSON, is returned trimmed.
 func stripJSONFences(s string) string {
 	s = strings.TrimSpace(s)
-	if strings.HasPrefix(s, "```") {
-		lines := strings.Split(s, "\n")
-		if len(lines) >= 2 {
-			lines = lines[1:]
+	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			start = i
+			break
 		}
-		if len(lines) > 0 {
-			last := strings.TrimSpace(lines[len(lines)-1])
-			if last == "```" {
-				lines = lines[:len(lines)-1]
-			}
+	}
+	if start < 0 {
+		return s
+	}
+	end := len(lines)
+	for j := start + 1; j < len(lines); j++ {
+		if strings.TrimSpace(lines[j]) == "```" {
+			end = j
+			break
 		}
-		s = strings.TrimSpace(strings.Join(lines, "\n"))
 	}
-	return s
+	inner := strings.TrimSpace(strings.Join(lines[start+1:end], "\n"))
+	if strings.HasPrefix(inner, "```") {
+		return stripJSONFences(inner)
+	}
+	return inner
 }
 
 func splitLines(s string) []string {
@@ -18,6 +18,16 @@ func TestJsonIntUnmarshal(t *testing.T) {
 		{"empty string", `""`, 0, true},
 		{"zero", `0`, 0, false},
 		{"negative", `-5`, -5, false},
+		{"float", `1.0`, 1, false},
+		{"string float", `"1.0"`, 1, false},
+		{"float exponent", `2e0`, 2, false},
+		{"negative float", `-3.0`, -3, false},
+		{"fractional float", `1.5`, 0, true},
+		{"fractional string", `"2.25"`, 0, true},
+		{"not a number", `"one"`, 0, true},
+		{"boolean", `true`, 0, true},
+		{"huge float", `1e40`, 0, true},
+		{"NaN string", `"NaN"`, 0, true},
 	}
 
 	for _, tt := range tests {
@@ -89,6 +99,36 @@ func TestStripJSONFences(t *testing.T) {
 			input: "  \n```json\n{\"key\": \"value\"}\n```\n  ",
 			want:  `{"key": "value"}`,
 		},
+		{
+			name:  "fenced with trailing prose",
+			input: "```json\n{\"key\": \"value\"}\n```\n\nLet me know if you want changes.",
+			want:  `{"key": "value"}`,
+		},
+		{
+			name:  "prose before and after",
+			input: "Here is the updated PRD:\n\n```json\n{\"key\": \"value\"}\n```\nThe second ```block``` is ignored.\n```\nother\n```",
+			want:  `{"key": "value"}`,
+		},
+		{
+			name:  "double fenced",
+			input: "```json\n```json\n{\"key\": \"value\"}\n```\n```",
+			want:  `{"key": "value"}`,
+		},
+		{
+			name:  "other language tag",
+			input: "```jsonc\n[1, 2]\n```",
+			want:  `[1, 2]`,
+		},
+		{
+			name:  "unterminated fence",
+			input: "```json\n{\"key\": \"value\"}",
+			want:  `{"key": "value"}`,
+		},
+		{
+			name:  "prose without fence",
+			input: "  no json here  ",
+			want:  "no json here",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// RotateLearnings keeps the learnings file at path from growing without
+// bound. Once it exceeds maxBytes, the oldest entries (blocks separated by
+// blank lines) are appended to archivePath and the file keeps only the most
+// recent entries, up to half of maxBytes; the newest entry is always kept.
+// It reports whether anything was archived. maxBytes <= 0 disables rotation.
+func RotateLearnings(path, archivePath string, maxBytes int64) (bool, error) {
+	if maxBytes <= 0 {
+		return false, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if info.Size() <= maxBytes {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	entries := splitLearningEntries(string(data))
+	if len(entries) < 2 {
+		return false, nil
+	}
+
+	// Walk back from the newest entry while the kept part fits.
+	keep := len(entries) - 1
+	size := int64(len(entries[keep]))
+	for keep > 0 {
+		next := size + int64(len(entries[keep-1])) + 2
+		if next > maxBytes/2 {
+			break
+		}
+		size = next
+		keep--
+	}
+	if keep == 0 {
+		return false, nil
+	}
+
+	archived := strings.Join(entries[:keep], "\n\n")
+	f, err := os.OpenFile(archivePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return false, err
+	}
+	_, err = fmt.Fprintf(f, "<!-- archived %s -->\n\n%s\n\n", time.Now().UTC().Format(time.RFC3339), archived)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Write the kept entries via a temp file so a crash can't lose them.
+	kept := strings.Join(entries[keep:], "\n\n") + "\n"
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(kept), info.Mode().Perm()); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return false, err
+	}
+	return true, nil
+}
+
+// splitLearningEntries splits s into blocks separated by blank lines,
+// trimming each block and dropping empty ones.
+func splitLearningEntries(s string) []string {
+	var entries []string
+	var cur []string
+	flush := func() {
+		if block := strings.TrimSpace(strings.Join(cur, "\n")); block != "" {
+			entries = append(entries, block)
+		}
+		cur = cur[:0]
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		cur = append(cur, line)
+	}
+	flush()
+	return entries
+}
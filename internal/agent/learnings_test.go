@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeLearnings(t *testing.T, n int) (path, archive string) {
+	t.Helper()
+	dir := t.TempDir()
+	path = filepath.Join(dir, "learnings.md")
+	archive = filepath.Join(dir, "learnings.archive.md")
+	var entries []string
+	for i := 1; i <= n; i++ {
+		entries = append(entries, fmt.Sprintf("- Learning %02d: %s", i, strings.Repeat("x", 30)))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(entries, "\n\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, archive
+}
+
+func TestRotateLearningsThreshold(t *testing.T) {
+	path, archive := writeLearnings(t, 10)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := info.Size()
+
+	tests := []struct {
+		name     string
+		maxBytes int64
+		want     bool
+	}{
+		{"disabled", 0, false},
+		{"under limit", 1000, false},
+		{"at size", size, false},
+		{"over limit", 200, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RotateLearnings(path, archive, tt.maxBytes)
+			if err != nil {
+				t.Fatalf("RotateLearnings: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RotateLearnings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if info, err = os.Stat(path); err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > 100 {
+		t.Errorf("rotated learnings is %d bytes, want <= 100 (half the limit)", info.Size())
+	}
+}
+
+func TestRotateLearningsPreservesArchivedContent(t *testing.T) {
+	path, archive := writeLearnings(t, 10)
+	original, _ := os.ReadFile(path)
+
+	if _, err := RotateLearnings(path, archive, 200); err != nil {
+		t.Fatalf("RotateLearnings: %v", err)
+	}
+
+	kept, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatalf("archive not written: %v", err)
+	}
+
+	if !strings.Contains(string(kept), "Learning 10") {
+		t.Errorf("newest learning not kept:\n%s", kept)
+	}
+	if strings.Contains(string(kept), "Learning 01") {
+		t.Errorf("oldest learning still in learnings.md:\n%s", kept)
+	}
+	// Every original entry ends up in exactly one of the two files.
+	for _, entry := range splitLearningEntries(string(original)) {
+		inKept := strings.Contains(string(kept), entry)
+		inArchive := strings.Contains(string(archived), entry)
+		if inKept == inArchive {
+			t.Errorf("entry %q: kept=%v archived=%v, want exactly one", entry, inKept, inArchive)
+		}
+	}
+
+	// A second rotation appends rather than overwriting the archive.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(f, "\n- Learning 11: %s\n\n- Learning 12: %s\n", strings.Repeat("y", 100), strings.Repeat("z", 100))
+	f.Close()
+	if rotated, err := RotateLearnings(path, archive, 200); err != nil || !rotated {
+		t.Fatalf("second RotateLearnings() = %v, %v", rotated, err)
+	}
+	archived2, _ := os.ReadFile(archive)
+	if !strings.HasPrefix(string(archived2), string(archived)) {
+		t.Error("second rotation overwrote earlier archived content")
+	}
+	if strings.Count(string(archived2), "<!-- archived") != 2 {
+		t.Errorf("expected two archive sections:\n%s", archived2)
+	}
+}
+
+func TestRotateLearningsMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	rotated, err := RotateLearnings(filepath.Join(dir, "learnings.md"), filepath.Join(dir, "archive.md"), 10)
+	if err != nil || rotated {
+		t.Errorf("RotateLearnings() on missing file = %v, %v; want false, nil", rotated, err)
+	}
+}
@@ -21,6 +21,9 @@ func CheckPRDTasks(path string) (hasTasks bool, allComplete bool, err error) {
 	if strings.TrimSpace(clean) == "" {
 		return false, false, ErrPRDNoTasks
 	}
+	if err := ValidatePRD([]byte(clean)); err != nil {
+		return false, false, err
+	}
 
 	var f prdFile
 	if err := json.Unmarshal([]byte(clean), &f); err != nil {
@@ -0,0 +1,246 @@
+package agent
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed prd.schema.json
+var prdSchema []byte
+
+// PRDSchema returns the JSON Schema for prd.json, as printed by
+// `ralph schema prd`.
+func PRDSchema() []byte {
+	return prdSchema
+}
+
+var (
+	prdPriorities = []string{"high", "medium", "low"}
+	prdStatuses   = []string{"todo", "in_progress", "done", "failed"}
+)
+
+// PRDValidationError lists every way a prd.json breaks the schema.
+type PRDValidationError struct {
+	Problems []string
+}
+
+func (e *PRDValidationError) Error() string {
+	return "invalid prd.json: " + strings.Join(e.Problems, "; ")
+}
+
+// ValidatePRD checks prd.json content against PRDSchema and returns a
+// *PRDValidationError naming each bad field, e.g. "task[2].id is required".
+// It is as lenient as the loaders: version may be a numeric string, unknown
+// fields are allowed, and a priority or status outside the schema's enum is
+// only reported by PRDWarnings, since the loaders sort unknown priorities
+// last and count unknown statuses as in progress.
+func ValidatePRD(data []byte) error {
+	problems, _, err := validatePRD(data)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return &PRDValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// PRDWarnings lists the priority and status values in prd.json content that
+// the schema doesn't know, e.g. `task[2].status "blocked" is not one of
+// todo|in_progress|done|failed`. It returns nil for content ValidatePRD
+// rejects.
+func PRDWarnings(data []byte) []string {
+	problems, warnings, err := validatePRD(data)
+	if err != nil || len(problems) > 0 {
+		return nil
+	}
+	return warnings
+}
+
+// LoadPRDWarnings returns PRDWarnings for the prd.json at path, or nil if it
+// can't be read.
+func LoadPRDWarnings(path string) []string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return PRDWarnings([]byte(stripJSONFences(string(b))))
+}
+
+func validatePRD(data []byte) (problems, warnings []string, err error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var root any
+	if err := dec.Decode(&root); err != nil {
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			line, col := lineAndColumn(data, syntax.Offset)
+			return nil, nil, fmt.Errorf("prd.json is not valid JSON at line %d, column %d: %w", line, col, err)
+		}
+		return nil, nil, fmt.Errorf("prd.json is not valid JSON: %w", err)
+	}
+
+	obj, ok := root.(map[string]any)
+	if !ok {
+		return []string{"top level must be an object with a tasks array, got " + jsonKind(root)}, nil, nil
+	}
+
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	if v, ok := obj["version"]; ok && v != nil {
+		var version jsonInt
+		raw, _ := json.Marshal(v)
+		if _, isBool := v.(bool); isBool || version.UnmarshalJSON(raw) != nil {
+			add("version must be an integer, got %s", raw)
+		}
+	}
+	if v, ok := obj["preserve_order"]; ok {
+		if _, isBool := v.(bool); !isBool {
+			add("preserve_order must be true or false, got %s", jsonKind(v))
+		}
+	}
+
+	if v, ok := obj["tasks"]; ok && v != nil {
+		tasks, isArray := v.([]any)
+		if !isArray {
+			add("tasks must be an array, got %s", jsonKind(v))
+		}
+		for i, t := range tasks {
+			validatePRDTask(fmt.Sprintf("task[%d]", i), t, add, warn)
+		}
+	}
+	return problems, warnings, nil
+}
+
+func validatePRDTask(path string, v any, add, warn func(format string, args ...any)) {
+	task, ok := v.(map[string]any)
+	if !ok {
+		add("%s must be an object, got %s", path, jsonKind(v))
+		return
+	}
+
+	if id, ok := task["id"]; !ok {
+		add("%s.id is required", path)
+	} else if s, isString := id.(string); !isString {
+		add("%s.id must be a string, got %s", path, jsonKind(id))
+	} else if strings.TrimSpace(s) == "" {
+		add("%s.id must not be empty", path)
+	}
+	if title, ok := task["title"]; !ok {
+		add("%s.title is required", path)
+	} else if _, isString := title.(string); !isString {
+		add("%s.title must be a string, got %s", path, jsonKind(title))
+	}
+
+	for _, field := range []string{"details", "tests", "failed_at"} {
+		if fv, ok := task[field]; ok && fv != nil {
+			if _, isString := fv.(string); !isString {
+				add("%s.%s must be a string, got %s", path, field, jsonKind(fv))
+			}
+		}
+	}
+
+	checkEnum := func(field string, allowed []string) {
+		fv, ok := task[field]
+		if !ok || fv == nil {
+			return
+		}
+		s, isString := fv.(string)
+		if !isString {
+			add("%s.%s must be one of %s, got %s", path, field, strings.Join(allowed, "|"), jsonKind(fv))
+			return
+		}
+		norm := strings.ToLower(strings.TrimSpace(s))
+		if norm == "" {
+			return
+		}
+		for _, a := range allowed {
+			if norm == a {
+				return
+			}
+		}
+		warn("%s.%s %q is not one of %s", path, field, s, strings.Join(allowed, "|"))
+	}
+	checkEnum("priority", prdPriorities)
+	checkEnum("status", prdStatuses)
+
+	if deps, ok := task["depends_on"]; ok && deps != nil {
+		list, isArray := deps.([]any)
+		if !isArray {
+			add("%s.depends_on must be an array of task IDs, got %s", path, jsonKind(deps))
+		}
+		for j, d := range list {
+			if _, isString := d.(string); !isString {
+				add("%s.depends_on[%d] must be a string, got %s", path, j, jsonKind(d))
+			}
+		}
+	}
+
+	if issue, ok := task["issue"]; ok && issue != nil {
+		ref, isObject := issue.(map[string]any)
+		if !isObject {
+			add("%s.issue must be an object, got %s", path, jsonKind(issue))
+			return
+		}
+		if n, ok := ref["number"]; ok {
+			if num, isNumber := n.(json.Number); !isNumber || !isInteger(num) {
+				add("%s.issue.number must be an integer, got %s", path, jsonKind(n))
+			}
+		}
+		if u, ok := ref["url"]; ok {
+			if _, isString := u.(string); !isString {
+				add("%s.issue.url must be a string, got %s", path, jsonKind(u))
+			}
+		}
+	}
+}
+
+func isInteger(n json.Number) bool {
+	_, err := n.Int64()
+	return err == nil
+}
+
+// jsonKind names the JSON type of a decoded value for error messages.
+func jsonKind(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// lineAndColumn converts a json.SyntaxError offset, which counts the bad
+// byte itself, to the 1-based line and column of that byte.
+func lineAndColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset > 0 {
+		offset--
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
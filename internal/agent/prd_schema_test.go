@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidatePRD(t *testing.T) {
+	tests := []struct {
+		name string
+		prd  string
+		want []string // substrings of the error; none means valid
+	}{
+		{
+			name: "valid",
+			prd: `{"version":1,"preserve_order":true,"tasks":[
+				{"id":"T001","title":"Schema","details":"users table","priority":"high","status":"done","tests":"go test ./db"},
+				{"id":"T002","title":"API","priority":"medium","status":"in_progress","depends_on":["T001"]},
+				{"id":"T003","title":"Bug","status":"failed","failed_at":"2026-10-01T12:00:00Z","issue":{"number":42,"url":"https://github.com/o/r/issues/42"}}]}`,
+		},
+		{
+			name: "lenient where the loaders are",
+			prd: `{"version":"2","extra":{"ignored":true},"tasks":[
+				{"id":"T001","title":"Mixed case","priority":" High ","status":"TODO","owner":"someone"},
+				{"id":"T002","title":"No priority or status"}]}`,
+		},
+		{
+			name: "whole number float version",
+			prd:  `{"version":1.0,"tasks":[{"id":"T001","title":"Schema"}]}`,
+		},
+		{
+			name: "fractional version",
+			prd:  `{"version":1.5,"tasks":[{"id":"T001","title":"Schema"}]}`,
+			want: []string{"version must be an integer, got 1.5"},
+		},
+		{
+			name: "empty object",
+			prd:  `{}`,
+		},
+		{
+			name: "unknown priority and status are only warnings",
+			prd: `{"tasks":[{"id":"T1","title":"a"},{"id":"T2","title":"b","status":"blocked"},
+				{"id":"T3","title":"c","priority":"critical"}]}`,
+		},
+		{
+			name: "missing fields",
+			prd:  `{"tasks":[{"title":"no id","status":"complete"},{"id":"  ","title":5,"priority":3}]}`,
+			want: []string{
+				"task[0].id is required",
+				"task[1].id must not be empty",
+				"task[1].title must be a string, got a number",
+				"task[1].priority must be one of high|medium|low, got a number",
+			},
+		},
+		{
+			name: "wrong types",
+			prd: `{"version":"one","preserve_order":"yes","tasks":[
+				{"id":"T1","title":"a","depends_on":"T0"},
+				{"id":"T2","title":"b","depends_on":["T1",3],"issue":{"number":"42"}},
+				"T3"]}`,
+			want: []string{
+				`version must be an integer, got "one"`,
+				"preserve_order must be true or false, got a string",
+				"task[0].depends_on must be an array of task IDs, got a string",
+				"task[1].depends_on[1] must be a string, got a number",
+				"task[1].issue.number must be an integer, got a string",
+				"task[2] must be an object, got a string",
+			},
+		},
+		{
+			name: "tasks not an array",
+			prd:  `{"tasks":{"id":"T1"}}`,
+			want: []string{"tasks must be an array, got an object"},
+		},
+		{
+			name: "top level array",
+			prd:  `[{"id":"T1","title":"a"}]`,
+			want: []string{"top level must be an object with a tasks array, got an array"},
+		},
+		{
+			name: "syntax error",
+			prd:  "{\"tasks\":[\n  {\"id\":\"T1\",}\n]}",
+			want: []string{"not valid JSON at line 2, column 14"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePRD([]byte(tt.prd))
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("ValidatePRD() error = %v, want valid", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ValidatePRD() = nil, want errors %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q missing %q", err, want)
+				}
+			}
+			var verr *PRDValidationError
+			if errors.As(err, &verr) && len(verr.Problems) != len(tt.want) {
+				t.Errorf("got %d problems, want %d: %q", len(verr.Problems), len(tt.want), verr.Problems)
+			}
+		})
+	}
+}
+
+func TestPRDWarnings(t *testing.T) {
+	got := PRDWarnings([]byte(`{"tasks":[{"id":"T1","title":"a","status":"blocked"},{"id":"T2","title":"b","priority":"Critical","status":"DONE"}]}`))
+	want := []string{
+		`task[0].status "blocked" is not one of todo|in_progress|done|failed`,
+		`task[1].priority "Critical" is not one of high|medium|low`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("PRDWarnings() = %q, want %q", got, want)
+	}
+	if got := PRDWarnings([]byte(`{"tasks":[{"title":"no id","status":"blocked"}]}`)); got != nil {
+		t.Errorf("PRDWarnings() for an invalid prd = %q, want nil", got)
+	}
+}
+
+func TestLoadPRDStatusRejectsInvalidPRD(t *testing.T) {
+	path := writePRD(t, `{"version":1,"tasks":[{"id":5,"title":"a","status":"todo"}]}`)
+
+	st, err := LoadPRDStatus(path)
+	var verr *PRDValidationError
+	if st != nil || !errors.As(err, &verr) || !strings.Contains(err.Error(), "task[0].id must be a string") {
+		t.Fatalf("LoadPRDStatus() = %+v, %v; want a validation error", st, err)
+	}
+	if _, _, err := CheckPRDTasks(path); !errors.As(err, &verr) {
+		t.Errorf("CheckPRDTasks() error = %v, want a validation error", err)
+	}
+}
+
+func TestLoadPRDStatusAcceptsFloatVersion(t *testing.T) {
+	path := writePRD(t, `{"version":1.0,"tasks":[{"id":"T1","title":"a","status":"done"},{"id":"T2","title":"b"}]}`)
+
+	st, err := LoadPRDStatus(path)
+	if err != nil || st == nil || st.TotalTasks != 2 || st.CompletedTasks != 1 {
+		t.Fatalf("LoadPRDStatus() = %+v, %v; want 2 tasks, 1 done", st, err)
+	}
+	if hasTasks, allComplete, err := CheckPRDTasks(path); err != nil || !hasTasks || allComplete {
+		t.Errorf("CheckPRDTasks() = %v, %v, %v; want tasks left and no error", hasTasks, allComplete, err)
+	}
+}
+
+func TestLoadPRDStatusToleratesUnknownEnums(t *testing.T) {
+	path := writePRD(t, `{"version":1,"tasks":[
+		{"id":"T1","title":"a","priority":"critical","status":"done"},
+		{"id":"T2","title":"b","priority":"high","status":"blocked"},
+		{"id":"T3","title":"c","status":"todo"}]}`)
+
+	st, err := LoadPRDStatus(path)
+	if err != nil || st == nil {
+		t.Fatalf("LoadPRDStatus() = %+v, %v; want a status", st, err)
+	}
+	if st.TotalTasks != 3 || st.CompletedTasks != 1 || st.TodoTasks != 1 || st.IncompleteTasks != 2 {
+		t.Errorf("LoadPRDStatus() counts = %+v, want 3 total, 1 done, 1 todo, 2 incomplete", st)
+	}
+	if hasTasks, allComplete, err := CheckPRDTasks(path); err != nil || !hasTasks || allComplete {
+		t.Errorf("CheckPRDTasks() = %v, %v, %v; want tasks left and no error", hasTasks, allComplete, err)
+	}
+}
+
+func TestPRDSchemaIsValidJSON(t *testing.T) {
+	var schema struct {
+		Defs struct {
+			Task struct {
+				Required   []string                   `json:"required"`
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"task"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(PRDSchema(), &schema); err != nil {
+		t.Fatalf("PRDSchema() is not valid JSON: %v", err)
+	}
+	for _, field := range []string{"id", "title", "priority", "status", "depends_on", "issue", "failed_at"} {
+		if _, ok := schema.Defs.Task.Properties[field]; !ok {
+			t.Errorf("schema has no task property %q", field)
+		}
+	}
+}
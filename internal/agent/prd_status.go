@@ -1,33 +1,55 @@
 package agent
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"math"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// stripJSONFences returns the contents of the first ``` fenced block in s,
+// whatever its language tag, dropping any prose around it. Nested fences
+// (a block wrapped in a second fence) are unwrapped too. Input that has no
+// fence, or already starts with bare JSON, is returned trimmed.
 func stripJSONFences(s string) string {
 	s = strings.TrimSpace(s)
-	if strings.HasPrefix(s, "```") {
-		lines := strings.Split(s, "\n")
-		if len(lines) >= 2 {
-			lines = lines[1:]
-		}
-		if len(lines) > 0 {
-			last := strings.TrimSpace(lines[len(lines)-1])
-			if last == "```" {
-				lines = lines[:len(lines)-1]
-			}
+	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			start = i
+			break
 		}
-		s = strings.TrimSpace(strings.Join(lines, "\n"))
 	}
-	return s
+	if start < 0 {
+		return s
+	}
+	end := len(lines)
+	for j := start + 1; j < len(lines); j++ {
+		if strings.TrimSpace(lines[j]) == "```" {
+			end = j
+			break
+		}
+	}
+	inner := strings.TrimSpace(strings.Join(lines[start+1:end], "\n"))
+	if strings.HasPrefix(inner, "```") {
+		return stripJSONFences(inner)
+	}
+	return inner
 }
 
 type prdFile struct {
-	Version jsonInt       `json:"version"`
-	Tasks   []prdFileTask `json:"tasks"`
+	Version       jsonInt       `json:"version"`
+	PreserveOrder bool          `json:"preserve_order,omitempty"` // Pick todo tasks in file order, ignoring priority
+	Tasks         []prdFileTask `json:"tasks"`
 }
 
 type jsonInt int
@@ -47,18 +69,25 @@ func (i *jsonInt) UnmarshalJSON(b []byte) error {
 	}
 	n, err := strconv.Atoi(s)
 	if err != nil {
-		return err
+		// Claude sometimes writes whole numbers as floats, e.g. 1.0 or 1e0.
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt32 {
+			return fmt.Errorf("invalid integer %s", string(b))
+		}
+		n = int(f)
 	}
 	*i = jsonInt(n)
 	return nil
 }
 
 type prdFileTask struct {
-	ID       string `json:"id"`
-	Title    string `json:"title"`
-	Details  string `json:"details,omitempty"`
-	Priority string `json:"priority,omitempty"`
-	Status   string `json:"status,omitempty"`
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Details   string   `json:"details,omitempty"`
+	Priority  string   `json:"priority,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	DependsOn []string `json:"depends_on,omitempty"`
+	FailedAt  string   `json:"failed_at,omitempty"` // RFC 3339 time the task was marked failed
 }
 
 // PRDStatus is a lightweight view of prd.json used for progress display and exit detection.
@@ -69,6 +98,7 @@ type PRDStatus struct {
 	IncompleteTasks int
 	TodoTasks       int
 	FailedTasks     int
+	BlockedTasks    int      // Todo tasks waiting on a depends_on task that isn't done
 	CurrentTaskID   string   // Deprecated: use CurrentTaskIDs for multi-task support
 	CurrentTask     string   // Deprecated: use CurrentTasks for multi-task support
 	CurrentTaskIDs  []string // All in-progress task IDs
@@ -79,9 +109,18 @@ func (s *PRDStatus) IsComplete() bool {
 	return s.TotalTasks > 0 && s.IncompleteTasks == 0
 }
 
-// HasActionableTasks returns true if there are tasks that can be worked on (status "todo")
+// HasActionableTasks returns true if there are tasks that can be worked on
+// (status "todo" with every dependency done)
 func (s *PRDStatus) HasActionableTasks() bool {
-	return s.TodoTasks > 0
+	return s.TodoTasks > s.BlockedTasks
+}
+
+// IsBlocked reports whether work can't continue: nothing is in progress and
+// every todo task waits on a dependency that isn't done, so the remaining
+// tasks are held up by failed (or missing) dependencies.
+func (s *PRDStatus) IsBlocked() bool {
+	inProgress := s.IncompleteTasks - s.TodoTasks - s.FailedTasks
+	return inProgress == 0 && s.TodoTasks > 0 && s.BlockedTasks == s.TodoTasks
 }
 
 func (s *PRDStatus) Progress() string {
@@ -92,6 +131,8 @@ func (s *PRDStatus) Progress() string {
 }
 
 // LoadPRDStatus reads prd.json and returns counts + current in-progress task (if any).
+// Any file that parses yields a status; only one that doesn't returns an
+// error, naming the bad field when ValidatePRD can.
 func LoadPRDStatus(path string) (*PRDStatus, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -104,9 +145,11 @@ func LoadPRDStatus(path string) (*PRDStatus, error) {
 	if clean == "" {
 		return &PRDStatus{}, nil
 	}
-
 	var f prdFile
 	if err := json.Unmarshal([]byte(clean), &f); err != nil {
+		if vErr := ValidatePRD([]byte(clean)); vErr != nil {
+			return nil, vErr
+		}
 		return nil, err
 	}
 
@@ -115,6 +158,13 @@ func LoadPRDStatus(path string) (*PRDStatus, error) {
 	st.CurrentTaskIDs = []string{}
 	st.CurrentTasks = []string{}
 
+	done := make(map[string]bool)
+	for _, t := range f.Tasks {
+		if strings.ToLower(strings.TrimSpace(t.Status)) == "done" {
+			done[strings.TrimSpace(t.ID)] = true
+		}
+	}
+
 	for _, t := range f.Tasks {
 		status := strings.ToLower(strings.TrimSpace(t.Status))
 		id := strings.TrimSpace(t.ID)
@@ -125,6 +175,9 @@ func LoadPRDStatus(path string) (*PRDStatus, error) {
 		case "todo":
 			st.TodoTasks++
 			st.IncompleteTasks++
+			if !dependenciesDone(t, done) {
+				st.BlockedTasks++
+			}
 		case "failed":
 			st.FailedTasks++
 			st.IncompleteTasks++
@@ -142,16 +195,25 @@ func LoadPRDStatus(path string) (*PRDStatus, error) {
 			}
 		}
 	}
-	// If no in-progress tasks, fall back to first todo task for backward compatibility
+	// If no in-progress tasks, fall back to the highest-priority todo task
+	// whose dependencies are done (file order breaks ties, or decides alone
+	// with preserve_order)
 	if st.CurrentTask == "" {
+		best := -1
 		for _, t := range f.Tasks {
 			status := strings.ToLower(strings.TrimSpace(t.Status))
-			id := strings.TrimSpace(t.ID)
 			title := strings.TrimSpace(t.Title)
-			if status == "todo" && title != "" {
-				st.CurrentTaskID = id
+			if status != "todo" || title == "" || !dependenciesDone(t, done) {
+				continue
+			}
+			rank := PriorityRank(t.Priority)
+			if f.PreserveOrder {
+				rank = 0
+			}
+			if best == -1 || rank < best {
+				best = rank
+				st.CurrentTaskID = strings.TrimSpace(t.ID)
 				st.CurrentTask = title
-				break
 			}
 		}
 	}
@@ -159,9 +221,134 @@ func LoadPRDStatus(path string) (*PRDStatus, error) {
 	return st, nil
 }
 
-// ResetFailedTasks changes all "failed" tasks back to "todo" so they can be retried.
+// PriorityRank orders task priorities for selection: high (0) before
+// medium (1) before low (2). A missing priority counts as medium and an
+// unknown one sorts last.
+func PriorityRank(priority string) int {
+	switch strings.ToLower(strings.TrimSpace(priority)) {
+	case "high":
+		return 0
+	case "medium", "":
+		return 1
+	case "low":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// dependenciesDone reports whether every depends_on ID of t is in done.
+// Unknown IDs never complete, so they block the task.
+func dependenciesDone(t prdFileTask, done map[string]bool) bool {
+	for _, dep := range t.DependsOn {
+		if dep = strings.TrimSpace(dep); dep != "" && !done[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// TaskStatus is one task's ID and normalized status, as recorded in prd.json.
+type TaskStatus struct {
+	ID     string
+	Status string
+}
+
+// LoadTaskStatuses reads prd.json and returns every task's status in file
+// order. A missing or empty file returns no tasks.
+func LoadTaskStatuses(path string) ([]TaskStatus, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	clean := stripJSONFences(string(b))
+	if clean == "" {
+		return nil, nil
+	}
+
+	var f prdFile
+	if err := json.Unmarshal([]byte(clean), &f); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]TaskStatus, 0, len(f.Tasks))
+	for _, t := range f.Tasks {
+		tasks = append(tasks, TaskStatus{
+			ID:     strings.TrimSpace(t.ID),
+			Status: strings.ToLower(strings.TrimSpace(t.Status)),
+		})
+	}
+	return tasks, nil
+}
+
+// NewlyCompleted returns the IDs of tasks that are done in after but were
+// not done in before, in after's file order. Tasks added between the two
+// snapshots already marked done count as newly completed.
+func NewlyCompleted(before, after []TaskStatus) []string {
+	wasDone := make(map[string]bool, len(before))
+	for _, t := range before {
+		if t.Status == "done" {
+			wasDone[t.ID] = true
+		}
+	}
+	var ids []string
+	for _, t := range after {
+		if t.Status == "done" && t.ID != "" && !wasDone[t.ID] {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids
+}
+
+// ResetFailedTasks changes "failed" tasks back to "todo" so they can be
+// retried. With a positive minAge, only tasks whose failed_at is at least
+// minAge ago are reset; a failed task with no failed_at is stamped with the
+// current time and kept failed, so its age counts from now.
 // Returns the number of tasks reset.
-func ResetFailedTasks(path string) (int, error) {
+func ResetFailedTasks(path string, minAge time.Duration) (int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	clean := stripJSONFences(string(b))
+	if clean == "" {
+		return 0, nil
+	}
+
+	now := time.Now()
+	count := 0
+	out, changed, err := rewritePRDTasks(string(b), func(t prdFileTask, raw *rawPRDTask) bool {
+		if strings.ToLower(strings.TrimSpace(t.Status)) != "failed" {
+			return false
+		}
+		due, stamped := failedResetDue(t.FailedAt, minAge, now)
+		if !stamped {
+			raw.set("failed_at", now.UTC().Format(time.RFC3339))
+			return true
+		}
+		if !due {
+			return false
+		}
+		raw.set("status", "todo")
+		raw.remove("failed_at")
+		count++
+		return true
+	})
+	if err != nil || !changed {
+		return 0, err
+	}
+	return count, os.WriteFile(path, out, 0644)
+}
+
+// ResettableFailedTasks returns how many failed tasks ResetFailedTasks would
+// reset right now with the same minAge, without changing the file.
+func ResettableFailedTasks(path string, minAge time.Duration) (int, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -178,24 +365,31 @@ func ResetFailedTasks(path string) (int, error) {
 	if err := json.Unmarshal([]byte(clean), &f); err != nil {
 		return 0, err
 	}
-
+	now := time.Now()
 	count := 0
-	for i := range f.Tasks {
-		if strings.ToLower(strings.TrimSpace(f.Tasks[i].Status)) == "failed" {
-			f.Tasks[i].Status = "todo"
+	for _, t := range f.Tasks {
+		if strings.ToLower(strings.TrimSpace(t.Status)) != "failed" {
+			continue
+		}
+		if due, _ := failedResetDue(t.FailedAt, minAge, now); due {
 			count++
 		}
 	}
+	return count, nil
+}
 
-	if count == 0 {
-		return 0, nil
+// failedResetDue reports whether a failed task with the given failed_at is
+// old enough to reset. stamped is false when minAge applies but failed_at is
+// missing or unparsable, so the task's age can't be known yet.
+func failedResetDue(failedAt string, minAge time.Duration, now time.Time) (due, stamped bool) {
+	if minAge <= 0 {
+		return true, true
 	}
-
-	out, err := json.MarshalIndent(f, "", "  ")
+	at, err := time.Parse(time.RFC3339, strings.TrimSpace(failedAt))
 	if err != nil {
-		return 0, err
+		return false, false
 	}
-	return count, os.WriteFile(path, out, 0644)
+	return now.Sub(at) >= minAge, true
 }
 
 // MarkTaskFailed updates the status of a task to "failed" in prd.json.
@@ -210,23 +404,203 @@ func MarkTaskFailed(path, taskID string) error {
 		return nil
 	}
 
-	var f prdFile
-	if err := json.Unmarshal([]byte(clean), &f); err != nil {
+	found := false
+	out, changed, err := rewritePRDTasks(string(b), func(t prdFileTask, raw *rawPRDTask) bool {
+		if found || strings.TrimSpace(t.ID) != strings.TrimSpace(taskID) {
+			return false
+		}
+		found = true
+		raw.set("status", "failed")
+		raw.set("failed_at", time.Now().UTC().Format(time.RFC3339))
+		return true
+	})
+	if err != nil || !changed {
 		return err
 	}
+	return os.WriteFile(path, out, 0644)
+}
 
-	// Find and update the task
-	for i := range f.Tasks {
-		if strings.TrimSpace(f.Tasks[i].ID) == strings.TrimSpace(taskID) {
-			f.Tasks[i].Status = "failed"
-			break
+// rawPRDTask collects the field edits an update makes to one prd.json task.
+// They are applied to the task's original bytes, so its other fields, their
+// order and the file's formatting stay as they are.
+type rawPRDTask struct {
+	edits []prdFieldEdit
+}
+
+type prdFieldEdit struct {
+	key   string
+	value []byte // nil removes the field
+}
+
+func (t *rawPRDTask) set(key, value string) {
+	b, _ := json.Marshal(value)
+	t.edits = append(t.edits, prdFieldEdit{key: key, value: b})
+}
+
+func (t *rawPRDTask) remove(key string) {
+	t.edits = append(t.edits, prdFieldEdit{key: key})
+}
+
+// rewritePRDTasks calls update on every task in the prd.json document data
+// and returns the patched document if any call reports a change. Only the
+// fields update edits are rewritten; every other byte is kept, unless the
+// JSON is wrapped in a code fence, which is dropped.
+func rewritePRDTasks(data string, update func(t prdFileTask, raw *rawPRDTask) bool) ([]byte, bool, error) {
+	if clean := stripJSONFences(data); clean != strings.TrimSpace(data) {
+		data = clean
+	}
+	doc := []byte(data)
+	members, err := jsonObjectMembers(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	var tasks []jsonSpan
+	for _, m := range members {
+		if m.key == "tasks" {
+			if tasks, err = jsonArrayElements(doc[m.valStart:m.valEnd]); err != nil {
+				return nil, false, err
+			}
+			for i := range tasks {
+				tasks[i].start += m.valStart
+				tasks[i].end += m.valStart
+			}
+		}
+	}
+
+	changed := false
+	patched := make([][]byte, len(tasks))
+	for i, span := range tasks {
+		var t prdFileTask
+		if err := json.Unmarshal(doc[span.start:span.end], &t); err != nil {
+			return nil, false, err
+		}
+		var raw rawPRDTask
+		if !update(t, &raw) || len(raw.edits) == 0 {
+			continue
 		}
+		changed = true
+		obj := doc[span.start:span.end]
+		for _, e := range raw.edits {
+			if obj, err = patchJSONField(obj, e); err != nil {
+				return nil, false, err
+			}
+		}
+		patched[i] = obj
+	}
+	if !changed {
+		return nil, false, nil
 	}
 
-	// Write back
-	out, err := json.MarshalIndent(f, "", "  ")
+	var out []byte
+	prev := 0
+	for i, span := range tasks {
+		if patched[i] == nil {
+			continue
+		}
+		out = append(out, doc[prev:span.start]...)
+		out = append(out, patched[i]...)
+		prev = span.end
+	}
+	return append(out, doc[prev:]...), true, nil
+}
+
+// jsonSpan is the byte range [start, end) of a value in a JSON document.
+type jsonSpan struct{ start, end int }
+
+// jsonMember locates one member of a JSON object: its key token and value.
+type jsonMember struct {
+	key              string
+	keyStart, keyEnd int
+	valStart, valEnd int
+}
+
+// jsonObjectMembers returns the members of the JSON object in data, in
+// document order, with their byte offsets.
+func jsonObjectMembers(data []byte) ([]jsonMember, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+	var members []jsonMember
+	for dec.More() {
+		prev := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		keyEnd := int(dec.InputOffset())
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		end := int(dec.InputOffset())
+		keyStart := prev + bytes.IndexByte(data[prev:], '"')
+		members = append(members, jsonMember{key: key, keyStart: keyStart, keyEnd: keyEnd, valStart: end - len(raw), valEnd: end})
+	}
+	return members, nil
+}
+
+// jsonArrayElements returns the byte range of each element of the JSON
+// array in data.
+func jsonArrayElements(data []byte) ([]jsonSpan, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('[') {
+		// tasks: null, or not an array at all, has nothing to rewrite.
+		return nil, nil
+	}
+	var spans []jsonSpan
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		end := int(dec.InputOffset())
+		spans = append(spans, jsonSpan{start: end - len(raw), end: end})
+	}
+	return spans, nil
+}
+
+// patchJSONField applies e to the JSON object obj. An existing value is
+// replaced in place; a new field is appended after the last one, copying its
+// indentation and colon spacing; a removed field takes its comma with it.
+func patchJSONField(obj []byte, e prdFieldEdit) ([]byte, error) {
+	members, err := jsonObjectMembers(obj)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return os.WriteFile(path, out, 0644)
+	i := slices.IndexFunc(members, func(m jsonMember) bool { return m.key == e.key })
+	splice := func(start, end int, with []byte) []byte {
+		return slices.Concat(obj[:start], with, obj[end:])
+	}
+
+	switch {
+	case i >= 0 && e.value != nil:
+		return splice(members[i].valStart, members[i].valEnd, e.value), nil
+	case i > 0:
+		return splice(members[i-1].valEnd, members[i].valEnd, nil), nil
+	case i >= 0 && len(members) > 1:
+		return splice(members[i].keyStart, members[1].keyStart, nil), nil
+	case i >= 0:
+		return splice(members[i].keyStart, members[i].valEnd, nil), nil
+	case e.value == nil:
+		return obj, nil
+	}
+
+	key, _ := json.Marshal(e.key)
+	if len(members) == 0 {
+		at := bytes.IndexByte(obj, '{') + 1
+		return splice(at, at, slices.Concat(key, []byte(":"), e.value)), nil
+	}
+	last := members[len(members)-1]
+	indent := last.keyStart
+	for indent > 0 && strings.ContainsRune(" \t\r\n", rune(obj[indent-1])) {
+		indent--
+	}
+	field := slices.Concat([]byte(","), obj[indent:last.keyStart], key, obj[last.keyEnd:last.valStart], e.value)
+	return splice(last.valEnd, last.valEnd, field), nil
 }
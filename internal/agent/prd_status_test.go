@@ -0,0 +1,457 @@
+package agent
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writePRD(t *testing.T, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "prd.json")
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return p
+}
+
+func TestLoadPRDStatus_DependsOn(t *testing.T) {
+	tests := []struct {
+		name        string
+		prd         string
+		wantCurrent string
+		wantBlocked int
+		actionable  bool
+		blocked     bool
+	}{
+		{
+			name: "skips task with unfinished dependency",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"api","status":"todo","depends_on":["T2"]},
+				{"id":"T2","title":"schema","status":"todo"}]}`,
+			wantCurrent: "T2",
+			wantBlocked: 1,
+			actionable:  true,
+		},
+		{
+			name: "dependency done unblocks task",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"done"},
+				{"id":"T2","title":"api","status":"todo","depends_on":["T1"]}]}`,
+			wantCurrent: "T2",
+			actionable:  true,
+		},
+		{
+			name: "in progress task wins over eligible todo",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"todo"},
+				{"id":"T2","title":"api","status":"in_progress","depends_on":["T1"]}]}`,
+			wantCurrent: "T2",
+			actionable:  true,
+		},
+		{
+			name: "all remaining blocked by failed dependency",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"failed"},
+				{"id":"T2","title":"api","status":"todo","depends_on":["T1"]},
+				{"id":"T3","title":"ui","status":"todo","depends_on":["T2"]}]}`,
+			wantBlocked: 2,
+			blocked:     true,
+		},
+		{
+			name: "unknown dependency blocks",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"api","status":"todo","depends_on":["T9"]}]}`,
+			wantBlocked: 1,
+			blocked:     true,
+		},
+		{
+			name: "no dependencies keeps first todo",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"a","status":"todo"},
+				{"id":"T2","title":"b","status":"todo"}]}`,
+			wantCurrent: "T1",
+			actionable:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := LoadPRDStatus(writePRD(t, tt.prd))
+			if err != nil {
+				t.Fatalf("LoadPRDStatus: %v", err)
+			}
+			if st.CurrentTaskID != tt.wantCurrent {
+				t.Errorf("CurrentTaskID = %q, want %q", st.CurrentTaskID, tt.wantCurrent)
+			}
+			if st.BlockedTasks != tt.wantBlocked {
+				t.Errorf("BlockedTasks = %d, want %d", st.BlockedTasks, tt.wantBlocked)
+			}
+			if got := st.HasActionableTasks(); got != tt.actionable {
+				t.Errorf("HasActionableTasks() = %v, want %v", got, tt.actionable)
+			}
+			if got := st.IsBlocked(); got != tt.blocked {
+				t.Errorf("IsBlocked() = %v, want %v", got, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestMarkTaskFailedKeepsDependsOn(t *testing.T) {
+	p := writePRD(t, `{"version":1,"tasks":[
+		{"id":"T1","title":"schema","status":"todo"},
+		{"id":"T2","title":"api","status":"todo","depends_on":["T1"]}]}`)
+
+	if err := MarkTaskFailed(p, "T1"); err != nil {
+		t.Fatalf("MarkTaskFailed: %v", err)
+	}
+	st, err := LoadPRDStatus(p)
+	if err != nil {
+		t.Fatalf("LoadPRDStatus: %v", err)
+	}
+	if !st.IsBlocked() {
+		t.Errorf("expected T2 to stay blocked on failed T1, got %+v", st)
+	}
+}
+
+func TestLoadPRDStatus_Priority(t *testing.T) {
+	tests := []struct {
+		name        string
+		prd         string
+		wantCurrent string
+	}{
+		{
+			name: "high before earlier medium and low",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"docs","priority":"low","status":"todo"},
+				{"id":"T2","title":"ui","priority":"medium","status":"todo"},
+				{"id":"T3","title":"auth","priority":"high","status":"todo"}]}`,
+			wantCurrent: "T3",
+		},
+		{
+			name: "file order breaks ties",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"docs","priority":"low","status":"todo"},
+				{"id":"T2","title":"api","priority":"High","status":"todo"},
+				{"id":"T3","title":"auth","priority":"high","status":"todo"}]}`,
+			wantCurrent: "T2",
+		},
+		{
+			name: "missing priority counts as medium",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"docs","priority":"low","status":"todo"},
+				{"id":"T2","title":"ui","status":"todo"}]}`,
+			wantCurrent: "T2",
+		},
+		{
+			name: "done and blocked tasks are skipped",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"setup","priority":"high","status":"done"},
+				{"id":"T2","title":"api","priority":"high","status":"todo","depends_on":["T4"]},
+				{"id":"T3","title":"docs","priority":"low","status":"todo"},
+				{"id":"T4","title":"schema","priority":"medium","status":"todo"}]}`,
+			wantCurrent: "T4",
+		},
+		{
+			name: "in progress task wins over higher priority todo",
+			prd: `{"version":1,"tasks":[
+				{"id":"T1","title":"auth","priority":"high","status":"todo"},
+				{"id":"T2","title":"docs","priority":"low","status":"in_progress"}]}`,
+			wantCurrent: "T2",
+		},
+		{
+			name: "preserve_order uses file order",
+			prd: `{"version":1,"preserve_order":true,"tasks":[
+				{"id":"T1","title":"docs","priority":"low","status":"todo"},
+				{"id":"T2","title":"auth","priority":"high","status":"todo"}]}`,
+			wantCurrent: "T1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := LoadPRDStatus(writePRD(t, tt.prd))
+			if err != nil {
+				t.Fatalf("LoadPRDStatus: %v", err)
+			}
+			if st.CurrentTaskID != tt.wantCurrent {
+				t.Errorf("CurrentTaskID = %q, want %q", st.CurrentTaskID, tt.wantCurrent)
+			}
+		})
+	}
+}
+
+func TestNewlyCompleted(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []string
+	}{
+		{
+			name: "todo and in progress tasks marked done",
+			before: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"done"},
+				{"id":"T2","title":"api","status":"in_progress"},
+				{"id":"T3","title":"ui","status":"todo"},
+				{"id":"T4","title":"docs","status":"todo"}]}`,
+			after: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"done"},
+				{"id":"T2","title":"api","status":"done"},
+				{"id":"T3","title":"ui","status":"Done"},
+				{"id":"T4","title":"docs","status":"in_progress"}]}`,
+			want: []string{"T2", "T3"},
+		},
+		{
+			name: "no transitions",
+			before: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"done"},
+				{"id":"T2","title":"api","status":"todo"}]}`,
+			after: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"done"},
+				{"id":"T2","title":"api","status":"failed"}]}`,
+			want: nil,
+		},
+		{
+			name: "new task added already done",
+			before: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"todo"}]}`,
+			after: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"todo"},
+				{"id":"T2","title":"hotfix","status":"done"}]}`,
+			want: []string{"T2"},
+		},
+		{
+			name:   "prd created during the loop",
+			before: "",
+			after: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"done"}]}`,
+			want: []string{"T1"},
+		},
+		{
+			name: "done task reopened is not reported",
+			before: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"done"}]}`,
+			after: `{"version":1,"tasks":[
+				{"id":"T1","title":"schema","status":"todo"}]}`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := LoadTaskStatuses(writePRD(t, tt.before))
+			if err != nil {
+				t.Fatalf("LoadTaskStatuses(before): %v", err)
+			}
+			after, err := LoadTaskStatuses(writePRD(t, tt.after))
+			if err != nil {
+				t.Fatalf("LoadTaskStatuses(after): %v", err)
+			}
+			if got := NewlyCompleted(before, after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewlyCompleted = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPRDStatusStripsFencesAndProse(t *testing.T) {
+	p := writePRD(t, "Here is the updated PRD:\n\n```json\n"+
+		`{"version":1,"tasks":[{"id":"T1","title":"a","status":"done"},{"id":"T2","title":"b"}]}`+
+		"\n```\n\nLet me know if you want changes.\n")
+
+	st, err := LoadPRDStatus(p)
+	if err != nil || st == nil || st.TotalTasks != 2 || st.CompletedTasks != 1 {
+		t.Fatalf("LoadPRDStatus() = %+v, %v; want 2 tasks, 1 done", st, err)
+	}
+}
+
+func TestLoadTaskStatusesMissingFile(t *testing.T) {
+	tasks, err := LoadTaskStatuses(filepath.Join(t.TempDir(), "prd.json"))
+	if err != nil || len(tasks) != 0 {
+		t.Fatalf("LoadTaskStatuses = %v, %v; want no tasks, nil", tasks, err)
+	}
+}
+
+func TestMarkTaskFailedRecordsFailedAt(t *testing.T) {
+	p := writePRD(t, `{"version":1,"tasks":[{"id":"T1","title":"schema","status":"in_progress"}]}`)
+
+	before := time.Now().Add(-time.Second)
+	if err := MarkTaskFailed(p, "T1"); err != nil {
+		t.Fatalf("MarkTaskFailed: %v", err)
+	}
+	tasks := readPRDTasks(t, p)
+	failedAt, err := time.Parse(time.RFC3339, tasks[0].FailedAt)
+	if err != nil {
+		t.Fatalf("failed_at = %q, want an RFC 3339 time", tasks[0].FailedAt)
+	}
+	if failedAt.Before(before) {
+		t.Errorf("failed_at = %s, want about now", failedAt)
+	}
+}
+
+func TestResetFailedTasks(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	recent := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	prd := `{"version":1,"tasks":[
+		{"id":"T1","title":"old","status":"failed","failed_at":"` + old + `"},
+		{"id":"T2","title":"recent","status":"failed","failed_at":"` + recent + `"},
+		{"id":"T3","title":"unstamped","status":"failed"},
+		{"id":"T4","title":"done","status":"done"}]}`
+
+	tests := []struct {
+		name       string
+		minAge     time.Duration
+		wantCount  int
+		wantStatus []string
+	}{
+		{"no age resets every failed task", 0, 3, []string{"todo", "todo", "todo", "done"}},
+		{"age keeps recent and unstamped failures", 24 * time.Hour, 1, []string{"todo", "failed", "failed", "done"}},
+		{"age longer than every failure", 72 * time.Hour, 0, []string{"failed", "failed", "failed", "done"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := writePRD(t, prd)
+			if n, err := ResettableFailedTasks(p, tt.minAge); err != nil || n != tt.wantCount {
+				t.Errorf("ResettableFailedTasks() = %d, %v; want %d", n, err, tt.wantCount)
+			}
+			count, err := ResetFailedTasks(p, tt.minAge)
+			if err != nil {
+				t.Fatalf("ResetFailedTasks: %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("ResetFailedTasks() = %d, want %d", count, tt.wantCount)
+			}
+
+			tasks := readPRDTasks(t, p)
+			var got []string
+			for _, task := range tasks {
+				got = append(got, task.Status)
+				if task.Status == "todo" && task.FailedAt != "" {
+					t.Errorf("%s reset to todo but kept failed_at %q", task.ID, task.FailedAt)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantStatus) {
+				t.Errorf("statuses = %v, want %v", got, tt.wantStatus)
+			}
+			if tt.minAge > 0 && tasks[2].FailedAt == "" {
+				t.Error("unstamped failed task should get failed_at so its age counts from now")
+			}
+		})
+	}
+}
+
+func TestPRDRewritesKeepUnknownFields(t *testing.T) {
+	prd := `{"version":1,"project":"demo","tasks":[
+		{"id":"T1","title":"API","status":"failed","tests":["POST /items returns 201"],"issue":{"number":7,"url":"https://example.com/7"}},
+		{"id":"T2","title":"UI","status":"in_progress","tests":["renders list"],"notes":"keep me"}]}`
+
+	rewrites := map[string]func(path string) error{
+		"ResetFailedTasks": func(path string) error { _, err := ResetFailedTasks(path, 0); return err },
+		"ResetFailedTasks stamping": func(path string) error {
+			_, err := ResetFailedTasks(path, time.Hour)
+			return err
+		},
+		"MarkTaskFailed": func(path string) error { return MarkTaskFailed(path, "T2") },
+	}
+	for name, rewrite := range rewrites {
+		t.Run(name, func(t *testing.T) {
+			p := writePRD(t, prd)
+			if err := rewrite(p); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got struct {
+				Project string `json:"project"`
+				Tasks   []struct {
+					Tests []string `json:"tests"`
+					Issue *struct {
+						Number int `json:"number"`
+					} `json:"issue"`
+					Notes string `json:"notes"`
+				} `json:"tasks"`
+			}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("rewritten prd.json is not JSON: %v\n%s", err, data)
+			}
+			if got.Project != "demo" || len(got.Tasks) != 2 {
+				t.Fatalf("top-level fields lost:\n%s", data)
+			}
+			if len(got.Tasks[0].Tests) != 1 || got.Tasks[0].Issue == nil || got.Tasks[0].Issue.Number != 7 {
+				t.Errorf("T1 lost tests or issue:\n%s", data)
+			}
+			if len(got.Tasks[1].Tests) != 1 || got.Tasks[1].Notes != "keep me" {
+				t.Errorf("T2 lost tests or notes:\n%s", data)
+			}
+		})
+	}
+}
+
+func TestPRDRewritesKeepBytesOutsideChangedFields(t *testing.T) {
+	prd := `{
+  "version": 1,
+  "project": "demo",
+  "tasks": [
+    {
+      "id": "T1",
+      "title": "API",
+      "status": "failed",
+      "failed_at": "2020-01-01T00:00:00Z",
+      "priority": "high"
+    },
+    {
+      "id": "T2",
+      "title": "UI",
+      "status": "in_progress",
+      "tests": ["renders list"]
+    },
+    {"failed_at":"2020-01-01T00:00:00Z","status":"failed","id":"T3","title":"Docs"}
+  ]
+}
+`
+
+	p := writePRD(t, prd)
+	if _, err := ResetFailedTasks(p, 0); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer(
+		"\"status\": \"failed\",\n      \"failed_at\": \"2020-01-01T00:00:00Z\",", "\"status\": \"todo\",",
+		`{"failed_at":"2020-01-01T00:00:00Z","status":"failed",`, `{"status":"todo",`,
+	).Replace(prd)
+	if got, _ := os.ReadFile(p); string(got) != want {
+		t.Errorf("ResetFailedTasks rewrote more than status and failed_at:\n%s\nwant:\n%s", got, want)
+	}
+
+	p = writePRD(t, prd)
+	if err := MarkTaskFailed(p, "T2"); err != nil {
+		t.Fatal(err)
+	}
+	failedAt := readPRDTasks(t, p)[1].FailedAt
+	want = strings.Replace(prd,
+		"\"status\": \"in_progress\",\n      \"tests\": [\"renders list\"]\n",
+		"\"status\": \"failed\",\n      \"tests\": [\"renders list\"],\n      \"failed_at\": \""+failedAt+"\"\n", 1)
+	if got, _ := os.ReadFile(p); failedAt == "" || string(got) != want {
+		t.Errorf("MarkTaskFailed rewrote more than status and failed_at:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func readPRDTasks(t *testing.T, path string) []prdFileTask {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f prdFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	return f.Tasks
+}
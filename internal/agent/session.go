@@ -4,9 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
+// DefaultSessionsDir holds per-step session state for named sessions.
+const DefaultSessionsDir = ".ralph/sessions"
+
 // SessionState represents the current session state
 type SessionState struct {
 	SessionID   string    `json:"session_id"`
@@ -36,6 +41,35 @@ func NewSessionManager(sessionFile, historyFile string, expiryHours int) *Sessio
 	}
 }
 
+// NewNamedSessionManager creates a session manager for the agent step called
+// name, keeping its session and history files in their own directory under
+// dir so several agent steps in one config don't share a session.
+func NewNamedSessionManager(dir, name string, expiryHours int) *SessionManager {
+	stepDir := filepath.Join(dir, sessionDirName(name))
+	return NewSessionManager(
+		filepath.Join(stepDir, "session.json"),
+		filepath.Join(stepDir, "history.json"),
+		expiryHours,
+	)
+}
+
+// sessionDirName turns a step name into a safe directory name: anything other
+// than letters, digits, '.', '_' and '-' becomes '-'.
+func sessionDirName(name string) string {
+	clean := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, strings.TrimSpace(name))
+	if clean == "" || strings.Trim(clean, ".") == "" {
+		return "default"
+	}
+	return clean
+}
+
 // Load reads the current session state
 func (m *SessionManager) Load() (*SessionState, error) {
 	data, err := os.ReadFile(m.sessionFile)
@@ -66,6 +100,9 @@ func (m *SessionManager) Save(state *SessionState) error {
 	if err != nil {
 		return err
 	}
+	if err := os.MkdirAll(filepath.Dir(m.sessionFile), 0755); err != nil {
+		return err
+	}
 	return os.WriteFile(m.sessionFile, data, 0644)
 }
 
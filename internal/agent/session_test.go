@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNamedSessionsAreIsolated(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sessions")
+	planner := NewNamedSessionManager(dir, "planner", 24)
+	coder := NewNamedSessionManager(dir, "coder", 24)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := planner.GetOrCreate(); err != nil {
+			t.Fatalf("planner GetOrCreate: %v", err)
+		}
+	}
+	coderState, isNew, err := coder.GetOrCreate()
+	if err != nil {
+		t.Fatalf("coder GetOrCreate: %v", err)
+	}
+	if !isNew || coderState.LoopCount != 1 {
+		t.Errorf("coder session = %+v (new %v), want a new session on loop 1", coderState, isNew)
+	}
+
+	if err := coder.Reset("stuck"); err != nil {
+		t.Fatalf("coder Reset: %v", err)
+	}
+	plannerState, err := planner.Load()
+	if err != nil || plannerState == nil {
+		t.Fatalf("planner Load = %v, %v", plannerState, err)
+	}
+	if plannerState.LoopCount != 2 || plannerState.ResetReason != "" {
+		t.Errorf("planner session = %+v, want loop 2 and untouched by the coder reset", plannerState)
+	}
+
+	for _, p := range []string{
+		filepath.Join(dir, "planner", "session.json"),
+		filepath.Join(dir, "coder", "session.json"),
+		filepath.Join(dir, "coder", "history.json"),
+	} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("expected %s: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "planner", "history.json")); !os.IsNotExist(err) {
+		t.Errorf("planner history should not record the coder reset, stat err = %v", err)
+	}
+}
+
+func TestSessionDirName(t *testing.T) {
+	tests := map[string]string{
+		"coder":       "coder",
+		"code review": "code-review",
+		"../escape":   "..-escape",
+		"..":          "default",
+		"":            "default",
+		"plan_v2.1-a": "plan_v2.1-a",
+	}
+	for name, want := range tests {
+		if got := sessionDirName(name); got != want {
+			t.Errorf("sessionDirName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package agent
+
+// StallDetector flags a task that keeps getting worked on without any task
+// completing. Unlike max_loops_per_task, progress on any task resets it.
+type StallDetector struct {
+	lastTaskID    string
+	lastCompleted int
+	stalledLoops  int
+	seen          bool
+
+	// Threshold is the number of consecutive no-progress loops on the same
+	// task before Observe reports a stall (0 = disabled).
+	Threshold int
+}
+
+// NewStallDetector creates a stall detector with the given threshold.
+func NewStallDetector(threshold int) *StallDetector {
+	return &StallDetector{Threshold: threshold}
+}
+
+// Observe should be called once before each loop with the current PRD status.
+// It returns true once CurrentTaskID and CompletedTasks have been unchanged
+// for Threshold consecutive loops.
+func (d *StallDetector) Observe(st *PRDStatus) bool {
+	if d.Threshold <= 0 || st == nil || st.CurrentTaskID == "" {
+		d.Reset()
+		return false
+	}
+	if !d.seen || st.CurrentTaskID != d.lastTaskID || st.CompletedTasks != d.lastCompleted {
+		d.seen = true
+		d.lastTaskID = st.CurrentTaskID
+		d.lastCompleted = st.CompletedTasks
+		d.stalledLoops = 0
+		return false
+	}
+	d.stalledLoops++
+	return d.stalledLoops >= d.Threshold
+}
+
+// StalledLoops returns how many consecutive loops have made no progress.
+func (d *StallDetector) StalledLoops() int {
+	return d.stalledLoops
+}
+
+// Reset clears all tracking state.
+func (d *StallDetector) Reset() {
+	d.seen = false
+	d.lastTaskID = ""
+	d.lastCompleted = 0
+	d.stalledLoops = 0
+}
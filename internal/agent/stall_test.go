@@ -0,0 +1,64 @@
+package agent
+
+import "testing"
+
+func TestStallDetector(t *testing.T) {
+	status := func(taskID string, completed int) *PRDStatus {
+		return &PRDStatus{CurrentTaskID: taskID, CompletedTasks: completed}
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		statuses  []*PRDStatus
+		want      []bool
+	}{
+		{
+			name:      "disabled",
+			threshold: 0,
+			statuses:  []*PRDStatus{status("T1", 0), status("T1", 0), status("T1", 0)},
+			want:      []bool{false, false, false},
+		},
+		{
+			name:      "stalls on same task",
+			threshold: 2,
+			statuses:  []*PRDStatus{status("T1", 0), status("T1", 0), status("T1", 0)},
+			want:      []bool{false, false, true},
+		},
+		{
+			name:      "completion resets",
+			threshold: 2,
+			statuses:  []*PRDStatus{status("T1", 0), status("T1", 0), status("T1", 1), status("T1", 1), status("T1", 1)},
+			want:      []bool{false, false, false, false, true},
+		},
+		{
+			name:      "task change resets",
+			threshold: 2,
+			statuses:  []*PRDStatus{status("T1", 0), status("T1", 0), status("T2", 0), status("T2", 0)},
+			want:      []bool{false, false, false, false},
+		},
+		{
+			name:      "no current task resets",
+			threshold: 1,
+			statuses:  []*PRDStatus{status("T1", 0), status("", 0), status("T1", 0), status("T1", 0)},
+			want:      []bool{false, false, false, true},
+		},
+		{
+			name:      "nil status",
+			threshold: 1,
+			statuses:  []*PRDStatus{nil, nil},
+			want:      []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewStallDetector(tt.threshold)
+			for i, st := range tt.statuses {
+				if got := d.Observe(st); got != tt.want[i] {
+					t.Errorf("Observe #%d = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
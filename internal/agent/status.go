@@ -8,21 +8,44 @@ const (
 	ExitReasonPlanComplete      ExitReason = "plan_complete"
 	ExitReasonNoProgress        ExitReason = "no_progress"
 	ExitReasonNoActionableTasks ExitReason = "no_actionable_tasks"
+	ExitReasonTasksBlocked      ExitReason = "tasks_blocked" // Remaining tasks depend on failed tasks
 )
 
+// DefaultNoProgressThreshold is how many loops in a row may finish without
+// completing a task before the detector exits.
+const DefaultNoProgressThreshold = 2
+
+// ExitDetectorConfig tunes when ExitDetector stops the loop.
+type ExitDetectorConfig struct {
+	// NoProgressThreshold is the number of consecutive loops without a newly
+	// completed task before exiting: 0 uses DefaultNoProgressThreshold and a
+	// negative value never exits for lack of progress.
+	NoProgressThreshold int
+	// ExitOnNoActionableTasks exits when no task is in progress and no todo
+	// task can start (e.g. only failed tasks remain).
+	ExitOnNoActionableTasks bool
+}
+
 // ExitDetector tracks exit conditions across loops
 type ExitDetector struct {
 	consecutiveNoProgress int
 	lastCompletedCount    int // Track prd.json completed task count
 
 	// Thresholds
-	NoProgressThreshold int
+	NoProgressThreshold     int // <= 0 disables the no-progress exit
+	ExitOnNoActionableTasks bool
 }
 
-// NewExitDetector creates an exit detector with default thresholds
-func NewExitDetector() *ExitDetector {
+// NewExitDetector creates an exit detector with the given thresholds; the
+// zero ExitDetectorConfig gives the defaults.
+func NewExitDetector(cfg ExitDetectorConfig) *ExitDetector {
+	threshold := cfg.NoProgressThreshold
+	if threshold == 0 {
+		threshold = DefaultNoProgressThreshold
+	}
 	return &ExitDetector{
-		NoProgressThreshold: 2,
+		NoProgressThreshold:     threshold,
+		ExitOnNoActionableTasks: cfg.ExitOnNoActionableTasks,
 	}
 }
 
@@ -44,13 +67,26 @@ func (d *ExitDetector) Check(planComplete bool, completedCount int) ExitReason {
 	}
 	// Note: consecutiveNoProgress is incremented by MarkLoopComplete, not here
 
-	if d.consecutiveNoProgress >= d.NoProgressThreshold {
+	if d.NoProgressThreshold > 0 && d.consecutiveNoProgress >= d.NoProgressThreshold {
 		return ExitReasonNoProgress
 	}
 
 	return ExitReasonNone
 }
 
+// CheckStatus is Check driven by a prd.json status. With
+// ExitOnNoActionableTasks it also exits when nothing is in progress and no
+// todo task can start.
+func (d *ExitDetector) CheckStatus(st *PRDStatus) ExitReason {
+	if st == nil {
+		return d.Check(false, 0)
+	}
+	if !st.IsComplete() && d.ExitOnNoActionableTasks && len(st.CurrentTaskIDs) == 0 && !st.HasActionableTasks() {
+		return ExitReasonNoActionableTasks
+	}
+	return d.Check(st.IsComplete(), st.CompletedTasks)
+}
+
 // MarkLoopComplete should be called once per loop iteration to track no-progress
 func (d *ExitDetector) MarkLoopComplete(completedCount int) {
 	if completedCount <= d.lastCompletedCount {
@@ -0,0 +1,71 @@
+package agent
+
+import "testing"
+
+// runProgress feeds completed-task counts to d, one per loop, and returns the
+// 1-based loop at which it first asked to exit (0 = never) and the reason.
+func runProgress(d *ExitDetector, completed []int) (int, ExitReason) {
+	for i, c := range completed {
+		d.MarkLoopComplete(c)
+		if reason := d.Check(false, c); reason != ExitReasonNone {
+			return i + 1, reason
+		}
+	}
+	return 0, ExitReasonNone
+}
+
+func TestExitDetectorNoProgressThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		completed []int
+		wantLoop  int
+	}{
+		{"default exits after two idle loops", 0, []int{1, 1, 1, 1}, 3},
+		{"progress resets the count", 0, []int{1, 1, 2, 2, 3, 3, 3}, 7},
+		{"threshold one exits on first idle loop", 1, []int{1, 1}, 2},
+		{"higher threshold waits longer", 4, []int{1, 1, 1, 1, 1, 1}, 5},
+		{"negative never exits for no progress", -1, []int{0, 0, 0, 0, 0, 0, 0, 0}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewExitDetector(ExitDetectorConfig{NoProgressThreshold: tt.threshold})
+			gotLoop, reason := runProgress(d, tt.completed)
+			if gotLoop != tt.wantLoop {
+				t.Fatalf("exited at loop %d (%q), want %d", gotLoop, reason, tt.wantLoop)
+			}
+			if gotLoop != 0 && reason != ExitReasonNoProgress {
+				t.Errorf("reason = %q, want %q", reason, ExitReasonNoProgress)
+			}
+		})
+	}
+}
+
+func TestExitDetectorCheckStatus(t *testing.T) {
+	onlyFailed := &PRDStatus{TotalTasks: 2, CompletedTasks: 1, IncompleteTasks: 1, FailedTasks: 1}
+	todo := &PRDStatus{TotalTasks: 2, CompletedTasks: 1, IncompleteTasks: 1, TodoTasks: 1}
+	done := &PRDStatus{TotalTasks: 2, CompletedTasks: 2}
+
+	tests := []struct {
+		name string
+		cfg  ExitDetectorConfig
+		st   *PRDStatus
+		want ExitReason
+	}{
+		{"plan complete", ExitDetectorConfig{}, done, ExitReasonPlanComplete},
+		{"no actionable tasks ignored by default", ExitDetectorConfig{}, onlyFailed, ExitReasonNone},
+		{"no actionable tasks exits when enabled", ExitDetectorConfig{ExitOnNoActionableTasks: true}, onlyFailed, ExitReasonNoActionableTasks},
+		{"todo task keeps running", ExitDetectorConfig{ExitOnNoActionableTasks: true}, todo, ExitReasonNone},
+		{"nil status", ExitDetectorConfig{ExitOnNoActionableTasks: true}, nil, ExitReasonNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewExitDetector(tt.cfg)
+			if got := d.CheckStatus(tt.st); got != tt.want {
+				t.Errorf("CheckStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
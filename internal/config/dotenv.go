@@ -0,0 +1,115 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var dotenvKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseDotenv parses .env content: KEY=VALUE lines with an optional
+// "export " prefix, # comments and blank lines. Values may be double-quoted
+// (with \n, \t, \" and \\ escapes), single-quoted (taken literally), or bare,
+// where a " #" starts a comment. A key set twice keeps its last value.
+// Values are not expanded.
+func ParseDotenv(data []byte) (map[string]string, error) {
+	vars := make(map[string]string)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for sc.Scan() {
+		lineNum++
+		line := strings.TrimSpace(sc.Text())
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNum)
+		}
+		if !dotenvKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", lineNum, key)
+		}
+		value, err := parseDotenvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", lineNum, key, err)
+		}
+		vars[key] = value
+	}
+	return vars, sc.Err()
+}
+
+func parseDotenvValue(v string) (string, error) {
+	if v == "" {
+		return "", nil
+	}
+	switch quote := v[0]; quote {
+	case '"', '\'':
+		end := closingQuote(v, quote)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated %c quote (multi-line values aren't supported)", quote)
+		}
+		if rest := strings.TrimSpace(v[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected text after closing quote: %q", rest)
+		}
+		if quote == '\'' {
+			return v[1:end], nil
+		}
+		return strconv.Unquote(v[:end+1])
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v), nil
+}
+
+// closingQuote returns the index of the quote closing v[0], skipping
+// backslash escapes inside double quotes, or -1 if there is none.
+func closingQuote(v string, quote byte) int {
+	for i := 1; i < len(v); i++ {
+		switch {
+		case quote == '"' && v[i] == '\\':
+			i++
+		case v[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// LoadDotenv reads the .env file at path into the process environment. A
+// variable that is already set keeps its value unless override is true.
+// It returns the names it set and the names it left alone, both sorted.
+func LoadDotenv(path string, override bool) (set, kept []string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	vars, err := ParseDotenv(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, key := range slices.Sorted(maps.Keys(vars)) {
+		if _, exists := os.LookupEnv(key); exists && !override {
+			kept = append(kept, key)
+			continue
+		}
+		if err := os.Setenv(key, vars[key]); err != nil {
+			return set, kept, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		set = append(set, key)
+	}
+	return set, kept, nil
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	data := "\ufeff# secrets for local runs\n" +
+		"API_KEY=abc123\n" +
+		"export DB_URL = postgres://localhost/dev \n" +
+		"\n" +
+		"GREETING=\"hello\\nworld\" # note\n" +
+		"LITERAL='keep $HOME and \\n as is'\n" +
+		"PASSWORD=p#ss # trailing comment\n" +
+		"QUOTED_HASH=\"a # b\"\n" +
+		"EMPTY=\n" +
+		"API_KEY=override\n"
+
+	got, err := ParseDotenv([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseDotenv() error = %v", err)
+	}
+	want := map[string]string{
+		"API_KEY":     "override",
+		"DB_URL":      "postgres://localhost/dev",
+		"GREETING":    "hello\nworld",
+		"LITERAL":     `keep $HOME and \n as is`,
+		"PASSWORD":    "p#ss",
+		"QUOTED_HASH": "a # b",
+		"EMPTY":       "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDotenv() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"missing equals", "A=1\nJUSTAKEY\n", "line 2: expected KEY=VALUE"},
+		{"bad name", "1ST=x\n", `line 1: invalid variable name "1ST"`},
+		{"unterminated quote", "TOKEN=\"abc\n", "line 1: TOKEN: unterminated \" quote"},
+		{"text after quote", "TOKEN='abc' def\n", "unexpected text after closing quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDotenv([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseDotenv() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDotenvPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("RALPH_TEST_NEW=from-file\nRALPH_TEST_SET=from-file\nRALPH_TEST_EMPTY=from-file\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reset := func() {
+		t.Setenv("RALPH_TEST_SET", "from-env")
+		t.Setenv("RALPH_TEST_EMPTY", "") // set, even though empty
+		t.Setenv("RALPH_TEST_NEW", "")
+		os.Unsetenv("RALPH_TEST_NEW")
+	}
+
+	reset()
+	set, kept, err := LoadDotenv(path, false)
+	if err != nil {
+		t.Fatalf("LoadDotenv() error = %v", err)
+	}
+	if !reflect.DeepEqual(set, []string{"RALPH_TEST_NEW"}) || !reflect.DeepEqual(kept, []string{"RALPH_TEST_EMPTY", "RALPH_TEST_SET"}) {
+		t.Errorf("LoadDotenv() set %v, kept %v", set, kept)
+	}
+	if got := os.Getenv("RALPH_TEST_SET"); got != "from-env" {
+		t.Errorf("existing variable = %q, want it kept", got)
+	}
+	if got := os.Getenv("RALPH_TEST_NEW"); got != "from-file" {
+		t.Errorf("new variable = %q, want from-file", got)
+	}
+	if got := ExpandEnvVars("${RALPH_TEST_NEW}"); got != "from-file" {
+		t.Errorf("ExpandEnvVars() = %q, want the loaded value", got)
+	}
+
+	reset()
+	set, kept, err = LoadDotenv(path, true)
+	if err != nil {
+		t.Fatalf("LoadDotenv(override) error = %v", err)
+	}
+	if len(set) != 3 || len(kept) != 0 {
+		t.Errorf("LoadDotenv(override) set %v, kept %v; want all three set", set, kept)
+	}
+	if got := os.Getenv("RALPH_TEST_SET"); got != "from-file" {
+		t.Errorf("with override, existing variable = %q, want from-file", got)
+	}
+
+	if _, _, err := LoadDotenv(filepath.Join(t.TempDir(), "missing.env"), false); err == nil {
+		t.Error("LoadDotenv() should fail for a missing file")
+	}
+}
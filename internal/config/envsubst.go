@@ -1,6 +1,9 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"os"
 	"regexp"
 )
@@ -36,3 +39,56 @@ func ExpandEnvVars(input string) string {
 func ExpandEnvVarsBytes(input []byte) []byte {
 	return []byte(ExpandEnvVars(string(input)))
 }
+
+// configRefPattern is envVarPattern plus the $$ escape used in config files.
+var configRefPattern = regexp.MustCompile(`\$\$|` + envVarPattern.String())
+
+// expandConfigString expands ${VAR} and ${VAR:-default} like ExpandEnvVars,
+// and turns $$ into a literal $ so "$${HOME}" stays "${HOME}".
+func expandConfigString(input string) string {
+	return configRefPattern.ReplaceAllStringFunc(input, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		return ExpandEnvVars(match)
+	})
+}
+
+// expandConfigJSON expands environment references in every string value of
+// a JSON document, at any depth (including step config blocks). Object keys
+// and non-string values are left alone, and expanded values can't break the
+// JSON however many quotes or backslashes they contain.
+func expandConfigJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("invalid data after top-level value")
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(expandConfigValue(v)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func expandConfigValue(v any) any {
+	switch t := v.(type) {
+	case string:
+		return expandConfigString(t)
+	case map[string]any:
+		for k, vv := range t {
+			t[k] = expandConfigValue(vv)
+		}
+	case []any:
+		for i, vv := range t {
+			t[i] = expandConfigValue(vv)
+		}
+	}
+	return v
+}
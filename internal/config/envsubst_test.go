@@ -93,3 +93,26 @@ func TestExpandEnvVarsBytes(t *testing.T) {
 		t.Errorf("ExpandEnvVarsBytes(%q) = %q, want %q", input, result, expected)
 	}
 }
+
+func TestExpandConfigString(t *testing.T) {
+	t.Setenv("TEST_VAR", "hello")
+	os.Unsetenv("UNSET_VAR")
+
+	tests := []struct {
+		input, want string
+	}{
+		{"${TEST_VAR}", "hello"},
+		{"${UNSET_VAR:-fallback}", "fallback"},
+		{"${UNSET_VAR}", ""},
+		{"$$", "$"},
+		{"$${TEST_VAR}", "${TEST_VAR}"},
+		{"$$$${TEST_VAR}", "$${TEST_VAR}"},
+		{"$$${TEST_VAR}", "$hello"},
+		{"price: $5", "price: $5"},
+	}
+	for _, tt := range tests {
+		if got := expandConfigString(tt.input); got != tt.want {
+			t.Errorf("expandConfigString(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -6,11 +6,21 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
+// StepConfigValidator checks a step's type-specific config block.
+type StepConfigValidator func(stepType string, rawConfig json.RawMessage) error
+
+// ConditionValidator checks a step's `when` expression.
+type ConditionValidator func(expr string) error
+
 // Loader handles loading configuration files.
 type Loader struct {
-	configDir string
+	configDir          string
+	stepValidator      StepConfigValidator
+	conditionValidator ConditionValidator
 }
 
 // NewLoader creates a new config loader.
@@ -18,26 +28,106 @@ func NewLoader(configDir string) *Loader {
 	return &Loader{configDir: configDir}
 }
 
+// SetStepValidator sets a hook that LoadAndValidate runs against each step's config.
+func (l *Loader) SetStepValidator(v StepConfigValidator) {
+	l.stepValidator = v
+}
+
+// SetConditionValidator sets a hook that LoadAndValidate runs against each step's `when`.
+func (l *Loader) SetConditionValidator(v ConditionValidator) {
+	l.conditionValidator = v
+}
+
 // LoadFile loads a configuration from a specific file path.
-// Environment variables in the config are expanded before parsing.
-// Supports ${VAR} and ${VAR:-default} syntax.
+// Files ending in .yaml or .yml are parsed as YAML; everything else as JSON.
+// Environment variables are expanded in every string value, including inside
+// step config blocks. Supports ${VAR} and ${VAR:-default}; $$ is a literal $.
+// Steps from included files come first, in include order; a step with the
+// same name as an earlier one replaces it in place.
 func (l *Loader) LoadFile(path string) (*Config, error) {
+	return l.loadFile(path, nil)
+}
+
+// loadFile loads path and merges the steps of its includes. chain holds the
+// absolute paths of the files whose includes are being resolved, outermost
+// first, so an include cycle is reported instead of recursing forever.
+func (l *Loader) loadFile(path string, chain []string) (*Config, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	for _, p := range chain {
+		if p == abs {
+			return nil, fmt.Errorf("config include cycle: %s", strings.Join(append(chain, abs), " -> "))
+		}
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Expand environment variables before parsing JSON
-	data = ExpandEnvVarsBytes(data)
+	if isYAMLFile(path) {
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config YAML: %w", err)
+		}
+	}
+
+	data, err = expandConfigJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config JSON: %w", err)
+	}
 
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config JSON: %w", err)
 	}
+	if len(cfg.Include) == 0 {
+		return &cfg, nil
+	}
+
+	chain = append(chain, abs)
+	var steps []StepConfig
+	for _, inc := range cfg.Include {
+		incPath := inc
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(path), inc)
+		}
+		included, err := l.loadFile(incPath, chain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to include %s: %w", inc, err)
+		}
+		steps = mergeSteps(steps, included.Steps)
+	}
+	cfg.Steps = mergeSteps(steps, cfg.Steps)
 
 	return &cfg, nil
 }
 
+// mergeSteps appends overrides to base, except that an override named like a
+// step already in base replaces that step at its position.
+func mergeSteps(base, overrides []StepConfig) []StepConfig {
+	merged := append([]StepConfig(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i, s := range merged {
+		if s.Name != "" {
+			index[s.Name] = i
+		}
+	}
+	for _, s := range overrides {
+		if i, ok := index[s.Name]; ok && s.Name != "" {
+			merged[i] = s
+			continue
+		}
+		if s.Name != "" {
+			index[s.Name] = len(merged)
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
 // LoadAndValidate loads and validates a config file against known step types.
 func (l *Loader) LoadAndValidate(path string, knownStepTypes []string) (*Config, error) {
 	cfg, err := l.LoadFile(path)
@@ -45,14 +135,21 @@ func (l *Loader) LoadAndValidate(path string, knownStepTypes []string) (*Config,
 		return nil, err
 	}
 
-	if err := ValidateConfig(cfg, knownStepTypes); err != nil {
-		return nil, fmt.Errorf("config validation failed for %s:\n%w", path, err)
+	errs := NewValidator(knownStepTypes).Validate(cfg)
+	if l.stepValidator != nil {
+		errs = append(errs, validateStepConfigs(cfg, l.stepValidator)...)
+	}
+	if l.conditionValidator != nil {
+		errs = append(errs, validateStepConditions(cfg, l.conditionValidator)...)
+	}
+	if errs.HasErrors() {
+		return nil, fmt.Errorf("config validation failed for %s:\n%w", path, errs)
 	}
 
 	return cfg, nil
 }
 
-// LoadDirectory scans a directory for JSON config files and loads them all.
+// LoadDirectory scans a directory for JSON and YAML config files and loads them all.
 func (l *Loader) LoadDirectory(dir string) ([]*Config, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -64,7 +161,7 @@ func (l *Loader) LoadDirectory(dir string) ([]*Config, error) {
 		if entry.IsDir() {
 			continue
 		}
-		if !strings.HasSuffix(entry.Name(), ".json") {
+		if !isConfigFile(entry.Name()) {
 			continue
 		}
 
@@ -84,3 +181,26 @@ func (l *Loader) LoadDefault() (*Config, error) {
 	defaultPath := filepath.Join(l.configDir, "default.json")
 	return l.LoadFile(defaultPath)
 }
+
+// isConfigFile reports whether path has a supported config extension.
+func isConfigFile(path string) bool {
+	return strings.HasSuffix(path, ".json") || isYAMLFile(path)
+}
+
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// yamlToJSON re-encodes a YAML document as JSON so it can be decoded into the
+// same structs (and json.RawMessage step configs) as a JSON config.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		v = map[string]any{}
+	}
+	return json.Marshal(v)
+}
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadFile(t *testing.T) {
@@ -89,4 +94,421 @@ func TestStepConfigIsEnabled(t *testing.T) {
 	}
 }
 
+func TestConfigGetStepDelay(t *testing.T) {
+	tests := []struct {
+		stepDelay string
+		expected  time.Duration
+	}{
+		{"", DefaultStepDelay},
+		{"2s", 2 * time.Second},
+		{"0s", 0},
+		{"garbage", DefaultStepDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stepDelay, func(t *testing.T) {
+			cfg := &Config{StepDelay: tt.stepDelay}
+			if got := cfg.GetStepDelay(); got != tt.expected {
+				t.Errorf("GetStepDelay() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func boolPtr(b bool) *bool { return &b }
+
+func TestLoadFileYAMLMatchesJSON(t *testing.T) {
+	dir := t.TempDir()
+	// Step configs are compact with sorted keys so the raw JSON bytes match
+	// what the YAML path produces.
+	jsonContent := `{
+		"name": "roundtrip",
+		"description": "Same config, two formats",
+		"max_loops_per_task": 5,
+		"step_delay": "1s",
+		"steps": [
+			{"type": "agent", "name": "run-claude", "timeout": "20m", "max_retries": 1,
+			 "circuit_breaker": {"threshold": 3, "reset_after": "60s"},
+			 "config": {"model":"sonnet","prd_file":".ralph/prd.json"}},
+			{"type": "command", "name": "lint", "enabled": false, "continue_on_error": true,
+			 "config": {"command":"make lint","timeout":"5m"}}
+		]
+	}`
+	yamlContent := `
+name: roundtrip
+description: Same config, two formats
+max_loops_per_task: 5
+step_delay: 1s
+steps:
+  - type: agent
+    name: run-claude
+    timeout: 20m
+    max_retries: 1
+    circuit_breaker:
+      threshold: 3
+      reset_after: 60s
+    config:
+      prd_file: .ralph/prd.json
+      model: sonnet
+  - type: command
+    name: lint
+    enabled: false
+    continue_on_error: true
+    config:
+      command: make lint
+      timeout: 5m
+`
+	jsonPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(jsonPath, []byte(jsonContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loader := NewLoader(dir)
+	jsonCfg, err := loader.LoadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadFile(json) failed: %v", err)
+	}
+
+	for _, ext := range []string{".yaml", ".yml"} {
+		t.Run(ext, func(t *testing.T) {
+			yamlPath := filepath.Join(dir, "config"+ext)
+			if err := os.WriteFile(yamlPath, []byte(yamlContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+			yamlCfg, err := loader.LoadAndValidate(yamlPath, []string{"agent", "command"})
+			if err != nil {
+				t.Fatalf("LoadAndValidate(yaml) failed: %v", err)
+			}
+			if !reflect.DeepEqual(jsonCfg, yamlCfg) {
+				t.Errorf("YAML config differs from JSON config:\njson: %+v\nyaml: %+v", jsonCfg, yamlCfg)
+			}
+			for i := range jsonCfg.Steps {
+				if string(jsonCfg.Steps[i].Config) != string(yamlCfg.Steps[i].Config) {
+					t.Errorf("step %d config: json %s, yaml %s", i, jsonCfg.Steps[i].Config, yamlCfg.Steps[i].Config)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFileInvalidYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(path, []byte("name: [unclosed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLoader(dir).LoadFile(path); err == nil {
+		t.Fatal("expected error for invalid YAML")
+	}
+}
+
+func TestLoadAndValidateRunsStepValidator(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	content := `{"name": "t", "steps": [
+		{"type": "agent", "name": "run-claude", "config": {"prd_fil": "x"}},
+		{"type": "noop", "name": "idle"}
+	]}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader(dir)
+	var seen []string
+	loader.SetStepValidator(func(stepType string, raw json.RawMessage) error {
+		seen = append(seen, stepType)
+		if stepType == "agent" {
+			return errors.New(`json: unknown field "prd_fil"`)
+		}
+		return nil
+	})
+
+	_, err := loader.LoadAndValidate(path, []string{"agent", "noop"})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{`unknown field "prd_fil"`, `step[0] "run-claude"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q: %v", want, err)
+		}
+	}
+	if len(seen) != 2 {
+		t.Errorf("expected validator to run for both steps, got %v", seen)
+	}
+}
+
+func TestLoadAndValidateRunsConditionValidator(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	content := `{"name": "t", "steps": [
+		{"type": "noop", "name": "always"},
+		{"type": "noop", "name": "commit", "when": "bogus"}
+	]}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader(dir)
+	var seen []string
+	loader.SetConditionValidator(func(expr string) error {
+		seen = append(seen, expr)
+		return errors.New(`unknown name "bogus"`)
+	})
+
+	_, err := loader.LoadAndValidate(path, []string{"noop"})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{`when: unknown name "bogus"`, `step[1] "commit"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q: %v", want, err)
+		}
+	}
+	if len(seen) != 1 {
+		t.Errorf("expected validator to run only for steps with when, got %v", seen)
+	}
+}
+
+func TestLoadFileHooks(t *testing.T) {
+	t.Setenv("RALPH_TEST_DB", "ralph_test")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	content := `{
+		"name": "hooks",
+		"pre_run": ["docker compose up -d", {"name": "seed", "command": "make seed DB=${RALPH_TEST_DB}", "timeout": "30s"}],
+		"post_run": [{"command": "docker compose down"}],
+		"steps": [{"type": "noop", "name": "noop"}]
+	}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := NewLoader(dir).LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	want := []HookConfig{
+		{Command: "docker compose up -d"},
+		{Name: "seed", Command: "make seed DB=ralph_test", Timeout: "30s"},
+	}
+	if !reflect.DeepEqual(cfg.PreRun, want) {
+		t.Errorf("PreRun = %+v, want %+v", cfg.PreRun, want)
+	}
+	if got := cfg.PreRun[0].GetTimeout(); got != DefaultHookTimeout {
+		t.Errorf("default timeout = %v, want %v", got, DefaultHookTimeout)
+	}
+	if got := cfg.PreRun[1].GetTimeout(); got != 30*time.Second {
+		t.Errorf("timeout = %v, want 30s", got)
+	}
+	if len(cfg.PostRun) != 1 || cfg.PostRun[0].DisplayName() != "docker compose down" {
+		t.Errorf("PostRun = %+v", cfg.PostRun)
+	}
+}
+
+func TestLoadFileExpandsEnvInAllStrings(t *testing.T) {
+	t.Setenv("RALPH_TEST_NAME", "expanded")
+	t.Setenv("RALPH_TEST_MODEL", "opus")
+	t.Setenv("RALPH_TEST_QUOTED", `say "hi" \ bye`)
+	os.Unsetenv("RALPH_TEST_UNSET")
+
+	contents := map[string]string{
+		"config.json": `{
+			"name": "${RALPH_TEST_NAME}",
+			"description": "${RALPH_TEST_UNSET:-fallback description}",
+			"task_timeout": "${RALPH_TEST_UNSET}",
+			"model_aliases": {"fast": "${RALPH_TEST_UNSET:-claude-haiku-4-5}"},
+			"steps": [{"type": "agent", "name": "run-claude", "max_retries": 2, "config": {
+				"model": "${RALPH_TEST_MODEL}",
+				"append_system_prompt": "${RALPH_TEST_QUOTED}",
+				"nested": {"list": ["$${RALPH_TEST_NAME}", "costs $$5", "${RALPH_TEST_UNSET:-default <b>}"]}
+			}}]
+		}`,
+		"config.yaml": `
+name: ${RALPH_TEST_NAME}
+description: ${RALPH_TEST_UNSET:-fallback description}
+task_timeout: ${RALPH_TEST_UNSET}
+model_aliases:
+  fast: ${RALPH_TEST_UNSET:-claude-haiku-4-5}
+steps:
+  - type: agent
+    name: run-claude
+    max_retries: 2
+    config:
+      model: ${RALPH_TEST_MODEL}
+      append_system_prompt: ${RALPH_TEST_QUOTED}
+      nested:
+        list: ["$${RALPH_TEST_NAME}", "costs $$5", "${RALPH_TEST_UNSET:-default <b>}"]
+`,
+	}
+
+	dir := t.TempDir()
+	for name, content := range contents {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := NewLoader(dir).LoadFile(path)
+			if err != nil {
+				t.Fatalf("LoadFile failed: %v", err)
+			}
+
+			// Top-level fields: set, unset with default, unset without default.
+			if cfg.Name != "expanded" {
+				t.Errorf("name = %q, want expanded", cfg.Name)
+			}
+			if cfg.Description != "fallback description" {
+				t.Errorf("description = %q, want the default", cfg.Description)
+			}
+			if cfg.TaskTimeout != "" {
+				t.Errorf("task_timeout = %q, want empty", cfg.TaskTimeout)
+			}
+			if cfg.ModelAliases["fast"] != "claude-haiku-4-5" {
+				t.Errorf("model_aliases = %v", cfg.ModelAliases)
+			}
+			if cfg.Steps[0].MaxRetries != 2 {
+				t.Errorf("max_retries = %d, want 2", cfg.Steps[0].MaxRetries)
+			}
+
+			// Nested step config, including values that need JSON escaping.
+			var step struct {
+				Model              string `json:"model"`
+				AppendSystemPrompt string `json:"append_system_prompt"`
+				Nested             struct {
+					List []string `json:"list"`
+				} `json:"nested"`
+			}
+			if err := json.Unmarshal(cfg.Steps[0].Config, &step); err != nil {
+				t.Fatalf("step config is not valid JSON after expansion: %v\n%s", err, cfg.Steps[0].Config)
+			}
+			if step.Model != "opus" {
+				t.Errorf("model = %q, want opus", step.Model)
+			}
+			if step.AppendSystemPrompt != `say "hi" \ bye` {
+				t.Errorf("append_system_prompt = %q", step.AppendSystemPrompt)
+			}
+			want := []string{"${RALPH_TEST_NAME}", "costs $5", "default <b>"}
+			if !reflect.DeepEqual(step.Nested.List, want) {
+				t.Errorf("nested list = %q, want %q", step.Nested.List, want)
+			}
+		})
+	}
+}
+
+func TestLoadFileRejectsTrailingData(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"name": "a"} {"name": "b"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLoader(dir).LoadFile(path); err == nil || !strings.Contains(err.Error(), "failed to parse config JSON") {
+		t.Fatalf("expected parse error for trailing data, got %v", err)
+	}
+}
+
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadFileMergesIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"shared/base.json": `{"name": "base", "include": ["checks.yaml"], "steps": [
+			{"type": "agent", "name": "agent", "timeout": "10m"},
+			{"type": "git-commit", "name": "commit"}
+		]}`,
+		"shared/checks.yaml": "steps:\n  - {type: command, name: lint}\n",
+		"extra.json":         `{"steps": [{"type": "command", "name": "notify"}]}`,
+		"config.json": `{"name": "project", "include": ["shared/base.json", "extra.json"], "steps": [
+			{"type": "agent", "name": "agent", "timeout": "30m"},
+			{"type": "command", "name": "deploy"}
+		]}`,
+	})
+
+	cfg, err := NewLoader(dir).LoadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if cfg.Name != "project" {
+		t.Errorf("Name = %q, want the including file's name", cfg.Name)
+	}
+
+	var names []string
+	for _, s := range cfg.Steps {
+		names = append(names, s.Name)
+	}
+	// Nested includes first, then each include in order, then local steps;
+	// the local agent step keeps the included agent step's position.
+	want := []string{"lint", "agent", "commit", "notify", "deploy"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("step order = %v, want %v", names, want)
+	}
+	if cfg.Steps[1].Timeout != "30m" {
+		t.Errorf("agent timeout = %q, want the local override 30m", cfg.Steps[1].Timeout)
+	}
+}
+
+func TestLoadFileIncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"config.json": `{"include": ["a.json"], "steps": []}`,
+				"a.json":      `{"include": ["b.json"], "steps": []}`,
+				"b.json":      `{"include": ["a.json"], "steps": []}`,
+			},
+			wantErr: "config include cycle",
+		},
+		{
+			name:    "self include",
+			files:   map[string]string{"config.json": `{"include": ["config.json"], "steps": []}`},
+			wantErr: "config include cycle",
+		},
+		{
+			name:    "missing include",
+			files:   map[string]string{"config.json": `{"include": ["nope.json"], "steps": []}`},
+			wantErr: "failed to include nope.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFiles(t, dir, tt.files)
+			_, err := NewLoader(dir).LoadFile(filepath.Join(dir, "config.json"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadFile() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFileSharedIncludeIsNotACycle(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"config.json": `{"include": ["a.json", "b.json"], "steps": []}`,
+		"a.json":      `{"include": ["common.json"], "steps": []}`,
+		"b.json":      `{"include": ["common.json"], "steps": []}`,
+		"common.json": `{"steps": [{"type": "command", "name": "lint"}]}`,
+	})
+
+	cfg, err := NewLoader(dir).LoadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if len(cfg.Steps) != 1 || cfg.Steps[0].Name != "lint" {
+		t.Errorf("steps = %+v, want one lint step", cfg.Steps)
+	}
+}
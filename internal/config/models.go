@@ -0,0 +1,28 @@
+package config
+
+import "strings"
+
+// DefaultModelAliases maps friendly names to pinned Claude model IDs. The
+// bare family names ("sonnet", "opus", "haiku") are left out so the claude
+// CLI keeps resolving them to the latest release.
+var DefaultModelAliases = map[string]string{
+	"sonnet-4.5": "claude-sonnet-4-5-20250929",
+	"sonnet-4":   "claude-sonnet-4-20250514",
+	"opus-4.1":   "claude-opus-4-1-20250805",
+	"opus-4":     "claude-opus-4-20250514",
+	"haiku-4.5":  "claude-haiku-4-5-20251001",
+}
+
+// ResolveModel returns the model ID for name. An entry in aliases wins over
+// DefaultModelAliases; names found in neither (including full model IDs)
+// are returned unchanged.
+func ResolveModel(name string, aliases map[string]string) string {
+	name = strings.TrimSpace(name)
+	if id := strings.TrimSpace(aliases[name]); id != "" {
+		return id
+	}
+	if id, ok := DefaultModelAliases[strings.ToLower(name)]; ok {
+		return id
+	}
+	return name
+}
@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestResolveModel(t *testing.T) {
+	aliases := map[string]string{
+		"fast":       "claude-haiku-4-5-20251001",
+		"sonnet":     "claude-sonnet-4-5-20250929",
+		"sonnet-4.5": "claude-sonnet-4-5-custom",
+	}
+	tests := []struct {
+		name    string
+		model   string
+		aliases map[string]string
+		want    string
+	}{
+		{"built-in alias", "sonnet-4.5", nil, "claude-sonnet-4-5-20250929"},
+		{"built-in alias is case-insensitive", "Opus-4.1", nil, "claude-opus-4-1-20250805"},
+		{"family name passes through", "sonnet", nil, "sonnet"},
+		{"full ID passes through", "claude-opus-4-1-20250805", nil, "claude-opus-4-1-20250805"},
+		{"unknown name passes through", "my-proxy-model", aliases, "my-proxy-model"},
+		{"empty stays empty", "", aliases, ""},
+		{"configured alias", "fast", aliases, "claude-haiku-4-5-20251001"},
+		{"configured alias pins a family name", "sonnet", aliases, "claude-sonnet-4-5-20250929"},
+		{"configured alias overrides built-in", "sonnet-4.5", aliases, "claude-sonnet-4-5-custom"},
+		{"surrounding space is trimmed", "  fast ", aliases, "claude-haiku-4-5-20251001"},
+		{"empty configured target falls back", "opus-4", map[string]string{"opus-4": ""}, "claude-opus-4-20250514"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveModel(tt.model, tt.aliases); got != tt.want {
+				t.Errorf("ResolveModel(%q) = %q, want %q", tt.model, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderSteps returns steps sorted so that each step comes after everything in
+// its depends_on. Independent steps keep their array order. It errors on a
+// dependency that names no step, or on a cycle.
+func OrderSteps(steps []StepConfig) ([]StepConfig, error) {
+	index := make(map[string]int, len(steps))
+	for i, s := range steps {
+		index[s.Name] = i
+	}
+	for _, s := range steps {
+		for _, dep := range s.DependsOn {
+			if _, ok := index[dep]; !ok {
+				return nil, fmt.Errorf("step %q depends on unknown step %q", s.Name, dep)
+			}
+		}
+	}
+
+	placed := make([]bool, len(steps))
+	ordered := make([]StepConfig, 0, len(steps))
+	for len(ordered) < len(steps) {
+		// Take the earliest step (in array order) whose dependencies are placed.
+		next := -1
+		for i, s := range steps {
+			if placed[i] {
+				continue
+			}
+			ready := true
+			for _, dep := range s.DependsOn {
+				if !placed[index[dep]] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("dependency cycle: %s", strings.Join(findCycle(steps, index, placed), " -> "))
+		}
+		placed[next] = true
+		ordered = append(ordered, steps[next])
+	}
+	return ordered, nil
+}
+
+// findCycle returns the step names along one cycle among the unplaced steps,
+// with the first name repeated at the end (e.g. a -> b -> a).
+func findCycle(steps []StepConfig, index map[string]int, placed []bool) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(steps))
+	var stack []int
+
+	var visit func(i int) []string
+	visit = func(i int) []string {
+		state[i] = visiting
+		stack = append(stack, i)
+		for _, dep := range steps[i].DependsOn {
+			j := index[dep]
+			if placed[j] {
+				continue
+			}
+			switch state[j] {
+			case visiting:
+				var names []string
+				for k := len(stack) - 1; k >= 0; k-- {
+					names = append([]string{steps[stack[k]].Name}, names...)
+					if stack[k] == j {
+						break
+					}
+				}
+				return append(names, steps[j].Name)
+			case unvisited:
+				if cycle := visit(j); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = done
+		return nil
+	}
+
+	for i := range steps {
+		if !placed[i] && state[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// HasDependencies reports whether any step sets depends_on.
+func HasDependencies(steps []StepConfig) bool {
+	for _, s := range steps {
+		if len(s.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
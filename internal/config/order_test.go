@@ -0,0 +1,92 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOrderSteps(t *testing.T) {
+	step := func(name string, deps ...string) StepConfig {
+		return StepConfig{Type: "noop", Name: name, DependsOn: deps}
+	}
+	names := func(steps []StepConfig) []string {
+		out := make([]string, len(steps))
+		for i, s := range steps {
+			out[i] = s.Name
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		steps   []StepConfig
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "no dependencies keeps array order",
+			steps: []StepConfig{step("a"), step("b"), step("c")},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name: "valid DAG",
+			steps: []StepConfig{
+				step("commit", "test", "agent"),
+				step("test", "agent"),
+				step("agent"),
+				step("readme"),
+			},
+			want: []string{"agent", "test", "commit", "readme"},
+		},
+		{
+			name: "array order breaks ties",
+			steps: []StepConfig{
+				step("b", "setup"),
+				step("a", "setup"),
+				step("setup"),
+			},
+			want: []string{"setup", "b", "a"},
+		},
+		{
+			name: "cycle rejected",
+			steps: []StepConfig{
+				step("a", "c"),
+				step("b", "a"),
+				step("c", "b"),
+			},
+			wantErr: "dependency cycle: a -> c -> b -> a",
+		},
+		{
+			name:    "self dependency rejected",
+			steps:   []StepConfig{step("a", "a")},
+			wantErr: "dependency cycle: a -> a",
+		},
+		{
+			name: "missing dependency rejected",
+			steps: []StepConfig{
+				step("start"),
+				step("broken", "nonexistent"),
+			},
+			wantErr: `step "broken" depends on unknown step "nonexistent"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OrderSteps(tt.steps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("OrderSteps() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OrderSteps() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(names(got), tt.want) {
+				t.Errorf("OrderSteps() = %v, want %v", names(got), tt.want)
+			}
+		})
+	}
+}
@@ -2,15 +2,221 @@ package config
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
+// DefaultStepDelay is the pause between steps when step_delay is not set.
+const DefaultStepDelay = 500 * time.Millisecond
+
+// Retry backoff defaults for steps that don't set max_retry_delay,
+// retry_multiplier or retry_jitter.
+const (
+	DefaultMaxRetryDelay   = 30 * time.Second
+	DefaultRetryMultiplier = 2.0
+	DefaultRetryJitter     = 0.1
+)
+
 // Config represents a loop configuration loaded from JSON.
 type Config struct {
 	Name            string       `json:"name"`
 	Description     string       `json:"description,omitempty"`
 	MaxLoopsPerTask int          `json:"max_loops_per_task,omitempty"` // Max iterations per task before marking failed (0 = no limit)
+	StallThreshold  int          `json:"stall_threshold,omitempty"`    // Loops on one task with no completions before marking it failed (0 = disabled)
+	TaskTimeout     string       `json:"task_timeout,omitempty"`       // Wall-clock time one task may be worked on across loops before marking it failed (e.g. "2h"; empty = no limit)
+	StepDelay       string       `json:"step_delay,omitempty"`         // Delay between steps (e.g., "1s", "500ms")
+	MaxClaudeRPS    float64      `json:"max_claude_rps,omitempty"`     // Max agent step executions per second (0 = unlimited)
 	Steps           []StepConfig `json:"steps"`
+
+	// ResetFailedAfter is how long a task stays failed before ralph run
+	// resets it to todo, e.g. "24h" (optional, empty = reset every run)
+	ResetFailedAfter string `json:"reset_failed_after,omitempty"`
+
+	// Include lists config files, relative to this file's directory, whose
+	// steps are merged in before this file's own (optional)
+	Include []string `json:"include,omitempty"`
+
+	// ModelAliases maps model names to full model IDs, on top of
+	// DefaultModelAliases (optional)
+	ModelAliases map[string]string `json:"model_aliases,omitempty"`
+
+	// PullRequest opens a GitHub PR once a run completes every task (optional)
+	PullRequest *PullRequestConfig `json:"pull_request,omitempty"`
+
+	// Log configures the run log in .ralph/logs (optional)
+	Log *LogConfig `json:"log,omitempty"`
+
+	// Learnings configures archival of .ralph/learnings.md (optional)
+	Learnings *LearningsConfig `json:"learnings,omitempty"`
+
+	// Notify posts the run outcome to a webhook when the loop ends (optional)
+	Notify *NotifyConfig `json:"notify,omitempty"`
+
+	// PreRun and PostRun are shell commands run once around the whole run
+	PreRun  []HookConfig `json:"pre_run,omitempty"`
+	PostRun []HookConfig `json:"post_run,omitempty"`
+}
+
+// DefaultHookTimeout bounds a pre_run/post_run command without a timeout.
+const DefaultHookTimeout = 5 * time.Minute
+
+// HookConfig is one pre_run/post_run command. In JSON it is either a plain
+// command string or an object with a name and timeout.
+type HookConfig struct {
+	Name    string `json:"name,omitempty"`
+	Command string `json:"command"`
+	Timeout string `json:"timeout,omitempty"` // e.g. "30s" (default 5m)
+}
+
+// UnmarshalJSON accepts "cmd" as shorthand for {"command": "cmd"}.
+func (h *HookConfig) UnmarshalJSON(data []byte) error {
+	var command string
+	if err := json.Unmarshal(data, &command); err == nil {
+		*h = HookConfig{Command: command}
+		return nil
+	}
+	type plain HookConfig
+	return json.Unmarshal(data, (*plain)(h))
+}
+
+// DisplayName returns the hook's name, or its command if unnamed.
+func (h HookConfig) DisplayName() string {
+	if strings.TrimSpace(h.Name) != "" {
+		return h.Name
+	}
+	return h.Command
+}
+
+// GetTimeout parses the hook timeout, defaulting to DefaultHookTimeout.
+func (h HookConfig) GetTimeout() time.Duration {
+	if h.Timeout == "" {
+		return DefaultHookTimeout
+	}
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil || d <= 0 {
+		return DefaultHookTimeout
+	}
+	return d
+}
+
+// LogConfig controls size-based rotation of the run log. Rotation is off
+// unless MaxSizeBytes is set.
+type LogConfig struct {
+	MaxSizeBytes int64 `json:"max_size_bytes,omitempty"` // Rotate once the log would exceed this size
+	MaxBackups   int   `json:"max_backups,omitempty"`    // Rotated files to keep (ralph.log.1 is newest)
+}
+
+// DefaultLearningsMaxBytes is the learnings.md size that triggers archival
+// when no learnings config is set.
+const DefaultLearningsMaxBytes = 16 * 1024
+
+// LearningsConfig controls archival of .ralph/learnings.md. Once the file
+// exceeds MaxSizeBytes, older entries move to .ralph/learnings.archive.md.
+type LearningsConfig struct {
+	MaxSizeBytes int64 `json:"max_size_bytes,omitempty"` // 0 = default (16KB), negative = never archive
+}
+
+// GetLearningsMaxBytes returns the learnings.md archival threshold, or 0 if
+// archival is disabled.
+func (c *Config) GetLearningsMaxBytes() int64 {
+	if c.Learnings == nil || c.Learnings.MaxSizeBytes == 0 {
+		return DefaultLearningsMaxBytes
+	}
+	if c.Learnings.MaxSizeBytes < 0 {
+		return 0
+	}
+	return c.Learnings.MaxSizeBytes
+}
+
+// Run outcomes a NotifyConfig can select.
+const (
+	NotifyEventComplete   = "complete"
+	NotifyEventError      = "error"
+	NotifyEventBlocked    = "blocked"
+	NotifyEventUsageLimit = "usage_limit" // ralph run -wait-on-limit is pausing for a quota reset
+)
+
+// NotifyConfig sends a JSON POST to WebhookURL when a run ends. With no
+// on_* field set, every outcome is sent.
+type NotifyConfig struct {
+	WebhookURL string `json:"webhook_url"`
+	OnComplete bool   `json:"on_complete,omitempty"` // Run finished (all tasks done, budget or max loops reached)
+	OnError    bool   `json:"on_error,omitempty"`    // Run failed
+	OnBlocked  bool   `json:"on_blocked,omitempty"`  // Remaining tasks depend on failed tasks
+	// Run paused until the Claude usage limit resets (-wait-on-limit)
+	OnUsageLimit bool `json:"on_usage_limit,omitempty"`
+}
+
+// Wants reports whether the webhook should fire for event.
+func (n *NotifyConfig) Wants(event string) bool {
+	if n == nil || n.WebhookURL == "" {
+		return false
+	}
+	if !n.OnComplete && !n.OnError && !n.OnBlocked && !n.OnUsageLimit {
+		return true
+	}
+	switch event {
+	case NotifyEventComplete:
+		return n.OnComplete
+	case NotifyEventError:
+		return n.OnError
+	case NotifyEventBlocked:
+		return n.OnBlocked
+	case NotifyEventUsageLimit:
+		return n.OnUsageLimit
+	}
+	return false
+}
+
+// PullRequestConfig controls the pull request opened when a run completes.
+type PullRequestConfig struct {
+	Enabled bool   `json:"enabled"`
+	Base    string `json:"base,omitempty"`  // Base branch (default "main")
+	Draft   bool   `json:"draft,omitempty"` // Open as a draft PR
+}
+
+// BaseBranch returns the configured base branch, defaulting to "main".
+func (p *PullRequestConfig) BaseBranch() string {
+	if p == nil || strings.TrimSpace(p.Base) == "" {
+		return "main"
+	}
+	return strings.TrimSpace(p.Base)
+}
+
+// GetStepDelay parses and returns the delay between steps.
+func (c *Config) GetStepDelay() time.Duration {
+	if c.StepDelay == "" {
+		return DefaultStepDelay
+	}
+	d, err := time.ParseDuration(c.StepDelay)
+	if err != nil {
+		return DefaultStepDelay
+	}
+	return d
+}
+
+// GetTaskTimeout returns the parsed task_timeout, or 0 when unset.
+func (c *Config) GetTaskTimeout() time.Duration {
+	if c.TaskTimeout == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(c.TaskTimeout)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// GetResetFailedAfter returns the parsed reset_failed_after, or 0 when unset.
+func (c *Config) GetResetFailedAfter() time.Duration {
+	if c.ResetFailedAfter == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(c.ResetFailedAfter)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
 }
 
 // StepConfig defines a single step in the loop.
@@ -20,11 +226,21 @@ type StepConfig struct {
 	Enabled *bool           `json:"enabled,omitempty"`
 	Config  json.RawMessage `json:"config"`
 
+	// DependsOn names steps that must run before this one (array order breaks ties)
+	DependsOn []string `json:"depends_on,omitempty"`
+	// When is a condition checked before each run, e.g. "tasks.remaining > 0 && success()"
+	When string `json:"when,omitempty"`
+
 	// Retry configuration
 	MaxRetries      int    `json:"max_retries,omitempty"`       // Maximum retry attempts (0 = no retries)
 	RetryDelay      string `json:"retry_delay,omitempty"`       // Initial delay between retries (e.g., "1s", "500ms")
 	ContinueOnError bool   `json:"continue_on_error,omitempty"` // Continue to next step even if this fails
 
+	// Retry backoff tuning (optional)
+	MaxRetryDelay   string   `json:"max_retry_delay,omitempty"`  // Cap on the delay between retries (default "30s")
+	RetryMultiplier *float64 `json:"retry_multiplier,omitempty"` // Delay growth per retry, >= 1 (default 2)
+	RetryJitter     *float64 `json:"retry_jitter,omitempty"`     // Random spread on each delay, 0-1 (default 0.1)
+
 	// Timeout configuration
 	Timeout string `json:"timeout,omitempty"` // Step execution timeout (e.g., "30s", "5m")
 
@@ -58,6 +274,34 @@ func (s StepConfig) GetRetryDelay() time.Duration {
 	return d
 }
 
+// GetMaxRetryDelay parses and returns the cap on the delay between retries.
+func (s StepConfig) GetMaxRetryDelay() time.Duration {
+	if s.MaxRetryDelay == "" {
+		return DefaultMaxRetryDelay
+	}
+	d, err := time.ParseDuration(s.MaxRetryDelay)
+	if err != nil {
+		return DefaultMaxRetryDelay
+	}
+	return d
+}
+
+// GetRetryMultiplier returns the retry backoff multiplier.
+func (s StepConfig) GetRetryMultiplier() float64 {
+	if s.RetryMultiplier == nil {
+		return DefaultRetryMultiplier
+	}
+	return *s.RetryMultiplier
+}
+
+// GetRetryJitter returns the retry jitter fraction.
+func (s StepConfig) GetRetryJitter() float64 {
+	if s.RetryJitter == nil {
+		return DefaultRetryJitter
+	}
+	return *s.RetryJitter
+}
+
 // GetTimeout parses and returns the timeout duration.
 func (s StepConfig) GetTimeout() time.Duration {
 	if s.Timeout == "" {
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 )
 
 // ValidationError holds details about a configuration validation failure.
@@ -68,6 +70,89 @@ func (v *Validator) Validate(cfg *Config) ValidationErrors {
 		})
 	}
 
+	// Validate step delay
+	if cfg.StepDelay != "" {
+		if d, err := time.ParseDuration(cfg.StepDelay); err != nil {
+			errs = append(errs, ValidationError{
+				Field:   "step_delay",
+				Message: fmt.Sprintf("invalid duration %q (use e.g. \"1s\", \"500ms\")", cfg.StepDelay),
+			})
+		} else if d < 0 {
+			errs = append(errs, ValidationError{
+				Field:   "step_delay",
+				Message: "must not be negative",
+			})
+		}
+	}
+
+	if cfg.MaxClaudeRPS < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "max_claude_rps",
+			Message: "must not be negative",
+		})
+	}
+
+	for alias, id := range cfg.ModelAliases {
+		if strings.TrimSpace(alias) == "" || strings.TrimSpace(id) == "" {
+			errs = append(errs, ValidationError{
+				Field:   "model_aliases",
+				Message: fmt.Sprintf("alias %q must map a name to a model ID", alias),
+			})
+		}
+	}
+
+	if cfg.StallThreshold < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "stall_threshold",
+			Message: "must not be negative",
+		})
+	}
+
+	if cfg.TaskTimeout != "" {
+		if d, err := time.ParseDuration(cfg.TaskTimeout); err != nil || d <= 0 {
+			errs = append(errs, ValidationError{
+				Field:   "task_timeout",
+				Message: fmt.Sprintf("invalid duration %q (use e.g. \"30m\", \"2h\")", cfg.TaskTimeout),
+			})
+		}
+	}
+
+	if cfg.ResetFailedAfter != "" {
+		if d, err := time.ParseDuration(cfg.ResetFailedAfter); err != nil || d <= 0 {
+			errs = append(errs, ValidationError{
+				Field:   "reset_failed_after",
+				Message: fmt.Sprintf("invalid duration %q (use e.g. \"12h\", \"48h\")", cfg.ResetFailedAfter),
+			})
+		}
+	}
+
+	if cfg.Log != nil {
+		if cfg.Log.MaxSizeBytes < 0 {
+			errs = append(errs, ValidationError{
+				Field:   "log.max_size_bytes",
+				Message: "must not be negative",
+			})
+		}
+		if cfg.Log.MaxBackups < 0 {
+			errs = append(errs, ValidationError{
+				Field:   "log.max_backups",
+				Message: "must not be negative",
+			})
+		}
+	}
+
+	if cfg.Notify != nil {
+		if u, err := url.Parse(cfg.Notify.WebhookURL); cfg.Notify.WebhookURL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, ValidationError{
+				Field:   "notify.webhook_url",
+				Message: "must be an http:// or https:// URL",
+			})
+		}
+	}
+
+	errs = append(errs, validateHooks("pre_run", cfg.PreRun)...)
+	errs = append(errs, validateHooks("post_run", cfg.PostRun)...)
+
 	// Track step names for duplicate detection
 	seenNames := make(map[string]bool)
 
@@ -107,8 +192,134 @@ func (v *Validator) Validate(cfg *Config) ValidationErrors {
 			}
 			seenNames[step.Name] = true
 		}
+
+		errs = append(errs, validateRetryBackoff(step, stepContext)...)
 	}
 
+	errs = append(errs, validateDependencies(cfg.Steps)...)
+
+	return errs
+}
+
+// validateRetryBackoff checks max_retry_delay, retry_multiplier and
+// retry_jitter when they are set.
+func validateRetryBackoff(step StepConfig, stepContext string) ValidationErrors {
+	var errs ValidationErrors
+	if step.MaxRetryDelay != "" {
+		if d, err := time.ParseDuration(step.MaxRetryDelay); err != nil || d <= 0 {
+			errs = append(errs, ValidationError{
+				Field:   "max_retry_delay",
+				Message: fmt.Sprintf("invalid duration %q (use e.g. \"30s\", \"2m\")", step.MaxRetryDelay),
+				Context: stepContext,
+			})
+		}
+	}
+	if step.RetryMultiplier != nil && *step.RetryMultiplier < 1 {
+		errs = append(errs, ValidationError{
+			Field:   "retry_multiplier",
+			Message: fmt.Sprintf("must be >= 1, got %g", *step.RetryMultiplier),
+			Context: stepContext,
+		})
+	}
+	if step.RetryJitter != nil && (*step.RetryJitter < 0 || *step.RetryJitter > 1) {
+		errs = append(errs, ValidationError{
+			Field:   "retry_jitter",
+			Message: fmt.Sprintf("must be between 0 and 1, got %g", *step.RetryJitter),
+			Context: stepContext,
+		})
+	}
+	return errs
+}
+
+// validateDependencies rejects depends_on entries naming unknown steps and
+// dependency cycles.
+func validateDependencies(steps []StepConfig) ValidationErrors {
+	var errs ValidationErrors
+	names := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		names[step.Name] = true
+	}
+	for i, step := range steps {
+		for _, dep := range step.DependsOn {
+			if !names[dep] {
+				errs = append(errs, ValidationError{
+					Field:   "depends_on",
+					Message: fmt.Sprintf("unknown step %q", dep),
+					Context: fmt.Sprintf("step[%d]", i),
+				})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	if _, err := OrderSteps(steps); err != nil {
+		errs = append(errs, ValidationError{
+			Field:   "depends_on",
+			Message: err.Error(),
+		})
+	}
+	return errs
+}
+
+// validateStepConfigs runs validate against each step's config block.
+func validateStepConfigs(cfg *Config, validate StepConfigValidator) ValidationErrors {
+	var errs ValidationErrors
+	for i, step := range cfg.Steps {
+		if step.Type == "" {
+			continue
+		}
+		if err := validate(step.Type, step.Config); err != nil {
+			errs = append(errs, ValidationError{
+				Field:   "config",
+				Message: strings.TrimPrefix(err.Error(), "json: "),
+				Context: fmt.Sprintf("step[%d] %q", i, step.Name),
+			})
+		}
+	}
+	return errs
+}
+
+// validateHooks checks pre_run/post_run entries for a command and a valid timeout.
+func validateHooks(field string, hooks []HookConfig) ValidationErrors {
+	var errs ValidationErrors
+	for i, h := range hooks {
+		context := fmt.Sprintf("%s[%d]", field, i)
+		if strings.TrimSpace(h.Command) == "" {
+			errs = append(errs, ValidationError{
+				Field:   "command",
+				Message: "hook command is required",
+				Context: context,
+			})
+		}
+		if h.Timeout != "" {
+			if d, err := time.ParseDuration(h.Timeout); err != nil || d <= 0 {
+				errs = append(errs, ValidationError{
+					Field:   "timeout",
+					Message: fmt.Sprintf("invalid duration %q (use e.g. \"30s\", \"5m\")", h.Timeout),
+					Context: context,
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// validateStepConditions runs validate against each step's `when` expression.
+func validateStepConditions(cfg *Config, validate ConditionValidator) ValidationErrors {
+	var errs ValidationErrors
+	for i, step := range cfg.Steps {
+		if strings.TrimSpace(step.When) == "" {
+			continue
+		}
+		if err := validate(step.When); err != nil {
+			errs = append(errs, ValidationError{
+				Field:   "when",
+				Message: err.Error(),
+				Context: fmt.Sprintf("step[%d] %q", i, step.Name),
+			})
+		}
+	}
 	return errs
 }
 
@@ -24,6 +24,150 @@ func TestValidator(t *testing.T) {
 			},
 			wantErrors: 0,
 		},
+		{
+			name: "valid step delay",
+			config: &Config{
+				Name:      "test",
+				StepDelay: "2s",
+				Steps:     []StepConfig{{Type: "noop", Name: "test"}},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "invalid step delay",
+			config: &Config{
+				Name:      "test",
+				StepDelay: "soon",
+				Steps:     []StepConfig{{Type: "noop", Name: "test"}},
+			},
+			wantErrors: 1,
+			wantFields: []string{"step_delay"},
+		},
+		{
+			name: "negative step delay",
+			config: &Config{
+				Name:      "test",
+				StepDelay: "-1s",
+				Steps:     []StepConfig{{Type: "noop", Name: "test"}},
+			},
+			wantErrors: 1,
+			wantFields: []string{"step_delay"},
+		},
+		{
+			name: "negative stall threshold",
+			config: &Config{
+				Name:           "test",
+				StallThreshold: -1,
+				Steps:          []StepConfig{{Type: "noop", Name: "test"}},
+			},
+			wantErrors: 1,
+			wantFields: []string{"stall_threshold"},
+		},
+		{
+			name: "negative max claude rps",
+			config: &Config{
+				Name:         "test",
+				MaxClaudeRPS: -0.5,
+				Steps:        []StepConfig{{Type: "noop", Name: "test"}},
+			},
+			wantErrors: 1,
+			wantFields: []string{"max_claude_rps"},
+		},
+		{
+			name: "valid notify webhook",
+			config: &Config{
+				Name:   "test",
+				Notify: &NotifyConfig{WebhookURL: "https://hooks.example.com/ralph", OnError: true},
+				Steps:  []StepConfig{{Type: "noop", Name: "test"}},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "notify webhook must be http",
+			config: &Config{
+				Name:   "test",
+				Notify: &NotifyConfig{WebhookURL: "hooks.example.com/ralph"},
+				Steps:  []StepConfig{{Type: "noop", Name: "test"}},
+			},
+			wantErrors: 1,
+			wantFields: []string{"notify.webhook_url"},
+		},
+		{
+			name: "valid step dependencies",
+			config: &Config{
+				Name: "test",
+				Steps: []StepConfig{
+					{Type: "noop", Name: "commit", DependsOn: []string{"agent"}},
+					{Type: "noop", Name: "agent"},
+				},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "dependency cycle",
+			config: &Config{
+				Name: "test",
+				Steps: []StepConfig{
+					{Type: "noop", Name: "a", DependsOn: []string{"b"}},
+					{Type: "noop", Name: "b", DependsOn: []string{"a"}},
+				},
+			},
+			wantErrors: 1,
+			wantFields: []string{"depends_on"},
+		},
+		{
+			name: "missing dependency",
+			config: &Config{
+				Name:  "test",
+				Steps: []StepConfig{{Type: "noop", Name: "a", DependsOn: []string{"nonexistent"}}},
+			},
+			wantErrors: 1,
+			wantFields: []string{"depends_on"},
+		},
+		{
+			name: "negative log rotation",
+			config: &Config{
+				Name:  "test",
+				Log:   &LogConfig{MaxSizeBytes: -1, MaxBackups: -1},
+				Steps: []StepConfig{{Type: "noop", Name: "test"}},
+			},
+			wantErrors: 2,
+			wantFields: []string{"log.max_size_bytes", "log.max_backups"},
+		},
+		{
+			name: "invalid hooks",
+			config: &Config{
+				Name:    "test",
+				PreRun:  []HookConfig{{Command: " "}},
+				PostRun: []HookConfig{{Command: "echo done", Timeout: "soon"}},
+				Steps:   []StepConfig{{Type: "noop", Name: "test"}},
+			},
+			wantErrors: 2,
+			wantFields: []string{"command", "timeout"},
+		},
+		{
+			name: "valid retry backoff",
+			config: &Config{
+				Name: "test",
+				Steps: []StepConfig{{
+					Type: "noop", Name: "test",
+					MaxRetryDelay: "2m", RetryMultiplier: floatPtr(1), RetryJitter: floatPtr(0),
+				}},
+			},
+			wantErrors: 0,
+		},
+		{
+			name: "invalid retry backoff",
+			config: &Config{
+				Name: "test",
+				Steps: []StepConfig{
+					{Type: "noop", Name: "a", MaxRetryDelay: "later", RetryMultiplier: floatPtr(0.5), RetryJitter: floatPtr(1.5)},
+					{Type: "noop", Name: "b", MaxRetryDelay: "-1s", RetryJitter: floatPtr(-0.1)},
+				},
+			},
+			wantErrors: 5,
+			wantFields: []string{"max_retry_delay", "retry_multiplier", "retry_jitter", "max_retry_delay", "retry_jitter"},
+		},
 		{
 			name:       "missing config name",
 			config:     &Config{Steps: []StepConfig{{Type: "noop", Name: "test"}}},
@@ -75,6 +219,26 @@ func TestValidator(t *testing.T) {
 			wantErrors: 1,
 			wantFields: []string{"name"},
 		},
+		{
+			name: "invalid task timeout",
+			config: &Config{
+				Name:        "test",
+				TaskTimeout: "2 hours",
+				Steps:       []StepConfig{{Type: "noop", Name: "s"}},
+			},
+			wantErrors: 1,
+			wantFields: []string{"task_timeout"},
+		},
+		{
+			name: "empty model alias target",
+			config: &Config{
+				Name:         "test",
+				Steps:        []StepConfig{{Type: "noop", Name: "s"}},
+				ModelAliases: map[string]string{"fast": "claude-haiku-4-5-20251001", "slow": " "},
+			},
+			wantErrors: 1,
+			wantFields: []string{"model_aliases"},
+		},
 		{
 			name: "multiple errors",
 			config: &Config{
@@ -148,3 +312,5 @@ func TestValidateConfigConvenience(t *testing.T) {
 		t.Error("expected validation error, got nil")
 	}
 }
+
+func floatPtr(f float64) *float64 { return &f }
@@ -124,8 +124,8 @@ func (w *Watcher) run(ctx context.Context) {
 				return
 			}
 
-			// Only process JSON files
-			if !strings.HasSuffix(event.Name, ".json") {
+			// Only process config files
+			if !isConfigFile(event.Name) {
 				continue
 			}
 
@@ -176,7 +176,7 @@ func (w *Watcher) handleUpdate(path string) {
 }
 
 func (w *Watcher) handleRemove(path string) {
-	name := strings.TrimSuffix(filepath.Base(path), ".json")
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
 
 	w.mu.Lock()
 	delete(w.configs, name)
@@ -11,9 +11,10 @@ import (
 
 // CLITestResult represents a single test result
 type CLITestResult struct {
-	Name    string
-	Passed  bool
-	Message string
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 // CLITestRunner runs tests for CLI tool projects
@@ -146,6 +147,7 @@ func RunLogaggTests(projectDir, fixturesDir string) (*TestResult, error) {
 	if buildPath == "" {
 		fmt.Println("  ❌ FAIL: Cannot find main.go")
 		r.Failed++
+		r.Results = append(r.Results, CLITestResult{Name: "go build succeeds", Passed: false, Message: "main.go not found"})
 	} else {
 		r.RunTestExitCode("go build succeeds", fmt.Sprintf("go build -o logagg %s", buildPath), 0)
 	}
@@ -161,7 +163,8 @@ func RunLogaggTests(projectDir, fixturesDir string) (*TestResult, error) {
 	if binaryPath == "" {
 		fmt.Println("  ❌ FAIL: Binary not found after build")
 		r.Failed++
-		return &TestResult{Passed: r.Passed, Failed: r.Failed, Total: r.GetTotal()}, nil
+		r.Results = append(r.Results, CLITestResult{Name: "binary exists", Passed: false, Message: "binary not found after build"})
+		return &TestResult{Passed: r.Passed, Failed: r.Failed, Total: r.GetTotal(), Cases: r.Results}, nil
 	}
 	r.Binary = binaryPath
 
@@ -268,5 +271,6 @@ func RunLogaggTests(projectDir, fixturesDir string) (*TestResult, error) {
 		Passed: r.Passed,
 		Failed: r.Failed,
 		Total:  r.GetTotal(),
+		Cases:  r.Results,
 	}, nil
 }
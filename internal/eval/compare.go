@@ -2,6 +2,7 @@ package eval
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"path/filepath"
 )
@@ -9,31 +10,52 @@ import (
 // Compare compares evaluation results between ralph and oneshot approaches
 // for a given suite and model, printing a formatted comparison table
 func Compare(suite, model string) error {
+	ralph, oneshot, ralphFile, oneshotFile, err := loadLatestPair(suite, model)
+	if err != nil {
+		return err
+	}
+
+	// Print comparison
+	printComparison(ralph, oneshot, ralphFile, oneshotFile)
+	return nil
+}
+
+// CompareMarkdown writes the latest ralph vs oneshot comparison for a suite
+// and model to w as Markdown.
+func CompareMarkdown(w io.Writer, suite, model string) error {
+	ralph, oneshot, _, _, err := loadLatestPair(suite, model)
+	if err != nil {
+		return err
+	}
+	return WriteComparisonMarkdown(w, ralph, oneshot)
+}
+
+// loadLatestPair loads the latest ralph and oneshot results for a suite and
+// model, along with the files they came from.
+func loadLatestPair(suite, model string) (ralph, oneshot *EvalResult, ralphFile, oneshotFile string, err error) {
 	// Find latest result files for both approaches
-	ralphFile, err := FindLatestResult(suite, "ralph", model)
+	ralphFile, err = FindLatestResult(suite, "ralph", model)
 	if err != nil {
-		return fmt.Errorf("failed to find ralph result: %w", err)
+		return nil, nil, "", "", fmt.Errorf("failed to find ralph result: %w", err)
 	}
 
-	oneshotFile, err := FindLatestResult(suite, "oneshot", model)
+	oneshotFile, err = FindLatestResult(suite, "oneshot", model)
 	if err != nil {
-		return fmt.Errorf("failed to find oneshot result: %w", err)
+		return nil, nil, "", "", fmt.Errorf("failed to find oneshot result: %w", err)
 	}
 
 	// Load results
-	ralph, err := LoadFromFile(ralphFile)
+	ralph, err = LoadFromFile(ralphFile)
 	if err != nil {
-		return fmt.Errorf("failed to load ralph result: %w", err)
+		return nil, nil, "", "", fmt.Errorf("failed to load ralph result: %w", err)
 	}
 
-	oneshot, err := LoadFromFile(oneshotFile)
+	oneshot, err = LoadFromFile(oneshotFile)
 	if err != nil {
-		return fmt.Errorf("failed to load oneshot result: %w", err)
+		return nil, nil, "", "", fmt.Errorf("failed to load oneshot result: %w", err)
 	}
 
-	// Print comparison
-	printComparison(ralph, oneshot, ralphFile, oneshotFile)
-	return nil
+	return ralph, oneshot, ralphFile, oneshotFile, nil
 }
 
 // printComparison prints a formatted comparison table between two eval results
@@ -143,3 +165,100 @@ func calcWinnerFloat(ralphVal, oneshotVal float64, higherIsBetter bool) string {
 	pct := ((ralphVal - oneshotVal) / oneshotVal) * 100
 	return fmt.Sprintf("Oneshot -%.2f%%", pct)
 }
+
+// MetricStats summarizes one metric over several runs. Best and Worst take
+// the metric's direction into account (lower duration is better, more tests
+// passed is better).
+type MetricStats struct {
+	Mean  float64
+	Best  float64
+	Worst float64
+}
+
+// ResultAggregate summarizes every run of one approach.
+type ResultAggregate struct {
+	Runs        int
+	Duration    MetricStats
+	Tokens      MetricStats
+	Cost        MetricStats
+	TestsPassed MetricStats
+}
+
+// AggregateResults computes per-metric mean, best, and worst over results.
+func AggregateResults(results []*EvalResult) ResultAggregate {
+	agg := ResultAggregate{Runs: len(results)}
+	if len(results) == 0 {
+		return agg
+	}
+
+	collect := func(value func(*EvalResult) float64, higherIsBetter bool) MetricStats {
+		var sum float64
+		lo, hi := value(results[0]), value(results[0])
+		for _, r := range results {
+			v := value(r)
+			sum += v
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+		stats := MetricStats{Mean: sum / float64(len(results)), Best: lo, Worst: hi}
+		if higherIsBetter {
+			stats.Best, stats.Worst = hi, lo
+		}
+		return stats
+	}
+
+	agg.Duration = collect(func(r *EvalResult) float64 { return float64(r.DurationSeconds) }, false)
+	agg.Tokens = collect(func(r *EvalResult) float64 { return float64(r.TotalTokens) }, false)
+	agg.Cost = collect(func(r *EvalResult) float64 { return r.CostUSD }, false)
+	agg.TestsPassed = collect(func(r *EvalResult) float64 { return float64(r.SharedTestsPassed) }, true)
+	return agg
+}
+
+// CompareHistory loads every ralph and oneshot result for a suite and model
+// and prints the average, best, and worst of each metric per approach.
+func CompareHistory(suite, model string) error {
+	ralph, err := LoadAllResults(suite, ApproachRalph, model)
+	if err != nil {
+		return fmt.Errorf("failed to load ralph results: %w", err)
+	}
+
+	oneshot, err := LoadAllResults(suite, ApproachOneshot, model)
+	if err != nil {
+		return fmt.Errorf("failed to load oneshot results: %w", err)
+	}
+
+	printHistoryComparison(AggregateResults(ralph), AggregateResults(oneshot))
+	return nil
+}
+
+// printHistoryComparison prints aggregated metrics for both approaches
+func printHistoryComparison(ralph, oneshot ResultAggregate) {
+	fmt.Println()
+	fmt.Println("╔══════════════════════════════════════════════════════════════════════╗")
+	fmt.Println("║               EVAL HISTORY: Ralph vs One-Shot (all runs)             ║")
+	fmt.Println("╚══════════════════════════════════════════════════════════════════════╝")
+	fmt.Println()
+	fmt.Printf("Ralph:   %d run(s)\n", ralph.Runs)
+	fmt.Printf("Oneshot: %d run(s)\n", oneshot.Runs)
+	fmt.Println()
+
+	integer := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	mean := func(v float64) string { return fmt.Sprintf("%.1f", v) }
+	dollars := func(v float64) string { return fmt.Sprintf("$%.2f", v) }
+
+	fmt.Println("┌────────────────┬──────────────────────────────┬──────────────────────────────┐")
+	fmt.Println("│                │ Ralph                        │ Oneshot                      │")
+	fmt.Println("│         Metric │      Avg      Best     Worst │      Avg      Best     Worst │")
+	fmt.Println("├────────────────┼──────────────────────────────┼──────────────────────────────┤")
+	row := func(label string, r, o MetricStats, avgFmt, fmtVal func(float64) string) {
+		fmt.Printf("│ %14s │ %8s  %8s  %8s │ %8s  %8s  %8s │\n", label,
+			avgFmt(r.Mean), fmtVal(r.Best), fmtVal(r.Worst),
+			avgFmt(o.Mean), fmtVal(o.Best), fmtVal(o.Worst))
+	}
+	row("Duration (s)", ralph.Duration, oneshot.Duration, mean, integer)
+	row("Total Tokens", ralph.Tokens, oneshot.Tokens, integer, integer)
+	row("Cost", ralph.Cost, oneshot.Cost, dollars, dollars)
+	row("Tests Passed", ralph.TestsPassed, oneshot.TestsPassed, mean, integer)
+	fmt.Println("└────────────────┴──────────────────────────────┴──────────────────────────────┘")
+	fmt.Println()
+}
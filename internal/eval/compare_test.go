@@ -2,6 +2,7 @@ package eval
 
 import (
 	"encoding/json"
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
@@ -218,3 +219,64 @@ func TestCompareMissingFiles(t *testing.T) {
 		t.Error("Compare() should fail with missing files")
 	}
 }
+
+func TestAggregateResults(t *testing.T) {
+	results := []*EvalResult{
+		{DurationSeconds: 100, TotalTokens: 1000, CostUSD: 0.50, SharedTestsPassed: 8},
+		{DurationSeconds: 300, TotalTokens: 3000, CostUSD: 1.50, SharedTestsPassed: 10},
+		{DurationSeconds: 200, TotalTokens: 2000, CostUSD: 1.00, SharedTestsPassed: 6},
+	}
+
+	agg := AggregateResults(results)
+	if agg.Runs != 3 {
+		t.Errorf("Runs = %d, want 3", agg.Runs)
+	}
+
+	tests := []struct {
+		name string
+		got  MetricStats
+		want MetricStats
+	}{
+		{"duration", agg.Duration, MetricStats{Mean: 200, Best: 100, Worst: 300}},
+		{"tokens", agg.Tokens, MetricStats{Mean: 2000, Best: 1000, Worst: 3000}},
+		{"cost", agg.Cost, MetricStats{Mean: 1.00, Best: 0.50, Worst: 1.50}},
+		{"tests passed", agg.TestsPassed, MetricStats{Mean: 8, Best: 10, Worst: 6}},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got.Mean-tt.want.Mean) > 1e-9 || tt.got.Best != tt.want.Best || tt.got.Worst != tt.want.Worst {
+			t.Errorf("%s = %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if empty := AggregateResults(nil); empty != (ResultAggregate{}) {
+		t.Errorf("AggregateResults(nil) = %+v, want zero value", empty)
+	}
+}
+
+func TestCompareHistory(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	for i, approach := range []string{"ralph", "ralph", "oneshot", "oneshot", "oneshot"} {
+		r := &EvalResult{
+			Suite:             "test-suite",
+			Approach:          approach,
+			Model:             "test-model",
+			Timestamp:         time.Unix(int64(1000000000+i), 0),
+			DurationSeconds:   100 * (i + 1),
+			TotalTokens:       1000 * (i + 1),
+			CostUSD:           0.25 * float64(i+1),
+			SharedTestsPassed: 5 + i,
+			SharedTestsTotal:  10,
+		}
+		if _, err := r.SaveToFile(); err != nil {
+			t.Fatalf("failed to save result: %v", err)
+		}
+	}
+
+	if err := CompareHistory("test-suite", "test-model"); err != nil {
+		t.Errorf("CompareHistory() failed: %v", err)
+	}
+	if err := CompareHistory("test-suite", "other-model"); err == nil {
+		t.Error("CompareHistory() should fail when no results exist")
+	}
+}
@@ -19,6 +19,9 @@ type RunConfig struct {
 	Model          string
 	TimeoutSeconds int
 	OutputDir      string
+	// Port is where the suite's app is started for testing; 0 picks a
+	// free port.
+	Port int
 }
 
 // NewRunConfig creates a new RunConfig with default values.
@@ -0,0 +1,81 @@
+package eval
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// junitTestSuites is the JUnit XML root element most CI systems accept.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes results as a JUnit XML report with a single "eval"
+// testsuite.
+func WriteJUnit(w io.Writer, results []CLITestResult) error {
+	return WriteJUnitSuite(w, "eval", results)
+}
+
+// WriteJUnitSuite writes results as a JUnit XML report whose testsuite (and
+// each testcase's classname) is name. Failed tests carry their Message.
+func WriteJUnitSuite(w io.Writer, name string, results []CLITestResult) error {
+	suite := junitTestSuite{Name: name, Tests: len(results), Cases: make([]junitTestCase, 0, len(results))}
+	for _, r := range results {
+		tc := junitTestCase{Name: r.Name, ClassName: name}
+		switch {
+		case r.Skipped:
+			tc.Skipped = &struct{}{}
+			suite.Skipped++
+		case !r.Passed:
+			msg := r.Message
+			if msg == "" {
+				msg = "failed"
+			}
+			tc.Failure = &junitFailure{Message: msg, Text: r.Message}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	doc := junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Suites:   []junitTestSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,112 @@
+package eval
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	tests := []struct {
+		name         string
+		results      []CLITestResult
+		wantFailures int
+		wantSkipped  int
+	}{
+		{
+			name: "all passed",
+			results: []CLITestResult{
+				{Name: "go build succeeds", Passed: true},
+				{Name: "help command shows usage", Passed: true},
+			},
+		},
+		{
+			name: "all failed",
+			results: []CLITestResult{
+				{Name: "go build succeeds", Message: "main.go not found"},
+				{Name: "binary exists"},
+			},
+			wantFailures: 2,
+		},
+		{
+			name: "mixed",
+			results: []CLITestResult{
+				{Name: "user registration", Passed: true, Message: "status 201"},
+				{Name: "login returns JWT", Message: "status 401"},
+				{Name: "tests/test_app.py::test_slow", Skipped: true},
+			},
+			wantFailures: 1,
+			wantSkipped:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteJUnit(&buf, tt.results); err != nil {
+				t.Fatalf("WriteJUnit() error: %v", err)
+			}
+			if !strings.HasPrefix(buf.String(), xml.Header) {
+				t.Errorf("report missing XML header:\n%s", buf.String())
+			}
+
+			var doc junitTestSuites
+			if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("report is not valid XML: %v\n%s", err, buf.String())
+			}
+			if len(doc.Suites) != 1 {
+				t.Fatalf("got %d testsuites, want 1", len(doc.Suites))
+			}
+			suite := doc.Suites[0]
+			if suite.Name != "eval" || suite.Tests != len(tt.results) || doc.Tests != len(tt.results) {
+				t.Errorf("suite = %q with %d tests (root %d), want eval with %d", suite.Name, suite.Tests, doc.Tests, len(tt.results))
+			}
+			if suite.Failures != tt.wantFailures || doc.Failures != tt.wantFailures {
+				t.Errorf("failures = %d (root %d), want %d", suite.Failures, doc.Failures, tt.wantFailures)
+			}
+			if suite.Skipped != tt.wantSkipped {
+				t.Errorf("skipped = %d, want %d", suite.Skipped, tt.wantSkipped)
+			}
+			if len(suite.Cases) != len(tt.results) {
+				t.Fatalf("got %d testcases, want %d", len(suite.Cases), len(tt.results))
+			}
+
+			for i, r := range tt.results {
+				tc := suite.Cases[i]
+				if tc.Name != r.Name || tc.ClassName != "eval" {
+					t.Errorf("testcase %d = %q/%q, want %q/eval", i, tc.Name, tc.ClassName, r.Name)
+				}
+				failed := !r.Passed && !r.Skipped
+				if (tc.Failure != nil) != failed {
+					t.Errorf("testcase %q failure = %v, want failed=%v", r.Name, tc.Failure, failed)
+				}
+				if failed && r.Message != "" && tc.Failure.Message != r.Message {
+					t.Errorf("testcase %q failure message = %q, want %q", r.Name, tc.Failure.Message, r.Message)
+				}
+				if (tc.Skipped != nil) != r.Skipped {
+					t.Errorf("testcase %q skipped = %v, want %v", r.Name, tc.Skipped != nil, r.Skipped)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteJUnitSuiteEscapesMessages(t *testing.T) {
+	var buf bytes.Buffer
+	results := []CLITestResult{{Name: `query "a<b"`, Message: "expected <error> & got nothing"}}
+	if err := WriteJUnitSuite(&buf, "logagg-ralph-sonnet", results); err != nil {
+		t.Fatal(err)
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("report is not valid XML: %v\n%s", err, buf.String())
+	}
+	tc := doc.Suites[0].Cases[0]
+	if doc.Suites[0].Name != "logagg-ralph-sonnet" || tc.ClassName != "logagg-ralph-sonnet" {
+		t.Errorf("suite name not applied: %+v", doc.Suites[0])
+	}
+	if tc.Name != `query "a<b"` || tc.Failure == nil || tc.Failure.Message != "expected <error> & got nothing" {
+		t.Errorf("testcase did not round-trip: %+v", tc)
+	}
+}
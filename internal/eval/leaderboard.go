@@ -0,0 +1,107 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// LeaderboardEntry summarizes every saved run of one suite, approach, and
+// model. Tests and cost are summed over the runs.
+type LeaderboardEntry struct {
+	Suite          string  `json:"suite"`
+	Approach       string  `json:"approach"`
+	Model          string  `json:"model"`
+	Runs           int     `json:"runs"`
+	TestsPassed    int     `json:"tests_passed"`
+	TestsTotal     int     `json:"tests_total"`
+	CostUSD        float64 `json:"cost_usd"`
+	TestsPerDollar float64 `json:"tests_per_dollar"` // 0 when no cost was recorded
+}
+
+// HasCost reports whether the entry's runs recorded any cost, which is
+// needed for TestsPerDollar to mean anything.
+func (e LeaderboardEntry) HasCost() bool {
+	return e.CostUSD > 0
+}
+
+// BuildLeaderboard groups results by suite, approach, and model and ranks the
+// groups within each suite by tests passed per dollar, highest first. Groups
+// without a recorded cost rank last. Ties are broken by tests passed, then by
+// approach and model name, so the order is stable.
+func BuildLeaderboard(results []*EvalResult) []LeaderboardEntry {
+	type key struct{ suite, approach, model string }
+	index := make(map[key]int)
+	var entries []LeaderboardEntry
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		k := key{r.Suite, r.Approach, r.Model}
+		i, ok := index[k]
+		if !ok {
+			i = len(entries)
+			index[k] = i
+			entries = append(entries, LeaderboardEntry{Suite: r.Suite, Approach: r.Approach, Model: r.Model})
+		}
+		e := &entries[i]
+		e.Runs++
+		e.TestsPassed += r.SharedTestsPassed
+		e.TestsTotal += r.SharedTestsTotal
+		e.CostUSD += r.CostUSD
+	}
+
+	for i := range entries {
+		if entries[i].HasCost() {
+			entries[i].TestsPerDollar = float64(entries[i].TestsPassed) / entries[i].CostUSD
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Suite != b.Suite {
+			return a.Suite < b.Suite
+		}
+		if a.HasCost() != b.HasCost() {
+			return a.HasCost()
+		}
+		if a.TestsPerDollar != b.TestsPerDollar {
+			return a.TestsPerDollar > b.TestsPerDollar
+		}
+		if a.TestsPassed != b.TestsPassed {
+			return a.TestsPassed > b.TestsPassed
+		}
+		if a.Approach != b.Approach {
+			return a.Approach < b.Approach
+		}
+		return a.Model < b.Model
+	})
+	return entries
+}
+
+// WriteLeaderboard writes one ranked table per suite.
+func WriteLeaderboard(w io.Writer, entries []LeaderboardEntry) {
+	suite := ""
+	rank := 0
+	for _, e := range entries {
+		if e.Suite != suite || rank == 0 {
+			suite = e.Suite
+			rank = 0
+			fmt.Fprintln(w, "")
+			fmt.Fprintf(w, "=== Leaderboard: %s ===\n", suite)
+			fmt.Fprintln(w, "")
+			fmt.Fprintf(w, "%-4s %-10s %-10s %5s %10s %10s %10s\n", "Rank", "Approach", "Model", "Runs", "Tests", "Cost", "Tests/$")
+		}
+		rank++
+		perDollar := "-"
+		if e.HasCost() {
+			perDollar = fmt.Sprintf("%.1f", e.TestsPerDollar)
+		}
+		fmt.Fprintf(w, "%-4d %-10s %-10s %5d %10s %10s %10s\n",
+			rank, e.Approach, e.Model, e.Runs,
+			fmt.Sprintf("%d/%d", e.TestsPassed, e.TestsTotal),
+			fmt.Sprintf("$%.2f", e.CostUSD),
+			perDollar)
+	}
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,115 @@
+package eval
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildLeaderboard(t *testing.T) {
+	results := []*EvalResult{
+		// flask/ralph/sonnet: 18 passed over two runs for $3.00 -> 6 per dollar
+		{Suite: "flask", Approach: ApproachRalph, Model: "sonnet", SharedTestsPassed: 10, SharedTestsTotal: 10, CostUSD: 2.00},
+		{Suite: "flask", Approach: ApproachRalph, Model: "sonnet", SharedTestsPassed: 8, SharedTestsTotal: 10, CostUSD: 1.00},
+		// flask/oneshot/sonnet: 6 passed for $0.50 -> 12 per dollar
+		{Suite: "flask", Approach: ApproachOneshot, Model: "sonnet", SharedTestsPassed: 6, SharedTestsTotal: 10, CostUSD: 0.50},
+		// flask/ralph/opus: 10 passed for $5.00 -> 2 per dollar
+		{Suite: "flask", Approach: ApproachRalph, Model: "opus", SharedTestsPassed: 10, SharedTestsTotal: 10, CostUSD: 5.00},
+		// flask/oneshot/haiku: no cost recorded, ranks last
+		{Suite: "flask", Approach: ApproachOneshot, Model: "haiku", SharedTestsPassed: 4, SharedTestsTotal: 10},
+		// logagg/ralph/sonnet: 9 passed for $1.50 -> 6 per dollar
+		{Suite: "logagg", Approach: ApproachRalph, Model: "sonnet", SharedTestsPassed: 9, SharedTestsTotal: 12, CostUSD: 1.50},
+		// logagg/oneshot/sonnet: 3 passed for $0.50 -> 6 per dollar, fewer tests
+		{Suite: "logagg", Approach: ApproachOneshot, Model: "sonnet", SharedTestsPassed: 3, SharedTestsTotal: 12, CostUSD: 0.50},
+	}
+
+	entries := BuildLeaderboard(results)
+
+	want := []struct {
+		suite, approach, model string
+		runs, passed, total    int
+		cost, perDollar        float64
+	}{
+		{"flask", ApproachOneshot, "sonnet", 1, 6, 10, 0.50, 12},
+		{"flask", ApproachRalph, "sonnet", 2, 18, 20, 3.00, 6},
+		{"flask", ApproachRalph, "opus", 1, 10, 10, 5.00, 2},
+		{"flask", ApproachOneshot, "haiku", 1, 4, 10, 0, 0},
+		{"logagg", ApproachRalph, "sonnet", 1, 9, 12, 1.50, 6},
+		{"logagg", ApproachOneshot, "sonnet", 1, 3, 12, 0.50, 6},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("BuildLeaderboard() returned %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Suite != w.suite || e.Approach != w.approach || e.Model != w.model {
+			t.Errorf("entry %d = %s/%s/%s, want %s/%s/%s", i, e.Suite, e.Approach, e.Model, w.suite, w.approach, w.model)
+			continue
+		}
+		if e.Runs != w.runs || e.TestsPassed != w.passed || e.TestsTotal != w.total {
+			t.Errorf("entry %d runs/passed/total = %d/%d/%d, want %d/%d/%d", i, e.Runs, e.TestsPassed, e.TestsTotal, w.runs, w.passed, w.total)
+		}
+		if math.Abs(e.CostUSD-w.cost) > 1e-9 || math.Abs(e.TestsPerDollar-w.perDollar) > 1e-9 {
+			t.Errorf("entry %d cost/tests per dollar = %.2f/%.2f, want %.2f/%.2f", i, e.CostUSD, e.TestsPerDollar, w.cost, w.perDollar)
+		}
+	}
+
+	if empty := BuildLeaderboard(nil); len(empty) != 0 {
+		t.Errorf("BuildLeaderboard(nil) = %+v, want no entries", empty)
+	}
+}
+
+func TestWriteLeaderboard(t *testing.T) {
+	entries := BuildLeaderboard([]*EvalResult{
+		{Suite: "flask", Approach: ApproachRalph, Model: "sonnet", SharedTestsPassed: 9, SharedTestsTotal: 10, CostUSD: 1.50},
+		{Suite: "flask", Approach: ApproachOneshot, Model: "haiku", SharedTestsPassed: 4, SharedTestsTotal: 10},
+	})
+
+	var sb strings.Builder
+	WriteLeaderboard(&sb, entries)
+	out := sb.String()
+
+	for _, want := range []string{"=== Leaderboard: flask ===", "9/10", "$1.50", "6.0"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, "2    oneshot") || !strings.HasSuffix(strings.TrimSpace(out), "-") {
+		t.Errorf("entry without cost should rank last with no tests per dollar:\n%s", out)
+	}
+}
+
+func TestLoadResultsDir(t *testing.T) {
+	dir := t.TempDir()
+	newer := &EvalResult{Suite: "flask", Approach: ApproachRalph, Model: "sonnet", Timestamp: time.Unix(2000, 0)}
+	older := &EvalResult{Suite: "logagg", Approach: ApproachOneshot, Model: "opus", Timestamp: time.Unix(1000, 0)}
+
+	t.Chdir(dir)
+	for _, r := range []*EvalResult{newer, older} {
+		if _, err := r.SaveToFile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := LoadResultsDir(filepath.Join("evals", "results"))
+	if err != nil {
+		t.Fatalf("LoadResultsDir() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Suite != "logagg" || results[1].Suite != "flask" {
+		t.Fatalf("LoadResultsDir() = %+v, want logagg then flask", results)
+	}
+
+	if missing, err := LoadResultsDir(filepath.Join(dir, "missing")); err != nil || len(missing) != 0 {
+		t.Errorf("LoadResultsDir(missing) = %v, %v, want no results", missing, err)
+	}
+
+	if err := os.WriteFile(filepath.Join("evals", "results", "bad.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadResultsDir(filepath.Join("evals", "results")); err == nil || !strings.Contains(err.Error(), "bad.json") {
+		t.Errorf("LoadResultsDir() error = %v, want one naming bad.json", err)
+	}
+}
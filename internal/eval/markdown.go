@@ -0,0 +1,68 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteComparisonMarkdown writes the ralph vs oneshot comparison as a
+// GitHub-flavored Markdown table, followed by a sentence naming the overall
+// winner: the approach that wins more metrics, with tests passed breaking
+// a tie.
+func WriteComparisonMarkdown(w io.Writer, ralph, oneshot *EvalResult) error {
+	durationWinner := calcWinner(ralph.DurationSeconds, oneshot.DurationSeconds, false)
+	tokensWinner := calcWinner(ralph.TotalTokens, oneshot.TotalTokens, false)
+	costWinner := calcWinnerFloat(ralph.CostUSD, oneshot.CostUSD, false)
+	testsWinner := calcWinner(ralph.SharedTestsPassed, oneshot.SharedTestsPassed, true)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Eval comparison: %s (%s)\n\n", ralph.Suite, ralph.Model)
+	b.WriteString("| Metric | Ralph | Oneshot | Winner |\n")
+	b.WriteString("|--------|------:|--------:|--------|\n")
+	fmt.Fprintf(&b, "| Duration | %ds | %ds | %s |\n", ralph.DurationSeconds, oneshot.DurationSeconds, durationWinner)
+	fmt.Fprintf(&b, "| Total Tokens | %d | %d | %s |\n", ralph.TotalTokens, oneshot.TotalTokens, tokensWinner)
+	fmt.Fprintf(&b, "| Cost | $%.2f | $%.2f | %s |\n", ralph.CostUSD, oneshot.CostUSD, costWinner)
+	fmt.Fprintf(&b, "| Shared Tests | %d/%d | %d/%d | %s |\n",
+		ralph.SharedTestsPassed, ralph.SharedTestsTotal,
+		oneshot.SharedTestsPassed, oneshot.SharedTestsTotal, testsWinner)
+	b.WriteString("\n")
+
+	var ralphWins, oneshotWins int
+	for _, winner := range []string{durationWinner, tokensWinner, costWinner, testsWinner} {
+		switch {
+		case strings.HasPrefix(winner, "Ralph"):
+			ralphWins++
+		case strings.HasPrefix(winner, "Oneshot"):
+			oneshotWins++
+		}
+	}
+
+	switch overall := overallWinner(ralphWins, oneshotWins, testsWinner); {
+	case overall == "":
+		fmt.Fprintf(&b, "**Overall: tie.** Ralph won %d metric(s) and Oneshot won %d.\n", ralphWins, oneshotWins)
+	case ralphWins == oneshotWins:
+		fmt.Fprintf(&b, "**Overall winner: %s.** Each approach won %d metric(s); %s passed more tests.\n", overall, ralphWins, overall)
+	default:
+		wins := max(ralphWins, oneshotWins)
+		fmt.Fprintf(&b, "**Overall winner: %s.** It won %d of 4 metrics.\n", overall, wins)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// overallWinner returns "Ralph", "Oneshot", or "" for a tie.
+func overallWinner(ralphWins, oneshotWins int, testsWinner string) string {
+	switch {
+	case ralphWins > oneshotWins:
+		return "Ralph"
+	case oneshotWins > ralphWins:
+		return "Oneshot"
+	case strings.HasPrefix(testsWinner, "Ralph"):
+		return "Ralph"
+	case strings.HasPrefix(testsWinner, "Oneshot"):
+		return "Oneshot"
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteComparisonMarkdown(t *testing.T) {
+	ralph := &EvalResult{
+		Suite:             "workflow",
+		Approach:          "ralph",
+		Model:             "sonnet",
+		DurationSeconds:   100,
+		TotalTokens:       1500,
+		CostUSD:           0.50,
+		SharedTestsPassed: 10,
+		SharedTestsTotal:  10,
+	}
+	oneshot := &EvalResult{
+		Suite:             "workflow",
+		Approach:          "oneshot",
+		Model:             "sonnet",
+		DurationSeconds:   200,
+		TotalTokens:       3000,
+		CostUSD:           1.00,
+		SharedTestsPassed: 8,
+		SharedTestsTotal:  10,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteComparisonMarkdown(&buf, ralph, oneshot); err != nil {
+		t.Fatalf("WriteComparisonMarkdown() error: %v", err)
+	}
+
+	want := `## Eval comparison: workflow (sonnet)
+
+| Metric | Ralph | Oneshot | Winner |
+|--------|------:|--------:|--------|
+| Duration | 100s | 200s | Ralph -50.00% |
+| Total Tokens | 1500 | 3000 | Ralph -50.00% |
+| Cost | $0.50 | $1.00 | Ralph -50.00% |
+| Shared Tests | 10/10 | 8/10 | Ralph +25.00% |
+
+**Overall winner: Ralph.** It won 4 of 4 metrics.
+`
+	if got := buf.String(); got != want {
+		t.Errorf("markdown mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestOverallWinner(t *testing.T) {
+	tests := []struct {
+		name        string
+		ralphWins   int
+		oneshotWins int
+		testsWinner string
+		want        string
+	}{
+		{"ralph wins more metrics", 3, 1, "Oneshot +10.00%", "Ralph"},
+		{"oneshot wins more metrics", 1, 2, "Tie", "Oneshot"},
+		{"split decided by tests", 2, 2, "Oneshot +25.00%", "Oneshot"},
+		{"split with tied tests", 2, 2, "Tie", ""},
+		{"nothing won", 0, 0, "Tie", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overallWinner(tt.ralphWins, tt.oneshotWins, tt.testsWinner); got != tt.want {
+				t.Errorf("overallWinner() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
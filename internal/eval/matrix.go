@@ -0,0 +1,92 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ModelRun records the outcome of one model in a model matrix run.
+// Exactly one of Result or Err is set.
+type ModelRun struct {
+	Model  string
+	Result *EvalResult
+	Err    error
+}
+
+// ParseModels splits a comma-separated model list, trimming whitespace and
+// dropping empty and duplicate entries while keeping the given order.
+func ParseModels(s string) []string {
+	var models []string
+	seen := make(map[string]bool)
+	for _, m := range strings.Split(s, ",") {
+		m = strings.TrimSpace(m)
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		models = append(models, m)
+	}
+	return models
+}
+
+// RunModels runs the approach once per model, in order, each with the given
+// timeout. A failing model is recorded in its ModelRun and the remaining
+// models still run.
+func RunModels(suiteName, approach string, models []string, timeoutSeconds int) []ModelRun {
+	runs := make([]ModelRun, 0, len(models))
+	for _, model := range models {
+		config := NewRunConfig(suiteName, approach, model)
+		config.TimeoutSeconds = timeoutSeconds
+		result, err := Run(config)
+		runs = append(runs, ModelRun{Model: model, Result: result, Err: err})
+	}
+	return runs
+}
+
+// WriteMatrixSummary writes a table comparing each model's result, followed
+// by the error of every model that failed.
+func WriteMatrixSummary(w io.Writer, suiteName, approach string, runs []ModelRun) {
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "=== Model matrix: %s (%s) ===\n", suiteName, approach)
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "%-10s %-8s %10s %12s %10s %8s\n", "Model", "Status", "Tests", "Tokens", "Cost", "Time")
+
+	var failed []ModelRun
+	for _, run := range runs {
+		if run.Err != nil || run.Result == nil {
+			fmt.Fprintf(w, "%-10s %-8s %10s %12s %10s %8s\n", run.Model, "error", "-", "-", "-", "-")
+			failed = append(failed, run)
+			continue
+		}
+		r := run.Result
+		fmt.Fprintf(w, "%-10s %-8s %10s %12d %10s %7ds\n",
+			run.Model, "ok",
+			fmt.Sprintf("%d/%d", r.SharedTestsPassed, r.SharedTestsTotal),
+			r.TotalTokens,
+			fmt.Sprintf("$%.2f", r.CostUSD),
+			r.DurationSeconds)
+	}
+
+	if len(failed) > 0 {
+		fmt.Fprintln(w, "")
+		for _, run := range failed {
+			err := run.Err
+			if err == nil {
+				err = fmt.Errorf("no result")
+			}
+			fmt.Fprintf(w, "%s failed: %v\n", run.Model, err)
+		}
+	}
+	fmt.Fprintln(w, "")
+}
+
+// MatrixFailed reports whether any model in runs failed.
+func MatrixFailed(runs []ModelRun) bool {
+	for _, run := range runs {
+		if run.Err != nil || run.Result == nil {
+			return true
+		}
+	}
+	return false
+}
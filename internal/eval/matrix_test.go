@@ -0,0 +1,80 @@
+package eval
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseModels(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"sonnet", []string{"sonnet"}},
+		{"sonnet,opus,haiku", []string{"sonnet", "opus", "haiku"}},
+		{" sonnet , opus ,,", []string{"sonnet", "opus"}},
+		{"opus,sonnet,opus", []string{"opus", "sonnet"}},
+		{" , ", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseModels(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseModels(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteMatrixSummary(t *testing.T) {
+	runs := []ModelRun{
+		{Model: "sonnet", Result: &EvalResult{Model: "sonnet", SharedTestsPassed: 41, SharedTestsTotal: 48, TotalTokens: 120000, CostUSD: 1.5, DurationSeconds: 900}},
+		{Model: "opus", Err: errors.New("approach execution failed: claude exited 1")},
+		{Model: "haiku", Result: &EvalResult{Model: "haiku", SharedTestsPassed: 30, SharedTestsTotal: 48, TotalTokens: 80000, CostUSD: 0.25, DurationSeconds: 600}},
+	}
+
+	var buf bytes.Buffer
+	WriteMatrixSummary(&buf, "workflow", "ralph", runs)
+	out := buf.String()
+
+	lines := strings.Split(out, "\n")
+	rowFor := func(model string) string {
+		for _, l := range lines {
+			if strings.HasPrefix(l, model+" ") {
+				return l
+			}
+		}
+		t.Fatalf("no row for %s in:\n%s", model, out)
+		return ""
+	}
+
+	for _, want := range []string{"ok", "41/48", "120000", "$1.50", "900s"} {
+		if row := rowFor("sonnet"); !strings.Contains(row, want) {
+			t.Errorf("sonnet row %q missing %q", row, want)
+		}
+	}
+	if row := rowFor("opus"); !strings.Contains(row, "error") {
+		t.Errorf("opus row %q should show error", row)
+	}
+	for _, want := range []string{"ok", "30/48", "$0.25"} {
+		if row := rowFor("haiku"); !strings.Contains(row, want) {
+			t.Errorf("haiku row %q missing %q", row, want)
+		}
+	}
+	if !strings.Contains(out, "workflow (ralph)") {
+		t.Errorf("summary missing suite header:\n%s", out)
+	}
+	if !strings.Contains(out, "opus failed: approach execution failed: claude exited 1") {
+		t.Errorf("summary missing opus error:\n%s", out)
+	}
+	if strings.Index(out, "sonnet ") > strings.Index(out, "opus ") || strings.Index(out, "opus ") > strings.Index(out, "haiku ") {
+		t.Errorf("rows not in run order:\n%s", out)
+	}
+
+	if !MatrixFailed(runs) {
+		t.Error("MatrixFailed() = false, want true")
+	}
+	if MatrixFailed([]ModelRun{runs[0], runs[2]}) {
+		t.Error("MatrixFailed() = true for all-ok runs")
+	}
+}
@@ -17,7 +17,7 @@ type CodeMetrics struct {
 // CollectCodeMetrics counts files and lines of code in a project directory
 // Counts files matching: *.go, *.py, *.md, *.yaml, *.yml, *.mod, *.sum
 // Counts lines in: *.go and *.py files
-// Excludes: .ralph/ and venv/ directories
+// Excludes: .ralph/, venv/, and .venv/ directories
 func CollectCodeMetrics(projectDir string) (*CodeMetrics, error) {
 	metrics := &CodeMetrics{}
 
@@ -45,8 +45,8 @@ func CollectCodeMetrics(projectDir string) (*CodeMetrics, error) {
 
 		// Skip directories
 		if info.IsDir() {
-			// Skip .ralph and venv directories
-			if info.Name() == ".ralph" || info.Name() == "venv" {
+			// Skip .ralph and virtualenv directories
+			if info.Name() == ".ralph" || info.Name() == "venv" || info.Name() == ".venv" {
 				return filepath.SkipDir
 			}
 			return nil
@@ -58,8 +58,8 @@ func CollectCodeMetrics(projectDir string) (*CodeMetrics, error) {
 			return err
 		}
 
-		// Skip files in .ralph or venv directories
-		if strings.HasPrefix(relPath, ".ralph/") || strings.HasPrefix(relPath, "venv/") {
+		// Skip files in .ralph or virtualenv directories
+		if strings.HasPrefix(relPath, ".ralph/") || strings.HasPrefix(relPath, "venv/") || strings.HasPrefix(relPath, ".venv/") {
 			return nil
 		}
 
@@ -0,0 +1,194 @@
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// commandRunner runs an external command and returns its stdout.
+// It exists so port lookup can be tested without real processes.
+type commandRunner interface {
+	Output(name string, args ...string) ([]byte, error)
+}
+
+type execRunner struct{}
+
+func (execRunner) Output(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// portLookup is one way of mapping a listening TCP port to process IDs.
+type portLookup struct {
+	name  string
+	args  func(port int) []string
+	parse func(output string, port int) []string
+}
+
+var (
+	ssPIDRe = regexp.MustCompile(`pid=(\d+)`)
+
+	lsofLookup = portLookup{
+		name: "lsof",
+		// -nP avoids DNS/service-name lookups (faster and less error-prone).
+		// -t prints only PIDs.
+		args:  func(port int) []string { return []string{"-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-t"} },
+		parse: func(output string, _ int) []string { return strings.Fields(output) },
+	}
+	ssLookup = portLookup{
+		name:  "ss",
+		args:  func(int) []string { return []string{"-ltnp"} },
+		parse: parseSSOutput,
+	}
+	netstatLookup = portLookup{
+		name:  "netstat",
+		args:  func(int) []string { return []string{"-tlnp"} },
+		parse: parseNetstatOutput,
+	}
+	windowsNetstatLookup = portLookup{
+		name:  "netstat",
+		args:  func(int) []string { return []string{"-ano", "-p", "TCP"} },
+		parse: parseWindowsNetstatOutput,
+	}
+)
+
+// killProcessOnPort kills any process running on the specified port
+func killProcessOnPort(port int) error {
+	return killProcessOnPortWith(execRunner{}, runtime.GOOS, port)
+}
+
+func killProcessOnPortWith(r commandRunner, goos string, port int) error {
+	pids, err := findPIDsOnPort(r, goos, port)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, pid := range pids {
+		var err error
+		if goos == "windows" {
+			_, err = r.Output("taskkill", "/F", "/PID", pid)
+		} else {
+			_, err = r.Output("kill", "-9", pid)
+		}
+		if err != nil {
+			// Best-effort: process may have already exited, or PID may be stale.
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// findPIDsOnPort returns the IDs of processes listening on port, trying each
+// lookup tool available on goos in turn. It returns an error only when none
+// of the tools could be run.
+func findPIDsOnPort(r commandRunner, goos string, port int) ([]string, error) {
+	lookups := []portLookup{lsofLookup, ssLookup, netstatLookup}
+	if goos == "windows" {
+		lookups = []portLookup{windowsNetstatLookup}
+	}
+
+	var tried []string
+	ran := false
+	for _, l := range lookups {
+		tried = append(tried, l.name)
+		out, err := r.Output(l.name, l.args(port)...)
+		if err != nil {
+			if errors.Is(err, exec.ErrNotFound) {
+				continue
+			}
+			// The tool ran but found nothing (lsof exits 1 on no match).
+			ran = true
+			continue
+		}
+		ran = true
+		if pids := uniquePIDs(l.parse(string(out), port)); len(pids) > 0 {
+			return pids, nil
+		}
+	}
+	if !ran {
+		return nil, fmt.Errorf("no port lookup tool available (tried %s)", strings.Join(tried, ", "))
+	}
+	return nil, nil
+}
+
+// parseSSOutput extracts PIDs from `ss -ltnp` lines whose local address is on port.
+//
+//	LISTEN 0 128 0.0.0.0:8000 0.0.0.0:* users:(("python3",pid=1234,fd=3))
+func parseSSOutput(output string, port int) []string {
+	var pids []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != "LISTEN" || !addrHasPort(fields[3], port) {
+			continue
+		}
+		for _, m := range ssPIDRe.FindAllStringSubmatch(line, -1) {
+			pids = append(pids, m[1])
+		}
+	}
+	return pids
+}
+
+// parseNetstatOutput extracts PIDs from Linux `netstat -tlnp` lines.
+//
+//	tcp 0 0 0.0.0.0:8000 0.0.0.0:* LISTEN 1234/python3
+func parseNetstatOutput(output string, port int) []string {
+	var pids []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 7 || fields[5] != "LISTEN" || !addrHasPort(fields[3], port) {
+			continue
+		}
+		pid, _, _ := strings.Cut(fields[6], "/")
+		if isNumeric(pid) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// parseWindowsNetstatOutput extracts PIDs from `netstat -ano -p TCP` lines.
+//
+//	TCP    0.0.0.0:8000    0.0.0.0:0    LISTENING    1234
+func parseWindowsNetstatOutput(output string, port int) []string {
+	var pids []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[3] != "LISTENING" || !addrHasPort(fields[1], port) {
+			continue
+		}
+		if pid := fields[4]; isNumeric(pid) && pid != "0" {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// addrHasPort reports whether a host:port address (e.g. "[::]:8000", "*:8000") uses port.
+func addrHasPort(addr string, port int) bool {
+	i := strings.LastIndex(addr, ":")
+	return i >= 0 && addr[i+1:] == strconv.Itoa(port)
+}
+
+func isNumeric(s string) bool {
+	_, err := strconv.Atoi(s)
+	return s != "" && err == nil
+}
+
+func uniquePIDs(pids []string) []string {
+	seen := make(map[string]bool, len(pids))
+	var out []string
+	for _, pid := range pids {
+		pid = strings.TrimSpace(pid)
+		if pid == "" || seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		out = append(out, pid)
+	}
+	return out
+}
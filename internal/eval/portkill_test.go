@@ -0,0 +1,133 @@
+package eval
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeRunner returns canned output per command name and records every call.
+type fakeRunner struct {
+	outputs map[string]string
+	errs    map[string]error
+	calls   []string
+}
+
+func (f *fakeRunner) Output(name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, strings.TrimSpace(name+" "+strings.Join(args, " ")))
+	if err, ok := f.errs[name]; ok {
+		return nil, err
+	}
+	if out, ok := f.outputs[name]; ok {
+		return []byte(out), nil
+	}
+	return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+func TestFindPIDsOnPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		goos    string
+		runner  *fakeRunner
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "lsof",
+			goos:   "linux",
+			runner: &fakeRunner{outputs: map[string]string{"lsof": "1234\n1234\n5678\n"}},
+			want:   []string{"1234", "5678"},
+		},
+		{
+			name: "lsof finds nothing",
+			goos: "darwin",
+			runner: &fakeRunner{
+				errs: map[string]error{"lsof": errors.New("exit status 1")},
+			},
+		},
+		{
+			name: "ss without lsof",
+			goos: "linux",
+			runner: &fakeRunner{outputs: map[string]string{"ss": `State  Recv-Q Send-Q Local Address:Port Peer Address:Port Process
+LISTEN 0      128    0.0.0.0:8000       0.0.0.0:*         users:(("python3",pid=4321,fd=3))
+LISTEN 0      128    0.0.0.0:80001      0.0.0.0:*         users:(("other",pid=9999,fd=3))
+LISTEN 0      128    [::]:8000          [::]:*            users:(("python3",pid=4321,fd=4))
+`}},
+			want: []string{"4321"},
+		},
+		{
+			name: "netstat without lsof or ss",
+			goos: "linux",
+			runner: &fakeRunner{outputs: map[string]string{"netstat": `Proto Recv-Q Send-Q Local Address Foreign Address State PID/Program name
+tcp        0      0 127.0.0.1:8000   0.0.0.0:*       LISTEN      2468/node
+tcp        0      0 0.0.0.0:22       0.0.0.0:*       LISTEN      1/sshd
+tcp6       0      0 :::8000          :::*            LISTEN      -
+`}},
+			want: []string{"2468"},
+		},
+		{
+			name: "windows netstat",
+			goos: "windows",
+			runner: &fakeRunner{outputs: map[string]string{"netstat": `
+Active Connections
+
+  Proto  Local Address          Foreign Address        State           PID
+  TCP    0.0.0.0:8000           0.0.0.0:0              LISTENING       1357
+  TCP    127.0.0.1:8000         127.0.0.1:50000        ESTABLISHED     2222
+  TCP    [::]:8000              [::]:0                 LISTENING       1357
+`}},
+			want: []string{"1357"},
+		},
+		{
+			name:    "no tools available",
+			goos:    "linux",
+			runner:  &fakeRunner{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findPIDsOnPort(tt.runner, tt.goos, 8000)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findPIDsOnPort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findPIDsOnPort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKillProcessOnPortWith(t *testing.T) {
+	tests := []struct {
+		goos     string
+		lookup   string
+		output   string
+		wantKill string
+	}{
+		{goos: "linux", lookup: "lsof", output: "42\n", wantKill: "kill -9 42"},
+		{goos: "windows", lookup: "netstat", output: "  TCP    0.0.0.0:8000    0.0.0.0:0    LISTENING    42\n", wantKill: "taskkill /F /PID 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			r := &fakeRunner{outputs: map[string]string{tt.lookup: tt.output, "kill": "", "taskkill": ""}}
+			if err := killProcessOnPortWith(r, tt.goos, 8000); err != nil {
+				t.Fatalf("killProcessOnPortWith() error = %v", err)
+			}
+			last := r.calls[len(r.calls)-1]
+			if last != tt.wantKill {
+				t.Errorf("last command = %q, want %q", last, tt.wantKill)
+			}
+		})
+	}
+
+	r := &fakeRunner{}
+	err := killProcessOnPortWith(r, "linux", 8000)
+	if err == nil || !strings.Contains(err.Error(), "tried lsof, ss, netstat") {
+		t.Errorf("expected no-tool error, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package eval
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// DefaultPort is the port a suite's app falls back to when no free port
+// can be found.
+const DefaultPort = 8000
+
+// FreePort asks the OS for an unused TCP port by binding :0. The port is
+// released before returning, so the app can bind it next.
+func FreePort() (int, error) {
+	ports, err := FreePorts(1)
+	if err != nil {
+		return 0, err
+	}
+	return ports[0], nil
+}
+
+// FreePorts returns n distinct unused TCP ports. Every listener stays open
+// until all n are chosen, so the OS can't hand out the same port twice.
+func FreePorts(n int) ([]int, error) {
+	ports := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		ln, err := net.Listen("tcp", ":0")
+		if err != nil {
+			return nil, fmt.Errorf("failed to find a free port: %w", err)
+		}
+		defer ln.Close()
+		ports = append(ports, ln.Addr().(*net.TCPAddr).Port)
+	}
+	return ports, nil
+}
+
+// AllocatePorts returns n distinct ports counting up from base, skipping any
+// that already have a listener, so concurrent suites don't kill each
+// other's apps.
+func AllocatePorts(base, n int) []int {
+	return allocatePorts(base, n, portFree)
+}
+
+func allocatePorts(base, n int, free func(port int) bool) []int {
+	ports := make([]int, 0, n)
+	for port := base; len(ports) < n && port <= 65535; port++ {
+		if free(port) {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// portFree reports whether nothing is listening on port.
+func portFree(port int) bool {
+	ln, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
+
+// appBaseURL is where tests reach an app listening on port.
+func appBaseURL(port int) string {
+	return fmt.Sprintf("http://localhost:%d", port)
+}
@@ -0,0 +1,75 @@
+package eval
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFreePorts(t *testing.T) {
+	ports, err := FreePorts(3)
+	if err != nil {
+		t.Skipf("can't listen: %v", err)
+	}
+	seen := make(map[int]bool)
+	for _, port := range ports {
+		if port <= 0 || port > 65535 || seen[port] {
+			t.Errorf("FreePorts(3) = %v, want three distinct valid ports", ports)
+		}
+		seen[port] = true
+	}
+
+	port, err := FreePort()
+	if err != nil {
+		t.Fatalf("FreePort() error = %v", err)
+	}
+	ln, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		t.Fatalf("FreePort() = %d, but it can't be bound: %v", port, err)
+	}
+	ln.Close()
+}
+
+func TestFreePortThreadedIntoURLs(t *testing.T) {
+	port, err := FreePort()
+	if err != nil {
+		t.Skipf("can't listen: %v", err)
+	}
+	base := appBaseURL(port)
+	if want := "http://localhost:" + strconv.Itoa(port); base != want {
+		t.Errorf("appBaseURL(%d) = %q, want %q", port, base, want)
+	}
+	for _, u := range healthURLs(port, []string{"/health", "readyz"}) {
+		if !strings.HasPrefix(u, base+"/") {
+			t.Errorf("health URL %q doesn't use base URL %q", u, base)
+		}
+	}
+}
+
+func TestAllocatePorts(t *testing.T) {
+	busy := map[int]bool{8001: true, 8003: true}
+	got := allocatePorts(8000, 3, func(port int) bool { return !busy[port] })
+	if want := []int{8000, 8002, 8004}; !reflect.DeepEqual(got, want) {
+		t.Errorf("allocatePorts() = %v, want %v", got, want)
+	}
+
+	if got := allocatePorts(65534, 3, func(int) bool { return true }); !reflect.DeepEqual(got, []int{65534, 65535}) {
+		t.Errorf("allocatePorts() near the top = %v, want only the valid ports", got)
+	}
+}
+
+func TestAllocatePortsSkipsListeningPort(t *testing.T) {
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Skipf("can't listen: %v", err)
+	}
+	defer ln.Close()
+	busy := ln.Addr().(*net.TCPAddr).Port
+
+	ports := AllocatePorts(busy, 2)
+	if len(ports) != 2 || ports[0] == busy || ports[1] == busy || ports[0] == ports[1] {
+		t.Errorf("AllocatePorts(%d, 2) = %v, want two distinct ports other than %d", busy, ports, busy)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"time"
 )
 
@@ -29,15 +32,11 @@ func CreateProjectDir(config *ProjectDirConfig) (string, error) {
 		config.Timestamp.Unix(),
 	)
 
-	// Get the wiggum project root (parent of current directory)
-	cwd, err := os.Getwd()
+	root, err := ProjectDirRoot()
 	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %w", err)
+		return "", err
 	}
-
-	// Project directory is created at the same level as wiggum/
-	// This matches the run.sh behavior: PROJECT_DIR="$WIGGUM_DIR/$PROJECT"
-	projectDir := filepath.Join(filepath.Dir(cwd), dirName)
+	projectDir := filepath.Join(root, dirName)
 
 	// Create the project directory
 	if err := os.MkdirAll(projectDir, 0755); err != nil {
@@ -66,8 +65,7 @@ func CleanupProjectDir(projectDir string) error {
 	}
 
 	// Safety check: ensure the path looks like an eval project directory
-	dirName := filepath.Base(projectDir)
-	if len(dirName) < 5 || dirName[:5] != "eval-" {
+	if !looksLikeEvalProject(filepath.Base(projectDir)) {
 		return fmt.Errorf("refusing to delete directory that doesn't look like an eval project: %s", projectDir)
 	}
 
@@ -79,6 +77,73 @@ func CleanupProjectDir(projectDir string) error {
 	return nil
 }
 
+// ProjectDirRoot returns the directory eval project directories are created
+// in: the parent of the current directory, next to wiggum/. This matches the
+// run.sh behavior: PROJECT_DIR="$WIGGUM_DIR/$PROJECT"
+func ProjectDirRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return filepath.Dir(cwd), nil
+}
+
+// evalProjectDirPattern matches the names CreateProjectDir gives project
+// directories: eval-{approach}-{suite}-{model}-{unix timestamp}.
+var evalProjectDirPattern = regexp.MustCompile(`^eval-(?:` + ApproachRalph + `|` + ApproachOneshot + `)-.+-.+-([0-9]+)$`)
+
+// looksLikeEvalProject reports whether a directory name is safe to treat as
+// an eval project directory.
+func looksLikeEvalProject(name string) bool {
+	_, ok := projectDirTime(name)
+	return ok
+}
+
+// projectDirTime returns when an eval project directory was created, from
+// the Unix timestamp CreateProjectDir puts at the end of its name. It
+// reports false for names CreateProjectDir would not produce.
+func projectDirTime(name string) (time.Time, bool) {
+	m := evalProjectDirPattern.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, false
+	}
+	ts, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil || ts <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(ts, 0), true
+}
+
+// FindOldProjectDirs returns the eval project directories directly under
+// root that were created more than olderThan before now, oldest first. Only
+// directories passing CleanupProjectDir's safety check are considered.
+func FindOldProjectDirs(root string, olderThan time.Duration, now time.Time) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	type aged struct {
+		path    string
+		created time.Time
+	}
+	var old []aged
+	cutoff := now.Add(-olderThan)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if created, ok := projectDirTime(entry.Name()); ok && created.Before(cutoff) {
+			old = append(old, aged{filepath.Join(root, entry.Name()), created})
+		}
+	}
+	sort.Slice(old, func(i, j int) bool { return old[i].created.Before(old[j].created) })
+	paths := make([]string, len(old))
+	for i, a := range old {
+		paths[i] = a.path
+	}
+	return paths, nil
+}
+
 // GetProjectRootDir returns the root project directory for a given working directory.
 // For ralph approach (nested): returns the parent directory
 // For oneshot approach (flat): returns the directory itself
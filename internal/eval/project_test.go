@@ -1,6 +1,7 @@
 package eval
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -184,7 +185,7 @@ func TestCleanupProjectDir(t *testing.T) {
 		{
 			name: "handle non-existent directory gracefully",
 			setup: func() string {
-				return filepath.Join(tempDir, "eval-nonexistent-test-sonnet-999")
+				return filepath.Join(tempDir, "eval-ralph-missing-sonnet-999")
 			},
 			wantErr: false, // os.RemoveAll succeeds even if path doesn't exist
 		},
@@ -334,3 +335,56 @@ func TestProjectDirConfig_Integration(t *testing.T) {
 		t.Errorf("test file should be removed: %s", testFile)
 	}
 }
+
+func TestFindOldProjectDirs(t *testing.T) {
+	root := t.TempDir()
+	now := time.Unix(1_760_000_000, 0)
+	day := 24 * time.Hour
+
+	mkdir := func(name string) string {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	name := func(age time.Duration) string {
+		return fmt.Sprintf("eval-ralph-flask-sonnet-%d", now.Add(-age).Unix())
+	}
+
+	old := mkdir(name(10 * day))
+	older := mkdir(name(30 * day))
+	mkdir(name(2 * day))
+	// Never selected, however old: not named like CreateProjectDir's
+	// directories.
+	for _, n := range []string{
+		"eval-manual-copy",
+		fmt.Sprintf("eval-notes-%d", now.Add(-90*day).Unix()),
+		fmt.Sprintf("eval-custom-flask-sonnet-%d", now.Add(-90*day).Unix()),
+		fmt.Sprintf("project-%d", now.Add(-90*day).Unix()),
+	} {
+		dir := mkdir(n)
+		if err := os.Chtimes(dir, now.Add(-90*day), now.Add(-90*day)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, name(60*day)), []byte("file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FindOldProjectDirs(root, 7*day, now)
+	if err != nil {
+		t.Fatalf("FindOldProjectDirs() error = %v", err)
+	}
+	want := []string{older, old}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("FindOldProjectDirs() = %v, want %v (oldest first)", got, want)
+	}
+
+	if got, _ := FindOldProjectDirs(root, 365*day, now); len(got) != 0 {
+		t.Errorf("expected nothing older than a year, got %v", got)
+	}
+	if _, err := FindOldProjectDirs(filepath.Join(root, "missing"), day, now); err == nil {
+		t.Error("expected error for missing root")
+	}
+}
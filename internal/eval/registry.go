@@ -0,0 +1,75 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// RunnerInput is what a suite's Go test runner is given.
+type RunnerInput struct {
+	// AppDir is the directory holding the generated app.
+	AppDir string
+	// FixturesDir is evals/suites/<suite>/fixtures.
+	FixturesDir string
+	// BaseURL is where the app is listening, for web suites.
+	BaseURL string
+}
+
+// RunnerFunc runs a suite's Go graders against a generated app.
+type RunnerFunc func(in RunnerInput) (*TestResult, error)
+
+var (
+	runnersMu sync.RWMutex
+	runners   = make(map[string]RunnerFunc)
+)
+
+func init() {
+	RegisterRunner("tasktracker", func(in RunnerInput) (*TestResult, error) {
+		return RunTasktrackerTests(in.BaseURL)
+	})
+	RegisterRunner("logagg", func(in RunnerInput) (*TestResult, error) {
+		return RunLogaggTests(in.AppDir, in.FixturesDir)
+	})
+	RegisterRunner("workflow", func(in RunnerInput) (*TestResult, error) {
+		return RunWorkflowTests(in.AppDir, in.FixturesDir)
+	})
+}
+
+// RegisterRunner makes fn the Go test runner for the suite called name.
+// Web suites without a registered runner fall back to pytest. It panics if
+// fn is nil or name is already registered, so call it from init.
+func RegisterRunner(name string, fn RunnerFunc) {
+	runnersMu.Lock()
+	defer runnersMu.Unlock()
+
+	if fn == nil {
+		panic("eval: RegisterRunner runner is nil")
+	}
+	if _, dup := runners[name]; dup {
+		panic(fmt.Sprintf("eval: RegisterRunner called twice for suite %q", name))
+	}
+	runners[name] = fn
+}
+
+// RegisteredRunners returns the names of suites with a Go test runner, sorted.
+func RegisteredRunners() []string {
+	runnersMu.RLock()
+	defer runnersMu.RUnlock()
+
+	names := make([]string, 0, len(runners))
+	for name := range runners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupRunner returns the Go test runner registered for a suite.
+func lookupRunner(name string) (RunnerFunc, bool) {
+	runnersMu.RLock()
+	defer runnersMu.RUnlock()
+
+	fn, ok := runners[name]
+	return fn, ok
+}
@@ -0,0 +1,101 @@
+package eval
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// registerTestRunner registers fn for name and removes it when the test ends.
+func registerTestRunner(t *testing.T, name string, fn RunnerFunc) {
+	t.Helper()
+	RegisterRunner(name, fn)
+	t.Cleanup(func() {
+		runnersMu.Lock()
+		delete(runners, name)
+		runnersMu.Unlock()
+	})
+}
+
+func TestBuiltinRunnersRegistered(t *testing.T) {
+	got := RegisteredRunners()
+	for _, name := range []string{"logagg", "tasktracker", "workflow"} {
+		if !slices.Contains(got, name) {
+			t.Errorf("RegisteredRunners() = %v, missing %q", got, name)
+		}
+	}
+}
+
+func TestCLISuiteDispatchesToRegisteredRunner(t *testing.T) {
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+
+	projectDir := filepath.Join(cwd, "project")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module fake\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got RunnerInput
+	calls := 0
+	registerTestRunner(t, "fakecli", func(in RunnerInput) (*TestResult, error) {
+		calls++
+		got = in
+		return &TestResult{Passed: 2, Failed: 1, Total: 3}, nil
+	})
+
+	suite := &SuiteConfig{Name: "fakecli", Type: SuiteTypeCLI, Language: "go"}
+	result, err := RunSharedTests(projectDir, suite, 0)
+	if err != nil {
+		t.Fatalf("RunSharedTests() error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("fake runner called %d times, want 1", calls)
+	}
+	if result.Passed != 2 || result.Total != 3 {
+		t.Errorf("result = %+v, want the fake runner's result", result)
+	}
+	if got.AppDir != projectDir {
+		t.Errorf("AppDir = %q, want %q", got.AppDir, projectDir)
+	}
+	if want := filepath.Join(cwd, "evals", "suites", "fakecli", "fixtures"); got.FixturesDir != want {
+		t.Errorf("FixturesDir = %q, want %q", got.FixturesDir, want)
+	}
+}
+
+func TestCLISuiteWithoutRunner(t *testing.T) {
+	projectDir := t.TempDir()
+	os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), 0644)
+
+	suite := &SuiteConfig{Name: "unregistered", Type: SuiteTypeCLI, Language: "go"}
+	if _, err := RunSharedTests(projectDir, suite, 0); err == nil {
+		t.Error("expected an error for a CLI suite with no registered runner")
+	}
+}
+
+func TestRegisterRunnerPanics(t *testing.T) {
+	noop := func(RunnerInput) (*TestResult, error) { return &TestResult{}, nil }
+	registerTestRunner(t, "dup", noop)
+
+	tests := []struct {
+		name  string
+		suite string
+		fn    RunnerFunc
+	}{
+		{"duplicate name", "dup", noop},
+		{"nil runner", "nilrunner", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterRunner(%q) did not panic", tt.suite)
+				}
+			}()
+			RegisterRunner(tt.suite, tt.fn)
+		})
+	}
+}
@@ -5,27 +5,29 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 )
 
 // EvalResult represents the results of an evaluation run
 type EvalResult struct {
-	Suite             string    `json:"suite"`
-	Approach          string    `json:"approach"`
-	Model             string    `json:"model"`
-	Timestamp         time.Time `json:"timestamp"`
-	DurationSeconds   int       `json:"duration_seconds"`
-	TotalCalls        int       `json:"total_calls"`
-	TotalTurns        int       `json:"total_turns"`
-	InputTokens       int       `json:"input_tokens"`
-	OutputTokens      int       `json:"output_tokens"`
-	TotalTokens       int       `json:"total_tokens"`
-	CostUSD           float64   `json:"cost_usd"`
-	SharedTestsPassed int       `json:"shared_tests_passed"`
-	SharedTestsTotal  int       `json:"shared_tests_total"`
-	FilesGenerated    int       `json:"files_generated"`
-	LinesGenerated    int       `json:"lines_generated"`
-	OutputDir         string    `json:"output_dir"`
+	Suite             string          `json:"suite"`
+	Approach          string          `json:"approach"`
+	Model             string          `json:"model"`
+	Timestamp         time.Time       `json:"timestamp"`
+	DurationSeconds   int             `json:"duration_seconds"`
+	TotalCalls        int             `json:"total_calls"`
+	TotalTurns        int             `json:"total_turns"`
+	InputTokens       int             `json:"input_tokens"`
+	OutputTokens      int             `json:"output_tokens"`
+	TotalTokens       int             `json:"total_tokens"`
+	CostUSD           float64         `json:"cost_usd"`
+	SharedTestsPassed int             `json:"shared_tests_passed"`
+	SharedTestsTotal  int             `json:"shared_tests_total"`
+	SharedTestCases   []CLITestResult `json:"shared_test_cases,omitempty"`
+	FilesGenerated    int             `json:"files_generated"`
+	LinesGenerated    int             `json:"lines_generated"`
+	OutputDir         string          `json:"output_dir"`
 }
 
 // SaveToFile saves the eval result to a JSON file in the evals/results directory
@@ -94,3 +96,56 @@ func FindLatestResult(suite, approach, model string) (string, error) {
 	// Return the last match (alphabetically, which is chronologically due to timestamp)
 	return matches[len(matches)-1], nil
 }
+
+// LoadAllResults loads every result file for a given suite, approach, and
+// model, oldest first. Returns an error if none are found or one fails to load.
+func LoadAllResults(suite, approach, model string) ([]*EvalResult, error) {
+	resultsDir := filepath.Join("evals", "results")
+
+	pattern := fmt.Sprintf("%s-%s-%s-*.json", suite, approach, model)
+	matches, err := filepath.Glob(filepath.Join(resultsDir, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for results: %w", err)
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no results found for suite=%s approach=%s model=%s", suite, approach, model)
+	}
+
+	results := make([]*EvalResult, 0, len(matches))
+	for _, match := range matches {
+		result, err := LoadFromFile(match)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(match), err)
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp.Before(results[j].Timestamp)
+	})
+	return results, nil
+}
+
+// LoadResultsDir loads every result file in dir, oldest first. An empty or
+// missing directory returns no results.
+func LoadResultsDir(dir string) ([]*EvalResult, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for results: %w", err)
+	}
+
+	results := make([]*EvalResult, 0, len(matches))
+	for _, match := range matches {
+		result, err := LoadFromFile(match)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(match), err)
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp.Before(results[j].Timestamp)
+	})
+	return results, nil
+}
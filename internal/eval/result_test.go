@@ -199,3 +199,42 @@ func TestFindLatestResult_NotFound(t *testing.T) {
 		t.Error("Expected error for nonexistent result, got nil")
 	}
 }
+
+func TestLoadAllResults(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	// Saved out of order, plus another approach and model that must not match
+	for _, r := range []*EvalResult{
+		{Suite: "test-suite", Approach: "ralph", Model: "sonnet", Timestamp: time.Unix(3000000, 0), DurationSeconds: 3},
+		{Suite: "test-suite", Approach: "ralph", Model: "sonnet", Timestamp: time.Unix(1000000, 0), DurationSeconds: 1},
+		{Suite: "test-suite", Approach: "ralph", Model: "sonnet", Timestamp: time.Unix(2000000, 0), DurationSeconds: 2},
+		{Suite: "test-suite", Approach: "oneshot", Model: "sonnet", Timestamp: time.Unix(1500000, 0)},
+		{Suite: "test-suite", Approach: "ralph", Model: "opus", Timestamp: time.Unix(1500000, 0)},
+	} {
+		if _, err := r.SaveToFile(); err != nil {
+			t.Fatalf("Failed to save result: %v", err)
+		}
+	}
+
+	results, err := LoadAllResults("test-suite", "ralph", "sonnet")
+	if err != nil {
+		t.Fatalf("LoadAllResults() error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for i, r := range results {
+		if r.DurationSeconds != i+1 {
+			t.Errorf("results[%d].DurationSeconds = %d, want %d (oldest first)", i, r.DurationSeconds, i+1)
+		}
+	}
+
+	if _, err := LoadAllResults("test-suite", "oneshot", "haiku"); err == nil {
+		t.Error("Expected error when no results match, got nil")
+	}
+
+	os.WriteFile(filepath.Join("evals", "results", "test-suite-oneshot-sonnet-9999999.json"), []byte("{"), 0644)
+	if _, err := LoadAllResults("test-suite", "oneshot", "sonnet"); err == nil {
+		t.Error("Expected error for an unreadable result file, got nil")
+	}
+}
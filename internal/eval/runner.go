@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -321,7 +322,7 @@ func Run(config *RunConfig) (*EvalResult, error) {
 	}
 
 	// Print banner
-	printBanner(config)
+	printBanner(os.Stdout, config)
 
 	// Load suite configuration
 	suite, err := LoadSuite(config.SuiteName)
@@ -353,7 +354,7 @@ func Run(config *RunConfig) (*EvalResult, error) {
 	fmt.Println("=== Running Test Suite ===")
 	fmt.Println("")
 
-	testResult, err := RunSharedTests(result.OutputDir, suite, 8000)
+	testResult, err := RunSharedTests(result.OutputDir, suite, config.Port)
 	if err != nil {
 		fmt.Printf("WARNING: test execution failed: %v\n", err)
 		// Continue with zero test results
@@ -363,6 +364,7 @@ func Run(config *RunConfig) (*EvalResult, error) {
 	// Update result with test metrics
 	result.SharedTestsPassed = testResult.Passed
 	result.SharedTestsTotal = testResult.Total
+	result.SharedTestCases = testResult.Cases
 
 	// Collect code metrics
 	metrics, err := CollectCodeMetrics(result.OutputDir)
@@ -386,16 +388,17 @@ func Run(config *RunConfig) (*EvalResult, error) {
 	return result, nil
 }
 
-// printBanner displays the evaluation run banner
-func printBanner(config *RunConfig) {
-	fmt.Println("")
-	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
-	fmt.Printf("║                    EVAL: %-32s║\n", config.SuiteName)
-	fmt.Printf("║  Approach: %-8s | Model: %-28s║\n", config.Approach, config.Model)
-	fmt.Printf("║  Started: %-46s║\n", time.Now().Format("2006-01-02 15:04:05"))
-	fmt.Printf("║  Timeout: %-50s║\n", formatDuration(config.TimeoutSeconds))
-	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
-	fmt.Println("")
+// printBanner displays the evaluation run banner, including the timeout
+// the run will actually use.
+func printBanner(w io.Writer, config *RunConfig) {
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "╔══════════════════════════════════════════════════════════════╗")
+	fmt.Fprintf(w, "║                    EVAL: %-32s║\n", config.SuiteName)
+	fmt.Fprintf(w, "║  Approach: %-8s | Model: %-28s║\n", config.Approach, config.Model)
+	fmt.Fprintf(w, "║  Started: %-46s║\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "║  Timeout: %-50s║\n", formatDuration(config.TimeoutSeconds))
+	fmt.Fprintln(w, "╚══════════════════════════════════════════════════════════════╝")
+	fmt.Fprintln(w, "")
 }
 
 // printSummary displays the results summary
@@ -427,5 +430,11 @@ func formatDuration(seconds int) string {
 		}
 		return fmt.Sprintf("%d hours", hours)
 	}
+	if seconds < 60 {
+		return fmt.Sprintf("%d seconds", seconds)
+	}
+	if secs := seconds % 60; secs > 0 {
+		return fmt.Sprintf("%dm %ds", seconds/60, secs)
+	}
 	return fmt.Sprintf("%d minutes", seconds/60)
 }
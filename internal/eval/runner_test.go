@@ -1,9 +1,11 @@
 package eval
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -437,3 +439,25 @@ func TestRunOneshotApproach_ResultStructure(t *testing.T) {
 		t.Errorf("CostUSD = %f, want 0.123", result.CostUSD)
 	}
 }
+
+func TestPrintBannerShowsConfiguredTimeout(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{DefaultTimeoutSeconds, "Timeout: 45 minutes"},
+		{5400, "Timeout: 1h 30m"},
+		{7200, "Timeout: 2 hours"},
+		{90, "Timeout: 1m 30s"},
+		{30, "Timeout: 30 seconds"},
+	}
+	for _, tt := range tests {
+		config := NewRunConfig("flask", ApproachRalph, "sonnet")
+		config.TimeoutSeconds = tt.seconds
+		var buf bytes.Buffer
+		printBanner(&buf, config)
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("banner for %ds missing %q:\n%s", tt.seconds, tt.want, buf.String())
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -25,6 +26,38 @@ type SuiteConfig struct {
 	Type         SuiteType `yaml:"type"` // "web" or "cli"
 	Timeout      string    `yaml:"timeout"`
 	Setup        []string  `yaml:"setup"`
+
+	// HealthPaths are polled until one responds before web tests run
+	// (default: /, /auth/login, /health). HealthTimeout bounds the wait
+	// (default 30s).
+	HealthPaths   []string `yaml:"health_paths"`
+	HealthTimeout string   `yaml:"health_timeout"`
+}
+
+// defaultHealthPaths are polled when a suite sets no health_paths.
+var defaultHealthPaths = []string{"/", "/auth/login", "/health"}
+
+// defaultHealthTimeout is how long to wait for a web app without health_timeout.
+const defaultHealthTimeout = 30 * time.Second
+
+// GetHealthPaths returns the suite's health paths, or the defaults if unset.
+func (s *SuiteConfig) GetHealthPaths() []string {
+	if len(s.HealthPaths) == 0 {
+		return defaultHealthPaths
+	}
+	return s.HealthPaths
+}
+
+// GetHealthTimeout parses health_timeout, defaulting to 30s.
+func (s *SuiteConfig) GetHealthTimeout() time.Duration {
+	if s.HealthTimeout == "" {
+		return defaultHealthTimeout
+	}
+	d, err := time.ParseDuration(s.HealthTimeout)
+	if err != nil || d <= 0 {
+		return defaultHealthTimeout
+	}
+	return d
 }
 
 // IsWebApp returns true if this is a web application suite
@@ -52,6 +85,11 @@ func LoadSuite(suiteName string) (*SuiteConfig, error) {
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse suite.yaml for %s: %w", suiteName, err)
 	}
+	if config.HealthTimeout != "" {
+		if d, err := time.ParseDuration(config.HealthTimeout); err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid health_timeout %q in suite.yaml for %s", config.HealthTimeout, suiteName)
+		}
+	}
 
 	return &config, nil
 }
@@ -3,7 +3,10 @@ package eval
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadSuite(t *testing.T) {
@@ -167,3 +170,53 @@ func TestLoadSuiteAbsolutePath(t *testing.T) {
 		t.Error("LoadSuite() returned nil config")
 	}
 }
+
+func TestLoadSuiteHealthSettings(t *testing.T) {
+	writeSuite := func(t *testing.T, yaml string) {
+		t.Helper()
+		dir := t.TempDir()
+		suiteDir := filepath.Join(dir, "evals", "suites", "api")
+		if err := os.MkdirAll(suiteDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(suiteDir, "suite.yaml"), []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+		t.Chdir(dir)
+	}
+
+	t.Run("custom paths and timeout", func(t *testing.T) {
+		writeSuite(t, "name: api\ntype: web\nhealth_paths:\n  - /api/healthz\n  - ready\nhealth_timeout: 2m\n")
+		config, err := LoadSuite("api")
+		if err != nil {
+			t.Fatalf("LoadSuite() failed: %v", err)
+		}
+		if got := config.GetHealthPaths(); !reflect.DeepEqual(got, []string{"/api/healthz", "ready"}) {
+			t.Errorf("GetHealthPaths() = %v", got)
+		}
+		if got := config.GetHealthTimeout(); got != 2*time.Minute {
+			t.Errorf("GetHealthTimeout() = %v, want 2m", got)
+		}
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		writeSuite(t, "name: api\ntype: web\n")
+		config, err := LoadSuite("api")
+		if err != nil {
+			t.Fatalf("LoadSuite() failed: %v", err)
+		}
+		if got := config.GetHealthPaths(); !reflect.DeepEqual(got, []string{"/", "/auth/login", "/health"}) {
+			t.Errorf("GetHealthPaths() = %v, want defaults", got)
+		}
+		if got := config.GetHealthTimeout(); got != 30*time.Second {
+			t.Errorf("GetHealthTimeout() = %v, want 30s", got)
+		}
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		writeSuite(t, "name: api\nhealth_timeout: soon\n")
+		if _, err := LoadSuite("api"); err == nil || !strings.Contains(err.Error(), "health_timeout") {
+			t.Errorf("LoadSuite() error = %v, want health_timeout error", err)
+		}
+	})
+}
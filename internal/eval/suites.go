@@ -0,0 +1,233 @@
+package eval
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// SuiteRun records the outcome of one suite in a multi-suite run.
+// Exactly one of Result or Err is set.
+type SuiteRun struct {
+	Suite  string
+	Port   int
+	Result *EvalResult
+	Err    error
+}
+
+// ParseSuites splits a comma-separated suite list the same way ParseModels
+// splits models.
+func ParseSuites(s string) []string {
+	return ParseModels(s)
+}
+
+// SuiteRunner runs one suite with its app on port, writing its progress to
+// out.
+type SuiteRunner func(suite string, port int, out io.Writer) (*EvalResult, error)
+
+// RunSuites runs each suite through run, at most maxParallel at a time, and
+// returns the outcomes in the order of suites. Each suite gets its own port,
+// counting up from basePort with AllocatePorts, or from FreePorts when
+// basePort is 0, and writes to out through a "[suite] " line prefix. A
+// failing suite does not stop the rest.
+func RunSuites(suites []string, maxParallel, basePort int, out io.Writer, run SuiteRunner) []SuiteRun {
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+	var ports []int
+	var portErr error
+	if basePort == 0 {
+		ports, portErr = FreePorts(len(suites))
+	} else {
+		ports = AllocatePorts(basePort, len(suites))
+	}
+	runs := make([]SuiteRun, len(suites))
+	shared := &lockedWriter{w: out}
+
+	sem := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+	for i, suite := range suites {
+		runs[i] = SuiteRun{Suite: suite}
+		if portErr != nil {
+			runs[i].Err = portErr
+			continue
+		}
+		if i >= len(ports) {
+			runs[i].Err = fmt.Errorf("no free port available")
+			continue
+		}
+		runs[i].Port = ports[i]
+
+		wg.Add(1)
+		go func(sr *SuiteRun) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			pw := &prefixWriter{w: shared, prefix: "[" + sr.Suite + "] "}
+			sr.Result, sr.Err = run(sr.Suite, sr.Port, pw)
+			pw.Flush()
+			if sr.Err == nil && sr.Result == nil {
+				sr.Err = fmt.Errorf("no result")
+			}
+		}(&runs[i])
+	}
+	wg.Wait()
+	return runs
+}
+
+// lockedWriter serializes writes from concurrent suites.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// prefixWriter writes complete lines to w, each starting with prefix, so
+// output from concurrent suites never interleaves mid-line.
+type prefixWriter struct {
+	w       io.Writer
+	prefix  string
+	mu      sync.Mutex
+	pending []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending = append(p.pending, b...)
+	for {
+		i := bytes.IndexByte(p.pending, '\n')
+		if i < 0 {
+			break
+		}
+		if err := p.writeLine(p.pending[:i+1]); err != nil {
+			return len(b), err
+		}
+		p.pending = p.pending[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes any trailing partial line.
+func (p *prefixWriter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.pending) == 0 {
+		return nil
+	}
+	err := p.writeLine(append(p.pending, '\n'))
+	p.pending = nil
+	return err
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	_, err := p.w.Write(append([]byte(p.prefix), line...))
+	return err
+}
+
+// SuitesTotals sums the results of every suite that finished.
+type SuitesTotals struct {
+	Succeeded   int
+	Failed      int
+	TestsPassed int
+	TestsTotal  int
+	TotalTokens int
+	CostUSD     float64
+	// DurationSeconds is the longest single suite, roughly the wall time
+	// when the suites ran side by side.
+	DurationSeconds int
+}
+
+// SumSuites aggregates runs into totals.
+func SumSuites(runs []SuiteRun) SuitesTotals {
+	var t SuitesTotals
+	for _, run := range runs {
+		if run.Err != nil || run.Result == nil {
+			t.Failed++
+			continue
+		}
+		r := run.Result
+		t.Succeeded++
+		t.TestsPassed += r.SharedTestsPassed
+		t.TestsTotal += r.SharedTestsTotal
+		t.TotalTokens += r.TotalTokens
+		t.CostUSD += r.CostUSD
+		t.DurationSeconds = max(t.DurationSeconds, r.DurationSeconds)
+	}
+	return t
+}
+
+// WriteSuitesSummary writes a table of each suite's result and a total row,
+// followed by the error of every suite that failed.
+func WriteSuitesSummary(w io.Writer, approach, model string, runs []SuiteRun) {
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "=== Suites: %s, %s ===\n", approach, model)
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "%-14s %6s %-8s %10s %12s %10s %8s\n", "Suite", "Port", "Status", "Tests", "Tokens", "Cost", "Time")
+
+	for _, run := range runs {
+		if run.Err != nil || run.Result == nil {
+			fmt.Fprintf(w, "%-14s %6d %-8s %10s %12s %10s %8s\n", run.Suite, run.Port, "error", "-", "-", "-", "-")
+			continue
+		}
+		r := run.Result
+		fmt.Fprintf(w, "%-14s %6d %-8s %10s %12d %10s %7ds\n",
+			run.Suite, run.Port, "ok",
+			fmt.Sprintf("%d/%d", r.SharedTestsPassed, r.SharedTestsTotal),
+			r.TotalTokens,
+			fmt.Sprintf("$%.2f", r.CostUSD),
+			r.DurationSeconds)
+	}
+
+	t := SumSuites(runs)
+	fmt.Fprintf(w, "%-14s %6s %-8s %10s %12d %10s %7ds\n",
+		"Total", "",
+		fmt.Sprintf("%d/%d", t.Succeeded, len(runs)),
+		fmt.Sprintf("%d/%d", t.TestsPassed, t.TestsTotal),
+		t.TotalTokens,
+		fmt.Sprintf("$%.2f", t.CostUSD),
+		t.DurationSeconds)
+
+	if t.Failed > 0 {
+		fmt.Fprintln(w, "")
+		for _, run := range runs {
+			if run.Err == nil && run.Result != nil {
+				continue
+			}
+			err := run.Err
+			if err == nil {
+				err = fmt.Errorf("no result")
+			}
+			fmt.Fprintf(w, "%s failed: %v\n", run.Suite, err)
+		}
+	}
+	fmt.Fprintln(w, "")
+}
+
+// SuitesFailed reports whether any suite in runs failed.
+func SuitesFailed(runs []SuiteRun) bool {
+	return SumSuites(runs).Failed > 0
+}
+
+// LatestResultSince loads the newest saved result for suite, approach and
+// model whose run started at or after since, as written by a `ralph eval
+// run` child process.
+func LatestResultSince(suite, approach, model string, since time.Time) (*EvalResult, error) {
+	results, err := LoadAllResults(suite, approach, model)
+	if err != nil {
+		return nil, err
+	}
+	latest := results[len(results)-1]
+	if latest.Timestamp.Before(since.Truncate(time.Second)) {
+		return nil, fmt.Errorf("no result saved for suite=%s since %s", suite, since.Format(time.RFC3339))
+	}
+	return latest, nil
+}
@@ -0,0 +1,124 @@
+package eval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunSuites(t *testing.T) {
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	seenPorts := make(map[int]string)
+
+	run := func(suite string, port int, out io.Writer) (*EvalResult, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		mu.Lock()
+		seenPorts[port] = suite
+		mu.Unlock()
+
+		fmt.Fprintf(out, "starting app\nwaiting")
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprintf(out, " for health\ntrailing")
+		if suite == "broken" {
+			return nil, errors.New("ralph init failed")
+		}
+		return &EvalResult{Suite: suite, SharedTestsPassed: 1, SharedTestsTotal: 2}, nil
+	}
+
+	var buf bytes.Buffer
+	suites := []string{"flask", "broken", "logagg", "workflow"}
+	runs := RunSuites(suites, 2, 0, &buf, run)
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("%d suites ran at once, want at most 2", got)
+	}
+	if len(seenPorts) != len(suites) {
+		t.Errorf("suites shared ports: %v", seenPorts)
+	}
+	for i, r := range runs {
+		if r.Suite != suites[i] {
+			t.Errorf("runs[%d].Suite = %q, want %q", i, r.Suite, suites[i])
+		}
+		if seenPorts[r.Port] != r.Suite {
+			t.Errorf("runs[%d].Port = %d, but the runner got that port for %q", i, r.Port, seenPorts[r.Port])
+		}
+	}
+	if runs[1].Err == nil || runs[0].Err != nil || runs[0].Result == nil {
+		t.Errorf("runs = %+v, want only broken to fail", runs)
+	}
+	if !SuitesFailed(runs) {
+		t.Error("SuitesFailed() = false with a failing suite")
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		prefix, rest, ok := strings.Cut(line, "] ")
+		if !ok || !strings.HasPrefix(prefix, "[") {
+			t.Errorf("line %q has no suite prefix", line)
+			continue
+		}
+		if rest != "starting app" && rest != "waiting for health" && rest != "trailing" {
+			t.Errorf("line %q was interleaved with another suite's output", line)
+		}
+	}
+	if n := strings.Count(buf.String(), "[logagg] waiting for health\n"); n != 1 {
+		t.Errorf("logagg output appears %d times, want once:\n%s", n, buf.String())
+	}
+}
+
+func TestWriteSuitesSummary(t *testing.T) {
+	runs := []SuiteRun{
+		{Suite: "flask", Port: 8000, Result: &EvalResult{SharedTestsPassed: 40, SharedTestsTotal: 48, TotalTokens: 100000, CostUSD: 1.25, DurationSeconds: 900}},
+		{Suite: "tasktracker", Port: 8001, Err: errors.New("approach execution failed: ralph init failed")},
+		{Suite: "logagg", Port: 8002, Result: &EvalResult{SharedTestsPassed: 10, SharedTestsTotal: 12, TotalTokens: 50000, CostUSD: 0.5, DurationSeconds: 1200}},
+	}
+
+	totals := SumSuites(runs)
+	want := SuitesTotals{Succeeded: 2, Failed: 1, TestsPassed: 50, TestsTotal: 60, TotalTokens: 150000, CostUSD: 1.75, DurationSeconds: 1200}
+	if totals != want {
+		t.Errorf("SumSuites() = %+v, want %+v", totals, want)
+	}
+
+	var buf bytes.Buffer
+	WriteSuitesSummary(&buf, "ralph", "sonnet", runs)
+	out := buf.String()
+
+	rowFor := func(name string) string {
+		for _, l := range strings.Split(out, "\n") {
+			if strings.HasPrefix(l, name+" ") {
+				return l
+			}
+		}
+		t.Fatalf("no row for %s in:\n%s", name, out)
+		return ""
+	}
+	for _, want := range []string{"8000", "ok", "40/48", "100000", "$1.25", "900s"} {
+		if row := rowFor("flask"); !strings.Contains(row, want) {
+			t.Errorf("flask row %q missing %q", row, want)
+		}
+	}
+	if row := rowFor("tasktracker"); !strings.Contains(row, "error") {
+		t.Errorf("tasktracker row %q should show error", row)
+	}
+	for _, want := range []string{"2/3", "50/60", "150000", "$1.75", "1200s"} {
+		if row := rowFor("Total"); !strings.Contains(row, want) {
+			t.Errorf("Total row %q missing %q", row, want)
+		}
+	}
+	if !strings.Contains(out, "tasktracker failed: approach execution failed: ralph init failed") {
+		t.Errorf("summary missing the tasktracker error:\n%s", out)
+	}
+}
@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 )
 
+// readOnlyCheckWorkers bounds how many independent checks run at once.
+const readOnlyCheckWorkers = 4
+
 // APITestRunner runs HTTP API tests
 type APITestRunner struct {
 	BaseURL string
@@ -17,6 +21,17 @@ type APITestRunner struct {
 	Passed  int
 	Failed  int
 	client  *http.Client
+
+	// mu guards Results, Passed, Failed, and the printed output, which
+	// recordResult may be called for from several goroutines.
+	mu sync.Mutex
+}
+
+// apiCheck is a request that neither depends on nor changes the state other
+// tests rely on, so it can run alongside other checks.
+type apiCheck struct {
+	name string
+	run  func() (passed bool, msg string)
 }
 
 // NewAPITestRunner creates a new API test runner
@@ -32,6 +47,12 @@ func NewAPITestRunner(baseURL string) *APITestRunner {
 
 // doRequest makes an HTTP request and returns the response
 func (r *APITestRunner) doRequest(method, path string, body interface{}) (*http.Response, map[string]interface{}, error) {
+	return r.doRequestWithToken(method, path, body, r.Token)
+}
+
+// doRequestWithToken is doRequest with an explicit bearer token ("" for
+// none), for checks that must not touch the shared r.Token.
+func (r *APITestRunner) doRequestWithToken(method, path string, body interface{}, token string) (*http.Response, map[string]interface{}, error) {
 	var reqBody io.Reader
 	if body != nil {
 		jsonBytes, err := json.Marshal(body)
@@ -47,8 +68,8 @@ func (r *APITestRunner) doRequest(method, path string, body interface{}) (*http.
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if r.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+r.Token)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
 	resp, err := r.client.Do(req)
@@ -74,6 +95,9 @@ func getStatus(resp *http.Response) int {
 
 // recordResult records a test result
 func (r *APITestRunner) recordResult(name string, passed bool, msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if passed {
 		fmt.Printf("  %s... ✅ PASS\n", name)
 		r.Passed++
@@ -87,6 +111,46 @@ func (r *APITestRunner) recordResult(name string, passed bool, msg string) {
 	r.Results = append(r.Results, CLITestResult{Name: name, Passed: passed, Message: msg})
 }
 
+// startChecks runs checks concurrently on at most workers goroutines. The
+// returned record function waits for the named check and records its result,
+// so results land in the order record is called regardless of which request
+// finished first.
+func (r *APITestRunner) startChecks(workers int, checks []apiCheck) (record func(name string)) {
+	type outcome struct {
+		passed bool
+		msg    string
+		done   chan struct{}
+	}
+
+	outcomes := make(map[string]*outcome, len(checks))
+	jobs := make(chan apiCheck)
+	for _, c := range checks {
+		outcomes[c.name] = &outcome{done: make(chan struct{})}
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for c := range jobs {
+				o := outcomes[c.name]
+				o.passed, o.msg = c.run()
+				close(o.done)
+			}
+		}()
+	}
+	go func() {
+		for _, c := range checks {
+			jobs <- c
+		}
+		close(jobs)
+	}()
+
+	return func(name string) {
+		o := outcomes[name]
+		<-o.done
+		r.recordResult(name, o.passed, o.msg)
+	}
+}
+
 // RunTasktrackerTests runs the tasktracker API test suite
 func RunTasktrackerTests(baseURL string) (*TestResult, error) {
 	fmt.Println("")
@@ -148,6 +212,35 @@ func RunTasktrackerTests(baseURL string) (*TestResult, error) {
 		r.Token = token
 	}
 
+	// Read-only checks run in the background while the stateful sequences
+	// below proceed; each is recorded at its usual place in the output.
+	token := r.Token
+	statusCheck := func(name, path, token string, want int) apiCheck {
+		return apiCheck{name: name, run: func() (bool, string) {
+			resp, _, _ := r.doRequestWithToken("GET", path, nil, token)
+			return resp != nil && getStatus(resp) == want, fmt.Sprintf("status %d", getStatus(resp))
+		}}
+	}
+	recordCheck := r.startChecks(readOnlyCheckWorkers, []apiCheck{
+		statusCheck("protected route requires auth", "/api/tasks", "", 401),
+		statusCheck("invalid token rejected", "/api/tasks", "invalid_token_12345", 401),
+		{name: "list tasks", run: func() (bool, string) {
+			resp, data, _ := r.doRequestWithToken("GET", "/api/tasks", nil, token)
+			var tasks []interface{}
+			if data != nil {
+				if t, ok := data["tasks"].([]interface{}); ok {
+					tasks = t
+				} else if t, ok := data["data"].([]interface{}); ok {
+					tasks = t
+				} else if t, ok := data["items"].([]interface{}); ok {
+					tasks = t
+				}
+			}
+			return resp != nil && getStatus(resp) == 200, fmt.Sprintf("status %d, count=%d", getStatus(resp), len(tasks))
+		}},
+		statusCheck("invalid task ID returns 404", "/api/tasks/999999", token, 404),
+	})
+
 	// Test: Invalid credentials rejected (try both formats)
 	badLogin := map[string]string{
 		"username": "nonexistent_user",
@@ -169,19 +262,10 @@ func RunTasktrackerTests(baseURL string) (*TestResult, error) {
 		fmt.Sprintf("status %d", statusCode))
 
 	// Test: Protected route requires auth
-	oldToken := r.Token
-	r.Token = ""
-	resp, _, _ = r.doRequest("GET", "/api/tasks", nil)
-	r.recordResult("protected route requires auth", resp != nil && getStatus(resp) == 401,
-		fmt.Sprintf("status %d", getStatus(resp)))
-	r.Token = oldToken
+	recordCheck("protected route requires auth")
 
 	// Test: Invalid token rejected
-	r.Token = "invalid_token_12345"
-	resp, _, _ = r.doRequest("GET", "/api/tasks", nil)
-	r.recordResult("invalid token rejected", resp != nil && getStatus(resp) == 401,
-		fmt.Sprintf("status %d", getStatus(resp)))
-	r.Token = oldToken
+	recordCheck("invalid token rejected")
 
 	// Test: Duplicate username rejected
 	resp, _, _ = r.doRequest("POST", "/api/auth/register", regBody)
@@ -207,19 +291,7 @@ func RunTasktrackerTests(baseURL string) (*TestResult, error) {
 		fmt.Sprintf("status %d, id=%v", getStatus(resp), taskID))
 
 	// Test: List tasks
-	resp, data, _ = r.doRequest("GET", "/api/tasks", nil)
-	var tasks []interface{}
-	if data != nil {
-		if t, ok := data["tasks"].([]interface{}); ok {
-			tasks = t
-		} else if t, ok := data["data"].([]interface{}); ok {
-			tasks = t
-		} else if t, ok := data["items"].([]interface{}); ok {
-			tasks = t
-		}
-	}
-	r.recordResult("list tasks", resp != nil && getStatus(resp) == 200,
-		fmt.Sprintf("status %d, count=%d", getStatus(resp), len(tasks)))
+	recordCheck("list tasks")
 
 	// Test: Get task by ID
 	if taskID != nil {
@@ -275,9 +347,7 @@ func RunTasktrackerTests(baseURL string) (*TestResult, error) {
 	}
 
 	// Test: Invalid task ID
-	resp, _, _ = r.doRequest("GET", "/api/tasks/999999", nil)
-	r.recordResult("invalid task ID returns 404", resp != nil && getStatus(resp) == 404,
-		fmt.Sprintf("status %d", getStatus(resp)))
+	recordCheck("invalid task ID returns 404")
 
 	// ========== FILTER TESTS ==========
 	fmt.Println("")
@@ -503,5 +573,6 @@ func RunTasktrackerTests(baseURL string) (*TestResult, error) {
 		Passed: r.Passed,
 		Failed: r.Failed,
 		Total:  r.Passed + r.Failed,
+		Cases:  r.Results,
 	}, nil
 }
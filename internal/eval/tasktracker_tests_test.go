@@ -0,0 +1,111 @@
+package eval
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRecordResultConcurrent(t *testing.T) {
+	r := NewAPITestRunner("http://localhost")
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.recordResult(fmt.Sprintf("check %d", i), i%2 == 0, "")
+		}(i)
+	}
+	wg.Wait()
+
+	if r.Passed != n/2 || r.Failed != n/2 {
+		t.Errorf("Passed=%d Failed=%d, want %d each", r.Passed, r.Failed, n/2)
+	}
+	if len(r.Results) != n {
+		t.Errorf("got %d results, want %d", len(r.Results), n)
+	}
+}
+
+func TestStartChecksRecordsInCallOrder(t *testing.T) {
+	r := NewAPITestRunner("http://localhost")
+
+	var running, peak int32
+	check := func(name string, delay time.Duration, passed bool) apiCheck {
+		return apiCheck{name: name, run: func() (bool, string) {
+			cur := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if cur <= p || atomic.CompareAndSwapInt32(&peak, p, cur) {
+					break
+				}
+			}
+			time.Sleep(delay)
+			atomic.AddInt32(&running, -1)
+			return passed, name + " msg"
+		}}
+	}
+
+	// The slowest check is recorded first; faster ones must wait their turn.
+	record := r.startChecks(2, []apiCheck{
+		check("slow", 50*time.Millisecond, true),
+		check("fast", time.Millisecond, false),
+		check("medium", 10*time.Millisecond, true),
+	})
+	record("slow")
+	record("fast")
+	record("medium")
+
+	want := []CLITestResult{
+		{Name: "slow", Passed: true, Message: "slow msg"},
+		{Name: "fast", Passed: false, Message: "fast msg"},
+		{Name: "medium", Passed: true, Message: "medium msg"},
+	}
+	if len(r.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(r.Results), len(want))
+	}
+	for i := range want {
+		if r.Results[i] != want[i] {
+			t.Errorf("Results[%d] = %+v, want %+v", i, r.Results[i], want[i])
+		}
+	}
+	if r.Passed != 2 || r.Failed != 1 {
+		t.Errorf("Passed=%d Failed=%d, want 2 and 1", r.Passed, r.Failed)
+	}
+	if p := atomic.LoadInt32(&peak); p > 2 {
+		t.Errorf("peak concurrency %d exceeds 2 workers", p)
+	}
+}
+
+func TestDoRequestWithTokenLeavesSharedToken(t *testing.T) {
+	var got []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		got = append(got, req.Header.Get("Authorization"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	r := NewAPITestRunner(srv.URL)
+	r.Token = "shared"
+	r.doRequestWithToken("GET", "/api/tasks", nil, "")
+	r.doRequestWithToken("GET", "/api/tasks", nil, "other")
+	r.doRequest("GET", "/api/tasks", nil)
+
+	want := []string{"", "Bearer other", "Bearer shared"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d Authorization = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if r.Token != "shared" {
+		t.Errorf("r.Token = %q, want it unchanged", r.Token)
+	}
+}
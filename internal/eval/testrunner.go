@@ -3,6 +3,7 @@ package eval
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,13 +22,20 @@ type TestResult struct {
 	Failed  int
 	Skipped int
 	Total   int
+	Cases   []CLITestResult // per-test outcomes, when the runner reports them
 }
 
 // RunSharedTests executes the shared test suite for a project.
 // It sets up the environment, starts the app (for web apps), runs tests, and returns the results.
+// A port of 0 starts the app on a free port chosen by FreePort.
 func RunSharedTests(projectDir string, suite *SuiteConfig, port int) (*TestResult, error) {
-	if port == 0 {
-		port = 8000 // Default port
+	forced := port != 0
+	if !forced {
+		var err error
+		if port, err = FreePort(); err != nil {
+			fmt.Printf("WARNING: %v, using port %d\n", err, DefaultPort)
+			port, forced = DefaultPort, true
+		}
 	}
 
 	fmt.Println("")
@@ -45,9 +53,9 @@ func RunSharedTests(projectDir string, suite *SuiteConfig, port int) (*TestResul
 		return runCLITests(projectDir, suite)
 	}
 
-	// Route to Go-based API tests for specific suites
-	if suite.Name == "tasktracker" {
-		return runWebAPITests(projectDir, suite, port)
+	// Route to Go-based API tests for suites that register a runner
+	if _, ok := lookupRunner(suite.Name); ok {
+		return runWebAPITests(projectDir, suite, port, forced)
 	}
 
 	// Web app flow: find app, setup, start server, run pytest
@@ -72,9 +80,11 @@ func RunSharedTests(projectDir string, suite *SuiteConfig, port int) (*TestResul
 		fmt.Printf("WARNING: failed to start Docker services: %v\n", err)
 	}
 
-	// Kill any existing process on the port
-	if err := killProcessOnPort(port); err != nil {
-		fmt.Printf("WARNING: failed to kill process on port %d: %v\n", port, err)
+	// A forced port may still be held by an app from an earlier run
+	if forced {
+		if err := killProcessOnPort(port); err != nil {
+			fmt.Printf("WARNING: failed to kill process on port %d: %v\n", port, err)
+		}
 	}
 
 	// Start the app in background
@@ -89,7 +99,7 @@ func RunSharedTests(projectDir string, suite *SuiteConfig, port int) (*TestResul
 	}()
 
 	// Wait for app to be ready
-	if err := waitForApp(port, 30*time.Second, appCmd); err != nil {
+	if err := waitForApp(port, suite.GetHealthPaths(), suite.GetHealthTimeout(), appCmd); err != nil {
 		return nil, err
 	}
 
@@ -113,7 +123,7 @@ func RunSharedTests(projectDir string, suite *SuiteConfig, port int) (*TestResul
 }
 
 // runWebAPITests runs Go-based tests for web API suites
-func runWebAPITests(projectDir string, suite *SuiteConfig, port int) (*TestResult, error) {
+func runWebAPITests(projectDir string, suite *SuiteConfig, port int, forced bool) (*TestResult, error) {
 	// Find the actual project directory (handle nested dirs)
 	appDir, err := findAppDirectory(projectDir)
 	if err != nil {
@@ -130,9 +140,11 @@ func runWebAPITests(projectDir string, suite *SuiteConfig, port int) (*TestResul
 		fmt.Printf("WARNING: failed to set up .env: %v\n", err)
 	}
 
-	// Kill any existing process on the port
-	if err := killProcessOnPort(port); err != nil {
-		fmt.Printf("WARNING: failed to kill process on port %d: %v\n", port, err)
+	// A forced port may still be held by an app from an earlier run
+	if forced {
+		if err := killProcessOnPort(port); err != nil {
+			fmt.Printf("WARNING: failed to kill process on port %d: %v\n", port, err)
+		}
 	}
 
 	// Start the app in background
@@ -147,19 +159,24 @@ func runWebAPITests(projectDir string, suite *SuiteConfig, port int) (*TestResul
 	}()
 
 	// Wait for app to be ready (but continue even if it fails)
-	if err := waitForApp(port, 30*time.Second, appCmd); err != nil {
+	if err := waitForApp(port, suite.GetHealthPaths(), suite.GetHealthTimeout(), appCmd); err != nil {
 		fmt.Printf("WARNING: app not ready: %v\n", err)
 	}
 
 	// Run Go-based API tests (they will fail if app isn't running)
-	baseURL := fmt.Sprintf("http://localhost:%d", port)
-
-	switch suite.Name {
-	case "tasktracker":
-		return RunTasktrackerTests(baseURL)
-	default:
+	run, ok := lookupRunner(suite.Name)
+	if !ok {
 		return nil, fmt.Errorf("no Go test runner for web API suite: %s", suite.Name)
 	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return run(RunnerInput{
+		AppDir:      appDir,
+		FixturesDir: filepath.Join(cwd, "evals", "suites", suite.Name, "fixtures"),
+		BaseURL:     appBaseURL(port),
+	})
 }
 
 // runCLITests runs tests for CLI tool suites
@@ -177,15 +194,12 @@ func runCLITests(projectDir string, suite *SuiteConfig) (*TestResult, error) {
 	}
 	fixturesDir := filepath.Join(cwd, "evals", "suites", suite.Name, "fixtures")
 
-	// Route to appropriate test runner based on suite name
-	switch suite.Name {
-	case "logagg":
-		return RunLogaggTests(appDir, fixturesDir)
-	case "workflow":
-		return RunWorkflowTests(appDir, fixturesDir)
-	default:
+	// Route to the test runner registered for the suite
+	run, ok := lookupRunner(suite.Name)
+	if !ok {
 		return nil, fmt.Errorf("no Go test runner for CLI suite: %s", suite.Name)
 	}
+	return run(RunnerInput{AppDir: appDir, FixturesDir: fixturesDir})
 }
 
 // findCLIAppDirectory finds the app directory for CLI tools
@@ -228,11 +242,8 @@ func findCLIAppDirectory(projectDir string, language string) (string, error) {
 
 // findAppDirectory locates the actual app directory, handling nested structures
 func findAppDirectory(projectDir string) (string, error) {
-	// Check if app.py, main.py, run.py exists in projectDir
-	if fileExists(filepath.Join(projectDir, "app.py")) ||
-		fileExists(filepath.Join(projectDir, "main.py")) ||
-		fileExists(filepath.Join(projectDir, "run.py")) ||
-		dirExists(filepath.Join(projectDir, "app")) {
+	// Check if a known app entry point exists in projectDir
+	if hasAppMarker(projectDir) {
 		return projectDir, nil
 	}
 
@@ -245,27 +256,84 @@ func findAppDirectory(projectDir string) (string, error) {
 	for _, entry := range entries {
 		if entry.IsDir() {
 			nestedPath := filepath.Join(projectDir, entry.Name())
-			if fileExists(filepath.Join(nestedPath, "app.py")) ||
-				fileExists(filepath.Join(nestedPath, "main.py")) ||
-				fileExists(filepath.Join(nestedPath, "run.py")) ||
-				dirExists(filepath.Join(nestedPath, "app")) {
+			if hasAppMarker(nestedPath) {
 				return nestedPath, nil
 			}
 		}
 	}
 
-	return "", fmt.Errorf("no app.py, main.py, run.py, or app/ found in %s", projectDir)
+	return "", fmt.Errorf("no app.py, main.py, run.py, app/, package.json, or go.mod found in %s", projectDir)
 }
 
-// setupVenv creates and sets up a Python virtual environment if requirements.txt exists
-func setupVenv(appDir string) error {
-	requirementsPath := filepath.Join(appDir, "requirements.txt")
-	venvPath := filepath.Join(appDir, "venv")
+// hasAppMarker reports whether dir looks like the root of a Python, Node, or Go app.
+func hasAppMarker(dir string) bool {
+	return fileExists(filepath.Join(dir, "app.py")) ||
+		fileExists(filepath.Join(dir, "main.py")) ||
+		fileExists(filepath.Join(dir, "run.py")) ||
+		dirExists(filepath.Join(dir, "app")) ||
+		fileExists(filepath.Join(dir, "package.json")) ||
+		fileExists(filepath.Join(dir, "go.mod")) ||
+		fileExists(filepath.Join(dir, "main.go"))
+}
+
+// Python dependency toolchains detected by detectPythonToolchain.
+const (
+	toolchainNone   = ""
+	toolchainVenv   = "venv"
+	toolchainPoetry = "poetry"
+	toolchainPipenv = "pipenv"
+)
 
-	// Check if requirements.txt exists
-	if !fileExists(requirementsPath) {
-		return nil // No requirements.txt, nothing to do
+// detectPythonToolchain picks how to install an app's Python dependencies
+// from its marker files: Pipfile (pipenv), a Poetry pyproject.toml, or
+// requirements.txt (stdlib venv + pip).
+func detectPythonToolchain(appDir string) string {
+	if fileExists(filepath.Join(appDir, "Pipfile")) {
+		return toolchainPipenv
 	}
+	if fileExists(filepath.Join(appDir, "poetry.lock")) {
+		return toolchainPoetry
+	}
+	if data, err := os.ReadFile(filepath.Join(appDir, "pyproject.toml")); err == nil && strings.Contains(string(data), "[tool.poetry") {
+		return toolchainPoetry
+	}
+	if fileExists(filepath.Join(appDir, "requirements.txt")) {
+		return toolchainVenv
+	}
+	return toolchainNone
+}
+
+// venvBinary returns the path to a binary inside the app's virtualenv:
+// venv/ (created by setupVenv) or .venv/ (created in-project by Poetry or
+// Pipenv). It returns "" if neither has it.
+func venvBinary(appDir, name string) string {
+	for _, dir := range []string{"venv", ".venv"} {
+		p := filepath.Join(appDir, dir, "bin", name)
+		if fileExists(p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// setupVenv installs the app's Python dependencies using the toolchain
+// its marker files call for
+func setupVenv(appDir string) error {
+	switch detectPythonToolchain(appDir) {
+	case toolchainPoetry:
+		return setupManagedVenv(appDir, "poetry", []string{"install"}, "POETRY_VIRTUALENVS_IN_PROJECT=true")
+	case toolchainPipenv:
+		return setupManagedVenv(appDir, "pipenv", []string{"install", "--dev"}, "PIPENV_VENV_IN_PROJECT=1")
+	case toolchainVenv:
+		return setupRequirementsVenv(appDir)
+	default:
+		return nil // No Python dependencies, nothing to do
+	}
+}
+
+// setupRequirementsVenv creates a venv and installs requirements.txt into it
+func setupRequirementsVenv(appDir string) error {
+	venvPath := filepath.Join(appDir, "venv")
 
 	// Check if venv already exists
 	if dirExists(venvPath) {
@@ -288,11 +356,7 @@ func setupVenv(appDir string) error {
 	pipPath := filepath.Join(venvPath, "bin", "pip")
 
 	// Install pytest and requests
-	cmd = exec.Command(pipPath, "install", "-q", "requests", "pytest")
-	cmd.Dir = appDir
-	if err := cmd.Run(); err != nil {
-		fmt.Printf("WARNING: failed to install test dependencies: %v\n", err)
-	}
+	installTestDependencies(appDir, pipPath)
 
 	// Install requirements.txt
 	cmd = exec.Command(pipPath, "install", "-q", "-r", "requirements.txt")
@@ -304,6 +368,41 @@ func setupVenv(appDir string) error {
 	return nil
 }
 
+// setupManagedVenv runs a Poetry/Pipenv install with the virtualenv kept in
+// the project (.venv/) so venvBinary can find its interpreter
+func setupManagedVenv(appDir, tool string, installArgs []string, inProjectEnv string) error {
+	if dirExists(filepath.Join(appDir, ".venv")) {
+		return nil // Venv already exists
+	}
+
+	fmt.Printf("Setting up venv with %s...\n", tool)
+
+	cmd := exec.Command(tool, installArgs...)
+	cmd.Dir = appDir
+	cmd.Env = append(os.Environ(), inProjectEnv)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s install failed: %w", tool, err)
+	}
+
+	if pipPath := venvBinary(appDir, "pip"); pipPath != "" {
+		installTestDependencies(appDir, pipPath)
+	} else {
+		fmt.Printf("WARNING: %s did not create .venv; test dependencies not installed\n", tool)
+	}
+	return nil
+}
+
+// installTestDependencies installs the packages the shared pytest suites need
+func installTestDependencies(appDir, pipPath string) {
+	cmd := exec.Command(pipPath, "install", "-q", "requests", "pytest")
+	cmd.Dir = appDir
+	if err := cmd.Run(); err != nil {
+		fmt.Printf("WARNING: failed to install test dependencies: %v\n", err)
+	}
+}
+
 // setupEnvFile copies .env.example to .env if it exists and .env doesn't
 func setupEnvFile(appDir string) error {
 	envExamplePath := filepath.Join(appDir, ".env.example")
@@ -343,54 +442,50 @@ func startDockerServices(appDir string) error {
 	return nil
 }
 
-// killProcessOnPort kills any process running on the specified port
-func killProcessOnPort(port int) error {
-	// Use lsof to find LISTENing process IDs on the port.
-	// -nP avoids DNS/service-name lookups (faster and less error-prone).
-	// -t prints only PIDs.
-	cmd := exec.Command("lsof", "-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-t")
-	output, err := cmd.Output()
+// startApp starts the application in the background
+func startApp(appDir string, port int) (*exec.Cmd, error) {
+	fmt.Printf("Starting app on port %d...\n", port)
+
+	cmd, err := appLaunchCommand(appDir, port)
 	if err != nil {
-		// No process found, which is fine
-		return nil
+		return nil, err
 	}
 
-	pids := strings.Fields(string(output))
-	if len(pids) == 0 {
-		return nil
+	// Capture output to a pipe so we can monitor for errors
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
-	var lastErr error
-	for _, pid := range pids {
-		pid = strings.TrimSpace(pid)
-		if pid == "" {
-			continue
-		}
-		killCmd := exec.Command("kill", "-9", pid)
-		if err := killCmd.Run(); err != nil {
-			// Best-effort: process may have already exited, or PID may be stale.
-			lastErr = err
-		}
+	// Start the command
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start app: %w", err)
 	}
 
-	return lastErr
-}
+	// Stream output in background
+	go streamOutput(stdout, "APP")
+	go streamOutput(stderr, "APP-ERR")
 
-// startApp starts the application in the background
-func startApp(appDir string, port int) (*exec.Cmd, error) {
-	fmt.Printf("Starting app on port %d...\n", port)
+	return cmd, nil
+}
 
+// appLaunchCommand picks the command that starts the app in appDir.
+// Python apps are checked first, then Node, then Go; the command is not started.
+func appLaunchCommand(appDir string, port int) (*exec.Cmd, error) {
 	var cmd *exec.Cmd
-	venvPython := filepath.Join(appDir, "venv", "bin", "python")
 
 	// Determine which Python to use
 	pythonCmd := "python3"
-	if fileExists(venvPython) {
+	if venvPython := venvBinary(appDir, "python"); venvPython != "" {
 		pythonCmd = venvPython
 	}
 
 	// Determine which file to run
-	uvicornCmd := filepath.Join(appDir, "venv", "bin", "uvicorn")
+	uvicornCmd := venvBinary(appDir, "uvicorn")
 
 	if fileExists(filepath.Join(appDir, "run.py")) {
 		cmd = exec.Command(pythonCmd, "run.py")
@@ -398,7 +493,7 @@ func startApp(appDir string, port int) (*exec.Cmd, error) {
 		cmd = exec.Command(pythonCmd, "app.py")
 	} else if fileExists(filepath.Join(appDir, "main.py")) {
 		// FastAPI app - use uvicorn
-		if fileExists(uvicornCmd) {
+		if uvicornCmd != "" {
 			cmd = exec.Command(uvicornCmd, "main:app", "--host", "0.0.0.0", "--port", strconv.Itoa(port))
 		} else {
 			// Try running main.py directly (may have uvicorn.run inside)
@@ -406,44 +501,58 @@ func startApp(appDir string, port int) (*exec.Cmd, error) {
 		}
 	} else if fileExists(filepath.Join(appDir, "app", "main.py")) {
 		// FastAPI app with app/ directory structure
-		if fileExists(uvicornCmd) {
+		if uvicornCmd != "" {
 			cmd = exec.Command(uvicornCmd, "app.main:app", "--host", "0.0.0.0", "--port", strconv.Itoa(port))
 		} else {
 			cmd = exec.Command(pythonCmd, "-m", "uvicorn", "app.main:app", "--host", "0.0.0.0", "--port", strconv.Itoa(port))
 		}
+	} else if fileExists(filepath.Join(appDir, "package.json")) {
+		// Node app - prefer the start script, else run the entry file directly
+		cmd = nodeLaunchCommand(appDir)
+		if cmd == nil {
+			return nil, fmt.Errorf("package.json has no start script and no server.js found")
+		}
+		cmd.Env = append(os.Environ(), fmt.Sprintf("PORT=%d", port))
+	} else if fileExists(filepath.Join(appDir, "go.mod")) || fileExists(filepath.Join(appDir, "main.go")) {
+		// Go app
+		cmd = exec.Command("go", "run", ".")
+		cmd.Env = append(os.Environ(), fmt.Sprintf("PORT=%d", port))
 	} else {
 		// Try Flask run
-		flaskCmd := filepath.Join(appDir, "venv", "bin", "flask")
-		if fileExists(flaskCmd) {
+		if flaskCmd := venvBinary(appDir, "flask"); flaskCmd != "" {
 			cmd = exec.Command(flaskCmd, "run", "--port", strconv.Itoa(port))
 			cmd.Env = append(os.Environ(), "FLASK_APP=app")
 		} else {
-			return nil, fmt.Errorf("no run.py, app.py, main.py, app/main.py, or flask command found")
+			return nil, fmt.Errorf("no run.py, app.py, main.py, app/main.py, package.json, go.mod, or flask command found")
 		}
 	}
 
 	cmd.Dir = appDir
+	return cmd, nil
+}
 
-	// Capture output to a pipe so we can monitor for errors
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+// nodeLaunchCommand returns `npm start` when package.json defines a start
+// script, otherwise `node` on its main entry or server.js. It returns nil if
+// neither applies.
+func nodeLaunchCommand(appDir string) *exec.Cmd {
+	var pkg struct {
+		Main    string            `json:"main"`
+		Scripts map[string]string `json:"scripts"`
 	}
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	if data, err := os.ReadFile(filepath.Join(appDir, "package.json")); err == nil {
+		_ = json.Unmarshal(data, &pkg)
 	}
 
-	// Start the command
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start app: %w", err)
+	if strings.TrimSpace(pkg.Scripts["start"]) != "" {
+		return exec.Command("npm", "start")
 	}
-
-	// Stream output in background
-	go streamOutput(stdout, "APP")
-	go streamOutput(stderr, "APP-ERR")
-
-	return cmd, nil
+	if pkg.Main != "" && fileExists(filepath.Join(appDir, pkg.Main)) {
+		return exec.Command("node", pkg.Main)
+	}
+	if fileExists(filepath.Join(appDir, "server.js")) {
+		return exec.Command("node", "server.js")
+	}
+	return nil
 }
 
 // streamOutput reads from a reader and prints lines with a prefix
@@ -454,19 +563,32 @@ func streamOutput(r io.Reader, prefix string) {
 	}
 }
 
+// healthURLs builds the localhost URLs to poll for each health path.
+// Paths without a leading slash get one; "" and "/" mean the root.
+func healthURLs(port int, paths []string) []string {
+	urls := make([]string, 0, len(paths))
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p != "" && !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		if p == "/" {
+			p = ""
+		}
+		urls = append(urls, appBaseURL(port)+p)
+	}
+	return urls
+}
+
 // waitForApp waits for the app to be ready by checking health endpoints
 // If appCmd is provided, it also checks if the process has exited
-func waitForApp(port int, timeout time.Duration, appCmd *exec.Cmd) error {
+func waitForApp(port int, healthPaths []string, timeout time.Duration, appCmd *exec.Cmd) error {
 	fmt.Println("Waiting for app to start...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	urls := []string{
-		fmt.Sprintf("http://localhost:%d", port),
-		fmt.Sprintf("http://localhost:%d/auth/login", port),
-		fmt.Sprintf("http://localhost:%d/health", port),
-	}
+	urls := healthURLs(port, healthPaths)
 
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
@@ -482,7 +604,7 @@ func waitForApp(port int, timeout time.Duration, appCmd *exec.Cmd) error {
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("timeout waiting for app to start")
+			return fmt.Errorf("timeout waiting for app to start after %s (tried %s)", timeout, strings.Join(urls, ", "))
 		case err := <-processDone:
 			return fmt.Errorf("app process exited: %v", err)
 		case <-ticker.C:
@@ -507,14 +629,13 @@ func runPytest(appDir, suiteDir string, port int) (*TestResult, error) {
 
 	// Build the pytest command
 	pytestCmd := "pytest"
-	venvPytest := filepath.Join(appDir, "venv", "bin", "pytest")
-	if fileExists(venvPytest) {
+	if venvPytest := venvBinary(appDir, "pytest"); venvPytest != "" {
 		pytestCmd = venvPytest
 	}
 
 	cmd := exec.Command(pytestCmd, suiteDir, "--tb=short", "-v")
 	cmd.Dir = appDir
-	cmd.Env = append(os.Environ(), fmt.Sprintf("EVAL_BASE_URL=http://localhost:%d", port))
+	cmd.Env = append(os.Environ(), "EVAL_BASE_URL="+appBaseURL(port))
 
 	// Capture output
 	output, err := cmd.CombinedOutput()
@@ -544,6 +665,7 @@ func runPytest(appDir, suiteDir string, port int) (*TestResult, error) {
 	}
 
 	result.Total = result.Passed + result.Failed + result.Skipped
+	result.Cases = parsePytestCases(outputStr)
 
 	// Check for pytest error (command not found or execution failed)
 	if err != nil && result.Total == 0 {
@@ -553,6 +675,28 @@ func runPytest(appDir, suiteDir string, port int) (*TestResult, error) {
 	return result, nil
 }
 
+// pytestCaseRe matches verbose pytest lines like
+// "tests/test_app.py::test_index PASSED [ 50%]".
+var pytestCaseRe = regexp.MustCompile(`(?m)^(\S+::\S+)\s+(PASSED|FAILED|ERROR|SKIPPED|XFAIL|XPASS)\b`)
+
+// parsePytestCases extracts per-test outcomes from `pytest -v` output.
+func parsePytestCases(output string) []CLITestResult {
+	var cases []CLITestResult
+	for _, m := range pytestCaseRe.FindAllStringSubmatch(output, -1) {
+		c := CLITestResult{Name: m[1]}
+		switch m[2] {
+		case "PASSED", "XPASS":
+			c.Passed = true
+		case "SKIPPED", "XFAIL":
+			c.Skipped = true
+		default:
+			c.Message = strings.ToLower(m[2])
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
 // fileExists checks if a file exists
 func fileExists(path string) bool {
 	info, err := os.Stat(path)
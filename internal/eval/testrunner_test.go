@@ -1,9 +1,15 @@
 package eval
 
 import (
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestFindAppDirectory(t *testing.T) {
@@ -50,6 +56,26 @@ func TestFindAppDirectory(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "nested package.json",
+			setupFunc: func(t *testing.T) string {
+				dir := t.TempDir()
+				nested := filepath.Join(dir, "webapp")
+				os.MkdirAll(nested, 0755)
+				os.WriteFile(filepath.Join(nested, "package.json"), []byte("{}"), 0644)
+				return dir
+			},
+			expectError: false,
+		},
+		{
+			name: "go.mod in root",
+			setupFunc: func(t *testing.T) string {
+				dir := t.TempDir()
+				os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module app\n"), 0644)
+				return dir
+			},
+			expectError: false,
+		},
 		{
 			name: "no app found",
 			setupFunc: func(t *testing.T) string {
@@ -230,6 +256,31 @@ func TestParsePytestOutput(t *testing.T) {
 	}
 }
 
+func TestParsePytestCases(t *testing.T) {
+	output := `============================= test session starts ==============================
+tests/test_app.py::test_index PASSED                                     [ 33%]
+tests/test_app.py::test_day FAILED                                       [ 66%]
+tests/test_app.py::test_slow SKIPPED (needs network)                     [100%]
+=========================== short test summary info ============================
+FAILED tests/test_app.py::test_day - AssertionError
+==================== 1 failed, 1 passed, 1 skipped in 0.12s ====================`
+
+	got := parsePytestCases(output)
+	want := []CLITestResult{
+		{Name: "tests/test_app.py::test_index", Passed: true},
+		{Name: "tests/test_app.py::test_day", Message: "failed"},
+		{Name: "tests/test_app.py::test_slow", Skipped: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parsePytestCases() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("case %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
 func TestFileExists(t *testing.T) {
 	dir := t.TempDir()
 	filePath := filepath.Join(dir, "test.txt")
@@ -285,3 +336,224 @@ func TestDirExists(t *testing.T) {
 		})
 	}
 }
+
+func TestAppLaunchCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		wantArgs    []string
+		wantPortEnv bool
+		expectError bool
+	}{
+		{
+			name:     "flask app.py",
+			files:    map[string]string{"app.py": "# app"},
+			wantArgs: []string{"python3", "app.py"},
+		},
+		{
+			name:     "python wins over package.json",
+			files:    map[string]string{"run.py": "# run", "package.json": `{"scripts":{"start":"node server.js"}}`},
+			wantArgs: []string{"python3", "run.py"},
+		},
+		{
+			name:        "node start script",
+			files:       map[string]string{"package.json": `{"scripts":{"start":"node index.js"}}`},
+			wantArgs:    []string{"npm", "start"},
+			wantPortEnv: true,
+		},
+		{
+			name:        "node main entry",
+			files:       map[string]string{"package.json": `{"main":"index.js"}`, "index.js": "//"},
+			wantArgs:    []string{"node", "index.js"},
+			wantPortEnv: true,
+		},
+		{
+			name:        "node server.js",
+			files:       map[string]string{"package.json": `{}`, "server.js": "//"},
+			wantArgs:    []string{"node", "server.js"},
+			wantPortEnv: true,
+		},
+		{
+			name:        "node without entry point",
+			files:       map[string]string{"package.json": `{}`},
+			expectError: true,
+		},
+		{
+			name:        "go module",
+			files:       map[string]string{"go.mod": "module app\n"},
+			wantArgs:    []string{"go", "run", "."},
+			wantPortEnv: true,
+		},
+		{
+			name:        "go main.go only",
+			files:       map[string]string{"main.go": "package main\n"},
+			wantArgs:    []string{"go", "run", "."},
+			wantPortEnv: true,
+		},
+		{
+			name:        "nothing recognizable",
+			files:       map[string]string{"README.md": "# hi"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cmd, err := appLaunchCommand(dir, 8123)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("appLaunchCommand() error = %v, expectError %v", err, tt.expectError)
+			}
+			if tt.expectError {
+				return
+			}
+			if !reflect.DeepEqual(cmd.Args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", cmd.Args, tt.wantArgs)
+			}
+			if cmd.Dir != dir {
+				t.Errorf("dir = %q, want %q", cmd.Dir, dir)
+			}
+			hasPort := false
+			for _, e := range cmd.Env {
+				if e == "PORT=8123" {
+					hasPort = true
+				}
+			}
+			if hasPort != tt.wantPortEnv {
+				t.Errorf("PORT env set = %v, want %v", hasPort, tt.wantPortEnv)
+			}
+		})
+	}
+}
+
+func TestDetectPythonToolchain(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{name: "no markers", files: map[string]string{"app.py": "# app"}, want: toolchainNone},
+		{name: "requirements.txt", files: map[string]string{"requirements.txt": "flask\n"}, want: toolchainVenv},
+		{name: "pipfile", files: map[string]string{"Pipfile": "[packages]\n", "requirements.txt": "flask\n"}, want: toolchainPipenv},
+		{name: "poetry pyproject", files: map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"app\"\n"}, want: toolchainPoetry},
+		{name: "poetry lock", files: map[string]string{"poetry.lock": "", "requirements.txt": "flask\n"}, want: toolchainPoetry},
+		{name: "non-poetry pyproject", files: map[string]string{"pyproject.toml": "[project]\nname = \"app\"\n", "requirements.txt": "flask\n"}, want: toolchainVenv},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+			}
+			if got := detectPythonToolchain(dir); got != tt.want {
+				t.Errorf("detectPythonToolchain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVenvBinary(t *testing.T) {
+	dir := t.TempDir()
+	if got := venvBinary(dir, "python"); got != "" {
+		t.Errorf("expected no binary, got %q", got)
+	}
+
+	dotVenv := filepath.Join(dir, ".venv", "bin", "python")
+	os.MkdirAll(filepath.Dir(dotVenv), 0755)
+	os.WriteFile(dotVenv, []byte(""), 0755)
+	if got := venvBinary(dir, "python"); got != dotVenv {
+		t.Errorf("venvBinary() = %q, want %q", got, dotVenv)
+	}
+
+	venv := filepath.Join(dir, "venv", "bin", "python")
+	os.MkdirAll(filepath.Dir(venv), 0755)
+	os.WriteFile(venv, []byte(""), 0755)
+	if got := venvBinary(dir, "python"); got != venv {
+		t.Errorf("venvBinary() = %q, want venv/ to take precedence (%q)", got, venv)
+	}
+
+}
+
+func TestAppLaunchCommandUsesDotVenv(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "app.py"), []byte("# app"), 0644)
+	python := filepath.Join(dir, ".venv", "bin", "python")
+	os.MkdirAll(filepath.Dir(python), 0755)
+	os.WriteFile(python, []byte(""), 0755)
+
+	cmd, err := appLaunchCommand(dir, 8000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Path != python {
+		t.Errorf("expected %q for app launch, got %q", python, cmd.Path)
+	}
+}
+
+func TestHealthURLs(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{
+			name:  "defaults",
+			paths: defaultHealthPaths,
+			want:  []string{"http://localhost:8000", "http://localhost:8000/auth/login", "http://localhost:8000/health"},
+		},
+		{
+			name:  "custom paths",
+			paths: []string{"/api/healthz", "readyz", " /status "},
+			want:  []string{"http://localhost:8000/api/healthz", "http://localhost:8000/readyz", "http://localhost:8000/status"},
+		},
+		{
+			name:  "empty path is the root",
+			paths: []string{""},
+			want:  []string{"http://localhost:8000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := healthURLs(8000, tt.paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("healthURLs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForAppCustomHealthPath(t *testing.T) {
+	var hits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/healthz", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	})
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Skipf("cannot listen on localhost: %v", err)
+	}
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	if err := waitForApp(port, []string{"/api/healthz"}, 5*time.Second, nil); err != nil {
+		t.Fatalf("waitForApp() error: %v", err)
+	}
+	if hits.Load() == 0 {
+		t.Error("custom health path was never requested")
+	}
+
+	srv.Close()
+	err = waitForApp(port, []string{"/api/healthz"}, 1500*time.Millisecond, nil)
+	if err == nil || !strings.Contains(err.Error(), "/api/healthz") {
+		t.Errorf("waitForApp() after shutdown = %v, want timeout naming the path", err)
+	}
+}
@@ -42,7 +42,8 @@ func RunWorkflowTests(projectDir, fixturesDir string) (*TestResult, error) {
 	if binaryPath == "" {
 		fmt.Println("  ❌ FAIL: Binary not found after build")
 		r.Failed++
-		return &TestResult{Passed: r.Passed, Failed: r.Failed, Total: r.GetTotal()}, nil
+		r.Results = append(r.Results, CLITestResult{Name: "binary exists", Passed: false, Message: "binary not found after build"})
+		return &TestResult{Passed: r.Passed, Failed: r.Failed, Total: r.GetTotal(), Cases: r.Results}, nil
 	}
 	r.Binary = binaryPath
 
@@ -264,6 +265,7 @@ func RunWorkflowTests(projectDir, fixturesDir string) (*TestResult, error) {
 		Passed: r.Passed,
 		Failed: r.Failed,
 		Total:  r.GetTotal(),
+		Cases:  r.Results,
 	}, nil
 }
 
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// jsonEntry is one line written by JSONLogger.
+type jsonEntry struct {
+	Timestamp string         `json:"timestamp"`
+	Level     string         `json:"level"`
+	Message   string         `json:"message"`
+	Fields    map[string]any `json:"fields,omitempty"`
+}
+
+// JSONLogger writes one JSON object per line, for log ingestion.
+type JSONLogger struct {
+	writer io.Writer
+	level  Level
+	fields []Field
+	mu     *sync.Mutex
+	file   *rotatingFile
+}
+
+// NewJSONLogger creates a logger that appends JSON lines to a file.
+func NewJSONLogger(path string, level Level) (*JSONLogger, error) {
+	return NewRotatingJSONLogger(path, level, RotationConfig{})
+}
+
+// NewRotatingJSONLogger creates a JSON logger that rotates the file by size.
+func NewRotatingJSONLogger(path string, level Level, rot RotationConfig) (*JSONLogger, error) {
+	file, err := openRotatingFile(path, rot)
+	if err != nil {
+		return nil, err
+	}
+
+	l := newJSONLogger(file, level)
+	l.file = file
+	return l, nil
+}
+
+func newJSONLogger(w io.Writer, level Level) *JSONLogger {
+	return &JSONLogger{writer: w, level: level, mu: &sync.Mutex{}}
+}
+
+func (l *JSONLogger) log(level Level, msg string, fields ...Field) {
+	if level < l.level {
+		return
+	}
+
+	entry := jsonEntry{
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		Level:     level.String(),
+		Message:   msg,
+	}
+	if n := len(l.fields) + len(fields); n > 0 {
+		entry.Fields = make(map[string]any, n)
+		for _, f := range l.fields {
+			entry.Fields[f.Key] = jsonValue(f.Value)
+		}
+		for _, f := range fields {
+			entry.Fields[f.Key] = jsonValue(f.Value)
+		}
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.writer.Write(append(line, '\n'))
+}
+
+// jsonValue converts values that don't serialize usefully (errors marshal as
+// {}, durations as nanoseconds) to their string form.
+func jsonValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return v
+}
+
+func (l *JSONLogger) Debug(msg string, fields ...Field) { l.log(LevelDebug, msg, fields...) }
+func (l *JSONLogger) Info(msg string, fields ...Field)  { l.log(LevelInfo, msg, fields...) }
+func (l *JSONLogger) Warn(msg string, fields ...Field)  { l.log(LevelWarn, msg, fields...) }
+func (l *JSONLogger) Error(msg string, fields ...Field) { l.log(LevelError, msg, fields...) }
+
+func (l *JSONLogger) WithFields(fields ...Field) Logger {
+	return &JSONLogger{
+		writer: l.writer,
+		level:  l.level,
+		fields: append(append([]Field{}, l.fields...), fields...),
+		mu:     l.mu,
+		file:   l.file,
+	}
+}
+
+// Close closes the log file.
+func (l *JSONLogger) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func decodeLines(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var m map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+			t.Fatalf("line is not JSON: %q: %v", sc.Text(), err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestJSONLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := newJSONLogger(&buf, LevelDebug)
+
+	l.WithFields(F("run", "abc")).Info("step done",
+		F("step", "agent"),
+		F("loop", 3),
+		F("error", errors.New("boom")),
+		F("duration", 1500*time.Millisecond),
+	)
+
+	lines := decodeLines(t, buf.Bytes())
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %d", len(lines))
+	}
+	got := lines[0]
+
+	if got["level"] != "INFO" || got["message"] != "step done" {
+		t.Errorf("unexpected level/message: %v", got)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, got["timestamp"].(string)); err != nil {
+		t.Errorf("timestamp not RFC3339: %v", got["timestamp"])
+	}
+
+	fields, ok := got["fields"].(map[string]any)
+	if !ok {
+		t.Fatalf("fields missing or not an object: %v", got["fields"])
+	}
+	want := map[string]any{
+		"run":      "abc",
+		"step":     "agent",
+		"loop":     float64(3),
+		"error":    "boom",
+		"duration": "1.5s",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("fields[%q] = %v, want %v", k, fields[k], v)
+		}
+	}
+}
+
+func TestJSONLoggerDropsBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := newJSONLogger(&buf, LevelWarn)
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	lines := decodeLines(t, buf.Bytes())
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), buf.String())
+	}
+	if lines[0]["level"] != "WARN" || lines[1]["level"] != "ERROR" {
+		t.Errorf("unexpected levels: %v, %v", lines[0]["level"], lines[1]["level"])
+	}
+	if _, ok := lines[0]["fields"]; ok {
+		t.Errorf("expected no fields key when there are no fields")
+	}
+}
+
+func TestNewJSONLoggerAppendsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ralph.jsonl")
+	for i := 0; i < 2; i++ {
+		l, err := NewJSONLogger(path, LevelInfo)
+		if err != nil {
+			t.Fatalf("NewJSONLogger: %v", err)
+		}
+		l.Info("hello", F("i", i))
+		if err := l.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := decodeLines(t, data); len(lines) != 2 {
+		t.Errorf("expected 2 lines appended, got %d", len(lines))
+	}
+}
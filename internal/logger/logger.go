@@ -113,14 +113,19 @@ func (l *StdoutLogger) WithFields(fields ...Field) Logger {
 // FileLogger logs to a file.
 type FileLogger struct {
 	baseLogger
-	file *os.File
+	file *rotatingFile
 }
 
 // NewFileLogger creates a logger that writes to a file.
 func NewFileLogger(path string, level Level) (*FileLogger, error) {
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	return NewRotatingFileLogger(path, level, RotationConfig{})
+}
+
+// NewRotatingFileLogger creates a file logger that rotates the file by size.
+func NewRotatingFileLogger(path string, level Level, rot RotationConfig) (*FileLogger, error) {
+	file, err := openRotatingFile(path, rot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %w", err)
+		return nil, err
 	}
 
 	return &FileLogger{
@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces secrets in redacted log output.
+const Redacted = "***REDACTED***"
+
+// tokenPatterns match common credential shapes.
+var tokenPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`sk-ant-[A-Za-z0-9_-]{10,}`),                               // Anthropic
+	regexp.MustCompile(`sk-[A-Za-z0-9_-]{20,}`),                                   // OpenAI-style
+	regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{20,}`),                              // GitHub
+	regexp.MustCompile(`github_pat_[A-Za-z0-9_]{20,}`),                            // GitHub fine-grained
+	regexp.MustCompile(`glpat-[A-Za-z0-9_-]{20,}`),                                // GitLab
+	regexp.MustCompile(`xox[abprs]-[A-Za-z0-9-]{10,}`),                            // Slack
+	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),                                        // AWS access key ID
+	regexp.MustCompile(`eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]+`), // JWT
+	regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]{10,}`),                    // Authorization headers
+}
+
+// secretEnvSuffixes mark env vars whose values are treated as secrets.
+var secretEnvSuffixes = []string{"_TOKEN", "_KEY", "_SECRET"}
+
+// minEnvSecretLen keeps short values (e.g. KEY=1) from redacting unrelated text.
+const minEnvSecretLen = 6
+
+// DefaultRedactPatterns returns the common token patterns plus the values of
+// env vars named like *_TOKEN, *_KEY or *_SECRET.
+func DefaultRedactPatterns() []*regexp.Regexp {
+	return append(append([]*regexp.Regexp{}, tokenPatterns...), envSecretPatterns(os.Environ())...)
+}
+
+// envSecretPatterns builds literal patterns for secret-looking env values,
+// longest first so a value containing another is redacted whole.
+func envSecretPatterns(environ []string) []*regexp.Regexp {
+	var values []string
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || len(value) < minEnvSecretLen {
+			continue
+		}
+		upper := strings.ToUpper(name)
+		for _, suffix := range secretEnvSuffixes {
+			if strings.HasSuffix(upper, suffix) {
+				values = append(values, value)
+				break
+			}
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	patterns := make([]*regexp.Regexp, 0, len(values))
+	for _, v := range values {
+		patterns = append(patterns, regexp.MustCompile(regexp.QuoteMeta(v)))
+	}
+	return patterns
+}
+
+// RedactingLogger replaces secrets in messages and field values before
+// passing them to the wrapped logger.
+type RedactingLogger struct {
+	next     Logger
+	patterns []*regexp.Regexp
+}
+
+// NewRedactingLogger wraps next. With no patterns, DefaultRedactPatterns is used.
+func NewRedactingLogger(next Logger, patterns ...*regexp.Regexp) *RedactingLogger {
+	if len(patterns) == 0 {
+		patterns = DefaultRedactPatterns()
+	}
+	return &RedactingLogger{next: next, patterns: patterns}
+}
+
+// Redact replaces every pattern match in s with Redacted.
+func (r *RedactingLogger) Redact(s string) string {
+	for _, p := range r.patterns {
+		s = p.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// redactFields returns fields with secret-bearing values replaced by strings.
+// Values that don't contain a secret are passed through unchanged.
+func (r *RedactingLogger) redactFields(fields []Field) []Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	out := make([]Field, len(fields))
+	for i, f := range fields {
+		out[i] = f
+		var text string
+		switch v := f.Value.(type) {
+		case nil:
+			continue
+		case string:
+			text = v
+		case error:
+			text = v.Error()
+		case fmt.Stringer:
+			text = v.String()
+		default:
+			text = fmt.Sprintf("%v", v)
+		}
+		if redacted := r.Redact(text); redacted != text {
+			out[i].Value = redacted
+		}
+	}
+	return out
+}
+
+func (r *RedactingLogger) Debug(msg string, fields ...Field) {
+	r.next.Debug(r.Redact(msg), r.redactFields(fields)...)
+}
+
+func (r *RedactingLogger) Info(msg string, fields ...Field) {
+	r.next.Info(r.Redact(msg), r.redactFields(fields)...)
+}
+
+func (r *RedactingLogger) Warn(msg string, fields ...Field) {
+	r.next.Warn(r.Redact(msg), r.redactFields(fields)...)
+}
+
+func (r *RedactingLogger) Error(msg string, fields ...Field) {
+	r.next.Error(r.Redact(msg), r.redactFields(fields)...)
+}
+
+func (r *RedactingLogger) WithFields(fields ...Field) Logger {
+	return &RedactingLogger{
+		next:     r.next.WithFields(r.redactFields(fields)...),
+		patterns: r.patterns,
+	}
+}
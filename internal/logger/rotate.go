@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotationConfig enables size-based rotation of a log file. A zero
+// MaxSizeBytes disables rotation.
+type RotationConfig struct {
+	// MaxSizeBytes rotates the file before a write would take it past this size
+	MaxSizeBytes int64 `json:"max_size_bytes,omitempty"`
+	// MaxBackups is how many rotated files (path.1 is newest) to keep; 0 keeps none
+	MaxBackups int `json:"max_backups,omitempty"`
+}
+
+// rotatingFile is an append-only log file that rotates itself by size. Writes
+// and rotation share one mutex, so loggers derived via WithFields can share it.
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+	rot  RotationConfig
+}
+
+func openRotatingFile(path string, rot RotationConfig) (*rotatingFile, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	var size int64
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+	return &rotatingFile{path: path, file: file, size: size, rot: rot}, nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.rot.MaxSizeBytes > 0 && f.size > 0 && f.size+int64(len(p)) > f.rot.MaxSizeBytes {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate shifts path.N-1 -> path.N ... path -> path.1 and reopens path empty.
+// The oldest backup beyond MaxBackups is removed. Callers hold the lock.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file for rotation: %w", err)
+	}
+
+	if f.rot.MaxBackups > 0 {
+		_ = os.Remove(f.backupPath(f.rot.MaxBackups))
+		for i := f.rot.MaxBackups - 1; i >= 1; i-- {
+			if err := os.Rename(f.backupPath(i), f.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to rotate log file: %w", err)
+			}
+		}
+		if err := os.Rename(f.path, f.backupPath(1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_TRUNC|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to reopen log file: %w", err)
+	}
+	f.file = file
+	f.size = 0
+	return nil
+}
+
+func (f *rotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", f.path, n)
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileLoggerRotatesAndCapsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ralph.log")
+	l, err := NewRotatingFileLogger(path, LevelDebug, RotationConfig{MaxSizeBytes: 200, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFileLogger: %v", err)
+	}
+	defer l.Close()
+
+	// ~60 bytes per line, so 40 lines force many rotations.
+	for i := 0; i < 40; i++ {
+		l.Info("a line long enough to fill the file", F("i", i))
+	}
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", filepath.Base(p), err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("%s is %d bytes, over the 200 byte limit", filepath.Base(p), info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 backups, found %s.3", filepath.Base(path))
+	}
+
+	// The newest lines are in the active file; older ones in .1.
+	active, _ := os.ReadFile(path)
+	if !strings.Contains(string(active), "i=39") {
+		t.Errorf("active file missing last line:\n%s", active)
+	}
+	backup, _ := os.ReadFile(path + ".1")
+	if strings.Contains(string(backup), "i=39") || len(backup) == 0 {
+		t.Errorf("unexpected backup contents:\n%s", backup)
+	}
+}
+
+func TestFileLoggerRotationOffByDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ralph.log")
+	l, err := NewFileLogger(path, LevelDebug)
+	if err != nil {
+		t.Fatalf("NewFileLogger: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		l.Info("a line long enough to fill the file", F("i", i))
+	}
+	l.Close()
+
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("expected no backups without rotation config")
+	}
+}
+
+func TestFileLoggerRotationNoBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ralph.log")
+	l, err := NewRotatingFileLogger(path, LevelDebug, RotationConfig{MaxSizeBytes: 100})
+	if err != nil {
+		t.Fatalf("NewRotatingFileLogger: %v", err)
+	}
+	defer l.Close()
+
+	for i := 0; i < 20; i++ {
+		l.Info("a line long enough to fill the file", F("i", i))
+	}
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("expected no backups with max_backups 0")
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() > 100 {
+		t.Errorf("expected active file under limit, got %v, %v", info, err)
+	}
+}
+
+func TestFileLoggerRotationConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ralph.log")
+	l, err := NewRotatingFileLogger(path, LevelDebug, RotationConfig{MaxSizeBytes: 500, MaxBackups: 3})
+	if err != nil {
+		t.Fatalf("NewRotatingFileLogger: %v", err)
+	}
+	defer l.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			sub := l.WithFields(F("g", g))
+			for i := 0; i < 50; i++ {
+				sub.Info("concurrent write", F("i", i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Errorf("expected active file + 3 backups, got %d files", len(entries))
+	}
+	for _, e := range entries {
+		data, _ := os.ReadFile(filepath.Join(dir, e.Name()))
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if !strings.Contains(line, "INFO: concurrent write") {
+				t.Fatalf("torn or interleaved line in %s: %q", e.Name(), line)
+			}
+		}
+	}
+}
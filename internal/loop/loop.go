@@ -2,8 +2,13 @@ package loop
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/chr1sbest/wiggum/internal/agent"
@@ -19,10 +24,11 @@ import (
 type Status string
 
 const (
-	StatusRunning  Status = "RUNNING"
-	StatusComplete Status = "COMPLETE"
-	StatusBlocked  Status = "BLOCKED"
-	StatusError    Status = "ERROR"
+	StatusRunning     Status = "RUNNING"
+	StatusComplete    Status = "COMPLETE"
+	StatusBlocked     Status = "BLOCKED"
+	StatusError       Status = "ERROR"
+	StatusInterrupted Status = "INTERRUPTED" // Stopped by a signal or a quit at a step confirmation
 )
 
 // State holds the current loop execution state.
@@ -47,12 +53,71 @@ type StepResult struct {
 	CircuitOpen  bool // True if skipped due to open circuit
 }
 
+// BudgetExceededError is returned by Run when the cost spent during the run
+// reaches the configured budget. Like AgentExitError it is a clean stop.
+type BudgetExceededError struct {
+	BudgetUSD float64
+	SpentUSD  float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("budget of $%.2f reached ($%.2f spent)", e.BudgetUSD, e.SpentUSD)
+}
+
+// MaxLoopsReachedError is returned by Run after the configured number of
+// iterations. Like AgentExitError it is a clean stop.
+type MaxLoopsReachedError struct {
+	MaxLoops int
+}
+
+func (e *MaxLoopsReachedError) Error() string {
+	return fmt.Sprintf("max loops reached (%d)", e.MaxLoops)
+}
+
+// StatusDisplay shows loop progress. *status.Writer draws the terminal
+// display and *status.JSONWriter emits newline-delimited JSON records.
+type StatusDisplay interface {
+	Step(loopNum, stepNum, totalSteps int, stepName string)
+	StepWithRetry(loopNum, stepNum, totalSteps int, stepName string, attempt, maxRetries int)
+	Complete(loopNum, totalSteps int)
+	Error(loopNum, stepNum, totalSteps int, stepName string, err error)
+	CircuitOpen(loopNum, stepNum, totalSteps int, stepName string)
+	TasksDone(loopNum int, taskIDs []string)
+	StepOutput(loopNum int, stepName, line string)
+}
+
+// StepDecision is a StepConfirmer's answer for one step.
+type StepDecision int
+
+const (
+	StepRun  StepDecision = iota // Run the step
+	StepSkip                     // Skip the step for this iteration only
+	StepQuit                     // Stop the loop before the step runs
+)
+
+// StepPrompt describes the step about to run, for a StepConfirmer.
+type StepPrompt struct {
+	LoopNumber int
+	StepNumber int
+	TotalSteps int
+	Step       config.StepConfig
+	TaskID     string // Current task, if any
+	TaskTitle  string
+}
+
+// StepConfirmer approves each step before it executes, e.g. by asking on
+// the terminal for ralph run -confirm-each-step.
+type StepConfirmer interface {
+	ConfirmStep(ctx context.Context, p StepPrompt) StepDecision
+}
+
 // Loop is the main execution engine.
 type Loop struct {
 	config          *config.Config
 	registry        *StepRegistry
 	logger          logger.Logger
-	status          *status.Writer
+	status          StatusDisplay
+	out             io.Writer // task warnings printed for people
 	state           State
 	stepDelay       time.Duration
 	circuitBreakers *resilience.CircuitBreakerRegistry
@@ -63,18 +128,54 @@ type Loop struct {
 	// Task loop tracking for max_loops_per_task
 	currentTaskID string
 	loopsOnTask   int
+	// Wall-clock tracking for task_timeout, read from now
+	timedTaskID   string
+	taskStartedAt time.Time
+	now           func() time.Time
 	prdPath       string // Path to prd.json for marking tasks failed
+
+	// Stall tracking for stall_threshold
+	stallDetector *agent.StallDetector
+	// No-progress tracking carried between executions, by step name
+	exitDetectors map[string]*agent.ExitDetector
+
+	// Global iteration cap; 0 means unlimited.
+	maxLoops   int
+	totalLoops int
+
+	// Cost cap; 0 means unlimited. Requires run tracking.
+	budgetUSD float64
+	// Cost recorded when Run was first called, so a Run resumed after a
+	// usage-limit wait keeps counting against the same budget.
+	startCostUSD float64
+	startCostSet bool
+
+	// Paces agent step executions per max_claude_rps; nil means unlimited.
+	claudeLimiter *resilience.RateLimiter
+
+	// Asked before every step when set; nil runs steps unprompted.
+	confirmer StepConfirmer
+
+	// Rewrite .ralph/metrics.prom after every iteration, labeled with this
+	// model. Requires run tracking.
+	prometheus      bool
+	prometheusModel string
 }
 
 // NewLoop creates a new loop executor.
 func NewLoop(cfg *config.Config, registry *StepRegistry, log logger.Logger) *Loop {
 	return &Loop{
-		config:          cfg,
+		config:          withOrderedSteps(cfg),
 		registry:        registry,
 		logger:          log,
 		status:          status.New(),
-		stepDelay:       500 * time.Millisecond,
+		out:             os.Stdout,
+		stepDelay:       config.DefaultStepDelay,
+		stallDetector:   agent.NewStallDetector(0),
+		exitDetectors:   make(map[string]*agent.ExitDetector),
+		now:             time.Now,
 		circuitBreakers: resilience.NewCircuitBreakerRegistry(resilience.DefaultCircuitBreakerConfig()),
+		claudeLimiter:   claudeLimiterFor(cfg),
 		state: State{
 			Status:      StatusRunning,
 			TestsStatus: "NOT_RUN",
@@ -121,11 +222,103 @@ func (l *Loop) writeRunState(status string, currentStep string, stepStartedAt ti
 	_ = l.trackerWriter.WriteRunState(rs)
 }
 
+// MarkInterrupted records that the run stopped before finishing, so
+// run_state.json says "interrupted" instead of whatever the last step wrote.
+func (l *Loop) MarkInterrupted() {
+	l.state.Status = StatusInterrupted
+	l.writeRunState("interrupted", l.state.CurrentStep, time.Time{}, l.state.PreviousStep, nil)
+	l.emit(tracker.EventInterrupted, "", nil)
+}
+
+// emit appends a lifecycle event to events.jsonl when run tracking is enabled.
+func (l *Loop) emit(eventType, step string, fields map[string]any) {
+	if l.trackerWriter == nil {
+		return
+	}
+	_ = l.trackerWriter.AppendEvent(tracker.Event{
+		RunID:  l.runID,
+		Type:   eventType,
+		Loop:   l.state.LoopNumber,
+		Step:   step,
+		Fields: fields,
+	})
+}
+
+// SetStatusDisplay replaces the terminal status display, e.g. with a
+// status.JSONWriter for machine-readable progress.
+func (l *Loop) SetStatusDisplay(d StatusDisplay) {
+	l.status = d
+}
+
+// SetOutput sets where the loop prints task warnings, os.Stdout by default.
+func (l *Loop) SetOutput(w io.Writer) {
+	l.out = w
+}
+
+// SetStepConfirmer makes the loop ask c before executing each step.
+func (l *Loop) SetStepConfirmer(c StepConfirmer) {
+	l.confirmer = c
+}
+
+// EnablePrometheus makes each loop iteration rewrite the Prometheus textfile
+// next to the run metrics, labeled with model. It has no effect without
+// EnableRunTracking.
+func (l *Loop) EnablePrometheus(model string) {
+	l.prometheus = true
+	l.prometheusModel = model
+}
+
 // SetStepDelay sets the delay between steps.
 func (l *Loop) SetStepDelay(d time.Duration) {
 	l.stepDelay = d
 }
 
+// SetBudget stops Run once the cost spent during the run reaches usd.
+// A value <= 0 disables the cap.
+func (l *Loop) SetBudget(usd float64) {
+	l.budgetUSD = usd
+}
+
+// SetMaxLoops stops Run after n total iterations. A value <= 0 disables the cap.
+func (l *Loop) SetMaxLoops(n int) {
+	l.maxLoops = n
+}
+
+// SaveCircuitState writes every circuit breaker's state to path so a later run
+// can pick up where this one left off.
+func (l *Loop) SaveCircuitState(path string) error {
+	data, err := json.MarshalIndent(l.circuitBreakers.Snapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.tmp.%d", path, time.Now().UnixNano())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RestoreCircuitState loads circuit breaker state saved by SaveCircuitState.
+// A missing file is not an error.
+func (l *Loop) RestoreCircuitState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var snapshots map[string]resilience.CircuitSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return fmt.Errorf("failed to parse circuit state: %w", err)
+	}
+	l.circuitBreakers.Restore(snapshots)
+	return nil
+}
+
 // SetPRDPath sets the path to prd.json for task tracking.
 func (l *Loop) SetPRDPath(path string) {
 	l.prdPath = path
@@ -133,7 +326,35 @@ func (l *Loop) SetPRDPath(path string) {
 
 // SetConfig updates the loop configuration (for hot-reload).
 func (l *Loop) SetConfig(cfg *config.Config) {
-	l.config = cfg
+	if cfg == nil || l.config == nil || cfg.MaxClaudeRPS != l.config.MaxClaudeRPS {
+		l.claudeLimiter = claudeLimiterFor(cfg)
+	}
+	l.config = withOrderedSteps(cfg)
+}
+
+// claudeLimiterFor returns a limiter for cfg's max_claude_rps with a burst
+// of one, or nil when it is unset.
+func claudeLimiterFor(cfg *config.Config) *resilience.RateLimiter {
+	if cfg == nil {
+		return nil
+	}
+	return resilience.NewRateLimiter(cfg.MaxClaudeRPS, 1)
+}
+
+// withOrderedSteps returns cfg with its steps in depends_on order. Configs
+// without dependencies are returned as-is. LoadAndValidate rejects unknown
+// dependencies and cycles, so an ordering error leaves array order in place.
+func withOrderedSteps(cfg *config.Config) *config.Config {
+	if cfg == nil || !config.HasDependencies(cfg.Steps) {
+		return cfg
+	}
+	ordered, err := config.OrderSteps(cfg.Steps)
+	if err != nil {
+		return cfg
+	}
+	sorted := *cfg
+	sorted.Steps = ordered
+	return &sorted
 }
 
 // State returns the current loop state.
@@ -149,12 +370,21 @@ func (l *Loop) RunOnce(ctx context.Context) error {
 
 	// Mark loop start
 	l.writeRunState("running", l.state.CurrentStep, time.Time{}, l.state.PreviousStep, nil)
+	l.emit(tracker.EventLoopStart, "", nil)
 
 	l.logger.Debug("Starting loop iteration", logger.F("loop", l.state.LoopNumber))
+	defer l.writePrometheus()
+
+	// Diff prd.json across the iteration, whichever way it ends
+	if l.prdPath != "" {
+		before, _ := agent.LoadTaskStatuses(l.prdPath)
+		defer l.reportDoneTasks(before)
+	}
 
 	// Count enabled steps for progress display
 	enabledSteps := l.countEnabledSteps()
 	stepNum := 0
+	var results []StepResult // for success()/failure() in `when`
 
 	for _, stepCfg := range l.config.Steps {
 		if !stepCfg.IsEnabled() {
@@ -164,20 +394,39 @@ func (l *Loop) RunOnce(ctx context.Context) error {
 
 		select {
 		case <-ctx.Done():
-			l.state.Status = StatusBlocked
-			l.writeRunState("blocked", l.state.CurrentStep, time.Time{}, l.state.PreviousStep, ctx.Err())
+			l.state.Status = StatusInterrupted
+			l.writeRunState("interrupted", l.state.CurrentStep, time.Time{}, l.state.PreviousStep, ctx.Err())
 			return ctx.Err()
 		default:
 		}
 
 		stepNum++
+		if !l.shouldRunStep(stepCfg, results) {
+			continue
+		}
+		if l.confirmer != nil {
+			switch l.confirmStep(ctx, stepCfg, stepNum, enabledSteps) {
+			case StepSkip:
+				l.logger.Info("Skipping step (declined)", logger.F("step", stepCfg.Name))
+				continue
+			case StepQuit:
+				l.logger.Info("Stopping loop (quit at confirmation)", logger.F("step", stepCfg.Name))
+				l.state.Status = StatusInterrupted
+				l.writeRunState("interrupted", l.state.CurrentStep, time.Time{}, l.state.PreviousStep, context.Canceled)
+				return context.Canceled
+			}
+		}
 		stepStart := time.Now()
 		l.writeRunState("running", stepCfg.Name, stepStart, l.state.PreviousStep, nil)
+		l.emit(tracker.EventStepStart, stepCfg.Name, map[string]any{"type": stepCfg.Type})
 
 		// Update status display
 		l.status.Step(l.state.LoopNumber, stepNum, enabledSteps, stepCfg.Name)
 
 		result := l.executeStepWithResilience(ctx, stepCfg, stepNum, enabledSteps)
+		if !result.CircuitOpen {
+			results = append(results, result)
+		}
 
 		if result.CircuitOpen {
 			// Step was skipped due to open circuit, continue to next step
@@ -193,9 +442,24 @@ func (l *Loop) RunOnce(ctx context.Context) error {
 				l.state.Status = StatusComplete
 				l.status.Complete(l.state.LoopNumber, enabledSteps)
 				l.writeRunState("complete", l.state.CurrentStep, time.Time{}, l.state.CurrentStep, nil)
+				l.emit(tracker.EventComplete, stepCfg.Name, map[string]any{"reason": string(exitErr.Reason)})
 				return exitErr
 			}
 
+			l.emit(tracker.EventStepError, stepCfg.Name, map[string]any{
+				"error":       result.Error.Error(),
+				"retries":     result.RetryAttempt,
+				"duration_ms": result.Duration.Milliseconds(),
+			})
+
+			// Quota exhaustion stops the run even for continue_on_error steps.
+			if steps.IsClaudeUsageError(result.Error) {
+				l.state.Status = StatusError
+				l.status.Error(l.state.LoopNumber, stepNum, enabledSteps, stepCfg.Name, result.Error)
+				l.writeRunState("error", stepCfg.Name, stepStart, l.state.PreviousStep, result.Error)
+				return result.Error
+			}
+
 			if stepCfg.ContinueOnError {
 				l.logger.Debug("Step failed but continuing",
 					logger.F("step", stepCfg.Name),
@@ -219,6 +483,10 @@ func (l *Loop) RunOnce(ctx context.Context) error {
 		l.state.PreviousStep = l.state.CurrentStep
 		l.state.CurrentStep = stepCfg.Name
 		l.writeRunState("running", l.state.CurrentStep, stepStart, l.state.PreviousStep, nil)
+		l.emit(tracker.EventStepSuccess, stepCfg.Name, map[string]any{
+			"retries":     result.RetryAttempt,
+			"duration_ms": result.Duration.Milliseconds(),
+		})
 
 		// Delay between steps
 		if l.stepDelay > 0 {
@@ -233,6 +501,8 @@ func (l *Loop) RunOnce(ctx context.Context) error {
 	l.state.Status = StatusComplete
 	l.status.Complete(l.state.LoopNumber, enabledSteps)
 	l.writeRunState("complete", l.state.CurrentStep, time.Time{}, l.state.CurrentStep, nil)
+	l.emit(tracker.EventComplete, "", map[string]any{"duration_ms": time.Since(l.state.StartTime).Milliseconds()})
+	l.rotateLearnings()
 	l.logger.Debug("Loop iteration complete",
 		logger.F("loop", l.state.LoopNumber),
 		logger.F("duration", time.Since(l.state.StartTime)),
@@ -241,6 +511,126 @@ func (l *Loop) RunOnce(ctx context.Context) error {
 	return nil
 }
 
+// reportDoneTasks records the tasks that moved to done since before was
+// read from prd.json, in the event log and on the status display.
+func (l *Loop) reportDoneTasks(before []agent.TaskStatus) {
+	after, err := agent.LoadTaskStatuses(l.prdPath)
+	if err != nil {
+		l.logger.Debug("Failed to read prd.json for task diff", logger.F("error", err))
+		return
+	}
+	ids := agent.NewlyCompleted(before, after)
+	if len(ids) == 0 {
+		return
+	}
+	l.emit(tracker.EventTasksDone, "", map[string]any{"task_ids": ids})
+	l.status.TasksDone(l.state.LoopNumber, ids)
+	l.logger.Info("Tasks completed this loop",
+		logger.F("loop", l.state.LoopNumber),
+		logger.F("task_ids", strings.Join(ids, ", ")),
+	)
+}
+
+// writePrometheus refreshes metrics.prom when enabled. Failures only reach
+// the run log; a stale textfile shouldn't stop the loop.
+func (l *Loop) writePrometheus() {
+	if !l.prometheus || l.trackerWriter == nil {
+		return
+	}
+	if err := l.trackerWriter.WritePrometheus(l.runID, l.prometheusModel, l.state.LoopNumber); err != nil {
+		l.logger.Debug("Failed to write Prometheus metrics", logger.F("error", err))
+	}
+}
+
+// rotateLearnings archives old entries of learnings.md, which lives next to
+// prd.json, once it grows past the configured size.
+func (l *Loop) rotateLearnings() {
+	if l.prdPath == "" {
+		return
+	}
+	dir := filepath.Dir(l.prdPath)
+	rotated, err := agent.RotateLearnings(
+		filepath.Join(dir, "learnings.md"),
+		filepath.Join(dir, "learnings.archive.md"),
+		l.config.GetLearningsMaxBytes(),
+	)
+	if err != nil {
+		l.logger.Debug("Failed to rotate learnings", logger.F("error", err))
+	} else if rotated {
+		l.logger.Debug("Archived old learnings", logger.F("archive", filepath.Join(dir, "learnings.archive.md")))
+	}
+}
+
+// RunStep executes only the configured step with the given name, through the
+// same retry, timeout and circuit breaker handling as a full loop iteration.
+// It errors if no step has that name or the step is disabled.
+func (l *Loop) RunStep(ctx context.Context, name string) (StepResult, error) {
+	for _, stepCfg := range l.config.Steps {
+		if stepCfg.Name != name {
+			continue
+		}
+		if !stepCfg.IsEnabled() {
+			return StepResult{}, fmt.Errorf("step %q is disabled", name)
+		}
+		l.state.LoopNumber++
+		l.state.StartTime = time.Now()
+		l.status.Step(l.state.LoopNumber, 1, 1, stepCfg.Name)
+		return l.executeStepWithResilience(ctx, stepCfg, 1, 1), nil
+	}
+
+	names := make([]string, 0, len(l.config.Steps))
+	for _, stepCfg := range l.config.Steps {
+		names = append(names, stepCfg.Name)
+	}
+	return StepResult{}, fmt.Errorf("step %q not found (configured: %s)", name, strings.Join(names, ", "))
+}
+
+// confirmStep asks the confirmer about stepCfg, with the current task.
+func (l *Loop) confirmStep(ctx context.Context, stepCfg config.StepConfig, stepNum, totalSteps int) StepDecision {
+	p := StepPrompt{
+		LoopNumber: l.state.LoopNumber,
+		StepNumber: stepNum,
+		TotalSteps: totalSteps,
+		Step:       stepCfg,
+	}
+	if l.prdPath != "" {
+		if prd, err := agent.LoadPRDStatus(l.prdPath); err == nil && prd != nil {
+			p.TaskID, p.TaskTitle = prd.CurrentTaskID, prd.CurrentTask
+		}
+	}
+	return l.confirmer.ConfirmStep(ctx, p)
+}
+
+// shouldRunStep evaluates the step's `when` condition, if any. A false or
+// invalid condition skips the step.
+func (l *Loop) shouldRunStep(stepCfg config.StepConfig, results []StepResult) bool {
+	if strings.TrimSpace(stepCfg.When) == "" {
+		return true
+	}
+	env := conditionEnv{LoopNumber: l.state.LoopNumber, Results: results}
+	if l.prdPath != "" {
+		if prd, err := agent.LoadPRDStatus(l.prdPath); err == nil {
+			env.Tasks = prd
+		}
+	}
+	ok, err := evalCondition(stepCfg.When, env)
+	if err != nil {
+		l.logger.Warn("Skipping step (invalid when)",
+			logger.F("step", stepCfg.Name),
+			logger.F("when", stepCfg.When),
+			logger.F("error", err),
+		)
+		return false
+	}
+	if !ok {
+		l.logger.Info("Skipping step (when is false)",
+			logger.F("step", stepCfg.Name),
+			logger.F("when", stepCfg.When),
+		)
+	}
+	return ok
+}
+
 func (l *Loop) countEnabledSteps() int {
 	count := 0
 	for _, s := range l.config.Steps {
@@ -253,8 +643,18 @@ func (l *Loop) countEnabledSteps() int {
 
 // Run executes the loop continuously until context is cancelled.
 func (l *Loop) Run(ctx context.Context) error {
-	backoff := l.stepDelay
+	// Failed iterations wait at least minBackoff, so a step_delay of 0s
+	// doesn't retry a failing loop in a tight spin.
+	const minBackoff = config.DefaultStepDelay
 	const maxBackoff = 30 * time.Second
+	backoff := max(l.stepDelay, minBackoff)
+
+	// Metrics accumulate across runs, so measure spend relative to where this run started.
+	if !l.startCostSet {
+		l.startCostUSD = l.totalCostUSD()
+		l.startCostSet = true
+	}
+	startCostUSD := l.startCostUSD
 
 	for {
 		select {
@@ -263,6 +663,23 @@ func (l *Loop) Run(ctx context.Context) error {
 		default:
 		}
 
+		// Check the budget between iterations so a Claude call is never cut off mid-step.
+		if l.budgetUSD > 0 {
+			if spent := l.totalCostUSD() - startCostUSD; spent >= l.budgetUSD {
+				l.state.Status = StatusComplete
+				l.writeRunState("complete", "", time.Time{}, l.state.CurrentStep, nil)
+				l.emit(tracker.EventComplete, "", map[string]any{"reason": "budget_reached", "spent_usd": spent})
+				return &BudgetExceededError{BudgetUSD: l.budgetUSD, SpentUSD: spent}
+			}
+		}
+
+		if l.maxLoops > 0 && l.totalLoops >= l.maxLoops {
+			l.state.Status = StatusComplete
+			l.writeRunState("complete", "", time.Time{}, l.state.CurrentStep, nil)
+			l.emit(tracker.EventComplete, "", map[string]any{"reason": "max_loops_reached"})
+			return &MaxLoopsReachedError{MaxLoops: l.maxLoops}
+		}
+
 		// Preflight: check if all tasks are complete before running Claude
 		if l.prdPath != "" {
 			prdStatus, _ := agent.LoadPRDStatus(l.prdPath)
@@ -270,8 +687,19 @@ func (l *Loop) Run(ctx context.Context) error {
 				l.state.Status = StatusComplete
 				l.status.Complete(l.state.LoopNumber, l.countEnabledSteps())
 				l.writeRunState("complete", "", time.Time{}, "", nil)
+				l.emit(tracker.EventComplete, "", map[string]any{"reason": string(agent.ExitReasonPlanComplete)})
 				return &steps.AgentExitError{Reason: agent.ExitReasonPlanComplete}
 			}
+			// Nothing left can start: every todo task waits on a dependency that failed.
+			if prdStatus != nil && prdStatus.IsBlocked() {
+				l.state.Status = StatusBlocked
+				l.writeRunState("blocked", "", time.Time{}, l.state.CurrentStep, nil)
+				l.emit(tracker.EventComplete, "", map[string]any{
+					"reason":        string(agent.ExitReasonTasksBlocked),
+					"blocked_tasks": prdStatus.BlockedTasks,
+				})
+				return &steps.AgentExitError{Reason: agent.ExitReasonTasksBlocked}
+			}
 		}
 
 		// Check max_loops_per_task limit before running
@@ -294,10 +722,14 @@ func (l *Loop) Run(ctx context.Context) error {
 						logger.F("max", l.config.MaxLoopsPerTask),
 					)
 					// Print visible notification
-					fmt.Printf("\n⚠️  Task %s failed after %d loops - moving to next task\n", l.currentTaskID, l.config.MaxLoopsPerTask)
+					fmt.Fprintf(l.out, "\n⚠️  Task %s failed after %d loops - moving to next task\n", l.currentTaskID, l.config.MaxLoopsPerTask)
 					if err := agent.MarkTaskFailed(l.prdPath, l.currentTaskID); err != nil {
 						l.logger.Debug("Failed to mark task as failed", logger.F("error", err))
 					}
+					l.emit(tracker.EventTaskFailed, "", map[string]any{
+						"task_id": l.currentTaskID,
+						"loops":   l.config.MaxLoopsPerTask,
+					})
 					// Reset counter and continue to next task
 					l.currentTaskID = ""
 					l.loopsOnTask = 0
@@ -306,11 +738,73 @@ func (l *Loop) Run(ctx context.Context) error {
 			}
 		}
 
+		// Check task_timeout: same task for too long, however many loops
+		if timeout := l.config.GetTaskTimeout(); timeout > 0 && l.prdPath != "" {
+			prdStatus, _ := agent.LoadPRDStatus(l.prdPath)
+			if prdStatus != nil && prdStatus.CurrentTaskID != "" {
+				if prdStatus.CurrentTaskID != l.timedTaskID {
+					l.timedTaskID = prdStatus.CurrentTaskID
+					l.taskStartedAt = l.now()
+				}
+				if elapsed := l.now().Sub(l.taskStartedAt); elapsed >= timeout {
+					l.logger.Debug("Task timeout reached, marking task as failed",
+						logger.F("task_id", l.timedTaskID),
+						logger.F("elapsed", elapsed.Round(time.Second)),
+						logger.F("timeout", timeout),
+					)
+					fmt.Fprintf(l.out, "\n⚠️  Task %s failed after running for %s (task_timeout %s) - moving to next task\n", l.timedTaskID, elapsed.Round(time.Second), timeout)
+					if err := agent.MarkTaskFailed(l.prdPath, l.timedTaskID); err != nil {
+						l.logger.Debug("Failed to mark task as failed", logger.F("error", err))
+					}
+					l.emit(tracker.EventTaskFailed, "", map[string]any{
+						"task_id":     l.timedTaskID,
+						"elapsed_sec": int64(elapsed / time.Second),
+						"reason":      "timeout",
+					})
+					l.timedTaskID = ""
+					continue
+				}
+			}
+		}
+
+		// Check stall_threshold: same task, no completions
+		if l.config.StallThreshold > 0 && l.prdPath != "" {
+			prdStatus, _ := agent.LoadPRDStatus(l.prdPath)
+			l.stallDetector.Threshold = l.config.StallThreshold
+			if l.stallDetector.Observe(prdStatus) {
+				taskID := prdStatus.CurrentTaskID
+				l.logger.Debug("Task stalled, marking task as failed",
+					logger.F("task_id", taskID),
+					logger.F("loops", l.stallDetector.StalledLoops()),
+				)
+				fmt.Fprintf(l.out, "\n⚠️  Task %s stalled for %d loops with no completed tasks - moving to next task\n", taskID, l.stallDetector.StalledLoops())
+				if err := agent.MarkTaskFailed(l.prdPath, taskID); err != nil {
+					l.logger.Debug("Failed to mark task as failed", logger.F("error", err))
+				}
+				l.emit(tracker.EventTaskFailed, "", map[string]any{
+					"task_id": taskID,
+					"loops":   l.stallDetector.StalledLoops(),
+					"reason":  "stalled",
+				})
+				l.stallDetector.Reset()
+				continue
+			}
+		}
+
+		l.totalLoops++
 		if err := l.RunOnce(ctx); err != nil {
 			// Graceful completion signaled by the agent step should stop the loop.
 			if exitErr, ok := steps.IsAgentExitError(err); ok {
 				return exitErr
 			}
+			// Usage limits won't clear by retrying; let the CLI report them.
+			if steps.IsClaudeUsageError(err) {
+				return err
+			}
+			// Quit at a step confirmation, or any other cancellation.
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
 			if ctx.Err() != nil {
 				return ctx.Err()
 			}
@@ -328,11 +822,39 @@ func (l *Loop) Run(ctx context.Context) error {
 			}
 		} else {
 			// Reset backoff on success
-			backoff = l.stepDelay
+			backoff = max(l.stepDelay, minBackoff)
 		}
 	}
 }
 
+func isAgentExit(err error) bool {
+	_, ok := steps.IsAgentExitError(err)
+	return ok
+}
+
+// totalCostUSD returns the accumulated cost recorded in run metrics.
+func (l *Loop) totalCostUSD() float64 {
+	if l.trackerWriter == nil {
+		return 0
+	}
+	m, _ := l.trackerWriter.LoadMetrics()
+	if m == nil {
+		return 0
+	}
+	return m.TotalCostUSD
+}
+
+// retryConfigFor builds the retry policy for a step from its config.
+func retryConfigFor(stepCfg config.StepConfig) resilience.RetryConfig {
+	return resilience.RetryConfig{
+		MaxRetries: stepCfg.MaxRetries,
+		InitDelay:  stepCfg.GetRetryDelay(),
+		MaxDelay:   stepCfg.GetMaxRetryDelay(),
+		Multiplier: stepCfg.GetRetryMultiplier(),
+		Jitter:     stepCfg.GetRetryJitter(),
+	}
+}
+
 // executeStepWithResilience executes a step with retry and circuit breaker support.
 func (l *Loop) executeStepWithResilience(ctx context.Context, stepCfg config.StepConfig, stepNum, totalSteps int) StepResult {
 	start := time.Now()
@@ -348,6 +870,26 @@ func (l *Loop) executeStepWithResilience(ctx context.Context, stepCfg config.Ste
 		}
 	}
 
+	if streamer, ok := step.(OutputStreamer); ok {
+		streamer.SetOutputFunc(func(line string) {
+			l.status.StepOutput(l.state.LoopNumber, stepCfg.Name, line)
+			l.logger.Debug("Step output", logger.F("step", stepCfg.Name), logger.F("line", line))
+		})
+	}
+	if ls, ok := step.(LoggerSetter); ok {
+		ls.SetLogger(l.logger.WithFields(logger.F("step", stepCfg.Name)))
+	}
+	if carrier, ok := step.(ExitDetectorCarrier); ok {
+		if d := l.exitDetectors[stepCfg.Name]; d != nil {
+			carrier.SetExitDetector(d)
+		}
+		defer func() {
+			if d := carrier.ExitDetector(); d != nil {
+				l.exitDetectors[stepCfg.Name] = d
+			}
+		}()
+	}
+
 	// Get or create circuit breaker for this step
 	var cbConfig *resilience.CircuitBreakerConfig
 	if stepCfg.CircuitBreaker != nil {
@@ -361,6 +903,7 @@ func (l *Loop) executeStepWithResilience(ctx context.Context, stepCfg config.Ste
 	// Check circuit breaker state
 	if cb.State() == resilience.CircuitOpen {
 		l.status.CircuitOpen(l.state.LoopNumber, stepNum, totalSteps, stepCfg.Name)
+		l.emit(tracker.EventCircuitOpen, stepCfg.Name, nil)
 		return StepResult{
 			StepName:    stepCfg.Name,
 			Success:     false,
@@ -369,27 +912,17 @@ func (l *Loop) executeStepWithResilience(ctx context.Context, stepCfg config.Ste
 		}
 	}
 
-	// Build retry config
-	retryCfg := resilience.RetryConfig{
-		MaxRetries: stepCfg.MaxRetries,
-		InitDelay:  stepCfg.GetRetryDelay(),
-		MaxDelay:   30 * time.Second,
-		Multiplier: 2.0,
-		Jitter:     0.1,
-	}
+	retryCfg := retryConfigFor(stepCfg)
 
 	var retryAttempt int
 	var lastErr error
 
-	// Execute step - check for AgentExitError which is a success signal, not a failure
+	// Execute step - AgentExitError is a success signal (plan complete) and
+	// ClaudeUsageError needs the quota to reset; retrying helps neither, so
+	// mark both permanent.
 	execFunc := func(execCtx context.Context) error {
 		err := step.Execute(execCtx, stepCfg.Config)
-		// AgentExitError is a success signal (plan complete), not a failure to retry
-		// Mark it as permanent so retry logic doesn't treat it as transient
-		if _, isExit := steps.IsAgentExitError(err); isExit {
-			return resilience.NewPermanentError(err)
-		}
-		return err
+		return resilience.MarkPermanent(err, isAgentExit, steps.IsClaudeUsageError)
 	}
 
 	// Wrap with timeout if configured
@@ -403,12 +936,30 @@ func (l *Loop) executeStepWithResilience(ctx context.Context, stepCfg config.Ste
 		}
 	}
 
+	// Agent steps each call Claude, so take a token first. Waiting for it
+	// doesn't count against the step timeout, and retries wait again.
+	if stepCfg.Type == "agent" && l.claudeLimiter != nil {
+		limiter := l.claudeLimiter
+		limitedFunc := execFunc
+		execFunc = func(execCtx context.Context) error {
+			if err := limiter.Wait(execCtx); err != nil {
+				return resilience.NewPermanentError(err)
+			}
+			return limitedFunc(execCtx)
+		}
+	}
+
 	// Execute through circuit breaker
 	cbErr := cb.Execute(ctx, func(cbCtx context.Context) error {
 		// Update display callback for retries
 		callback := func(attempt int, err error, nextDelay time.Duration) {
 			retryAttempt = attempt
 			l.status.StepWithRetry(l.state.LoopNumber, stepNum, totalSteps, stepCfg.Name, attempt, stepCfg.MaxRetries)
+			l.emit(tracker.EventRetry, stepCfg.Name, map[string]any{
+				"attempt":       attempt,
+				"error":         err.Error(),
+				"next_delay_ms": nextDelay.Milliseconds(),
+			})
 			l.logger.Debug("Retrying step",
 				logger.F("step", stepCfg.Name),
 				logger.F("attempt", attempt),
@@ -424,6 +975,7 @@ func (l *Loop) executeStepWithResilience(ctx context.Context, stepCfg config.Ste
 	// Handle circuit breaker open error
 	if cbErr == resilience.ErrCircuitOpen {
 		l.status.CircuitOpen(l.state.LoopNumber, stepNum, totalSteps, stepCfg.Name)
+		l.emit(tracker.EventCircuitOpen, stepCfg.Name, nil)
 		return StepResult{
 			StepName:    stepCfg.Name,
 			Success:     false,
@@ -3,10 +3,20 @@ package loop
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/chr1sbest/wiggum/internal/agent"
 	"github.com/chr1sbest/wiggum/internal/config"
 	"github.com/chr1sbest/wiggum/internal/logger"
+	"github.com/chr1sbest/wiggum/internal/loop/steps"
+	"github.com/chr1sbest/wiggum/internal/resilience"
+	"github.com/chr1sbest/wiggum/internal/status"
+	"github.com/chr1sbest/wiggum/internal/tracker"
 )
 
 // testStep is a simple step for testing.
@@ -74,3 +84,828 @@ func TestStepRegistry(t *testing.T) {
 		t.Errorf("unexpected registered types: %v", types)
 	}
 }
+
+// costStep records a fixed cost in run metrics each time it executes.
+type costStep struct {
+	trk   *tracker.Writer
+	cost  float64
+	calls int
+}
+
+func (s *costStep) Name() string { return "cost" }
+func (s *costStep) Type() string { return "cost" }
+func (s *costStep) Execute(ctx context.Context, cfg json.RawMessage) error {
+	s.calls++
+	s.trk.AddUsage("run", tracker.UsageDelta{CostUSD: s.cost})
+	return nil
+}
+
+func TestLoopRunStopsAtBudget(t *testing.T) {
+	dir := t.TempDir()
+	trk := tracker.NewWriter(dir)
+	// Spend from earlier runs must not count against this run's budget.
+	trk.AddUsage("previous", tracker.UsageDelta{CostUSD: 10})
+
+	cfg := &config.Config{
+		Name:  "budget",
+		Steps: []config.StepConfig{{Type: "cost", Name: "cost", Config: json.RawMessage(`{}`)}},
+	}
+	step := &costStep{trk: trk, cost: 0.4}
+	registry := NewStepRegistry()
+	registry.Register("cost", func() Step { return step })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.EnableRunTracking("run", dir)
+	l.SetBudget(1.0)
+
+	err := l.Run(context.Background())
+	var budgetErr *BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("expected BudgetExceededError, got %v", err)
+	}
+	if step.calls != 3 {
+		t.Errorf("expected 3 iterations before stopping, got %d", step.calls)
+	}
+	if budgetErr.BudgetUSD != 1.0 || budgetErr.SpentUSD < 1.0 {
+		t.Errorf("unexpected budget error: %+v", budgetErr)
+	}
+}
+
+func TestLoopRunStopsAtMaxLoops(t *testing.T) {
+	cfg := &config.Config{
+		Name:  "max-loops",
+		Steps: []config.StepConfig{{Type: "test", Name: "step1", Config: json.RawMessage(`{}`)}},
+	}
+	registry := NewStepRegistry()
+	registry.Register("test", func() Step { return &testStep{} })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.SetMaxLoops(3)
+
+	err := l.Run(context.Background())
+	var maxErr *MaxLoopsReachedError
+	if !errors.As(err, &maxErr) {
+		t.Fatalf("expected MaxLoopsReachedError, got %v", err)
+	}
+	if maxErr.MaxLoops != 3 {
+		t.Errorf("expected MaxLoops 3, got %d", maxErr.MaxLoops)
+	}
+	if got := l.State().LoopNumber; got != 3 {
+		t.Errorf("expected 3 iterations, got %d", got)
+	}
+}
+
+// failStep always fails and counts its executions.
+type failStep struct{ calls int }
+
+func (s *failStep) Name() string { return "fail" }
+func (s *failStep) Type() string { return "fail" }
+func (s *failStep) Execute(ctx context.Context, cfg json.RawMessage) error {
+	s.calls++
+	return errors.New("broken")
+}
+
+func TestLoopRunBacksOffFailuresWithZeroStepDelay(t *testing.T) {
+	cfg := &config.Config{
+		Name:  "backoff",
+		Steps: []config.StepConfig{{Type: "fail", Name: "step1", Config: json.RawMessage(`{}`)}},
+	}
+	step := &failStep{}
+	registry := NewStepRegistry()
+	registry.Register("fail", func() Step { return step })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.DefaultStepDelay/2)
+	defer cancel()
+	if err := l.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to stop the loop, got %v", err)
+	}
+	if step.calls != 1 {
+		t.Errorf("expected one failed iteration before the backoff, got %d", step.calls)
+	}
+}
+
+func TestLoopCircuitStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "circuit_state.json")
+	cfg := &config.Config{Name: "cb", Steps: []config.StepConfig{{Type: "test", Name: "step1"}}}
+
+	l := NewLoop(cfg, NewStepRegistry(), logger.NewNoopLogger())
+	if err := l.RestoreCircuitState(path); err != nil {
+		t.Fatalf("missing file should not error: %v", err)
+	}
+
+	cb := l.circuitBreakers.Get("step1", &resilience.CircuitBreakerConfig{Threshold: 1, ResetAfter: time.Hour})
+	_ = cb.Execute(context.Background(), func(ctx context.Context) error { return errors.New("fail") })
+	if err := l.SaveCircuitState(path); err != nil {
+		t.Fatalf("SaveCircuitState failed: %v", err)
+	}
+
+	restarted := NewLoop(cfg, NewStepRegistry(), logger.NewNoopLogger())
+	if err := restarted.RestoreCircuitState(path); err != nil {
+		t.Fatalf("RestoreCircuitState failed: %v", err)
+	}
+	got := restarted.circuitBreakers.Get("step1", &resilience.CircuitBreakerConfig{Threshold: 1, ResetAfter: time.Hour})
+	if got.State() != resilience.CircuitOpen {
+		t.Errorf("expected open breaker after restart, got %v", got.State())
+	}
+}
+
+// usageStep always fails with a Claude usage-limit error.
+type usageStep struct{ calls int }
+
+func (s *usageStep) Name() string { return "usage" }
+func (s *usageStep) Type() string { return "usage" }
+func (s *usageStep) Execute(ctx context.Context, cfg json.RawMessage) error {
+	s.calls++
+	return &steps.ClaudeUsageError{Details: "out of extra usage"}
+}
+
+func TestLoopRunStopsOnUsageErrorWithoutRetry(t *testing.T) {
+	cfg := &config.Config{
+		Name: "usage",
+		Steps: []config.StepConfig{
+			{Type: "usage", Name: "claude", MaxRetries: 3, RetryDelay: "1ms", ContinueOnError: true},
+		},
+	}
+	step := &usageStep{}
+	registry := NewStepRegistry()
+	registry.Register("usage", func() Step { return step })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+
+	err := l.Run(context.Background())
+	if !steps.IsClaudeUsageError(err) {
+		t.Fatalf("expected ClaudeUsageError from Run, got %v", err)
+	}
+	if step.calls != 1 {
+		t.Errorf("expected usage error not to be retried, got %d calls", step.calls)
+	}
+}
+
+func TestLoopRunOnceEmitsEvents(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Name: "events",
+		Steps: []config.StepConfig{
+			{Type: "noop", Name: "first"},
+			{Type: "noop", Name: "second"},
+		},
+	}
+	registry := NewStepRegistry()
+	registry.Register("noop", func() Step { return steps.NewNoopStep() })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.EnableRunTracking("run-1", dir)
+
+	if err := l.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "events.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e tracker.Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid event line %q: %v", line, err)
+		}
+		if e.RunID != "run-1" || e.Loop != 1 {
+			t.Errorf("unexpected run/loop on event: %+v", e)
+		}
+		got = append(got, e.Type+":"+e.Step)
+	}
+
+	want := []string{
+		"loop_start:",
+		"step_start:first", "step_success:first",
+		"step_start:second", "step_success:second",
+		"complete:",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("event sequence = %v, want %v", got, want)
+	}
+}
+
+func TestLoopRunOnceWritesPrometheus(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Name:  "prom",
+		Steps: []config.StepConfig{{Type: "noop", Name: "first"}},
+	}
+	registry := NewStepRegistry()
+	registry.Register("noop", func() Step { return steps.NewNoopStep() })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.EnableRunTracking("run-1", dir)
+	l.EnablePrometheus("sonnet")
+
+	for i := 0; i < 2; i++ {
+		if err := l.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "metrics.prom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `ralph_loops_total{run_id="run-1",model="sonnet"} 2`) {
+		t.Errorf("metrics.prom after two loops:\n%s", data)
+	}
+}
+
+func TestLoopStreamsStepOutputToStatus(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cfg := &config.Config{
+		Name:  "stream",
+		Steps: []config.StepConfig{{Type: "command", Name: "build", Config: json.RawMessage(`{"command":"echo compiling"}`)}},
+	}
+	registry := NewStepRegistry()
+	registry.Register("command", func() Step { return steps.NewCommandStep() })
+
+	var out strings.Builder
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.SetStatusDisplay(status.NewJSONWithWriter(&out))
+	if err := l.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if !strings.Contains(out.String(), `"step":"build","state":"output"`) || !strings.Contains(out.String(), `"output":"compiling"`) {
+		t.Errorf("status output missing streamed line:\n%s", out.String())
+	}
+}
+
+// prdWriteStep rewrites prd.json, standing in for an agent finishing tasks.
+type prdWriteStep struct {
+	path    string
+	content string
+}
+
+func (s *prdWriteStep) Name() string { return "prd-write" }
+func (s *prdWriteStep) Type() string { return "prd-write" }
+func (s *prdWriteStep) Execute(ctx context.Context, cfg json.RawMessage) error {
+	return os.WriteFile(s.path, []byte(s.content), 0644)
+}
+
+func TestLoopRunOnceReportsDoneTasks(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := filepath.Join(dir, "prd.json")
+	before := `{"tasks":[{"id":"T1","title":"a","status":"done"},{"id":"T2","title":"b","status":"in_progress"},{"id":"T3","title":"c","status":"todo"}]}`
+	after := `{"tasks":[{"id":"T1","title":"a","status":"done"},{"id":"T2","title":"b","status":"done"},{"id":"T3","title":"c","status":"todo"}]}`
+	if err := os.WriteFile(prdPath, []byte(before), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name:  "done",
+		Steps: []config.StepConfig{{Type: "prd-write", Name: "agent"}},
+	}
+	registry := NewStepRegistry()
+	registry.Register("prd-write", func() Step { return &prdWriteStep{path: prdPath, content: after} })
+
+	var out strings.Builder
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.SetPRDPath(prdPath)
+	l.SetStatusDisplay(status.NewJSONWithWriter(&out))
+	l.EnableRunTracking("run-1", dir)
+
+	if err := l.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "events.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var done *tracker.Event
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e tracker.Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid event line %q: %v", line, err)
+		}
+		if e.Type == tracker.EventTasksDone {
+			done = &e
+		}
+	}
+	if done == nil {
+		t.Fatalf("no %s event in %s", tracker.EventTasksDone, data)
+	}
+	if ids, _ := done.Fields["task_ids"].([]any); len(ids) != 1 || ids[0] != "T2" {
+		t.Errorf("task_ids = %v, want [T2]", done.Fields["task_ids"])
+	}
+
+	if !strings.Contains(out.String(), `"done_task_ids":["T2"]`) {
+		t.Errorf("status output missing done_task_ids: %s", out.String())
+	}
+}
+
+func TestLoopRunMarksStalledTaskFailed(t *testing.T) {
+	prdPath := filepath.Join(t.TempDir(), "prd.json")
+	prd := `{"tasks":[{"id":"T1","title":"Stuck","status":"in_progress"},{"id":"T2","title":"Next","status":"todo"}]}`
+	if err := os.WriteFile(prdPath, []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name:           "stall",
+		StallThreshold: 2,
+		Steps:          []config.StepConfig{{Type: "test", Name: "step1", Config: json.RawMessage(`{}`)}},
+	}
+	registry := NewStepRegistry()
+	registry.Register("test", func() Step { return &testStep{} })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.SetPRDPath(prdPath)
+	l.SetMaxLoops(3)
+
+	err := l.Run(context.Background())
+	var maxErr *MaxLoopsReachedError
+	if !errors.As(err, &maxErr) {
+		t.Fatalf("expected MaxLoopsReachedError, got %v", err)
+	}
+
+	data, err := os.ReadFile(prdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Tasks []struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Tasks[0].Status != "failed" {
+		t.Errorf("expected stalled task T1 to be failed, got %q", got.Tasks[0].Status)
+	}
+	if got.Tasks[1].Status != "todo" {
+		t.Errorf("expected T2 untouched, got %q", got.Tasks[1].Status)
+	}
+}
+
+// clockStep advances a fake clock by d each run, like an agent call that
+// takes d and never finishes its task.
+type clockStep struct {
+	now *time.Time
+	d   time.Duration
+}
+
+func (s *clockStep) Name() string { return "clock" }
+func (s *clockStep) Type() string { return "clock" }
+func (s *clockStep) Execute(ctx context.Context, cfg json.RawMessage) error {
+	*s.now = s.now.Add(s.d)
+	return nil
+}
+
+func TestLoopRunMarksTimedOutTaskFailed(t *testing.T) {
+	prdPath := filepath.Join(t.TempDir(), "prd.json")
+	prd := `{"tasks":[{"id":"T1","title":"Slow","status":"in_progress"},{"id":"T2","title":"Next","status":"todo"}]}`
+	if err := os.WriteFile(prdPath, []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// T1 is worked on for two 60m loops, passing the 100m timeout; T2 then
+	// gets two loops of its own and stays within it.
+	cfg := &config.Config{
+		Name:        "timeout",
+		TaskTimeout: "100m",
+		Steps:       []config.StepConfig{{Type: "clock", Name: "step1", Config: json.RawMessage(`{}`)}},
+	}
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	registry := NewStepRegistry()
+	registry.Register("clock", func() Step { return &clockStep{now: &now, d: 60 * time.Minute} })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.now = func() time.Time { return now }
+	l.SetStepDelay(0)
+	l.SetPRDPath(prdPath)
+	l.SetMaxLoops(4)
+
+	err := l.Run(context.Background())
+	var maxErr *MaxLoopsReachedError
+	if !errors.As(err, &maxErr) {
+		t.Fatalf("expected MaxLoopsReachedError, got %v", err)
+	}
+
+	data, err := os.ReadFile(prdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Tasks []struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Tasks[0].Status != "failed" {
+		t.Errorf("expected timed-out task T1 to be failed, got %q", got.Tasks[0].Status)
+	}
+	if got.Tasks[1].Status != "todo" {
+		t.Errorf("expected T2 within its timeout, got %q", got.Tasks[1].Status)
+	}
+}
+
+func TestLoopRunStopsAgentWithoutProgress(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile("PROMPT.md", []byte("Work on the next task."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prd := `{"tasks":[{"id":"T1","title":"Never done","status":"todo"}]}`
+	if err := os.WriteFile("prd.json", []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The fake claude records each call and never completes a task.
+	claude := filepath.Join(dir, "claude")
+	if err := os.WriteFile(claude, []byte("#!/bin/sh\necho call >> calls.log\necho '{\"result\":\"no luck\"}'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	agentCfg, _ := json.Marshal(map[string]any{
+		"prd_file":          "prd.json",
+		"claude_binary":     claude,
+		"log_dir":           "",
+		"no_progress_loops": 2,
+	})
+	cfg := &config.Config{
+		Name:  "no-progress",
+		Steps: []config.StepConfig{{Type: "agent", Name: "agent", Config: agentCfg}},
+	}
+	registry := NewStepRegistry()
+	registry.Register("agent", func() Step {
+		s := steps.NewAgentStep()
+		s.DisableStatusRefresh()
+		return s
+	})
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.SetPRDPath("prd.json")
+	l.SetMaxLoops(5)
+
+	err := l.Run(context.Background())
+	exitErr, ok := steps.IsAgentExitError(err)
+	if !ok || exitErr.Reason != agent.ExitReasonNoProgress {
+		t.Fatalf("expected no_progress exit, got %v", err)
+	}
+	calls, err := os.ReadFile("calls.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(calls), "call"); n != 2 {
+		t.Errorf("expected the exit after 2 loops without progress, got %d agent calls", n)
+	}
+}
+
+// namedStep records which configured step invoked it.
+type namedStep struct {
+	ran *[]string
+	err error
+}
+
+func (s *namedStep) Name() string { return "named" }
+func (s *namedStep) Type() string { return "named" }
+func (s *namedStep) Execute(ctx context.Context, cfg json.RawMessage) error {
+	var c struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(cfg, &c)
+	*s.ran = append(*s.ran, c.ID)
+	return s.err
+}
+
+func TestLoopRunStopsWhenTasksBlocked(t *testing.T) {
+	prdPath := filepath.Join(t.TempDir(), "prd.json")
+	prd := `{"tasks":[{"id":"T1","title":"Schema","status":"failed"},{"id":"T2","title":"API","status":"todo","depends_on":["T1"]}]}`
+	if err := os.WriteFile(prdPath, []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name:  "blocked",
+		Steps: []config.StepConfig{{Type: "test", Name: "step1", Config: json.RawMessage(`{}`)}},
+	}
+	step := &testStep{}
+	registry := NewStepRegistry()
+	registry.Register("test", func() Step { return step })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.SetPRDPath(prdPath)
+	l.SetMaxLoops(3)
+
+	err := l.Run(context.Background())
+	exitErr, ok := steps.IsAgentExitError(err)
+	if !ok || exitErr.Reason != agent.ExitReasonTasksBlocked {
+		t.Fatalf("expected tasks_blocked exit, got %v", err)
+	}
+	if step.executed {
+		t.Error("expected no steps to run when every task is blocked")
+	}
+	if l.State().Status != StatusBlocked {
+		t.Errorf("expected status BLOCKED, got %s", l.State().Status)
+	}
+}
+
+func TestLoopRunStep(t *testing.T) {
+	disabled := false
+	cfg := &config.Config{
+		Name: "test-config",
+		Steps: []config.StepConfig{
+			{Type: "named", Name: "first", Config: json.RawMessage(`{"id": "first"}`)},
+			{Type: "named", Name: "second", Config: json.RawMessage(`{"id": "second"}`)},
+			{Type: "named", Name: "off", Enabled: &disabled, Config: json.RawMessage(`{"id": "off"}`)},
+		},
+	}
+
+	var ran []string
+	registry := NewStepRegistry()
+	registry.Register("named", func() Step { return &namedStep{ran: &ran} })
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+
+	result, err := l.RunStep(context.Background(), "second")
+	if err != nil {
+		t.Fatalf("RunStep: %v", err)
+	}
+	if !result.Success || result.StepName != "second" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(ran) != 1 || ran[0] != "second" {
+		t.Errorf("expected only step 'second' to run, ran %v", ran)
+	}
+
+	if _, err := l.RunStep(context.Background(), "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, err := l.RunStep(context.Background(), "off"); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("expected disabled error, got %v", err)
+	}
+	if len(ran) != 1 {
+		t.Errorf("missing/disabled steps should not run, ran %v", ran)
+	}
+}
+
+func TestLoopRunStepReportsFailure(t *testing.T) {
+	cfg := &config.Config{
+		Name:  "test-config",
+		Steps: []config.StepConfig{{Type: "named", Name: "broken", MaxRetries: 1, RetryDelay: "1ms", Config: json.RawMessage(`{}`)}},
+	}
+	var ran []string
+	registry := NewStepRegistry()
+	registry.Register("named", func() Step { return &namedStep{ran: &ran, err: errors.New("boom")} })
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+
+	result, err := l.RunStep(context.Background(), "broken")
+	if err != nil {
+		t.Fatalf("RunStep: %v", err)
+	}
+	if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "boom") {
+		t.Errorf("expected failed result with step error, got %+v", result)
+	}
+	if result.RetryAttempt != 1 || len(ran) != 2 {
+		t.Errorf("expected one retry (2 attempts), got retries=%d attempts=%d", result.RetryAttempt, len(ran))
+	}
+}
+
+func TestLoopRunOnceFollowsDependsOn(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test-config",
+		Steps: []config.StepConfig{
+			{Type: "named", Name: "commit", DependsOn: []string{"agent"}, Config: json.RawMessage(`{"id": "commit"}`)},
+			{Type: "named", Name: "readme", Config: json.RawMessage(`{"id": "readme"}`)},
+			{Type: "named", Name: "agent", Config: json.RawMessage(`{"id": "agent"}`)},
+		},
+	}
+
+	var ran []string
+	registry := NewStepRegistry()
+	registry.Register("named", func() Step { return &namedStep{ran: &ran} })
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+
+	if err := l.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	want := []string{"readme", "agent", "commit"}
+	if strings.Join(ran, ",") != strings.Join(want, ",") {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if cfg.Steps[0].Name != "commit" {
+		t.Errorf("caller's config should not be reordered")
+	}
+}
+
+func TestLoopRunOnceSkipsStepsWhenFalse(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test-config",
+		Steps: []config.StepConfig{
+			{Type: "named", Name: "first", Config: json.RawMessage(`{"id": "first"}`)},
+			{Type: "named", Name: "later", When: "loop.number > 1", Config: json.RawMessage(`{"id": "later"}`)},
+			{Type: "named", Name: "after-first", When: `success("first")`, Config: json.RawMessage(`{"id": "after-first"}`)},
+		},
+	}
+
+	var ran []string
+	registry := NewStepRegistry()
+	registry.Register("named", func() Step { return &namedStep{ran: &ran} })
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+
+	for i := 0; i < 2; i++ {
+		if err := l.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce failed: %v", err)
+		}
+	}
+	want := "first,after-first,first,later,after-first"
+	if got := strings.Join(ran, ","); got != want {
+		t.Errorf("ran %s, want %s", got, want)
+	}
+}
+
+// scriptedConfirmer answers step confirmations from a fixed list.
+type scriptedConfirmer struct {
+	answers []StepDecision
+	asked   []string
+}
+
+func (c *scriptedConfirmer) ConfirmStep(ctx context.Context, p StepPrompt) StepDecision {
+	c.asked = append(c.asked, p.Step.Name)
+	d := c.answers[0]
+	c.answers = c.answers[1:]
+	return d
+}
+
+func TestLoopRunOnceConfirmsEachStep(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test-config",
+		Steps: []config.StepConfig{
+			{Type: "named", Name: "first", Config: json.RawMessage(`{"id": "first"}`)},
+			{Type: "named", Name: "second", Config: json.RawMessage(`{"id": "second"}`)},
+		},
+	}
+
+	var ran []string
+	registry := NewStepRegistry()
+	registry.Register("named", func() Step { return &namedStep{ran: &ran} })
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	confirmer := &scriptedConfirmer{answers: []StepDecision{StepSkip, StepRun, StepRun, StepQuit}}
+	l.SetStepConfirmer(confirmer)
+
+	if err := l.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if got := strings.Join(ran, ","); got != "second" {
+		t.Errorf("ran %s, want only second after skipping first", got)
+	}
+
+	err := l.RunOnce(context.Background())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled after quit, got %v", err)
+	}
+	if got := strings.Join(ran, ","); got != "second,first" {
+		t.Errorf("ran %s, want second,first", got)
+	}
+	if got := strings.Join(confirmer.asked, ","); got != "first,second,first,second" {
+		t.Errorf("asked about %s", got)
+	}
+}
+
+func TestRetryConfigFor(t *testing.T) {
+	multiplier, jitter := 1.5, 0.0
+	tests := []struct {
+		name string
+		step config.StepConfig
+		want resilience.RetryConfig
+	}{
+		{
+			name: "defaults",
+			step: config.StepConfig{MaxRetries: 2},
+			want: resilience.RetryConfig{MaxRetries: 2, InitDelay: time.Second, MaxDelay: 30 * time.Second, Multiplier: 2.0, Jitter: 0.1},
+		},
+		{
+			name: "configured",
+			step: config.StepConfig{
+				MaxRetries:      3,
+				RetryDelay:      "200ms",
+				MaxRetryDelay:   "5s",
+				RetryMultiplier: &multiplier,
+				RetryJitter:     &jitter,
+			},
+			want: resilience.RetryConfig{MaxRetries: 3, InitDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second, Multiplier: 1.5, Jitter: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryConfigFor(tt.step); got != tt.want {
+				t.Errorf("retryConfigFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// timedStep records when each execution started.
+type timedStep struct{ starts []time.Time }
+
+func (s *timedStep) Name() string { return "timed" }
+func (s *timedStep) Type() string { return "timed" }
+func (s *timedStep) Execute(ctx context.Context, cfg json.RawMessage) error {
+	s.starts = append(s.starts, time.Now())
+	return nil
+}
+
+func TestLoopRateLimitsAgentSteps(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	cfg := &config.Config{
+		Name:         "rps",
+		MaxClaudeRPS: float64(time.Second / interval),
+		Steps: []config.StepConfig{
+			{Type: "agent", Name: "first"},
+			{Type: "noop", Name: "between"},
+			{Type: "agent", Name: "second"},
+			{Type: "agent", Name: "third"},
+		},
+	}
+	agentStep := &timedStep{}
+	noopStep := &timedStep{}
+	registry := NewStepRegistry()
+	registry.Register("agent", func() Step { return agentStep })
+	registry.Register("noop", func() Step { return noopStep })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	if err := l.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	if len(agentStep.starts) != 3 {
+		t.Fatalf("expected 3 agent executions, got %d", len(agentStep.starts))
+	}
+	for i := 1; i < len(agentStep.starts); i++ {
+		if gap := agentStep.starts[i].Sub(agentStep.starts[i-1]); gap < interval-5*time.Millisecond {
+			t.Errorf("agent execution %d started %s after the previous one, want >= %s", i+1, gap, interval)
+		}
+	}
+	// The noop step doesn't take a token, so it runs right after the first agent step.
+	if gap := noopStep.starts[0].Sub(agentStep.starts[0]); gap >= interval-5*time.Millisecond {
+		t.Errorf("noop step waited %s, want no rate limiting", gap)
+	}
+}
+
+func TestLoopRunOnceRotatesLearnings(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := filepath.Join(dir, "prd.json")
+	if err := os.WriteFile(prdPath, []byte(`{"tasks":[{"id":"T1","title":"Work","status":"todo"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	learnings := "- Old learning " + strings.Repeat("o", 200) + "\n\n- New learning\n"
+	if err := os.WriteFile(filepath.Join(dir, "learnings.md"), []byte(learnings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name:      "learnings",
+		Learnings: &config.LearningsConfig{MaxSizeBytes: 100},
+		Steps:     []config.StepConfig{{Type: "test", Name: "step1", Config: json.RawMessage(`{}`)}},
+	}
+	registry := NewStepRegistry()
+	registry.Register("test", func() Step { return &testStep{} })
+
+	l := NewLoop(cfg, registry, logger.NewNoopLogger())
+	l.SetStepDelay(0)
+	l.SetPRDPath(prdPath)
+	if err := l.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	kept, err := os.ReadFile(filepath.Join(dir, "learnings.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(kept)) != "- New learning" {
+		t.Errorf("learnings.md = %q, want only the newest entry", kept)
+	}
+	archived, err := os.ReadFile(filepath.Join(dir, "learnings.archive.md"))
+	if err != nil {
+		t.Fatalf("archive not written: %v", err)
+	}
+	if !strings.Contains(string(archived), "- Old learning") {
+		t.Errorf("archive missing old learning:\n%s", archived)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/logger"
 )
 
 // Step defines the interface for executable steps in the loop.
@@ -14,6 +17,35 @@ type Step interface {
 	Execute(ctx context.Context, config json.RawMessage) error
 }
 
+// ConfigValidator is optionally implemented by steps that can check their
+// config block before the loop starts. Steps without it accept any config.
+type ConfigValidator interface {
+	Validate(config json.RawMessage) error
+}
+
+// OutputStreamer is optionally implemented by steps that produce output
+// while they run. The loop sets fn before each execution so lines reach the
+// status display as they arrive instead of only when the step ends.
+type OutputStreamer interface {
+	SetOutputFunc(fn func(line string))
+}
+
+// LoggerSetter is optionally implemented by steps that log details of their
+// own. The loop sets the run's logger, tagged with the step name, before each
+// execution.
+type LoggerSetter interface {
+	SetLogger(l logger.Logger)
+}
+
+// ExitDetectorCarrier is optionally implemented by steps that track exit
+// conditions, such as no progress, across loops. Step instances are created
+// fresh for each execution, so the loop keeps each step's detector and hands
+// it back before every later execution.
+type ExitDetectorCarrier interface {
+	ExitDetector() *agent.ExitDetector
+	SetExitDetector(d *agent.ExitDetector)
+}
+
 // StepFactory creates a new step instance.
 type StepFactory func() Step
 
@@ -49,6 +81,19 @@ func (r *StepRegistry) Get(stepType string) (Step, error) {
 	return factory(), nil
 }
 
+// ValidateStepConfig validates rawConfig for the given step type. It is a no-op
+// for unknown types and for steps that don't implement ConfigValidator.
+func (r *StepRegistry) ValidateStepConfig(stepType string, rawConfig json.RawMessage) error {
+	step, err := r.Get(stepType)
+	if err != nil {
+		return nil
+	}
+	if v, ok := step.(ConfigValidator); ok {
+		return v.Validate(rawConfig)
+	}
+	return nil
+}
+
 // RegisteredTypes returns a list of all registered step types.
 func (r *StepRegistry) RegisteredTypes() []string {
 	r.mu.RLock()
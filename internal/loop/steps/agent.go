@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"time"
 
 	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/logger"
 	"github.com/chr1sbest/wiggum/internal/tracker"
 )
 
@@ -16,7 +18,8 @@ import (
 // This is the core orchestration - other concerns are split into:
 //   - agent_config.go:  configuration
 //   - agent_errors.go:  error types
-//   - agent_claude.go:  Claude CLI execution
+//   - agent_claude.go:  agent CLI execution and per-agent argv builders
+//   - agent_mcp.go:     MCP server config
 //   - agent_logging.go: output logging
 //   - agent_status.go:  terminal status display
 type AgentStep struct {
@@ -24,14 +27,41 @@ type AgentStep struct {
 	session      *agent.SessionManager
 	exitDetector *agent.ExitDetector
 	loopCount    int
+
+	// noStatusRefresh stops the step from redrawing the terminal status
+	// while the agent runs, for when progress is reported another way.
+	noStatusRefresh bool
+
+	// logger receives debug details from the loop; nil when run standalone.
+	logger logger.Logger
 }
 
 // NewAgentStep creates a new agent step
 func NewAgentStep() *AgentStep {
-	return &AgentStep{
-		name:         "agent",
-		exitDetector: agent.NewExitDetector(),
-	}
+	return &AgentStep{name: "agent"}
+}
+
+// DisableStatusRefresh stops the step from redrawing the terminal status
+// display while the agent runs.
+func (s *AgentStep) DisableStatusRefresh() {
+	s.noStatusRefresh = true
+}
+
+// SetLogger sets the logger for debug details, such as skipped context files.
+func (s *AgentStep) SetLogger(l logger.Logger) {
+	s.logger = l
+}
+
+// ExitDetector returns the step's no-progress tracking, nil before its
+// first execution.
+func (s *AgentStep) ExitDetector() *agent.ExitDetector {
+	return s.exitDetector
+}
+
+// SetExitDetector carries no-progress tracking over from an earlier
+// execution, so no_progress_loops counts loops rather than step instances.
+func (s *AgentStep) SetExitDetector(d *agent.ExitDetector) {
+	s.exitDetector = d
 }
 
 func (s *AgentStep) Name() string { return s.name }
@@ -60,6 +90,9 @@ func (s *AgentStep) Execute(ctx context.Context, rawConfig json.RawMessage) erro
 	}
 
 	// Initialize session manager
+	if s.session == nil && cfg.SessionName != "" {
+		s.session = agent.NewNamedSessionManager(agent.DefaultSessionsDir, cfg.SessionName, cfg.SessionExpiryHours)
+	}
 	if s.session == nil {
 		s.session = agent.NewSessionManager(
 			cfg.SessionFile,
@@ -68,6 +101,10 @@ func (s *AgentStep) Execute(ctx context.Context, rawConfig json.RawMessage) erro
 		)
 	}
 
+	if s.exitDetector == nil {
+		s.exitDetector = agent.NewExitDetector(cfg.exitDetectorConfig())
+	}
+
 	// Get or create session
 	sessionState, isNew, err := s.session.GetOrCreate()
 	if err != nil {
@@ -102,29 +139,40 @@ func (s *AgentStep) Execute(ctx context.Context, rawConfig json.RawMessage) erro
 			case <-stopRefresh:
 				return
 			case <-ticker.C:
-				s.refreshStatus(cfg.PrdFile, false)
+				if !s.noStatusRefresh {
+					s.refreshStatus(cfg.PrdFile, false)
+				}
 			}
 		}
 	}()
 
-	// Execute Claude
-	output, err := s.executeClaudeCode(ctx, cfg, string(promptContent), loopContext)
+	// Execute the agent, streaming output to a per-loop log that can be tailed
+	logDir := cfg.logDir()
+	live := s.openStreamLog(logDir, s.loopCount)
+	var liveWriter io.Writer
+	if live != nil {
+		liveWriter = live
+	}
+	output, err := s.executeAgent(ctx, cfg, string(promptContent), loopContext, liveWriter)
 	close(stopRefresh)
+	if live != nil {
+		live.Close()
+	}
 	if err != nil {
-		s.saveOutput(cfg.LogDir, output, s.loopCount)
-		return fmt.Errorf("claude execution failed: %w", err)
+		s.saveOutput(logDir, output, s.loopCount)
+		return fmt.Errorf("%s execution failed: %w", cfg.agentType(), err)
 	}
 
 	// Save output
-	s.saveOutput(cfg.LogDir, output, s.loopCount)
+	s.saveOutput(logDir, output, s.loopCount)
 
 	// Write marker file if configured
 	if cfg.MarkerFile != "" {
 		_ = os.WriteFile(cfg.MarkerFile, []byte(time.Now().Format(time.RFC3339)), 0644)
 	}
 
-	// Track usage metrics
-	if delta, ok := tracker.ParseClaudeUsageFromOutput(output); ok {
+	// Track usage metrics (only Claude reports usage in a parseable form)
+	if delta, ok := tracker.ParseClaudeUsageFromOutput(output); ok && cfg.agentType() == AgentTypeClaude {
 		runID := ""
 		if rs, err := trackerWriter.LoadRunState(); err == nil && rs != nil {
 			runID = rs.RunID
@@ -132,17 +180,25 @@ func (s *AgentStep) Execute(ctx context.Context, rawConfig json.RawMessage) erro
 		if runID == "" {
 			runID = sessionState.SessionID
 		}
-		trackerWriter.AddUsage(runID, tracker.UsageDelta{
-			InputTokens:  delta.InputTokens,
-			OutputTokens: delta.OutputTokens,
-			TotalTokens:  delta.TotalTokens,
-			CostUSD:      delta.CostUSD,
-		})
+		usage := tracker.UsageDelta{
+			InputTokens:         delta.InputTokens,
+			OutputTokens:        delta.OutputTokens,
+			TotalTokens:         delta.TotalTokens,
+			CacheCreationTokens: delta.CacheCreationTokens,
+			CacheReadTokens:     delta.CacheReadTokens,
+			CostUSD:             delta.CostUSD,
+		}
+		trackerWriter.AddUsage(runID, usage)
+		// Attribute to the task that was current when the call started.
+		taskID := ""
+		if prdStatus != nil {
+			taskID = prdStatus.CurrentTaskID
+		}
+		trackerWriter.AddTaskUsage(taskID, usage)
 	}
 
 	// Check exit conditions after execution
 	prdStatusAfter, _ := agent.LoadPRDStatus(cfg.PrdFile)
-	planComplete := prdStatusAfter != nil && prdStatusAfter.IsComplete()
 	completedAfter := 0
 	if prdStatusAfter != nil {
 		completedAfter = prdStatusAfter.CompletedTasks
@@ -151,7 +207,7 @@ func (s *AgentStep) Execute(ctx context.Context, rawConfig json.RawMessage) erro
 	// Mark loop complete for no-progress tracking (once per loop, not per check)
 	s.exitDetector.MarkLoopComplete(completedAfter)
 
-	if exitReason := s.exitDetector.Check(planComplete, completedAfter); exitReason != agent.ExitReasonNone {
+	if exitReason := s.exitDetector.CheckStatus(prdStatusAfter); exitReason != agent.ExitReasonNone {
 		return &AgentExitError{Reason: exitReason}
 	}
 
@@ -1,66 +1,98 @@
 package steps
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/chr1sbest/wiggum/internal/agent"
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/logger"
 )
 
-// executeClaudeCode runs the claude CLI
-func (s *AgentStep) executeClaudeCode(ctx context.Context, cfg AgentConfig, prompt, loopContext string) (string, error) {
-	args := []string{}
+// Agent types selectable via AgentConfig.AgentType.
+const (
+	AgentTypeClaude = "claude"
+	AgentTypeAider  = "aider"
+)
 
-	// Model
-	if strings.TrimSpace(cfg.Model) != "" {
-		args = append(args, "--model", strings.TrimSpace(cfg.Model))
-	}
+// agentCommandBuilder builds the argv (excluding the binary) for one agent CLI.
+type agentCommandBuilder func(cfg AgentConfig, prompt, loopContext string) []string
 
-	// Output format
-	if cfg.OutputFormat == "json" {
-		args = append(args, "--output-format", "json")
-	}
+var agentCommandBuilders = map[string]agentCommandBuilder{
+	AgentTypeClaude: buildClaudeArgs,
+	AgentTypeAider:  buildAiderArgs,
+}
 
-	// Allowed tools
-	if cfg.AllowedTools != "" {
-		args = append(args, "--allowedTools")
-		tools := strings.Split(cfg.AllowedTools, ",")
-		for _, tool := range tools {
-			tool = strings.TrimSpace(tool)
-			if tool != "" {
-				args = append(args, tool)
-			}
+// executeAgent runs the configured agent CLI. Output is copied line by line to
+// live (if non-nil) while the process runs; the full text is returned at the end.
+func (s *AgentStep) executeAgent(ctx context.Context, cfg AgentConfig, prompt, loopContext string, live io.Writer) (string, error) {
+	build, ok := agentCommandBuilders[cfg.agentType()]
+	if !ok {
+		return "", fmt.Errorf("unknown agent_type %q", cfg.AgentType)
+	}
+	if cfg.agentType() == AgentTypeClaude {
+		mcpPath, cleanup, err := prepareMCPConfig(cfg.MCPConfig)
+		if err != nil {
+			return "", err
 		}
+		defer cleanup()
+		cfg.mcpConfigPath = mcpPath
 	}
+	args := build(cfg, prompt, loopContext)
 
-	// Skip permission prompts for autonomous operation
-	args = append(args, "--dangerously-skip-permissions")
+	// Create command
+	cmd := exec.CommandContext(ctx, cfg.binary(), args...)
+	cmd.Dir, _ = os.Getwd()
 
-	// Add loop context
-	if loopContext != "" {
-		args = append(args, "--append-system-prompt", loopContext)
+	// Stream output as it arrives, keeping the full text for usage parsing and logs
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderrPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return "", fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
-	// Add the prompt
-	args = append(args, "-p", prompt)
-
-	// Create command
-	cmd := exec.CommandContext(ctx, cfg.ClaudeBinary, args...)
-	cmd.Dir, _ = os.Getwd()
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
 
-	// Capture output
+	if live != nil {
+		live = &syncWriter{w: live}
+	}
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	// Run the command
-	err := cmd.Run()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		streamOutput(stdoutPipe, &stdout, live, "")
+	}()
+	go func() {
+		defer wg.Done()
+		streamOutput(stderrPipe, &stderr, live, "[stderr] ")
+	}()
+	// Pipes must be drained before Wait closes them.
+	wg.Wait()
+	err = cmd.Wait()
 
 	output := stdout.String()
+	if cfg.agentType() == AgentTypeClaude && cfg.OutputFormat == "stream-json" {
+		// The stream log already has every event; keep only the final
+		// result, which has the same shape as --output-format json.
+		if result, ok := claudeStreamResult(output); ok {
+			output = result
+		}
+	}
 	if stderr.Len() > 0 {
 		output += "\n--- STDERR ---\n" + stderr.String()
 	}
@@ -68,7 +100,7 @@ func (s *AgentStep) executeClaudeCode(ctx context.Context, cfg AgentConfig, prom
 	if err != nil {
 		// Preserve combined output in error classification.
 		combinedText := strings.TrimSpace(output)
-		if isClaudeUsageLimitText(combinedText) {
+		if cfg.agentType() == AgentTypeClaude && isClaudeUsageLimitText(combinedText) {
 			return output, &ClaudeUsageError{Details: combinedText}
 		}
 
@@ -89,6 +121,118 @@ func (s *AgentStep) executeClaudeCode(ctx context.Context, cfg AgentConfig, prom
 	return output, nil
 }
 
+// claudeStreamResult returns the last "result" event of stream-json output
+func claudeStreamResult(output string) (string, bool) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal([]byte(line), &event) == nil && event.Type == "result" {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+// buildClaudeArgs builds the claude CLI argv
+func buildClaudeArgs(cfg AgentConfig, prompt, loopContext string) []string {
+	args := []string{}
+
+	// Model, with friendly aliases resolved to full IDs
+	if model := config.ResolveModel(cfg.Model, cfg.ModelAliases); model != "" {
+		args = append(args, "--model", model)
+	}
+
+	// Output format; stream-json emits one event per line as Claude works
+	// and requires --verbose in print mode
+	switch cfg.OutputFormat {
+	case "json":
+		args = append(args, "--output-format", "json")
+	case "stream-json":
+		args = append(args, "--output-format", "stream-json", "--verbose")
+	}
+
+	// Allowed tools
+	if cfg.AllowedTools != "" {
+		args = append(args, "--allowedTools")
+		tools := strings.Split(cfg.AllowedTools, ",")
+		for _, tool := range tools {
+			tool = strings.TrimSpace(tool)
+			if tool != "" {
+				args = append(args, tool)
+			}
+		}
+	}
+
+	// MCP servers (resolved to a file path by executeAgent)
+	if cfg.mcpConfigPath != "" {
+		args = append(args, "--mcp-config", cfg.mcpConfigPath)
+	}
+
+	// Skip permission prompts for autonomous operation
+	args = append(args, "--dangerously-skip-permissions")
+
+	// Add loop context
+	if loopContext != "" {
+		args = append(args, "--append-system-prompt", loopContext)
+	}
+
+	// Add the prompt
+	args = append(args, "-p", prompt)
+	return args
+}
+
+// buildAiderArgs builds the aider CLI argv. Aider has no system-prompt flag,
+// so loop context is prepended to the message. Auto-commits are disabled so
+// the git-commit step stays in charge of history.
+func buildAiderArgs(cfg AgentConfig, prompt, loopContext string) []string {
+	args := []string{"--yes-always", "--no-auto-commits", "--no-pretty"}
+
+	if strings.TrimSpace(cfg.Model) != "" {
+		args = append(args, "--model", strings.TrimSpace(cfg.Model))
+	}
+
+	message := prompt
+	if loopContext != "" {
+		message = loopContext + "\n\n" + prompt
+	}
+	return append(args, "--message", message)
+}
+
+// streamOutput copies r into buf, forwarding each line to live with prefix
+func streamOutput(r io.Reader, buf *bytes.Buffer, live io.Writer, prefix string) {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			buf.WriteString(line)
+			if live != nil {
+				_, _ = io.WriteString(live, prefix+line)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// syncWriter serializes writes from the stdout and stderr goroutines
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
 // buildLoopContext creates context string for Claude
 func (s *AgentStep) buildLoopContext(cfg AgentConfig, prdStatus *agent.PRDStatus, session *agent.SessionState) string {
 	var parts []string
@@ -131,6 +275,9 @@ func (s *AgentStep) buildLoopContext(cfg AgentConfig, prdStatus *agent.PRDStatus
 		}
 	}
 
+	// Include hand-written guidance files
+	parts = append(parts, s.contextFileParts(cfg.ContextFiles)...)
+
 	// Append custom context
 	if cfg.AppendSystemPrompt != "" {
 		parts = append(parts, cfg.AppendSystemPrompt)
@@ -138,3 +285,63 @@ func (s *AgentStep) buildLoopContext(cfg AgentConfig, prdStatus *agent.PRDStatus
 
 	return strings.Join(parts, " ")
 }
+
+const (
+	// contextFileMaxBytes caps how much of one context file reaches the prompt.
+	contextFileMaxBytes = 4000
+	// contextFilesMaxBytes caps all context files together; files past the
+	// cap are cut short or left out, so list the most important first.
+	contextFilesMaxBytes = 8000
+)
+
+// contextFileParts reads the context files in order for the loop context,
+// skipping missing and empty ones.
+func (s *AgentStep) contextFileParts(paths []string) []string {
+	var parts []string
+	remaining := contextFilesMaxBytes
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			s.debug("Skipping context file", logger.F("path", path), logger.F("error", err))
+			continue
+		}
+		content := strings.TrimSpace(string(data))
+		if content == "" {
+			continue
+		}
+		if remaining <= 0 {
+			s.debug("Skipping context file over the size cap", logger.F("path", path), logger.F("cap_bytes", contextFilesMaxBytes))
+			continue
+		}
+		if limit := min(contextFileMaxBytes, remaining); len(content) > limit {
+			content = truncateUTF8(content, limit)
+			remaining -= len(content)
+			content += "..."
+		} else {
+			remaining -= len(content)
+		}
+		parts = append(parts, fmt.Sprintf("\n\nProject guidance (%s):\n%s", path, content))
+	}
+	return parts
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func (s *AgentStep) debug(msg string, fields ...logger.Field) {
+	if s.logger != nil {
+		s.logger.Debug(msg, fields...)
+	}
+}
@@ -1,5 +1,15 @@
 package steps
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+)
+
 // AgentConfig holds configuration for the agent step
 type AgentConfig struct {
 	// PromptFile is the path to PROMPT.md (default: "PROMPT.md")
@@ -8,6 +18,9 @@ type AgentConfig struct {
 	PrdFile string `json:"prd_file,omitempty"`
 	// Model is the Claude model to use (optional)
 	Model string `json:"model,omitempty"`
+	// ModelAliases maps Model to a full model ID for the claude CLI; ralph run
+	// fills it from the top-level model_aliases (optional)
+	ModelAliases map[string]string `json:"model_aliases,omitempty"`
 	// MarkerFile is an optional file path. If it exists, the agent step is skipped.
 	// If set and the step runs successfully, the marker file will be created.
 	MarkerFile string `json:"marker_file,omitempty"`
@@ -17,16 +30,50 @@ type AgentConfig struct {
 	Timeout string `json:"timeout,omitempty"`
 	// SessionFile is where to store session state
 	SessionFile string `json:"session_file,omitempty"`
+	// SessionName keys the session by name under .ralph/sessions/<name>
+	// instead of SessionFile; ralph run sets it to the step name when a
+	// config has more than one agent step (optional)
+	SessionName string `json:"session_name,omitempty"`
 	// SessionExpiryHours is how long sessions last (default: 24)
 	SessionExpiryHours int `json:"session_expiry_hours,omitempty"`
+	// AgentType selects the agent CLI: "claude" (default) or "aider"
+	AgentType string `json:"agent_type,omitempty"`
+	// Binary is the path to the agent CLI (default: ClaudeBinary for claude,
+	// otherwise the agent type name)
+	Binary string `json:"binary,omitempty"`
 	// ClaudeBinary is the path to claude CLI (default: "claude")
 	ClaudeBinary string `json:"claude_binary,omitempty"`
-	// OutputFormat is json or text (default: "json")
+	// OutputFormat is stream-json, json or text (default: "stream-json")
 	OutputFormat string `json:"output_format,omitempty"`
 	// AppendSystemPrompt is extra context to add to the prompt
 	AppendSystemPrompt string `json:"append_system_prompt,omitempty"`
+	// ContextFiles are hand-written guidance files (coding standards, gotchas)
+	// added to the loop context every iteration, in order. Unlike learnings.md
+	// they are never compacted or rewritten. Missing files are skipped.
+	ContextFiles []string `json:"context_files,omitempty"`
 	// LogDir is where to save Claude output logs
 	LogDir string `json:"log_dir,omitempty"`
+	// MCPConfig is passed to claude as --mcp-config: either a path string or an
+	// inline JSON object (written to a temp file per call)
+	MCPConfig json.RawMessage `json:"mcp_config,omitempty"`
+	// mcpConfigPath is MCPConfig resolved to a file for the current call
+	mcpConfigPath string
+	// BudgetUSD is the default cost cap for `ralph run` (overridden by -budget)
+	BudgetUSD float64 `json:"budget_usd,omitempty"`
+	// NoProgressLoops is how many loops in a row may finish without completing
+	// a task before the loop stops (default: 2, negative: never stop for this)
+	NoProgressLoops int `json:"no_progress_loops,omitempty"`
+	// ExitOnNoActionableTasks stops the loop when no task is in progress and
+	// no todo task can start, e.g. only failed tasks remain (default: false)
+	ExitOnNoActionableTasks bool `json:"exit_on_no_actionable_tasks,omitempty"`
+}
+
+// exitDetectorConfig returns the exit detector tuning from the agent config.
+func (c AgentConfig) exitDetectorConfig() agent.ExitDetectorConfig {
+	return agent.ExitDetectorConfig{
+		NoProgressThreshold:     c.NoProgressLoops,
+		ExitOnNoActionableTasks: c.ExitOnNoActionableTasks,
+	}
 }
 
 // DefaultAgentConfig returns sensible defaults
@@ -40,8 +87,68 @@ func DefaultAgentConfig() AgentConfig {
 		Timeout:            "15m",
 		SessionFile:        ".ralph/.ralph_session",
 		SessionExpiryHours: 24,
+		AgentType:          AgentTypeClaude,
 		ClaudeBinary:       "claude",
-		OutputFormat:       "json",
+		OutputFormat:       "stream-json",
 		LogDir:             "logs",
 	}
 }
+
+// Validate rejects agent configs with unknown fields (e.g. a "prd_fil" typo).
+func (s *AgentStep) Validate(rawConfig json.RawMessage) error {
+	if len(bytes.TrimSpace(rawConfig)) == 0 || string(bytes.TrimSpace(rawConfig)) == "null" {
+		return nil
+	}
+	cfg := DefaultAgentConfig()
+	dec := json.NewDecoder(bytes.NewReader(rawConfig))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return err
+	}
+	if _, ok := agentCommandBuilders[cfg.agentType()]; !ok {
+		return fmt.Errorf("unknown agent_type %q (supported: %s, %s)", cfg.AgentType, AgentTypeClaude, AgentTypeAider)
+	}
+	return validateMCPConfig(cfg.MCPConfig)
+}
+
+// agentType returns the normalized agent type, defaulting to claude.
+func (c AgentConfig) agentType() string {
+	t := strings.ToLower(strings.TrimSpace(c.AgentType))
+	if t == "" {
+		return AgentTypeClaude
+	}
+	return t
+}
+
+// logDir returns where this step's loop logs go. Named sessions each count
+// their own loops, so their logs get a subdirectory of LogDir rather than
+// overwriting each other's loop_N files.
+func (c AgentConfig) logDir() string {
+	if c.LogDir == "" || c.SessionName == "" {
+		return c.LogDir
+	}
+	return filepath.Join(c.LogDir, c.SessionName)
+}
+
+// AgentBinary returns the agent type and executable that an agent step with
+// rawConfig runs, with defaults applied.
+func AgentBinary(rawConfig json.RawMessage) (agentType, binary string, err error) {
+	cfg := DefaultAgentConfig()
+	if len(rawConfig) > 0 {
+		if err := json.Unmarshal(rawConfig, &cfg); err != nil {
+			return "", "", fmt.Errorf("failed to parse agent config: %w", err)
+		}
+	}
+	return cfg.agentType(), cfg.binary(), nil
+}
+
+// binary returns the executable to run for the configured agent type.
+func (c AgentConfig) binary() string {
+	if b := strings.TrimSpace(c.Binary); b != "" {
+		return b
+	}
+	if c.agentType() == AgentTypeClaude && c.ClaudeBinary != "" {
+		return c.ClaudeBinary
+	}
+	return c.agentType()
+}
@@ -2,7 +2,10 @@ package steps
 
 import (
 	"errors"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/chr1sbest/wiggum/internal/agent"
 )
@@ -23,6 +26,84 @@ func (e *ClaudeUsageError) Error() string {
 	return "claude usage limit reached: " + strings.TrimSpace(e.Details)
 }
 
+var (
+	// "Claude AI usage limit reached|1760112000"
+	usageResetEpochRe = regexp.MustCompile(`limit reached\|(\d{9,})`)
+	// "resets 3pm", "resets at 10:30am (America/New_York)", "resets Oct 15, 2pm", "resets 14:00"
+	usageResetClockRe = regexp.MustCompile(`(?i)resets\s+(?:at\s+)?(?:([a-z]{3})[a-z]*\s+(\d{1,2}),?\s+(?:at\s+)?)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)?(?:\s*\(([^)]+)\))?`)
+)
+
+// ResetAt extracts when the usage limit resets from the Claude CLI message,
+// relative to now. Clock times without a date are taken as the next such
+// time; a parenthesized zone name is honored when it can be loaded.
+func (e *ClaudeUsageError) ResetAt(now time.Time) (time.Time, bool) {
+	if e == nil {
+		return time.Time{}, false
+	}
+	return parseUsageReset(e.Details, now)
+}
+
+func parseUsageReset(s string, now time.Time) (time.Time, bool) {
+	if m := usageResetEpochRe.FindStringSubmatch(s); m != nil {
+		sec, err := strconv.ParseInt(m[1], 10, 64)
+		if err == nil {
+			return time.Unix(sec, 0), true
+		}
+	}
+
+	m := usageResetClockRe.FindStringSubmatch(s)
+	if m == nil || (m[4] == "" && m[5] == "") {
+		// A bare number ("resets 5") is too ambiguous to act on.
+		return time.Time{}, false
+	}
+	hour, _ := strconv.Atoi(m[3])
+	minute := 0
+	if m[4] != "" {
+		minute, _ = strconv.Atoi(m[4])
+	}
+	switch strings.ToLower(m[5]) {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return time.Time{}, false
+	}
+
+	loc := now.Location()
+	if m[6] != "" {
+		if l, err := time.LoadLocation(strings.TrimSpace(m[6])); err == nil {
+			loc = l
+		}
+	}
+	local := now.In(loc)
+
+	if m[1] != "" {
+		month, err := time.Parse("Jan", strings.ToUpper(m[1][:1])+strings.ToLower(m[1][1:]))
+		if err != nil {
+			return time.Time{}, false
+		}
+		day, _ := strconv.Atoi(m[2])
+		t := time.Date(local.Year(), month.Month(), day, hour, minute, 0, 0, loc)
+		if t.Before(local.AddDate(0, -6, 0)) {
+			// "resets Jan 2" seen in late December.
+			t = t.AddDate(1, 0, 0)
+		}
+		return t, true
+	}
+
+	t := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !t.After(local) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, true
+}
+
 func isClaudeUsageLimitText(s string) bool {
 	msg := strings.ToLower(s)
 	return strings.Contains(msg, "out of extra usage") ||
@@ -54,3 +135,9 @@ func IsAgentExitError(err error) (*AgentExitError, bool) {
 	}
 	return nil, false
 }
+
+// IsClaudeUsageError reports whether err is (or wraps) a ClaudeUsageError.
+func IsClaudeUsageError(err error) bool {
+	var usageErr *ClaudeUsageError
+	return errors.As(err, &usageErr)
+}
@@ -58,3 +58,22 @@ func (s *AgentStep) saveOutput(logDir, output string, loopCount int) {
 		}
 	}
 }
+
+// openStreamLog creates loop_N.stream.log for live Claude output, or returns
+// nil if logging is disabled or the file can't be created
+func (s *AgentStep) openStreamLog(logDir string, loopCount int) *os.File {
+	if logDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		log.Printf("warning: failed to create log directory %s: %v", logDir, err)
+		return nil
+	}
+	path := filepath.Join(logDir, fmt.Sprintf("loop_%d.stream.log", loopCount))
+	f, err := os.Create(path)
+	if err != nil {
+		log.Printf("warning: failed to create stream log %s: %v", path, err)
+		return nil
+	}
+	return f
+}
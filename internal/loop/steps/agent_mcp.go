@@ -0,0 +1,69 @@
+package steps
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// mcpConfigIsSet reports whether raw holds a non-empty mcp_config value.
+func mcpConfigIsSet(raw json.RawMessage) bool {
+	t := string(bytes.TrimSpace(raw))
+	return t != "" && t != "null" && t != `""`
+}
+
+// validateMCPConfig checks that mcp_config is either a path to an existing
+// file or an inline JSON object.
+func validateMCPConfig(raw json.RawMessage) error {
+	if !mcpConfigIsSet(raw) {
+		return nil
+	}
+	var path string
+	if err := json.Unmarshal(raw, &path); err == nil {
+		if _, err := os.Stat(strings.TrimSpace(path)); err != nil {
+			return fmt.Errorf("mcp_config: %w", err)
+		}
+		return nil
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return fmt.Errorf("mcp_config must be a file path or a JSON object")
+	}
+	return nil
+}
+
+// prepareMCPConfig returns the path to pass to --mcp-config. Inline JSON is
+// written to a temp file that the returned cleanup removes. An unset config
+// returns an empty path.
+func prepareMCPConfig(raw json.RawMessage) (string, func(), error) {
+	noop := func() {}
+	if !mcpConfigIsSet(raw) {
+		return "", noop, nil
+	}
+	if err := validateMCPConfig(raw); err != nil {
+		return "", noop, err
+	}
+
+	var path string
+	if err := json.Unmarshal(raw, &path); err == nil {
+		return strings.TrimSpace(path), noop, nil
+	}
+
+	f, err := os.CreateTemp("", "ralph-mcp-*.json")
+	if err != nil {
+		return "", noop, fmt.Errorf("failed to write inline mcp_config: %w", err)
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		cleanup()
+		return "", noop, fmt.Errorf("failed to write inline mcp_config: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("failed to write inline mcp_config: %w", err)
+	}
+	return f.Name(), cleanup, nil
+}
@@ -1,9 +1,20 @@
 package steps
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chr1sbest/wiggum/internal/logger"
+	"github.com/chr1sbest/wiggum/internal/tracker"
 )
 
 func TestSaveOutput_JSON(t *testing.T) {
@@ -140,3 +151,526 @@ func TestSaveOutput_EmptyLogDir(t *testing.T) {
 
 	// No files should be created (test passes if no panic)
 }
+
+func TestAgentStepValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"empty", ``, ""},
+		{"null", `null`, ""},
+		{"known fields", `{"prompt_file": "p.md", "prd_file": ".ralph/prd.json", "model": "opus", "budget_usd": 5}`, ""},
+		{"typo", `{"prd_fil": ".ralph/prd.json"}`, `unknown field "prd_fil"`},
+		{"wrong type", `{"session_expiry_hours": "soon"}`, "cannot unmarshal"},
+		{"aider", `{"agent_type": "aider", "binary": "/usr/local/bin/aider"}`, ""},
+		{"exit tuning", `{"no_progress_loops": 5, "exit_on_no_actionable_tasks": true}`, ""},
+		{"unknown agent type", `{"agent_type": "cursor"}`, `unknown agent_type "cursor"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewAgentStep().Validate(json.RawMessage(tt.config))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBuildClaudeArgs(t *testing.T) {
+	cfg := DefaultAgentConfig()
+	cfg.Model = "opus"
+	cfg.AllowedTools = "Read, Edit"
+
+	got := buildClaudeArgs(cfg, "do the thing", "Loop #3.")
+	want := []string{
+		"--model", "opus",
+		"--output-format", "stream-json", "--verbose",
+		"--allowedTools", "Read", "Edit",
+		"--dangerously-skip-permissions",
+		"--append-system-prompt", "Loop #3.",
+		"-p", "do the thing",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildClaudeArgs() = %q, want %q", got, want)
+	}
+
+	cfg.OutputFormat = "json"
+	cfg.AllowedTools = ""
+	got = buildClaudeArgs(cfg, "p", "")
+	want = []string{"--model", "opus", "--output-format", "json", "--dangerously-skip-permissions", "-p", "p"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildClaudeArgs() = %q, want %q", got, want)
+	}
+
+	cfg.OutputFormat = "text"
+	got = buildClaudeArgs(cfg, "p", "")
+	want = []string{"--model", "opus", "--dangerously-skip-permissions", "-p", "p"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildClaudeArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildClaudeArgsResolvesModelAlias(t *testing.T) {
+	tests := []struct {
+		model   string
+		aliases map[string]string
+		want    string
+	}{
+		{"sonnet-4.5", nil, "claude-sonnet-4-5-20250929"},
+		{"sonnet", nil, "sonnet"},
+		{"claude-opus-4-1-20250805", nil, "claude-opus-4-1-20250805"},
+		{"sonnet", map[string]string{"sonnet": "claude-sonnet-4-20250514"}, "claude-sonnet-4-20250514"},
+	}
+	for _, tt := range tests {
+		cfg := DefaultAgentConfig()
+		cfg.Model = tt.model
+		cfg.ModelAliases = tt.aliases
+		got := buildClaudeArgs(cfg, "p", "")
+		if len(got) < 2 || got[0] != "--model" || got[1] != tt.want {
+			t.Errorf("model %q with aliases %v: args = %q, want --model %s", tt.model, tt.aliases, got, tt.want)
+		}
+	}
+}
+
+func TestBuildAiderArgs(t *testing.T) {
+	cfg := DefaultAgentConfig()
+	cfg.AgentType = AgentTypeAider
+
+	got := buildAiderArgs(cfg, "do the thing", "Loop #3.")
+	want := []string{"--yes-always", "--no-auto-commits", "--no-pretty", "--model", "sonnet", "--message", "Loop #3.\n\ndo the thing"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildAiderArgs() = %q, want %q", got, want)
+	}
+
+	cfg.Model = ""
+	got = buildAiderArgs(cfg, "p", "")
+	want = []string{"--yes-always", "--no-auto-commits", "--no-pretty", "--message", "p"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildAiderArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestAgentConfigBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  AgentConfig
+		want string
+	}{
+		{"claude default", DefaultAgentConfig(), "claude"},
+		{"claude_binary", AgentConfig{ClaudeBinary: "/opt/claude"}, "/opt/claude"},
+		{"binary wins", AgentConfig{ClaudeBinary: "/opt/claude", Binary: "/bin/claude2"}, "/bin/claude2"},
+		{"aider default", AgentConfig{AgentType: "aider", ClaudeBinary: "claude"}, "aider"},
+		{"aider binary", AgentConfig{AgentType: "aider", Binary: "/venv/bin/aider"}, "/venv/bin/aider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.binary(); got != tt.want {
+				t.Errorf("binary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPConfigArgs(t *testing.T) {
+	dir := t.TempDir()
+	mcpPath := filepath.Join(dir, "mcp.json")
+	if err := os.WriteFile(mcpPath, []byte(`{"mcpServers":{}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	inline := `{"mcpServers":{"fs":{"command":"npx","args":["@modelcontextprotocol/server-filesystem","."]}}}`
+
+	tests := []struct {
+		name       string
+		raw        string
+		wantInline bool
+		wantPath   string
+		wantErr    bool
+	}{
+		{name: "unset", raw: ``},
+		{name: "path", raw: `"` + mcpPath + `"`, wantPath: mcpPath},
+		{name: "inline", raw: inline, wantInline: true},
+		{name: "missing path", raw: `"` + filepath.Join(dir, "nope.json") + `"`, wantErr: true},
+		{name: "not an object", raw: `[1,2]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultAgentConfig()
+			cfg.MCPConfig = json.RawMessage(tt.raw)
+
+			if err := validateMCPConfig(cfg.MCPConfig); (err != nil) != tt.wantErr {
+				t.Fatalf("validateMCPConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			path, cleanup, err := prepareMCPConfig(cfg.MCPConfig)
+			defer cleanup()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("prepareMCPConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			cfg.mcpConfigPath = path
+			args := buildClaudeArgs(cfg, "p", "")
+			got := ""
+			for i, a := range args {
+				if a == "--mcp-config" && i+1 < len(args) {
+					got = args[i+1]
+				}
+			}
+
+			switch {
+			case tt.wantInline:
+				data, err := os.ReadFile(got)
+				if err != nil {
+					t.Fatalf("inline config not written: %v", err)
+				}
+				if string(data) != inline {
+					t.Errorf("temp file = %q, want %q", data, inline)
+				}
+				cleanup()
+				if _, err := os.Stat(got); !os.IsNotExist(err) {
+					t.Errorf("expected temp file removed after cleanup")
+				}
+			case got != tt.wantPath:
+				t.Errorf("--mcp-config = %q, want %q", got, tt.wantPath)
+			}
+		})
+	}
+}
+
+// timedWriter records when each write arrives.
+type timedWriter struct {
+	mu     sync.Mutex
+	writes []string
+	times  []time.Time
+}
+
+func (w *timedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	w.times = append(w.times, time.Now())
+	return len(p), nil
+}
+
+func writeFakeClaude(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExecuteClaudeCodeStreamsOutput(t *testing.T) {
+	cfg := DefaultAgentConfig()
+	cfg.ClaudeBinary = writeFakeClaude(t, "echo first\nsleep 0.5\necho second\necho oops >&2\n")
+
+	live := &timedWriter{}
+	s := NewAgentStep()
+	output, err := s.executeAgent(context.Background(), cfg, "prompt", "", live)
+	done := time.Now()
+	if err != nil {
+		t.Fatalf("executeAgent failed: %v", err)
+	}
+
+	want := "first\nsecond\n\n--- STDERR ---\noops\n"
+	if output != want {
+		t.Errorf("output = %q, want %q", output, want)
+	}
+
+	live.mu.Lock()
+	defer live.mu.Unlock()
+	if len(live.writes) != 3 {
+		t.Fatalf("expected 3 streamed lines, got %q", live.writes)
+	}
+	if live.writes[0] != "first\n" {
+		t.Errorf("first streamed line = %q", live.writes[0])
+	}
+	if !containsLine(live.writes, "[stderr] oops\n") {
+		t.Errorf("expected prefixed stderr line, got %q", live.writes)
+	}
+	if gap := done.Sub(live.times[0]); gap < 300*time.Millisecond {
+		t.Errorf("first line arrived %s before exit; expected it to stream before the process finished", gap)
+	}
+}
+
+func TestExecuteClaudeCodeStreamJSONKeepsResult(t *testing.T) {
+	cfg := DefaultAgentConfig()
+	cfg.ClaudeBinary = writeFakeClaude(t, `echo '{"type":"system","subtype":"init"}'
+echo '{"type":"assistant","message":{"usage":{"input_tokens":1,"output_tokens":1}}}'
+echo '{"type":"result","result":"done","total_cost_usd":0.5,"num_turns":2,"usage":{"input_tokens":100,"output_tokens":20}}'
+`)
+
+	live := &timedWriter{}
+	s := NewAgentStep()
+	output, err := s.executeAgent(context.Background(), cfg, "prompt", "", live)
+	if err != nil {
+		t.Fatalf("executeAgent failed: %v", err)
+	}
+	if len(live.writes) != 3 {
+		t.Errorf("expected every event in the stream log, got %q", live.writes)
+	}
+	if !strings.HasPrefix(output, `{"type":"result"`) {
+		t.Fatalf("output = %q, want the result event", output)
+	}
+
+	usage, ok := tracker.ParseClaudeUsageFromOutput(output)
+	if !ok {
+		t.Fatal("expected usage from the result event")
+	}
+	if usage.InputTokens != 100 || usage.OutputTokens != 20 || usage.CostUSD != 0.5 {
+		t.Errorf("usage = %+v, want the result event's totals", usage)
+	}
+}
+
+func TestExecuteClaudeCodeUsageErrorAfterStreaming(t *testing.T) {
+	cfg := DefaultAgentConfig()
+	cfg.ClaudeBinary = writeFakeClaude(t, "echo working\necho 'You are out of extra usage' >&2\nexit 1\n")
+
+	live := &timedWriter{}
+	s := NewAgentStep()
+	output, err := s.executeAgent(context.Background(), cfg, "prompt", "", live)
+	if !IsClaudeUsageError(err) {
+		t.Fatalf("expected ClaudeUsageError, got %v", err)
+	}
+	if !strings.Contains(output, "working") || !strings.Contains(output, "out of extra usage") {
+		t.Errorf("expected full output, got %q", output)
+	}
+	if len(live.writes) != 2 {
+		t.Errorf("expected 2 streamed lines, got %q", live.writes)
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, l := range lines {
+		if l == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestClaudeUsageErrorResetAt(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	now := time.Date(2026, 3, 10, 13, 20, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		details string
+		want    time.Time
+		ok      bool
+	}{
+		{"epoch suffix", "Claude AI usage limit reached|1773158400", time.Unix(1773158400, 0), true},
+		{"later today", "5-hour limit reached ∙ resets 3pm", time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC), true},
+		{"with minutes", "Usage limit reached, resets at 1:45 PM", time.Date(2026, 3, 10, 13, 45, 0, 0, time.UTC), true},
+		{"already passed today", "resets 9am", time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC), true},
+		{"midnight", "resets 12am", time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), true},
+		{"24-hour clock", "quota resets 18:30", time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC), true},
+		{"zone name", "You've hit your limit · resets 11am (America/New_York)", time.Date(2026, 3, 10, 11, 0, 0, 0, ny), true},
+		{"month and day", "weekly limit reached ∙ resets Mar 12, 2pm", time.Date(2026, 3, 12, 14, 0, 0, 0, time.UTC), true},
+		{"unknown zone falls back to local", "resets 4pm (Mars/Olympus)", time.Date(2026, 3, 10, 16, 0, 0, 0, time.UTC), true},
+		{"no reset time", "You are out of extra usage", time.Time{}, false},
+		{"bare number", "resets 5", time.Time{}, false},
+		{"out of range", "resets 25:00", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := (&ClaudeUsageError{Details: tt.details}).ResetAt(now)
+			if ok != tt.ok {
+				t.Fatalf("ResetAt(%q) ok = %v, want %v", tt.details, ok, tt.ok)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("ResetAt(%q) = %s, want %s", tt.details, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgentStepSessionLogsDoNotCollide(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile("PROMPT.md", []byte("work"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("prd.json", []byte(`{"tasks":[{"id":"T1","title":"One","status":"todo"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	claude := writeFakeClaude(t, `echo "{\"type\":\"result\",\"result\":\"$RALPH_TEST_STEP\"}"`+"\n")
+
+	for _, name := range []string{"backend", "frontend"} {
+		t.Setenv("RALPH_TEST_STEP", name)
+		raw := json.RawMessage(`{"claude_binary":"` + claude + `","prd_file":"prd.json","log_dir":"logs","session_name":"` + name + `","no_progress_loops":-1}`)
+		s := NewAgentStep()
+		s.DisableStatusRefresh()
+		if err := s.Execute(context.Background(), raw); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"backend", "frontend"} {
+		got, err := os.ReadFile(filepath.Join("logs", name, "loop_1.md"))
+		if err != nil {
+			t.Fatalf("%s log: %v", name, err)
+		}
+		if string(got) != name {
+			t.Errorf("%s loop_1.md = %q, want its own output", name, got)
+		}
+		if _, err := os.Stat(filepath.Join("logs", name, "loop_1.stream.log")); err != nil {
+			t.Errorf("%s stream log: %v", name, err)
+		}
+	}
+}
+
+func TestAgentStepAttributesUsageToCurrentTask(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile("PROMPT.md", []byte("work"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writePRD := func(prd string) {
+		t.Helper()
+		if err := os.WriteFile("prd.json", []byte(prd), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	claude := writeFakeClaude(t, `echo '{"type":"result","usage":{"input_tokens":100,"output_tokens":20},"total_cost_usd":0.5}'`+"\n")
+	raw := json.RawMessage(`{"claude_binary":"` + claude + `","prd_file":"prd.json","log_dir":"","no_progress_loops":-1}`)
+
+	s := NewAgentStep()
+	s.DisableStatusRefresh()
+	writePRD(`{"tasks":[{"id":"T1","title":"One","status":"in_progress"},{"id":"T2","title":"Two","status":"todo"}]}`)
+	for i := 0; i < 2; i++ {
+		if err := s.Execute(context.Background(), raw); err != nil {
+			t.Fatalf("loop %d on T1: %v", i+1, err)
+		}
+	}
+	writePRD(`{"tasks":[{"id":"T1","title":"One","status":"done"},{"id":"T2","title":"Two","status":"in_progress"}]}`)
+	if err := s.Execute(context.Background(), raw); err != nil {
+		t.Fatalf("loop on T2: %v", err)
+	}
+
+	got, err := tracker.NewWriter(".ralph").LoadTaskMetrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if t1 := got["T1"]; t1.ClaudeCalls != 2 || t1.TotalTokens != 240 || t1.CostUSD != 1.0 {
+		t.Errorf("T1 = %+v, want 2 calls, 240 tokens, $1.00", t1)
+	}
+	if t2 := got["T2"]; t2.ClaudeCalls != 1 || t2.TotalTokens != 120 {
+		t.Errorf("T2 = %+v, want 1 call, 120 tokens", t2)
+	}
+}
+
+type recordingLogger struct {
+	debug []string
+}
+
+func (l *recordingLogger) Debug(msg string, fields ...logger.Field) {
+	for _, f := range fields {
+		if f.Key == "path" {
+			msg += " " + fmt.Sprint(f.Value)
+		}
+	}
+	l.debug = append(l.debug, msg)
+}
+func (l *recordingLogger) Info(string, ...logger.Field)             {}
+func (l *recordingLogger) Warn(string, ...logger.Field)             {}
+func (l *recordingLogger) Error(string, ...logger.Field)            {}
+func (l *recordingLogger) WithFields(...logger.Field) logger.Logger { return l }
+
+func TestBuildLoopContextContextFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(".ralph", "learnings.md"), "Learned: run go vet")
+	write("STANDARDS.md", "Wrap errors with %w.\n")
+	write(filepath.Join("docs", "gotchas.md"), "The cache is not thread safe.")
+	write("empty.md", "  \n")
+
+	rec := &recordingLogger{}
+	s := NewAgentStep()
+	s.SetLogger(rec)
+	cfg := DefaultAgentConfig()
+	cfg.ContextFiles = []string{"docs/gotchas.md", "missing.md", "empty.md", "STANDARDS.md"}
+	cfg.AppendSystemPrompt = "Custom prompt."
+
+	got := s.buildLoopContext(cfg, nil, nil)
+
+	order := []string{
+		"Previous learnings:\nLearned: run go vet",
+		"Project guidance (docs/gotchas.md):\nThe cache is not thread safe.",
+		"Project guidance (STANDARDS.md):\nWrap errors with %w.",
+		"Custom prompt.",
+	}
+	last := -1
+	for _, want := range order {
+		i := strings.Index(got, want)
+		if i < 0 {
+			t.Fatalf("context missing %q:\n%s", want, got)
+		}
+		if i < last {
+			t.Errorf("%q is out of order:\n%s", want, got)
+		}
+		last = i
+	}
+	if strings.Contains(got, "empty.md") || strings.Contains(got, "missing.md") {
+		t.Errorf("missing or empty files should be skipped:\n%s", got)
+	}
+	if !reflect.DeepEqual(rec.debug, []string{"Skipping context file missing.md"}) {
+		t.Errorf("debug log = %q, want one line for missing.md", rec.debug)
+	}
+}
+
+func TestContextFilePartsTruncates(t *testing.T) {
+	t.Chdir(t.TempDir())
+	files := map[string]string{
+		"big.md":   strings.Repeat("a", contextFileMaxBytes+500),
+		"mid.md":   strings.Repeat("b", 3500),
+		"utf8.md":  strings.Repeat("é", 400), // 800 bytes, cut mid-way by the total cap
+		"after.md": "never reached",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := &recordingLogger{}
+	s := &AgentStep{logger: rec}
+	parts := s.contextFileParts([]string{"big.md", "mid.md", "utf8.md", "after.md"})
+	if len(parts) != 3 {
+		t.Fatalf("got %d parts, want 3: %q", len(parts), parts)
+	}
+
+	body := func(part string) string { return part[strings.Index(part, ":\n")+2:] }
+	if b := body(parts[0]); b != strings.Repeat("a", contextFileMaxBytes)+"..." {
+		t.Errorf("big.md kept %d bytes, want %d plus an ellipsis", len(b), contextFileMaxBytes)
+	}
+	if b := body(parts[1]); b != files["mid.md"] {
+		t.Errorf("mid.md should fit whole, got %d bytes", len(b))
+	}
+	b := strings.TrimSuffix(body(parts[2]), "...")
+	if want := contextFilesMaxBytes - contextFileMaxBytes - 3500; len(b) != want || !utf8.ValidString(b) {
+		t.Errorf("utf8.md kept %d bytes (valid UTF-8: %v), want %d", len(b), utf8.ValidString(b), want)
+	}
+	if !reflect.DeepEqual(rec.debug, []string{"Skipping context file over the size cap after.md"}) {
+		t.Errorf("debug log = %q", rec.debug)
+	}
+}
@@ -1,22 +1,61 @@
 package steps
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/chr1sbest/wiggum/internal/resilience"
 )
 
 // CommandConfig holds configuration for command step.
 type CommandConfig struct {
 	Command string `json:"command"`
 	Timeout string `json:"timeout,omitempty"`
+	// Shell runs the command via sh -c (cmd /c on Windows), the default, so
+	// pipes, globs, redirects and $VAR work. Set it false to split the
+	// command into arguments and execute it directly instead.
+	Shell *bool `json:"shell,omitempty"`
+	// Env is added to the process environment; values expand ${VAR}
+	Env map[string]string `json:"env,omitempty"`
+	// CaptureOutputTo writes combined output to this file under .ralph/logs
+	CaptureOutputTo string `json:"capture_output_to,omitempty"`
+	// Quiet stops output lines streaming to the status display while the
+	// command runs; output is still captured and shown on failure
+	Quiet bool `json:"quiet,omitempty"`
+	// RetryExitCodes lists the exit codes that mean a transient failure.
+	// When set, only these codes are retried under the step's max_retries;
+	// any other non-zero exit fails the step at once
+	RetryExitCodes []int `json:"retry_exit_codes,omitempty"`
+}
+
+// useShell reports whether the command runs in a shell.
+func (c CommandConfig) useShell() bool {
+	return c.Shell == nil || *c.Shell
 }
 
+// commandLogDir is where capture_output_to files are written.
+var commandLogDir = filepath.Join(".ralph", "logs")
+
+// commandWaitDelay bounds how long a cancelled command's children may hold
+// its output open before Execute returns.
+const commandWaitDelay = 2 * time.Second
+
 // CommandStep executes shell commands.
 type CommandStep struct {
-	name string
+	name   string
+	output func(line string)
 }
 
 // NewCommandStep creates a new command step.
@@ -27,14 +66,39 @@ func NewCommandStep() *CommandStep {
 func (s *CommandStep) Name() string { return s.name }
 func (s *CommandStep) Type() string { return "command" }
 
-func (s *CommandStep) Execute(ctx context.Context, rawConfig json.RawMessage) error {
+// SetOutputFunc makes Execute pass each line of output to fn as the command
+// writes it, unless the step config sets quiet.
+func (s *CommandStep) SetOutputFunc(fn func(line string)) {
+	s.output = fn
+}
+
+// Validate checks the command config block.
+func (s *CommandStep) Validate(rawConfig json.RawMessage) error {
+	_, _, err := parseCommandConfig(rawConfig)
+	return err
+}
+
+func parseCommandConfig(rawConfig json.RawMessage) (CommandConfig, time.Duration, error) {
 	var cfg CommandConfig
 	if err := json.Unmarshal(rawConfig, &cfg); err != nil {
-		return fmt.Errorf("failed to parse command config: %w", err)
+		return cfg, 0, fmt.Errorf("failed to parse command config: %w", err)
 	}
 
 	if cfg.Command == "" {
-		return fmt.Errorf("command is required")
+		return cfg, 0, fmt.Errorf("command is required")
+	}
+	if !cfg.useShell() {
+		if _, err := splitCommand(cfg.Command); err != nil {
+			return cfg, 0, err
+		}
+	}
+	if _, err := commandCapturePath(cfg.CaptureOutputTo); err != nil {
+		return cfg, 0, err
+	}
+	for _, code := range cfg.RetryExitCodes {
+		if code < 1 || code > 255 {
+			return cfg, 0, fmt.Errorf("retry_exit_codes must be between 1 and 255, got %d", code)
+		}
 	}
 
 	timeout := 5 * time.Minute
@@ -42,18 +106,198 @@ func (s *CommandStep) Execute(ctx context.Context, rawConfig json.RawMessage) er
 		var err error
 		timeout, err = time.ParseDuration(cfg.Timeout)
 		if err != nil {
-			return fmt.Errorf("invalid timeout: %w", err)
+			return cfg, 0, fmt.Errorf("invalid timeout: %w", err)
 		}
 	}
+	return cfg, timeout, nil
+}
+
+func (s *CommandStep) Execute(ctx context.Context, rawConfig json.RawMessage) error {
+	cfg, timeout, err := parseCommandConfig(rawConfig)
+	if err != nil {
+		return err
+	}
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "sh", "-c", cfg.Command)
-	output, err := cmd.CombinedOutput()
+	var cmd *exec.Cmd
+	if cfg.useShell() {
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/c", cfg.Command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", cfg.Command)
+		}
+	} else {
+		args, _ := splitCommand(cfg.Command)
+		cmd = exec.CommandContext(ctx, args[0], args[1:]...)
+	}
+	if len(cfg.Env) > 0 {
+		cmd.Env = append(os.Environ(), commandEnv(cfg.Env)...)
+	}
+	cmd.WaitDelay = commandWaitDelay
+
+	var output []byte
+	if s.output != nil && !cfg.Quiet {
+		lw := &lineWriter{fn: s.output}
+		cmd.Stdout, cmd.Stderr = lw, lw
+		err = cmd.Run()
+		output = lw.finish()
+	} else {
+		output, err = cmd.CombinedOutput()
+	}
+	if cfg.CaptureOutputTo != "" {
+		path, _ := commandCapturePath(cfg.CaptureOutputTo)
+		if mkErr := os.MkdirAll(filepath.Dir(path), 0755); mkErr != nil {
+			return fmt.Errorf("failed to create log directory: %w", mkErr)
+		}
+		if wErr := os.WriteFile(path, output, 0644); wErr != nil {
+			return fmt.Errorf("failed to write %s: %w", path, wErr)
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("command failed: %w\nOutput: %s", err, string(output))
+		err = fmt.Errorf("command failed: %w\nOutput: %s", err, string(output))
+		if len(cfg.RetryExitCodes) > 0 && !retryableExit(err, cfg.RetryExitCodes) {
+			return resilience.NewPermanentError(err)
+		}
+		return err
 	}
 
 	return nil
 }
+
+// retryableExit reports whether err is anything other than a normal exit
+// with a code missing from codes. Commands killed by a timeout or signal
+// have no exit code and keep the default retry behavior.
+func retryableExit(err error, codes []int) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() < 0 {
+		return true
+	}
+	return slices.Contains(codes, exitErr.ExitCode())
+}
+
+// lineWriter collects a command's combined output and passes each complete
+// line to fn as it arrives. exec writes stdout and stderr from one goroutine
+// when they share a writer, but the mutex keeps it safe regardless.
+type lineWriter struct {
+	mu      sync.Mutex
+	fn      func(line string)
+	all     bytes.Buffer
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.all.Write(p)
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.fn(strings.TrimRight(string(w.partial[:i]), "\r"))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// finish forwards a trailing line without a newline and returns everything
+// written.
+func (w *lineWriter) finish() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.fn(strings.TrimRight(string(w.partial), "\r"))
+		w.partial = nil
+	}
+	return w.all.Bytes()
+}
+
+// commandEnv renders env as sorted KEY=value pairs. The config loader has
+// already expanded ${VAR} references, so values are used as-is.
+func commandEnv(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+env[k])
+	}
+	return pairs
+}
+
+// commandCapturePath resolves capture_output_to under .ralph/logs, rejecting
+// paths that would land outside it. An empty name returns "".
+func commandCapturePath(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	clean := filepath.Clean(name)
+	if filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("capture_output_to must be a file name under %s, got %q", commandLogDir, name)
+	}
+	return filepath.Join(commandLogDir, clean), nil
+}
+
+// splitCommand splits a command into arguments for direct execution,
+// honoring single quotes, double quotes and backslash escapes. Unquoted
+// shell syntax (pipes, redirects, globs, $) is an error, since it would be
+// passed through literally; such commands need the default shell mode.
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range command {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				cur.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inArg = true, true
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		case strings.ContainsRune("|&;<>()$`*?", r):
+			return nil, fmt.Errorf("command %q uses shell syntax %q; remove \"shell\": false to run it in a shell", command, r)
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("command %q has an unterminated quote or escape", command)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("command is required")
+	}
+	return args, nil
+}
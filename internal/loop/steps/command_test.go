@@ -0,0 +1,267 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/config"
+	"github.com/chr1sbest/wiggum/internal/resilience"
+)
+
+func runCommandStep(t *testing.T, cfg map[string]any) (string, error) {
+	t.Helper()
+	cfg["capture_output_to"] = "out.log"
+	raw, _ := json.Marshal(cfg)
+	err := NewCommandStep().Execute(context.Background(), raw)
+	b, readErr := os.ReadFile(filepath.Join(".ralph", "logs", "out.log"))
+	if readErr != nil {
+		t.Fatalf("captured output not written: %v", readErr)
+	}
+	return string(b), err
+}
+
+func TestCommandStepShellVsDirect(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("a.txt", nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCommandStep(t, map[string]any{"command": "echo *.txt | tr a A", "shell": true})
+	if err != nil || out != "A.txt\n" {
+		t.Errorf("shell: output %q, err %v", out, err)
+	}
+
+	// Direct exec passes quoted metacharacters through literally.
+	out, err = runCommandStep(t, map[string]any{"command": `echo "*.txt | tr" 'a b'`, "shell": false})
+	if err != nil || out != "*.txt | tr a b\n" {
+		t.Errorf("direct: output %q, err %v", out, err)
+	}
+
+	raw, _ := json.Marshal(map[string]any{"command": "echo *.txt | tr a A", "shell": false})
+	if err := NewCommandStep().Execute(context.Background(), raw); err == nil || !strings.Contains(err.Error(), `"shell": false`) {
+		t.Errorf("direct exec of shell syntax: expected shell hint, got %v", err)
+	}
+}
+
+func TestCommandStepDefaultsToShell(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("RALPH_TEST_WORD", "world")
+
+	// Configs written before "shell" existed use sh syntax freely.
+	raw := json.RawMessage(`{"command": "echo hello && echo $RALPH_TEST_WORD | tr a-z A-Z", "capture_output_to": "out.log"}`)
+	if err := NewCommandStep().Validate(raw); err != nil {
+		t.Fatalf("legacy config rejected: %v", err)
+	}
+	if err := NewCommandStep().Execute(context.Background(), raw); err != nil {
+		t.Fatalf("legacy config failed: %v", err)
+	}
+	out, err := os.ReadFile(filepath.Join(".ralph", "logs", "out.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "hello\nWORLD\n" {
+		t.Errorf("output %q, want both commands run by sh", out)
+	}
+}
+
+func TestCommandStepEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("RALPH_TEST_BASE", "base")
+
+	// The config loader expands ${VAR} once and turns $$ into a literal $;
+	// the step must pass the result through untouched.
+	contents := `{"name":"env","steps":[{"type":"command","name":"print","config":{
+		"command": "printenv RALPH_TEST_NAME RALPH_TEST_HOME RALPH_TEST_LITERAL",
+		"capture_output_to": "out.log",
+		"env": {"RALPH_TEST_NAME": "${RALPH_TEST_BASE}-dev", "RALPH_TEST_HOME": "${RALPH_TEST_UNSET:-none}", "RALPH_TEST_LITERAL": "$${RALPH_TEST_BASE}"}}}]}`
+	if err := os.WriteFile("config.json", []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.NewLoader(".").LoadFile("config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewCommandStep().Execute(context.Background(), cfg.Steps[0].Config)
+	out, readErr := os.ReadFile(filepath.Join(".ralph", "logs", "out.log"))
+	if readErr != nil {
+		t.Fatalf("captured output not written: %v", readErr)
+	}
+	if err != nil || string(out) != "base-dev\nnone\n${RALPH_TEST_BASE}\n" {
+		t.Errorf("output %q, err %v", out, err)
+	}
+	if _, ok := os.LookupEnv("RALPH_TEST_NAME"); ok {
+		t.Error("step env leaked into the ralph process")
+	}
+}
+
+func TestCommandStepCapturesOutputOnFailure(t *testing.T) {
+	t.Chdir(t.TempDir())
+	out, err := runCommandStep(t, map[string]any{"command": "echo broken; exit 3", "shell": true})
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("expected command failure, got %v", err)
+	}
+	if out != "broken\n" {
+		t.Errorf("captured %q", out)
+	}
+}
+
+func TestCommandStepRetryExitCodes(t *testing.T) {
+	t.Chdir(t.TempDir())
+	script := "#!/bin/sh\necho \"failing with $1\"\nexit $1\n"
+	if err := os.WriteFile("flaky.sh", []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		code          string
+		retryCodes    []int
+		wantPermanent bool
+	}{
+		{"configured code is retryable", "75", []int{75, 111}, false},
+		{"unconfigured code is permanent", "1", []int{75, 111}, true},
+		{"no codes keeps every failure retryable", "1", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := map[string]any{"command": "./flaky.sh " + tt.code}
+			if tt.retryCodes != nil {
+				cfg["retry_exit_codes"] = tt.retryCodes
+			}
+			out, err := runCommandStep(t, cfg)
+			if err == nil || !strings.Contains(err.Error(), "exit status "+tt.code) {
+				t.Fatalf("expected exit %s, got %v", tt.code, err)
+			}
+			if got := resilience.IsPermanentError(err); got != tt.wantPermanent {
+				t.Errorf("IsPermanentError() = %v, want %v", got, tt.wantPermanent)
+			}
+			if out != "failing with "+tt.code+"\n" {
+				t.Errorf("captured %q", out)
+			}
+		})
+	}
+}
+
+func TestCommandStepStreamsOutput(t *testing.T) {
+	t.Chdir(t.TempDir())
+	script := "printf 'one\\n'; sleep 0.3; printf 'two\\n' >&2; sleep 0.3; printf 'three'"
+	if err := os.WriteFile("emit.sh", []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	type line struct {
+		text string
+		at   time.Time
+	}
+	var mu sync.Mutex
+	var got []line
+	step := NewCommandStep()
+	step.SetOutputFunc(func(l string) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, line{l, time.Now()})
+	})
+
+	raw, _ := json.Marshal(map[string]any{"command": "sh emit.sh", "capture_output_to": "emit.log"})
+	if err := step.Execute(context.Background(), raw); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	done := time.Now()
+
+	var texts []string
+	for _, l := range got {
+		texts = append(texts, l.text)
+	}
+	if want := []string{"one", "two", "three"}; !reflect.DeepEqual(texts, want) {
+		t.Fatalf("forwarded lines = %q, want %q", texts, want)
+	}
+	if done.Sub(got[0].at) < 400*time.Millisecond {
+		t.Errorf("first line arrived %s before the command finished; want it forwarded as it was written", done.Sub(got[0].at))
+	}
+	captured, err := os.ReadFile(filepath.Join(".ralph", "logs", "emit.log"))
+	if err != nil || string(captured) != "one\ntwo\nthree" {
+		t.Errorf("captured %q, err %v", captured, err)
+	}
+
+	// quiet keeps the output to itself.
+	got = nil
+	raw, _ = json.Marshal(map[string]any{"command": "sh emit.sh", "quiet": true})
+	if err := step.Execute(context.Background(), raw); err != nil {
+		t.Fatalf("quiet Execute: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("quiet step forwarded %d lines", len(got))
+	}
+}
+
+func TestCommandStepCancelStopsChild(t *testing.T) {
+	t.Chdir(t.TempDir())
+	step := NewCommandStep()
+	step.SetOutputFunc(func(string) {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	start := time.Now()
+	raw, _ := json.Marshal(map[string]any{"command": "echo started; sleep 30", "shell": true})
+	err := step.Execute(ctx, raw)
+	if err == nil {
+		t.Fatal("expected cancelled command to fail")
+	}
+	if elapsed := time.Since(start); elapsed > commandWaitDelay+5*time.Second {
+		t.Errorf("Execute returned after %s; cancellation should stop the command", elapsed)
+	}
+}
+
+func TestCommandStepValidate(t *testing.T) {
+	tests := []struct {
+		raw     string
+		wantErr string
+	}{
+		{`{"command": "make lint"}`, ""},
+		{`{"command": "make lint | tee x"}`, ""},
+		{`{"command": "make lint | tee x", "shell": true}`, ""},
+		{`{"command": ""}`, "command is required"},
+		{`{"command": "make lint | tee x", "shell": false}`, "shell syntax"},
+		{`{"command": "echo 'unterminated", "shell": false}`, "unterminated"},
+		{`{"command": "make", "capture_output_to": "../escape.log"}`, "under .ralph"},
+		{`{"command": "make", "capture_output_to": "/tmp/x.log"}`, "under .ralph"},
+		{`{"command": "make", "timeout": "soon"}`, "invalid timeout"},
+		{`{"command": "make", "retry_exit_codes": [75, 0]}`, "between 1 and 255"},
+	}
+	for _, tt := range tests {
+		err := NewCommandStep().Validate(json.RawMessage(tt.raw))
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("Validate(%s) = %v", tt.raw, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Validate(%s) = %v, want error containing %q", tt.raw, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"go test ./...", []string{"go", "test", "./..."}},
+		{"  a\tb  ", []string{"a", "b"}},
+		{`git commit -m "fix: it's done"`, []string{"git", "commit", "-m", "fix: it's done"}},
+		{`echo 'a "b"' \$HOME`, []string{"echo", `a "b"`, "$HOME"}},
+		{`echo "say \"hi\"" ''`, []string{"echo", `say "hi"`, ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitCommand(tt.in)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -30,6 +31,18 @@ type GitCommitConfig struct {
 	// CommitMessageFile is an optional file written by the agent containing subject + body.
 	// If present and non-empty, it will be used via: git commit -F <file>.
 	CommitMessageFile string `json:"commit_message_file,omitempty"`
+	// Push runs git push after a successful commit (default false)
+	Push bool `json:"push,omitempty"`
+	// Remote is the remote to push to (default: "origin")
+	Remote string `json:"remote,omitempty"`
+	// Branch is the branch to push (default: the current branch)
+	Branch string `json:"branch,omitempty"`
+	// BranchPerTask commits each task on its own branch, e.g. ralph/T003-add-auth (default false)
+	BranchPerTask bool `json:"branch_per_task,omitempty"`
+	// IncludeTaskRef appends a "Task: T003" trailer naming the current task(s) (default false)
+	IncludeTaskRef bool `json:"include_task_ref,omitempty"`
+	// CoAuthor appends a "Co-authored-by: <co_author>" trailer, e.g. "Ralph <ralph@local>"
+	CoAuthor string `json:"co_author,omitempty"`
 }
 
 // GitCommitStep stages and commits changes if there are any.
@@ -116,6 +129,12 @@ func (s *GitCommitStep) Execute(ctx context.Context, rawConfig json.RawMessage)
 		}
 	}
 
+	if cfg.BranchPerTask && len(taskIDs) > 0 {
+		if err := s.switchToTaskBranch(ctx, cfg.RepoDir, taskBranchName(taskIDs[0], task)); err != nil {
+			return err
+		}
+	}
+
 	msg := cfg.MessageTemplate
 
 	// Handle multiple task IDs
@@ -140,11 +159,19 @@ func (s *GitCommitStep) Execute(ctx context.Context, rawConfig json.RawMessage)
 	if msg == "" {
 		msg = "chore: progress"
 	}
+	trailers := commitTrailers(cfg, taskIDs)
+	msg = appendTrailers(msg, trailers)
 
+	committed := false
 	commitMsgPath := filepath.Join(cfg.RepoDir, cfg.CommitMessageFile)
 	if cfg.CommitMessageFile != "" {
 		if b, err := os.ReadFile(commitMsgPath); err == nil {
 			if strings.TrimSpace(string(b)) != "" {
+				if len(trailers) > 0 {
+					if err := os.WriteFile(commitMsgPath, []byte(appendTrailers(string(b), trailers)+"\n"), 0644); err != nil {
+						return err
+					}
+				}
 				if err := s.git(ctx, cfg.RepoDir, "commit", "-F", commitMsgPath); err != nil {
 					if strings.Contains(err.Error(), "nothing to commit") {
 						return nil
@@ -152,22 +179,163 @@ func (s *GitCommitStep) Execute(ctx context.Context, rawConfig json.RawMessage)
 					return err
 				}
 				_ = os.Remove(commitMsgPath)
-				return nil
+				committed = true
 			}
 		}
 	}
 
 	// Commit. If nothing to commit (race), just return nil.
-	if err := s.git(ctx, cfg.RepoDir, "commit", "-m", msg); err != nil {
-		if strings.Contains(err.Error(), "nothing to commit") {
-			return nil
+	if !committed {
+		if err := s.git(ctx, cfg.RepoDir, "commit", "-m", msg); err != nil {
+			if strings.Contains(err.Error(), "nothing to commit") {
+				return nil
+			}
+			return err
 		}
-		return err
 	}
 
+	if cfg.Push {
+		return s.push(ctx, cfg)
+	}
+	return nil
+}
+
+// commitTrailers returns the trailer lines enabled by cfg for a commit of taskIDs.
+func commitTrailers(cfg GitCommitConfig, taskIDs []string) []string {
+	var trailers []string
+	if cfg.IncludeTaskRef && len(taskIDs) > 0 {
+		trailers = append(trailers, "Task: "+sanitizeOneLine(strings.Join(taskIDs, ", ")))
+	}
+	if coAuthor := strings.TrimSpace(cfg.CoAuthor); coAuthor != "" {
+		trailers = append(trailers, "Co-authored-by: "+sanitizeOneLine(coAuthor))
+	}
+	return trailers
+}
+
+// appendTrailers adds trailers to msg's final trailer block (starting one if
+// needed), skipping any already present so a retried commit doesn't repeat them.
+func appendTrailers(msg string, trailers []string) string {
+	msg = strings.TrimRight(msg, "\n\r\t ")
+	lines := strings.Split(msg, "\n")
+	existing := map[string]bool{}
+	for _, line := range lines {
+		existing[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, t := range trailers {
+		if !existing[t] {
+			missing = append(missing, t)
+		}
+	}
+	if len(missing) == 0 {
+		return msg
+	}
+	sep := "\n\n"
+	if endsWithTrailerBlock(lines) {
+		sep = "\n"
+	}
+	return msg + sep + strings.Join(missing, "\n")
+}
+
+var trailerLineRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*: \S`)
+
+// endsWithTrailerBlock reports whether the last paragraph of a multi-paragraph
+// message is made up entirely of "Key: value" trailer lines.
+func endsWithTrailerBlock(lines []string) bool {
+	n := 0
+	for i := len(lines) - 1; i >= 0 && strings.TrimSpace(lines[i]) != ""; i-- {
+		if !trailerLineRe.MatchString(lines[i]) {
+			return false
+		}
+		n++
+	}
+	return n > 0 && n < len(lines)
+}
+
+// push pushes to the configured remote, setting the upstream if the branch has none.
+func (s *GitCommitStep) push(ctx context.Context, cfg GitCommitConfig) error {
+	remote := strings.TrimSpace(cfg.Remote)
+	if remote == "" {
+		remote = "origin"
+	}
+	branch := strings.TrimSpace(cfg.Branch)
+
+	args := []string{"push", remote}
+	if branch != "" {
+		args = append(args, branch)
+	}
+	err := s.git(ctx, cfg.RepoDir, args...)
+	if err == nil {
+		return nil
+	}
+	if !strings.Contains(err.Error(), "no upstream branch") {
+		return fmt.Errorf("push failed: %w", err)
+	}
+
+	if branch == "" {
+		out, err := s.gitOutput(ctx, cfg.RepoDir, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return fmt.Errorf("push failed: %w", err)
+		}
+		branch = strings.TrimSpace(out)
+	}
+	if err := s.git(ctx, cfg.RepoDir, "push", "-u", remote, branch); err != nil {
+		return fmt.Errorf("push failed: %w", err)
+	}
 	return nil
 }
 
+// switchToTaskBranch checks out branch, creating it from HEAD if needed.
+// Staged changes carry over to the branch.
+func (s *GitCommitStep) switchToTaskBranch(ctx context.Context, dir, branch string) error {
+	current, err := s.gitOutput(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err == nil && strings.TrimSpace(current) == branch {
+		return nil
+	}
+	if err := s.git(ctx, dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err != nil {
+		return s.git(ctx, dir, "checkout", "-b", branch)
+	}
+	return s.git(ctx, dir, "checkout", branch)
+}
+
+// taskBranchName derives a branch name like "ralph/T003-add-auth" from a task.
+func taskBranchName(taskID, title string) string {
+	id := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return -1
+	}, taskID)
+	name := "ralph/" + id
+	if slug := slugify(title); slug != "" && slug != "working" {
+		name += "-" + slug
+	}
+	return name
+}
+
+// slugify lowercases s and collapses anything that isn't a letter or digit into
+// single dashes, capped at 40 characters.
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	out := b.String()
+	if len(out) > 40 {
+		out = out[:40]
+	}
+	return strings.Trim(out, "-")
+}
+
 func (s *GitCommitStep) git(ctx context.Context, dir string, args ...string) error {
 	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = dir
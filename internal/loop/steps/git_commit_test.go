@@ -73,3 +73,256 @@ func TestGitCommitStepCommitsWhenChangesExist(t *testing.T) {
 		t.Fatalf("expected commit message to include llm summary, got:\n%s", string(mb))
 	}
 }
+
+func TestGitCommitStepPushesToRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	remoteDir := t.TempDir()
+	dir := t.TempDir()
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		b, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, string(b))
+		}
+		return strings.TrimSpace(string(b))
+	}
+
+	git(remoteDir, "init", "--bare")
+	git(dir, "init")
+	git(dir, "config", "user.email", "ralph@local")
+	git(dir, "config", "user.name", "Ralph")
+	git(dir, "config", "push.default", "simple")
+	git(dir, "remote", "add", "origin", remoteDir)
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(dir, "add", "-A")
+	git(dir, "commit", "-m", "init")
+
+	write := func(name string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// No upstream yet: the step should fall back to push -u.
+	write("one.txt")
+	raw, _ := json.Marshal(map[string]any{"repo_dir": dir, "push": true})
+	if err := NewGitCommitStep().Execute(context.Background(), raw); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	branch := git(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if got, want := git(remoteDir, "rev-parse", branch), git(dir, "rev-parse", "HEAD"); got != want {
+		t.Fatalf("remote %s = %s, want %s", branch, got, want)
+	}
+
+	// Upstream is set now, so a plain push should work.
+	write("two.txt")
+	if err := NewGitCommitStep().Execute(context.Background(), raw); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if got, want := git(remoteDir, "rev-parse", branch), git(dir, "rev-parse", "HEAD"); got != want {
+		t.Fatalf("remote %s = %s, want %s", branch, got, want)
+	}
+
+	// Push failures are returned so ContinueOnError can decide what happens.
+	write("three.txt")
+	raw, _ = json.Marshal(map[string]any{"repo_dir": dir, "push": true, "remote": "missing"})
+	err := NewGitCommitStep().Execute(context.Background(), raw)
+	if err == nil || !strings.Contains(err.Error(), "push failed") {
+		t.Fatalf("expected push failure, got %v", err)
+	}
+}
+
+func TestTaskBranchName(t *testing.T) {
+	tests := []struct {
+		id, title, want string
+	}{
+		{"T003", "Add auth", "ralph/T003-add-auth"},
+		{"T010", "  Fix: login/logout   bugs!! ", "ralph/T010-fix-login-logout-bugs"},
+		{"T001", "working", "ralph/T001"},
+		{"T002", "", "ralph/T002"},
+		{"T004", strings.Repeat("very long title ", 10), "ralph/T004-very-long-title-very-long-title-very-lon"},
+	}
+	for _, tt := range tests {
+		if got := taskBranchName(tt.id, tt.title); got != tt.want {
+			t.Errorf("taskBranchName(%q, %q) = %q, want %q", tt.id, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestGitCommitStepBranchPerTask(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		b, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, string(b))
+		}
+		return strings.TrimSpace(string(b))
+	}
+
+	git("init")
+	git("config", "user.email", "ralph@local")
+	git("config", "user.name", "Ralph")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "-A")
+	git("commit", "-m", "init")
+
+	prdPath := filepath.Join(t.TempDir(), "prd.json")
+	prd := `{"version": 1, "tasks": [{"id": "T003", "title": "Add auth", "status": "in_progress"}]}`
+	if err := os.WriteFile(prdPath, []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(map[string]any{"repo_dir": dir, "prd_file": prdPath, "branch_per_task": true})
+
+	for _, name := range []string{"one.txt", "two.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewGitCommitStep().Execute(context.Background(), raw); err != nil {
+			t.Fatalf("Execute error: %v", err)
+		}
+		if got := git("rev-parse", "--abbrev-ref", "HEAD"); got != "ralph/T003-add-auth" {
+			t.Fatalf("expected task branch, got %q", got)
+		}
+	}
+	if got := git("rev-list", "--count", "HEAD"); got != "3" {
+		t.Errorf("expected 3 commits on task branch, got %s", got)
+	}
+
+	// Switching back to an existing task branch from elsewhere.
+	git("checkout", "-b", "other")
+	if err := os.WriteFile(filepath.Join(dir, "three.txt"), []byte("three"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewGitCommitStep().Execute(context.Background(), raw); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if got := git("rev-parse", "--abbrev-ref", "HEAD"); got != "ralph/T003-add-auth" {
+		t.Fatalf("expected switch to existing task branch, got %q", got)
+	}
+}
+
+func TestAppendTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      string
+		trailers []string
+		want     string
+	}{
+		{
+			name:     "subject only",
+			msg:      "feat: add auth\n",
+			trailers: []string{"Task: T003", "Co-authored-by: Ralph <ralph@local>"},
+			want:     "feat: add auth\n\nTask: T003\nCo-authored-by: Ralph <ralph@local>",
+		},
+		{
+			name:     "no trailers",
+			msg:      "feat: add auth",
+			trailers: nil,
+			want:     "feat: add auth",
+		},
+		{
+			name:     "joins existing trailer block",
+			msg:      "feat: add auth\n\nBody text.\n\nSigned-off-by: Dev <dev@x>\n",
+			trailers: []string{"Task: T003"},
+			want:     "feat: add auth\n\nBody text.\n\nSigned-off-by: Dev <dev@x>\nTask: T003",
+		},
+		{
+			name:     "already present",
+			msg:      "feat: add auth\n\nTask: T003",
+			trailers: []string{"Task: T003"},
+			want:     "feat: add auth\n\nTask: T003",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appendTrailers(tt.msg, tt.trailers); got != tt.want {
+				t.Errorf("appendTrailers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitCommitStepAddsTaskRefAndCoAuthor(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		b, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, string(b))
+		}
+		return strings.TrimSpace(string(b))
+	}
+
+	git("init")
+	git("config", "user.email", "ralph@local")
+	git("config", "user.name", "Ralph")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "-A")
+	git("commit", "-m", "init")
+
+	prdPath := filepath.Join(t.TempDir(), "prd.json")
+	prd := `{"version": 1, "tasks": [{"id": "T003", "title": "Add auth", "status": "in_progress"}]}`
+	if err := os.WriteFile(prdPath, []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(map[string]any{
+		"repo_dir":         dir,
+		"prd_file":         prdPath,
+		"message_template": "feat: {{task}}",
+		"include_task_ref": true,
+		"co_author":        "Pair Dev <pair@example.com>",
+	})
+
+	// Templated message
+	if err := os.WriteFile(filepath.Join(dir, "one.txt"), []byte("one"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewGitCommitStep().Execute(context.Background(), raw); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	want := "feat: Add auth\n\nTask: T003\nCo-authored-by: Pair Dev <pair@example.com>"
+	if got := git("log", "-1", "--format=%B"); got != want {
+		t.Errorf("commit message = %q, want %q", got, want)
+	}
+
+	// Agent-written message file: trailers are appended, not replacing it
+	if err := os.WriteFile(filepath.Join(dir, "two.txt"), []byte("two"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "commit_message.txt"), []byte("feat: add login\n\nWhy it matters.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewGitCommitStep().Execute(context.Background(), raw); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	want = "feat: add login\n\nWhy it matters.\n\nTask: T003\nCo-authored-by: Pair Dev <pair@example.com>"
+	if got := git("log", "-1", "--format=%B"); got != want {
+		t.Errorf("commit message = %q, want %q", got, want)
+	}
+	if got := git("log", "-1", "--format=%(trailers:key=Task,valueonly)"); got != "T003" {
+		t.Errorf("git did not parse Task trailer, got %q", got)
+	}
+}
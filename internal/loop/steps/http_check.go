@@ -0,0 +1,84 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPCheckConfig holds configuration for the http-check step.
+type HTTPCheckConfig struct {
+	URL          string `json:"url"`
+	Method       string `json:"method,omitempty"`        // Default: GET
+	ExpectStatus int    `json:"expect_status,omitempty"` // Default: 200
+	Timeout      string `json:"timeout,omitempty"`       // Default: 30s
+}
+
+// HTTPCheckStep requests a URL and fails unless the response has the expected status.
+type HTTPCheckStep struct {
+	name   string
+	client *http.Client
+}
+
+// NewHTTPCheckStep creates a new http-check step.
+func NewHTTPCheckStep() *HTTPCheckStep {
+	return &HTTPCheckStep{name: "http-check", client: http.DefaultClient}
+}
+
+func (s *HTTPCheckStep) Name() string { return s.name }
+func (s *HTTPCheckStep) Type() string { return "http-check" }
+
+func (s *HTTPCheckStep) Execute(ctx context.Context, rawConfig json.RawMessage) error {
+	var cfg HTTPCheckConfig
+	if err := json.Unmarshal(rawConfig, &cfg); err != nil {
+		return fmt.Errorf("failed to parse http-check config: %w", err)
+	}
+
+	if strings.TrimSpace(cfg.URL) == "" {
+		return fmt.Errorf("url is required")
+	}
+
+	method := strings.ToUpper(strings.TrimSpace(cfg.Method))
+	if method == "" {
+		method = http.MethodGet
+	}
+	expect := cfg.ExpectStatus
+	if expect == 0 {
+		expect = http.StatusOK
+	}
+
+	timeout := 30 * time.Second
+	if cfg.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+	}
+
+	// The step-level timeout from the loop (if any) is already on ctx; whichever is shorter wins.
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, cfg.URL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http-check %s %s failed: %w", method, cfg.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expect {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("http-check %s %s: expected status %d, got %d\nBody: %s", method, cfg.URL, expect, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
@@ -1,23 +1,101 @@
 package steps
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
 )
 
+// NoopConfig holds optional configuration for the noop step. With no config
+// the step does nothing; the fields simulate agent behavior so retries,
+// circuit breakers and exits can be exercised without a real agent.
+type NoopConfig struct {
+	Sleep     string `json:"sleep,omitempty"`      // Wait this long (e.g. "2s") before finishing
+	Fail      bool   `json:"fail,omitempty"`       // Fail every invocation
+	FailTimes int    `json:"fail_times,omitempty"` // Fail the first N invocations, then succeed
+	Exit      bool   `json:"exit,omitempty"`       // Signal completion with an AgentExitError
+}
+
 // NoopStep is a step that does nothing (useful for testing).
 type NoopStep struct {
 	name string
+
+	mu    sync.Mutex
+	calls map[string]int // invocations so far, by config, for fail_times
 }
 
 // NewNoopStep creates a new noop step.
 func NewNoopStep() *NoopStep {
-	return &NoopStep{name: "noop"}
+	return &NoopStep{name: "noop", calls: make(map[string]int)}
 }
 
 func (s *NoopStep) Name() string { return s.name }
 func (s *NoopStep) Type() string { return "noop" }
 
 func (s *NoopStep) Execute(ctx context.Context, config json.RawMessage) error {
+	cfg, err := parseNoopConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Sleep != "" {
+		d, _ := time.ParseDuration(cfg.Sleep)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+
+	if cfg.FailTimes > 0 {
+		s.mu.Lock()
+		key := string(bytes.TrimSpace(config))
+		s.calls[key]++
+		call := s.calls[key]
+		s.mu.Unlock()
+		if call <= cfg.FailTimes {
+			return fmt.Errorf("noop: simulated failure %d of %d", call, cfg.FailTimes)
+		}
+	}
+
+	if cfg.Fail {
+		return fmt.Errorf("noop: simulated failure")
+	}
+	if cfg.Exit {
+		return &AgentExitError{Reason: agent.ExitReasonPlanComplete}
+	}
 	return nil
 }
+
+// Validate checks the noop config block.
+func (s *NoopStep) Validate(config json.RawMessage) error {
+	_, err := parseNoopConfig(config)
+	return err
+}
+
+func parseNoopConfig(raw json.RawMessage) (NoopConfig, error) {
+	var cfg NoopConfig
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return cfg, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse noop config: %w", err)
+	}
+	if cfg.Sleep != "" {
+		if _, err := time.ParseDuration(cfg.Sleep); err != nil {
+			return cfg, fmt.Errorf("invalid sleep: %w", err)
+		}
+	}
+	if cfg.FailTimes < 0 {
+		return cfg, fmt.Errorf("fail_times must be >= 0, got %d", cfg.FailTimes)
+	}
+	return cfg, nil
+}
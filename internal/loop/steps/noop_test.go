@@ -0,0 +1,114 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+)
+
+func TestNoopStepZeroConfig(t *testing.T) {
+	s := NewNoopStep()
+	for _, cfg := range []string{"", "null", "{}"} {
+		if err := s.Execute(context.Background(), json.RawMessage(cfg)); err != nil {
+			t.Errorf("Execute(%q) = %v, want nil", cfg, err)
+		}
+	}
+}
+
+func TestNoopStepSleep(t *testing.T) {
+	s := NewNoopStep()
+	start := time.Now()
+	if err := s.Execute(context.Background(), json.RawMessage(`{"sleep":"50ms"}`)); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Execute() returned after %s, want >= 50ms", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Execute(ctx, json.RawMessage(`{"sleep":"1h"}`)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Execute() with cancelled sleep = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestNoopStepFail(t *testing.T) {
+	s := NewNoopStep()
+	for i := 0; i < 3; i++ {
+		if err := s.Execute(context.Background(), json.RawMessage(`{"fail":true}`)); err == nil {
+			t.Fatalf("call %d: Execute() = nil, want simulated failure", i+1)
+		}
+	}
+}
+
+func TestNoopStepFailTimes(t *testing.T) {
+	s := NewNoopStep()
+	cfg := json.RawMessage(`{"fail_times":2}`)
+	for i := 1; i <= 4; i++ {
+		err := s.Execute(context.Background(), cfg)
+		if wantErr := i <= 2; (err != nil) != wantErr {
+			t.Errorf("call %d: Execute() = %v, want error=%v", i, err, wantErr)
+		}
+	}
+
+	// A differently configured noop step keeps its own count.
+	if err := s.Execute(context.Background(), json.RawMessage(`{"fail_times":1,"sleep":"1ms"}`)); err == nil {
+		t.Error("first call with a new config should fail")
+	}
+}
+
+func TestNoopStepExit(t *testing.T) {
+	s := NewNoopStep()
+	err := s.Execute(context.Background(), json.RawMessage(`{"exit":true}`))
+	exitErr, ok := IsAgentExitError(err)
+	if !ok {
+		t.Fatalf("Execute() = %v, want AgentExitError", err)
+	}
+	if exitErr.Reason != agent.ExitReasonPlanComplete {
+		t.Errorf("Reason = %q, want %q", exitErr.Reason, agent.ExitReasonPlanComplete)
+	}
+
+	// fail_times takes precedence until it is used up.
+	cfg := json.RawMessage(`{"fail_times":1,"exit":true}`)
+	if err := s.Execute(context.Background(), cfg); err == nil || isExit(err) {
+		t.Errorf("first call = %v, want simulated failure", err)
+	}
+	if err := s.Execute(context.Background(), cfg); !isExit(err) {
+		t.Errorf("second call = %v, want AgentExitError", err)
+	}
+}
+
+func TestNoopStepValidate(t *testing.T) {
+	tests := []struct {
+		config  string
+		wantErr string
+	}{
+		{config: ``},
+		{config: `{"sleep":"2s","fail_times":3,"exit":true}`},
+		{config: `{"sleep":"soon"}`, wantErr: "invalid sleep"},
+		{config: `{"fail_times":-1}`, wantErr: "fail_times must be >= 0"},
+		{config: `{"fial":true}`, wantErr: "unknown field"},
+	}
+	for _, tt := range tests {
+		err := NewNoopStep().Validate(json.RawMessage(tt.config))
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("Validate(%s) = %v, want nil", tt.config, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Validate(%s) = %v, want error containing %q", tt.config, err, tt.wantErr)
+		}
+	}
+}
+
+func isExit(err error) bool {
+	_, ok := IsAgentExitError(err)
+	return ok
+}
@@ -1,13 +1,18 @@
 package steps
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/chr1sbest/wiggum/internal/resilience"
 )
 
 // ReadmeCheckConfig holds configuration for the readme check step.
@@ -18,30 +23,73 @@ type ReadmeCheckConfig struct {
 	ProjectDir string `json:"project_dir,omitempty"`
 	// Patterns are file patterns to check for changes (default: ["*.go", "configs/*.json"])
 	Patterns []string `json:"patterns,omitempty"`
+	// Mode is "update" (default) to suggest a README review, or "check" to
+	// fail when code changed but the README was not touched
+	Mode string `json:"mode,omitempty"`
+	// BaseRef also counts files changed between this ref and HEAD, e.g.
+	// "origin/main" so check mode sees a branch's commits in CI
+	BaseRef string `json:"base_ref,omitempty"`
 }
 
+// Readme check modes.
+const (
+	ReadmeModeUpdate = "update"
+	ReadmeModeCheck  = "check"
+)
+
 // ReadmeCheckStep checks if README needs updating based on recent changes.
 type ReadmeCheckStep struct {
 	name string
+	out  io.Writer // check mode's staleness summary
 }
 
 // NewReadmeCheckStep creates a new readme check step.
 func NewReadmeCheckStep() *ReadmeCheckStep {
-	return &ReadmeCheckStep{name: "readme-check"}
+	return NewReadmeCheckStepWithWriter(os.Stdout)
+}
+
+// NewReadmeCheckStepWithWriter creates a readme check step that prints check
+// mode's summary to w.
+func NewReadmeCheckStepWithWriter(w io.Writer) *ReadmeCheckStep {
+	return &ReadmeCheckStep{name: "readme-check", out: w}
 }
 
 func (s *ReadmeCheckStep) Name() string { return s.name }
 func (s *ReadmeCheckStep) Type() string { return "readme-check" }
 
-func (s *ReadmeCheckStep) Execute(ctx context.Context, rawConfig json.RawMessage) error {
+// Validate checks the readme-check config block.
+func (s *ReadmeCheckStep) Validate(config json.RawMessage) error {
+	_, err := parseReadmeCheckConfig(config)
+	return err
+}
+
+func parseReadmeCheckConfig(raw json.RawMessage) (ReadmeCheckConfig, error) {
 	var cfg ReadmeCheckConfig
-	if rawConfig != nil && len(rawConfig) > 0 {
-		if err := json.Unmarshal(rawConfig, &cfg); err != nil {
-			return fmt.Errorf("failed to parse readme-check config: %w", err)
-		}
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(trimmed, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse readme-check config: %w", err)
+	}
+	switch cfg.Mode {
+	case "", ReadmeModeUpdate, ReadmeModeCheck:
+	default:
+		return cfg, fmt.Errorf("invalid mode %q (want %q or %q)", cfg.Mode, ReadmeModeUpdate, ReadmeModeCheck)
+	}
+	return cfg, nil
+}
+
+func (s *ReadmeCheckStep) Execute(ctx context.Context, rawConfig json.RawMessage) error {
+	cfg, err := parseReadmeCheckConfig(rawConfig)
+	if err != nil {
+		return err
 	}
 
 	// Set defaults
+	if cfg.Mode == "" {
+		cfg.Mode = ReadmeModeUpdate
+	}
 	if cfg.ReadmePath == "" {
 		cfg.ReadmePath = "README.md"
 	}
@@ -63,8 +111,11 @@ func (s *ReadmeCheckStep) Execute(ctx context.Context, rawConfig json.RawMessage
 	}
 
 	// Get list of changed files using git
-	changedFiles, err := s.getChangedFiles(ctx, cfg.ProjectDir)
+	changedFiles, err := s.getChangedFiles(ctx, cfg.ProjectDir, cfg.BaseRef)
 	if err != nil {
+		if cfg.Mode == ReadmeModeCheck {
+			return fmt.Errorf("readme-check: cannot list changed files: %w", err)
+		}
 		// If git fails, we can't determine changes - skip the check
 		return nil
 	}
@@ -81,15 +132,66 @@ func (s *ReadmeCheckStep) Execute(ctx context.Context, rawConfig json.RawMessage
 
 	// Check if changes might require README update
 	needsUpdate := s.analyzeChanges(significantChanges)
-	if needsUpdate != nil {
+	if needsUpdate == nil {
+		return nil
+	}
+	if cfg.Mode != ReadmeModeCheck {
 		return needsUpdate
 	}
 
-	return nil
+	// Check mode: the README is current if it changed alongside the code.
+	// git reports changed files relative to the repo root, which need not
+	// be ProjectDir, so the README path is resolved the same way.
+	readmeRel, err := s.repoRelPath(ctx, cfg.ProjectDir, cfg.ReadmePath)
+	if err != nil {
+		return fmt.Errorf("readme-check: cannot locate %s in the repo: %w", cfg.ReadmePath, err)
+	}
+	for _, file := range changedFiles {
+		if filepath.ToSlash(filepath.Clean(file)) == readmeRel {
+			return nil
+		}
+	}
+	fmt.Fprintf(s.out, "README check: %s is stale, %d change(s) without a README update:\n", cfg.ReadmePath, len(needsUpdate.Changes))
+	for _, change := range needsUpdate.Changes {
+		fmt.Fprintf(s.out, "  - %s\n", change)
+	}
+	// Retrying won't update the README, so fail the step outright.
+	return resilience.NewPermanentError(&ReadmeStale{
+		ReadmePath: cfg.ReadmePath,
+		Changes:    needsUpdate.Changes,
+	})
 }
 
-// getChangedFiles returns files changed since last commit.
-func (s *ReadmeCheckStep) getChangedFiles(ctx context.Context, dir string) ([]string, error) {
+// repoRelPath returns path, relative to dir, as a slash-separated path
+// relative to the root of the git repo containing dir.
+func (s *ReadmeCheckStep) repoRelPath(ctx context.Context, dir, path string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	root, err := filepath.EvalSymlinks(strings.TrimSpace(string(output)))
+	if err != nil {
+		return "", err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+	rel, err := filepath.Rel(root, filepath.Join(absDir, path))
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// getChangedFiles returns files changed since last commit, plus files
+// changed between baseRef and HEAD when baseRef is set.
+func (s *ReadmeCheckStep) getChangedFiles(ctx context.Context, dir, baseRef string) ([]string, error) {
 	// Get staged and unstaged changes
 	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
 	cmd.Dir = dir
@@ -107,6 +209,20 @@ func (s *ReadmeCheckStep) getChangedFiles(ctx context.Context, dir string) ([]st
 		}
 	}
 
+	if baseRef != "" {
+		cmd := exec.CommandContext(ctx, "git", "diff", "--name-only", baseRef+"...HEAD")
+		cmd.Dir = dir
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("git diff %s...HEAD: %w", baseRef, err)
+		}
+		for _, file := range strings.Split(string(output), "\n") {
+			if file = strings.TrimSpace(file); file != "" {
+				files = append(files, file)
+			}
+		}
+	}
+
 	return files, nil
 }
 
@@ -177,6 +293,28 @@ func (e *ReadmeUpdateNeeded) Error() string {
 		e.Reason, strings.Join(e.Changes, "\n  - "))
 }
 
+// ReadmeStale is returned in check mode when code changed but the README
+// did not.
+type ReadmeStale struct {
+	ReadmePath string
+	Changes    []string
+}
+
+func (e *ReadmeStale) Error() string {
+	return fmt.Sprintf("%s not updated for code changes:\n  - %s",
+		e.ReadmePath, strings.Join(e.Changes, "\n  - "))
+}
+
+// IsReadmeStale checks if an error indicates check mode found a stale
+// README.
+func IsReadmeStale(err error) (*ReadmeStale, bool) {
+	var r *ReadmeStale
+	if errors.As(err, &r) {
+		return r, true
+	}
+	return nil, false
+}
+
 // IsReadmeUpdateNeeded checks if an error indicates README needs updating.
 func IsReadmeUpdateNeeded(err error) (*ReadmeUpdateNeeded, bool) {
 	if err == nil {
@@ -0,0 +1,154 @@
+package steps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chr1sbest/wiggum/internal/resilience"
+)
+
+// readmeRepo creates a git repo with a committed README and config file.
+func readmeRepo(t *testing.T) (dir string, run func(args ...string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir = t.TempDir()
+	run = func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if b, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, string(b))
+		}
+	}
+	run("init")
+	run("config", "user.email", "ralph@local")
+	run("config", "user.name", "Ralph")
+	writeFile(t, filepath.Join(dir, "README.md"), "# Demo\n")
+	writeFile(t, filepath.Join(dir, "configs", "app.json"), "{}\n")
+	run("add", "-A")
+	run("commit", "-m", "init")
+	return dir, run
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runReadmeCheck(t *testing.T, cfg map[string]any) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	step := NewReadmeCheckStep()
+	step.out = &out
+	raw, _ := json.Marshal(cfg)
+	err := step.Execute(context.Background(), raw)
+	return out.String(), err
+}
+
+func TestReadmeCheckUpdateModeSuggestsReview(t *testing.T) {
+	dir, _ := readmeRepo(t)
+	writeFile(t, filepath.Join(dir, "configs", "app.json"), `{"port": 8080}`+"\n")
+
+	out, err := runReadmeCheck(t, map[string]any{"project_dir": dir})
+	if _, ok := IsReadmeUpdateNeeded(err); !ok {
+		t.Fatalf("expected ReadmeUpdateNeeded, got %v", err)
+	}
+	if out != "" {
+		t.Errorf("update mode should print nothing, got %q", out)
+	}
+
+	// The README is touched: update mode still suggests a review.
+	writeFile(t, filepath.Join(dir, "README.md"), "# Demo\n\nPort 8080.\n")
+	if _, err := runReadmeCheck(t, map[string]any{"project_dir": dir, "mode": "update"}); err == nil {
+		t.Error("expected update mode to keep suggesting a review")
+	}
+}
+
+func TestReadmeCheckCheckMode(t *testing.T) {
+	dir, run := readmeRepo(t)
+
+	if _, err := runReadmeCheck(t, map[string]any{"project_dir": dir, "mode": "check"}); err != nil {
+		t.Fatalf("clean tree should pass, got %v", err)
+	}
+
+	writeFile(t, filepath.Join(dir, "configs", "app.json"), `{"port": 8080}`+"\n")
+	out, err := runReadmeCheck(t, map[string]any{"project_dir": dir, "mode": "check"})
+	stale, ok := IsReadmeStale(err)
+	if !ok || stale.ReadmePath != "README.md" || len(stale.Changes) == 0 {
+		t.Fatalf("expected stale README error, got %v", err)
+	}
+	if !resilience.IsPermanentError(err) {
+		t.Errorf("expected a stale README not to be retried, got %v", err)
+	}
+	if !strings.Contains(out, "README.md is stale") || !strings.Contains(out, "configs/app.json") {
+		t.Errorf("missing diff summary:\n%s", out)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(b) != "# Demo\n" {
+		t.Errorf("check mode changed the README: %q", b)
+	}
+
+	writeFile(t, filepath.Join(dir, "README.md"), "# Demo\n\nPort 8080.\n")
+	if _, err := runReadmeCheck(t, map[string]any{"project_dir": dir, "mode": "check"}); err != nil {
+		t.Errorf("README updated alongside the code should pass, got %v", err)
+	}
+
+	// Committed changes on a branch are only seen relative to base_ref.
+	run("checkout", "-q", "-b", "main-base")
+	run("checkout", "-q", "-b", "feature")
+	run("checkout", "README.md")
+	run("commit", "-qam", "change config")
+	if _, err := runReadmeCheck(t, map[string]any{"project_dir": dir, "mode": "check"}); err != nil {
+		t.Errorf("clean tree without base_ref should pass, got %v", err)
+	}
+	if _, err := runReadmeCheck(t, map[string]any{"project_dir": dir, "mode": "check", "base_ref": "main-base"}); err == nil {
+		t.Error("expected base_ref diff to flag the stale README")
+	}
+}
+
+func TestReadmeCheckCheckModeInSubdirectory(t *testing.T) {
+	dir, run := readmeRepo(t)
+	app := filepath.Join(dir, "app")
+	writeFile(t, filepath.Join(app, "README.md"), "# App\n")
+	writeFile(t, filepath.Join(app, "configs", "app.json"), "{}\n")
+	run("add", "-A")
+	run("commit", "-qm", "add app")
+	cfg := map[string]any{"project_dir": app, "mode": "check", "patterns": []string{"app/configs/*.json"}}
+
+	// Only the repo root README changes: app/README.md is still stale.
+	writeFile(t, filepath.Join(app, "configs", "app.json"), `{"port": 8080}`+"\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "# Demo\n\nRoot only.\n")
+	if _, err := runReadmeCheck(t, cfg); err == nil {
+		t.Fatal("expected the root README not to count for app/README.md")
+	} else if _, ok := IsReadmeStale(err); !ok {
+		t.Fatalf("expected stale README error, got %v", err)
+	}
+
+	writeFile(t, filepath.Join(app, "README.md"), "# App\n\nPort 8080.\n")
+	if _, err := runReadmeCheck(t, cfg); err != nil {
+		t.Errorf("app/README.md updated alongside the code should pass, got %v", err)
+	}
+}
+
+func TestReadmeCheckValidate(t *testing.T) {
+	step := NewReadmeCheckStep()
+	for _, raw := range []string{``, `{}`, `{"mode": "update"}`, `{"mode": "check"}`} {
+		if err := step.Validate(json.RawMessage(raw)); err != nil {
+			t.Errorf("Validate(%s) = %v", raw, err)
+		}
+	}
+	if err := step.Validate(json.RawMessage(`{"mode": "fix"}`)); err == nil || !strings.Contains(err.Error(), "invalid mode") {
+		t.Errorf("expected invalid mode error, got %v", err)
+	}
+}
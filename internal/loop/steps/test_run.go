@@ -0,0 +1,141 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+// TestRunConfig holds configuration for the test-run step.
+type TestRunConfig struct {
+	// Command is the test command to run, e.g. "go test ./..." or "pytest"
+	Command string `json:"command"`
+	// WorkingDir is the directory to run the command in (default: ".")
+	WorkingDir string `json:"working_dir,omitempty"`
+	// Timeout is the max execution time (default: "10m")
+	Timeout string `json:"timeout,omitempty"`
+	// LogDir is where test output is saved as test_<loop>.log (default: ".ralph/logs")
+	LogDir string `json:"log_dir,omitempty"`
+}
+
+// TestRunResult holds pass/fail counts parsed from test output.
+type TestRunResult struct {
+	Passed int
+	Failed int
+}
+
+var (
+	testPassedRe  = regexp.MustCompile(`(\d+)\s+passed`)
+	testFailedRe  = regexp.MustCompile(`(\d+)\s+failed`)
+	goTestPassRe  = regexp.MustCompile(`(?m)^\s*--- PASS:`)
+	goTestFailRe  = regexp.MustCompile(`(?m)^\s*--- FAIL:`)
+	goPkgFailedRe = regexp.MustCompile(`(?m)^FAIL\s`)
+)
+
+// TestRunStep runs the project's test suite and fails the loop iteration if tests fail.
+type TestRunStep struct {
+	name string
+}
+
+// NewTestRunStep creates a new test-run step.
+func NewTestRunStep() *TestRunStep {
+	return &TestRunStep{name: "test-run"}
+}
+
+func (s *TestRunStep) Name() string { return s.name }
+func (s *TestRunStep) Type() string { return "test-run" }
+
+func (s *TestRunStep) Execute(ctx context.Context, rawConfig json.RawMessage) error {
+	var cfg TestRunConfig
+	if err := json.Unmarshal(rawConfig, &cfg); err != nil {
+		return fmt.Errorf("failed to parse test-run config: %w", err)
+	}
+
+	if strings.TrimSpace(cfg.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if cfg.WorkingDir == "" {
+		cfg.WorkingDir = "."
+	}
+	if cfg.LogDir == "" {
+		cfg.LogDir = filepath.Join(".ralph", "logs")
+	}
+
+	timeout := 10 * time.Minute
+	if cfg.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", cfg.Command)
+	cmd.Dir = cfg.WorkingDir
+	output, runErr := cmd.CombinedOutput()
+
+	s.saveLog(cfg.LogDir, output)
+
+	result := parseTestOutput(string(output))
+	if runErr != nil || result.Failed > 0 {
+		reason := "exit status 0"
+		if runErr != nil {
+			reason = runErr.Error()
+		}
+		return fmt.Errorf("tests failed (%d passed, %d failed, %s)\nOutput: %s", result.Passed, result.Failed, reason, tailOutput(string(output), 2000))
+	}
+
+	return nil
+}
+
+// parseTestOutput extracts pass/fail counts from pytest-style summaries
+// ("5 passed, 1 failed") or verbose go test output.
+func parseTestOutput(output string) TestRunResult {
+	var r TestRunResult
+	if m := testPassedRe.FindStringSubmatch(output); len(m) > 1 {
+		r.Passed, _ = strconv.Atoi(m[1])
+	}
+	if m := testFailedRe.FindStringSubmatch(output); len(m) > 1 {
+		r.Failed, _ = strconv.Atoi(m[1])
+	}
+	if r.Passed == 0 && r.Failed == 0 {
+		r.Passed = len(goTestPassRe.FindAllString(output, -1))
+		r.Failed = len(goTestFailRe.FindAllString(output, -1))
+		if r.Failed == 0 {
+			r.Failed = len(goPkgFailedRe.FindAllString(output, -1))
+		}
+	}
+	return r
+}
+
+// saveLog writes test output to test_<loop>.log, using the loop number from run_state.json.
+func (s *TestRunStep) saveLog(logDir string, output []byte) {
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return
+	}
+	name := fmt.Sprintf("test_%s.log", time.Now().Format("20060102_150405"))
+	if rs, err := tracker.NewWriter(".ralph").LoadRunState(); err == nil && rs != nil && rs.LoopNumber > 0 {
+		name = fmt.Sprintf("test_%d.log", rs.LoopNumber)
+	}
+	_ = os.WriteFile(filepath.Join(logDir, name), output, 0644)
+}
+
+func tailOutput(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
@@ -0,0 +1,92 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chr1sbest/wiggum/internal/tracker"
+)
+
+func TestParseTestOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   TestRunResult
+	}{
+		{"pytest summary", "===== 5 passed, 2 failed, 1 skipped in 0.3s =====", TestRunResult{Passed: 5, Failed: 2}},
+		{"pytest all pass", "===== 3 passed in 0.1s =====", TestRunResult{Passed: 3}},
+		{"go verbose", "=== RUN   TestA\n--- PASS: TestA (0.00s)\n=== RUN   TestB\n--- FAIL: TestB (0.00s)\nFAIL\n", TestRunResult{Passed: 1, Failed: 1}},
+		{"go package fail", "ok  \tpkg/a\t0.1s\nFAIL\tpkg/b\t0.2s\n", TestRunResult{Failed: 1}},
+		{"no summary", "hello", TestRunResult{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTestOutput(tt.output); got != tt.want {
+				t.Errorf("parseTestOutput() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestRunStep(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	trk := tracker.NewWriter(".ralph")
+	if err := os.MkdirAll(".ralph", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := trk.WriteRunState(tracker.RunState{RunID: "run", LoopNumber: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeScript := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	passing := writeScript("pass.sh", `echo "4 passed in 0.1s"`)
+	failing := writeScript("fail.sh", `echo "3 passed, 1 failed in 0.2s"; exit 1`)
+	crashing := writeScript("crash.sh", `echo "boom" >&2; exit 2`)
+
+	tests := []struct {
+		name    string
+		command string
+		wantErr string
+	}{
+		{"passing suite", passing, ""},
+		{"failing suite", failing, "tests failed (3 passed, 1 failed"},
+		{"non-zero exit without summary", crashing, "exit status 2"},
+		{"missing command", "", "command is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _ := json.Marshal(map[string]any{"command": tt.command, "timeout": "10s"})
+			err := NewTestRunStep().Execute(context.Background(), raw)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	b, err := os.ReadFile(filepath.Join(".ralph", "logs", "test_3.log"))
+	if err != nil {
+		t.Fatalf("expected test log: %v", err)
+	}
+	if !strings.Contains(string(b), "boom") {
+		t.Errorf("expected last run output in log, got %q", string(b))
+	}
+}
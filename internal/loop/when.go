@@ -0,0 +1,433 @@
+package loop
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+)
+
+// A step's `when` expression decides whether it runs this iteration.
+//
+//	expr    := or
+//	or      := and ("||" and)*
+//	and     := unary ("&&" unary)*
+//	unary   := "!" unary | compare
+//	compare := primary (("=="|"!="|">"|">="|"<"|"<=") primary)?
+//	primary := number | "true" | "false" | string | variable | call | "(" expr ")"
+//
+// Variables: loop.number, tasks.total, tasks.done, tasks.remaining,
+// tasks.todo, tasks.failed. Calls: success(), failure(), always(), and
+// success("step")/failure("step") for a named step earlier in the iteration.
+// Expressions are type checked when parsed, so a config that validates
+// cannot fail at run time.
+
+// conditionEnv is the loop state a `when` expression can read.
+type conditionEnv struct {
+	LoopNumber int
+	Tasks      *agent.PRDStatus // nil when there is no prd.json
+	Results    []StepResult     // steps run so far this iteration, in order
+}
+
+// ValidateCondition reports whether expr is a well-formed, boolean `when`
+// expression.
+func ValidateCondition(expr string) error {
+	_, err := parseCondition(expr)
+	return err
+}
+
+// evalCondition parses and evaluates expr against env.
+func evalCondition(expr string, env conditionEnv) (bool, error) {
+	n, err := parseCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	return n.eval(env).b, nil
+}
+
+type valueKind int
+
+const (
+	kindBool valueKind = iota
+	kindNum
+	kindStr
+)
+
+func (k valueKind) String() string {
+	switch k {
+	case kindBool:
+		return "boolean"
+	case kindNum:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+type value struct {
+	b bool
+	n float64
+	s string
+}
+
+// condNode is a type-checked expression node.
+type condNode interface {
+	kind() valueKind
+	eval(env conditionEnv) value
+}
+
+type literalNode struct {
+	k valueKind
+	v value
+}
+
+func (n literalNode) kind() valueKind         { return n.k }
+func (n literalNode) eval(conditionEnv) value { return n.v }
+
+type varNode struct {
+	get func(env conditionEnv) int
+}
+
+func (n varNode) kind() valueKind             { return kindNum }
+func (n varNode) eval(env conditionEnv) value { return value{n: float64(n.get(env))} }
+
+type notNode struct{ x condNode }
+
+func (n notNode) kind() valueKind             { return kindBool }
+func (n notNode) eval(env conditionEnv) value { return value{b: !n.x.eval(env).b} }
+
+type logicNode struct {
+	and         bool
+	left, right condNode
+}
+
+func (n logicNode) kind() valueKind { return kindBool }
+
+func (n logicNode) eval(env conditionEnv) value {
+	if n.and {
+		return value{b: n.left.eval(env).b && n.right.eval(env).b}
+	}
+	return value{b: n.left.eval(env).b || n.right.eval(env).b}
+}
+
+type compareNode struct {
+	op          string
+	left, right condNode
+}
+
+func (n compareNode) kind() valueKind { return kindBool }
+
+func (n compareNode) eval(env conditionEnv) value {
+	l, r := n.left.eval(env), n.right.eval(env)
+	switch n.left.kind() {
+	case kindBool:
+		return value{b: (l.b == r.b) == (n.op == "==")}
+	case kindStr:
+		return value{b: (l.s == r.s) == (n.op == "==")}
+	}
+	var b bool
+	switch n.op {
+	case "==":
+		b = l.n == r.n
+	case "!=":
+		b = l.n != r.n
+	case ">":
+		b = l.n > r.n
+	case ">=":
+		b = l.n >= r.n
+	case "<":
+		b = l.n < r.n
+	default:
+		b = l.n <= r.n
+	}
+	return value{b: b}
+}
+
+// callNode is success(), failure() or always(), optionally naming a step.
+type callNode struct {
+	fn   string
+	step string
+}
+
+func (n callNode) kind() valueKind { return kindBool }
+
+func (n callNode) eval(env conditionEnv) value {
+	if n.fn == "always" {
+		return value{b: true}
+	}
+	wantSuccess := n.fn == "success"
+	if n.step == "" {
+		// Most recent step; with none yet, the iteration counts as successful.
+		if len(env.Results) == 0 {
+			return value{b: wantSuccess}
+		}
+		return value{b: env.Results[len(env.Results)-1].Success == wantSuccess}
+	}
+	for i := len(env.Results) - 1; i >= 0; i-- {
+		if env.Results[i].StepName == n.step {
+			return value{b: env.Results[i].Success == wantSuccess}
+		}
+	}
+	return value{b: false}
+}
+
+// taskVars are the tasks.* variables, read from prd.json (0 without one).
+var taskVars = map[string]func(s *agent.PRDStatus) int{
+	"tasks.total":     func(s *agent.PRDStatus) int { return s.TotalTasks },
+	"tasks.done":      func(s *agent.PRDStatus) int { return s.CompletedTasks },
+	"tasks.remaining": func(s *agent.PRDStatus) int { return s.IncompleteTasks },
+	"tasks.todo":      func(s *agent.PRDStatus) int { return s.TodoTasks },
+	"tasks.failed":    func(s *agent.PRDStatus) int { return s.FailedTasks },
+}
+
+func lookupVar(name string) (varNode, bool) {
+	if name == "loop.number" {
+		return varNode{get: func(env conditionEnv) int { return env.LoopNumber }}, true
+	}
+	f, ok := taskVars[name]
+	if !ok {
+		return varNode{}, false
+	}
+	return varNode{get: func(env conditionEnv) int {
+		if env.Tasks == nil {
+			return 0
+		}
+		return f(env.Tasks)
+	}}, true
+}
+
+// --- parsing ---
+
+type condToken struct {
+	typ  string // "num", "str", "ident", "op", "eof"
+	text string
+	pos  int
+}
+
+func tokenizeCondition(expr string) ([]condToken, error) {
+	var toks []condToken
+	i := 0
+	for i < len(expr) {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(expr) && (unicode.IsDigit(rune(expr[i])) || expr[i] == '.') {
+				i++
+			}
+			toks = append(toks, condToken{"num", expr[start:i], start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(expr) && (unicode.IsLetter(rune(expr[i])) || unicode.IsDigit(rune(expr[i])) || expr[i] == '_' || expr[i] == '.') {
+				i++
+			}
+			toks = append(toks, condToken{"ident", expr[start:i], start})
+		case c == '"' || c == '\'':
+			start := i
+			end := strings.IndexByte(expr[i+1:], byte(c))
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			toks = append(toks, condToken{"str", expr[i+1 : i+1+end], start})
+			i += end + 2
+		default:
+			start := i
+			two := ""
+			if i+1 < len(expr) {
+				two = expr[i : i+2]
+			}
+			switch two {
+			case "&&", "||", "==", "!=", ">=", "<=":
+				toks = append(toks, condToken{"op", two, start})
+				i += 2
+				continue
+			}
+			switch c {
+			case '!', '>', '<', '(', ')':
+				toks = append(toks, condToken{"op", string(c), start})
+				i++
+			default:
+				return nil, fmt.Errorf("unexpected %q at position %d", c, start)
+			}
+		}
+	}
+	return append(toks, condToken{"eof", "", len(expr)}), nil
+}
+
+type condParser struct {
+	toks []condToken
+	pos  int
+}
+
+func parseCondition(expr string) (condNode, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	toks, err := tokenizeCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &condParser{toks: toks}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != "eof" {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+	if n.kind() != kindBool {
+		return nil, fmt.Errorf("expression is a %s, not a boolean", n.kind())
+	}
+	return n, nil
+}
+
+func (p *condParser) peek() condToken { return p.toks[p.pos] }
+
+func (p *condParser) next() condToken {
+	t := p.toks[p.pos]
+	if t.typ != "eof" {
+		p.pos++
+	}
+	return t
+}
+
+func (p *condParser) acceptOp(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.typ != "op" {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	return p.parseLogic("||", p.parseAnd)
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	return p.parseLogic("&&", p.parseUnary)
+}
+
+func (p *condParser) parseLogic(op string, operand func() (condNode, error)) (condNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if _, ok := p.acceptOp(op); !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if left.kind() != kindBool || right.kind() != kindBool {
+			return nil, fmt.Errorf("%s at position %d needs boolean operands", op, t.pos)
+		}
+		left = logicNode{and: op == "&&", left: left, right: right}
+	}
+}
+
+func (p *condParser) parseUnary() (condNode, error) {
+	t := p.peek()
+	if _, ok := p.acceptOp("!"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if x.kind() != kindBool {
+			return nil, fmt.Errorf("! at position %d needs a boolean operand", t.pos)
+		}
+		return notNode{x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *condParser) parseCompare() (condNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	op, ok := p.acceptOp("==", "!=", ">", ">=", "<", "<=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if left.kind() != right.kind() {
+		return nil, fmt.Errorf("cannot compare %s with %s at position %d", left.kind(), right.kind(), t.pos)
+	}
+	if left.kind() != kindNum && op != "==" && op != "!=" {
+		return nil, fmt.Errorf("%s at position %d needs numbers", op, t.pos)
+	}
+	return compareNode{op: op, left: left, right: right}, nil
+}
+
+func (p *condParser) parsePrimary() (condNode, error) {
+	t := p.next()
+	switch t.typ {
+	case "num":
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return literalNode{k: kindNum, v: value{n: n}}, nil
+	case "str":
+		return literalNode{k: kindStr, v: value{s: t.text}}, nil
+	case "ident":
+		switch t.text {
+		case "true", "false":
+			return literalNode{k: kindBool, v: value{b: t.text == "true"}}, nil
+		case "success", "failure", "always":
+			return p.parseCall(t)
+		}
+		if v, ok := lookupVar(t.text); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("unknown name %q at position %d", t.text, t.pos)
+	case "op":
+		if t.text == "(" {
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.acceptOp(")"); !ok {
+				return nil, fmt.Errorf("missing ) at position %d", p.peek().pos)
+			}
+			return n, nil
+		}
+	case "eof":
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+func (p *condParser) parseCall(name condToken) (condNode, error) {
+	if _, ok := p.acceptOp("("); !ok {
+		return nil, fmt.Errorf("%s at position %d must be called, e.g. %s()", name.text, name.pos, name.text)
+	}
+	call := callNode{fn: name.text}
+	if t := p.peek(); t.typ == "str" {
+		if name.text == "always" {
+			return nil, fmt.Errorf("always() takes no arguments")
+		}
+		call.step = p.next().text
+	}
+	if _, ok := p.acceptOp(")"); !ok {
+		return nil, fmt.Errorf("missing ) at position %d", p.peek().pos)
+	}
+	return call, nil
+}
@@ -0,0 +1,113 @@
+package loop
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+)
+
+func TestEvalCondition(t *testing.T) {
+	env := conditionEnv{
+		LoopNumber: 3,
+		Tasks:      &agent.PRDStatus{TotalTasks: 5, CompletedTasks: 2, IncompleteTasks: 3, TodoTasks: 2, FailedTasks: 1},
+		Results: []StepResult{
+			{StepName: "agent", Success: true},
+			{StepName: "test-run", Success: false},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"tasks.remaining > 0", true},
+		{"tasks.remaining == 0", false},
+		{"loop.number > 1", true},
+		{"loop.number >= 3 && loop.number <= 3", true},
+		{"loop.number != 3", false},
+		{"tasks.done < tasks.total", true},
+		{"tasks.failed == 1 && tasks.todo == 2", true},
+		{"success()", false},
+		{"failure()", true},
+		{`success("agent")`, true},
+		{`failure('test-run')`, true},
+		{`success("test-run")`, false},
+		{`success("never-ran") || failure("never-ran")`, false},
+		{"always()", true},
+		{"!success()", true},
+		{"!(tasks.remaining > 0) || loop.number == 3", true},
+		{"tasks.remaining > 0 && success()", false},
+		{"true", true},
+		{"false || true && false", false},
+		{"success() == false", true},
+		{"1.5 < 2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalCondition(tt.expr, env)
+			if err != nil {
+				t.Fatalf("evalCondition(%q) error: %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("evalCondition(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalConditionFirstStepAndNoPRD(t *testing.T) {
+	env := conditionEnv{LoopNumber: 1}
+
+	for expr, want := range map[string]bool{
+		"success()":           true,
+		"failure()":           false,
+		"tasks.remaining > 0": false,
+		"tasks.total == 0":    true,
+	} {
+		got, err := evalCondition(expr, env)
+		if err != nil {
+			t.Fatalf("evalCondition(%q) error: %v", expr, err)
+		}
+		if got != want {
+			t.Errorf("evalCondition(%q) = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestValidateCondition(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "empty expression"},
+		{"tasks.remaining", "not a boolean"},
+		{"tasks.remianing > 0", `unknown name "tasks.remianing"`},
+		{"success", "must be called"},
+		{"success(", "missing )"},
+		{"always('x')", "takes no arguments"},
+		{"loop.number > ", "unexpected end"},
+		{"loop.number > 1)", `unexpected ")"`},
+		{"(loop.number > 1", "missing )"},
+		{"success() > 1", "cannot compare"},
+		{"success() > true", "needs numbers"},
+		{"loop.number && true", "needs boolean operands"},
+		{"!loop.number", "needs a boolean operand"},
+		{"loop.number = 1", `unexpected '='`},
+		{`success("unterminated)`, "unterminated string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			err := ValidateCondition(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateCondition(%q) = %v, want error containing %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := ValidateCondition(`tasks.remaining > 0 && success("test-run")`); err != nil {
+		t.Errorf("valid expression rejected: %v", err)
+	}
+}
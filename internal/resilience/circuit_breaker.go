@@ -196,11 +196,51 @@ func (cb *CircuitBreaker) Reset() {
 	cb.setState(CircuitClosed)
 }
 
+// CircuitSnapshot is the persistable state of a circuit breaker.
+type CircuitSnapshot struct {
+	State       string    `json:"state"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure,omitempty"`
+}
+
+// Snapshot returns the breaker's current state for persistence.
+func (cb *CircuitBreaker) Snapshot() CircuitSnapshot {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return CircuitSnapshot{
+		State:       cb.state.String(),
+		Failures:    cb.failures,
+		LastFailure: cb.lastFailure,
+	}
+}
+
+// Restore loads a previously saved snapshot. An open breaker whose ResetAfter
+// has already elapsed comes back half-open.
+func (cb *CircuitBreaker) Restore(s CircuitSnapshot) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures = s.Failures
+	cb.lastFailure = s.LastFailure
+	switch s.State {
+	case CircuitOpen.String():
+		cb.state = CircuitOpen
+		if time.Since(s.LastFailure) >= cb.config.ResetAfter {
+			cb.state = CircuitHalfOpen
+		}
+	case CircuitHalfOpen.String():
+		cb.state = CircuitHalfOpen
+	default:
+		cb.state = CircuitClosed
+	}
+}
+
 // CircuitBreakerRegistry manages circuit breakers per step.
 type CircuitBreakerRegistry struct {
 	mu       sync.RWMutex
 	breakers map[string]*CircuitBreaker
 	defaults CircuitBreakerConfig
+	restored map[string]CircuitSnapshot // applied when a breaker is first created
 }
 
 // NewCircuitBreakerRegistry creates a new registry.
@@ -227,10 +267,49 @@ func (r *CircuitBreakerRegistry) Get(stepName string, cfg *CircuitBreakerConfig)
 	}
 
 	cb := NewCircuitBreaker(useCfg)
+	if snap, ok := r.restored[stepName]; ok {
+		cb.Restore(snap)
+		delete(r.restored, stepName)
+	}
 	r.breakers[stepName] = cb
 	return cb
 }
 
+// Snapshot returns the state of every breaker, keyed by step name.
+// Restored breakers that haven't been used yet are included as-is.
+func (r *CircuitBreakerRegistry) Snapshot() map[string]CircuitSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]CircuitSnapshot, len(r.breakers)+len(r.restored))
+	for name, snap := range r.restored {
+		out[name] = snap
+	}
+	for name, cb := range r.breakers {
+		out[name] = cb.Snapshot()
+	}
+	return out
+}
+
+// Restore seeds breakers from saved snapshots. Existing breakers are updated
+// immediately; others pick up their snapshot when first requested via Get, so
+// per-step config (e.g. ResetAfter) is applied.
+func (r *CircuitBreakerRegistry) Restore(snapshots map[string]CircuitSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.restored == nil {
+		r.restored = make(map[string]CircuitSnapshot)
+	}
+	for name, snap := range snapshots {
+		if cb, exists := r.breakers[name]; exists {
+			cb.Restore(snap)
+			continue
+		}
+		r.restored[name] = snap
+	}
+}
+
 // State returns the state of a specific step's circuit breaker.
 func (r *CircuitBreakerRegistry) State(stepName string) (CircuitState, bool) {
 	r.mu.RLock()
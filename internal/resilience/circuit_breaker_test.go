@@ -267,3 +267,64 @@ func TestCircuitState_String(t *testing.T) {
 		}
 	}
 }
+
+func TestCircuitBreakerRegistry_SnapshotRestore(t *testing.T) {
+	cfg := &CircuitBreakerConfig{Threshold: 2, ResetAfter: time.Hour}
+	reg := NewCircuitBreakerRegistry(DefaultCircuitBreakerConfig())
+
+	cb := reg.Get("agent", cfg)
+	for i := 0; i < 2; i++ {
+		_ = cb.Execute(context.Background(), func(ctx context.Context) error {
+			return errors.New("boom")
+		})
+	}
+	_ = reg.Get("commit", nil).Execute(context.Background(), func(ctx context.Context) error {
+		return errors.New("once")
+	})
+
+	snaps := reg.Snapshot()
+	if snaps["agent"].State != "open" || snaps["agent"].Failures != 2 {
+		t.Fatalf("unexpected agent snapshot: %+v", snaps["agent"])
+	}
+
+	// Fresh registry, as after a restart.
+	restored := NewCircuitBreakerRegistry(DefaultCircuitBreakerConfig())
+	restored.Restore(snaps)
+
+	// Unused restored breakers still round-trip.
+	if got := restored.Snapshot(); got["agent"] != snaps["agent"] || got["commit"] != snaps["commit"] {
+		t.Errorf("snapshot round-trip mismatch: got %+v, want %+v", got, snaps)
+	}
+
+	agent := restored.Get("agent", cfg)
+	if agent.State() != CircuitOpen {
+		t.Errorf("expected restored breaker to be open, got %v", agent.State())
+	}
+	if agent.Failures() != 2 {
+		t.Errorf("expected 2 failures, got %d", agent.Failures())
+	}
+	if s := restored.Get("commit", nil); s.State() != CircuitClosed || s.Failures() != 1 {
+		t.Errorf("expected closed breaker with 1 failure, got %v/%d", s.State(), s.Failures())
+	}
+}
+
+func TestCircuitBreaker_RestoreAfterResetElapsed(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{Threshold: 2, ResetAfter: time.Minute})
+	cb.Restore(CircuitSnapshot{
+		State:       "open",
+		Failures:    3,
+		LastFailure: time.Now().Add(-2 * time.Minute),
+	})
+
+	if cb.State() != CircuitHalfOpen {
+		t.Fatalf("expected half-open after ResetAfter elapsed, got %v", cb.State())
+	}
+
+	// A successful probe closes the circuit.
+	if err := cb.Execute(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cb.State() != CircuitClosed {
+		t.Errorf("expected closed after successful probe, got %v", cb.State())
+	}
+}
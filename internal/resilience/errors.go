@@ -29,6 +29,21 @@ func NewPermanentError(err error) error {
 	return &PermanentError{Err: err}
 }
 
+// MarkPermanent wraps err as a PermanentError if any of the predicates match it.
+// Callers use this to classify domain errors (e.g. quota exhaustion) that retrying
+// can't fix. Errors that match nothing are returned unchanged.
+func MarkPermanent(err error, predicates ...func(error) bool) error {
+	if err == nil {
+		return nil
+	}
+	for _, match := range predicates {
+		if match(err) {
+			return NewPermanentError(err)
+		}
+	}
+	return err
+}
+
 // TransientError wraps an error to mark it as retryable.
 type TransientError struct {
 	Err error
@@ -3,10 +3,12 @@ package resilience
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"os"
 	"syscall"
 	"testing"
+	"time"
 )
 
 func TestPermanentError(t *testing.T) {
@@ -204,3 +206,42 @@ func TestClassifyError_DNS(t *testing.T) {
 		t.Error("DNS temporary failure should be transient")
 	}
 }
+
+type quotaError struct{}
+
+func (quotaError) Error() string { return "quota exhausted" }
+
+func TestMarkPermanent(t *testing.T) {
+	isQuota := func(err error) bool {
+		var q quotaError
+		return errors.As(err, &q)
+	}
+
+	if MarkPermanent(nil, isQuota) != nil {
+		t.Error("expected nil for nil error")
+	}
+
+	plain := errors.New("flaky")
+	if got := MarkPermanent(plain, isQuota); got != plain {
+		t.Errorf("expected unmatched error unchanged, got %v", got)
+	}
+
+	marked := MarkPermanent(fmt.Errorf("wrapped: %w", quotaError{}), isQuota)
+	if !IsPermanentError(marked) {
+		t.Errorf("expected matched error to be permanent")
+	}
+	if !isQuota(marked) {
+		t.Errorf("expected original error to remain reachable via errors.As")
+	}
+
+	// Permanent errors must not be retried.
+	calls := 0
+	cfg := RetryConfig{MaxRetries: 3, InitDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	_ = Retry(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		return MarkPermanent(quotaError{}, isQuota)
+	})
+	if calls != 1 {
+		t.Errorf("expected 1 call for classified permanent error, got %d", calls)
+	}
+}
@@ -0,0 +1,85 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSpacing(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	l := NewRateLimiter(float64(time.Second/interval), 1)
+
+	ctx := context.Background()
+	var times []time.Time
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error: %v", err)
+		}
+		times = append(times, time.Now())
+	}
+
+	for i := 1; i < len(times); i++ {
+		// Allow a little timer slack below the interval.
+		if gap := times[i].Sub(times[i-1]); gap < interval-5*time.Millisecond {
+			t.Errorf("acquisition %d came %s after the previous one, want >= %s", i+1, gap, interval)
+		}
+	}
+	if total := times[len(times)-1].Sub(times[0]); total > 3*interval+100*time.Millisecond {
+		t.Errorf("4 acquisitions took %s, want about %s", total, 3*interval)
+	}
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	l := NewRateLimiter(1, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("burst of 3 took %s, want no waiting", elapsed)
+	}
+}
+
+func TestRateLimiterRespectsCancellation(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := l.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("cancelled Wait() returned after %s, want promptly", elapsed)
+	}
+
+	cancelled, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	if err := l.Wait(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() on cancelled ctx = %v, want context.Canceled", err)
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	for _, rps := range []float64{0, -1} {
+		if l := NewRateLimiter(rps, 1); l != nil {
+			t.Errorf("NewRateLimiter(%v) = %v, want nil", rps, l)
+		}
+	}
+
+	var l *RateLimiter
+	for i := 0; i < 100; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("nil limiter Wait() error: %v", err)
+		}
+	}
+}
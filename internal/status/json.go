@@ -0,0 +1,128 @@
+package status
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chr1sbest/wiggum/internal/agent"
+)
+
+// Progress states reported by JSONWriter.
+const (
+	StateRunning     = "running"
+	StateRetrying    = "retrying"
+	StateComplete    = "complete"
+	StateError       = "error"
+	StateCircuitOpen = "circuit_open"
+	StateTasksDone   = "tasks_done"
+	StateOutput      = "output"
+)
+
+// ProgressRecord is one line of the JSON progress stream.
+type ProgressRecord struct {
+	Time           time.Time `json:"time"`
+	Loop           int       `json:"loop"`
+	StepIndex      int       `json:"step_index,omitempty"`
+	StepTotal      int       `json:"step_total"`
+	Step           string    `json:"step,omitempty"`
+	State          string    `json:"state"`
+	Attempt        int       `json:"attempt,omitempty"`
+	MaxRetries     int       `json:"max_retries,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	TasksCompleted *int      `json:"tasks_completed,omitempty"`
+	TasksTotal     *int      `json:"tasks_total,omitempty"`
+	DoneTaskIDs    []string  `json:"done_task_ids,omitempty"`
+	Output         string    `json:"output,omitempty"`
+}
+
+// JSONWriter reports loop progress as newline-delimited JSON, one
+// ProgressRecord per update, for tools that wrap ralph.
+type JSONWriter struct {
+	w       io.Writer
+	mu      sync.Mutex
+	prdPath string
+}
+
+// NewJSON creates a JSON progress writer that outputs to stdout
+func NewJSON() *JSONWriter {
+	return NewJSONWithWriter(os.Stdout)
+}
+
+// NewJSONWithWriter creates a JSON progress writer with a custom output
+func NewJSONWithWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{w: w, prdPath: ".ralph/prd.json"}
+}
+
+// write emits rec with the current task counts from prd.json.
+func (s *JSONWriter) write(rec ProgressRecord) {
+	if prdStatus, _ := agent.LoadPRDStatus(s.prdPath); prdStatus != nil {
+		completed, total := prdStatus.CompletedTasks, prdStatus.TotalTasks
+		rec.TasksCompleted, rec.TasksTotal = &completed, &total
+	}
+	s.emit(rec)
+}
+
+// emit writes rec as one line without reading prd.json.
+func (s *JSONWriter) emit(rec ProgressRecord) {
+	rec.Time = time.Now().UTC()
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w.Write(append(data, '\n'))
+}
+
+// Step reports that a step started
+func (s *JSONWriter) Step(loopNum, stepNum, totalSteps int, stepName string) {
+	s.write(ProgressRecord{Loop: loopNum, StepIndex: stepNum, StepTotal: totalSteps, Step: stepName, State: StateRunning})
+}
+
+// StepWithRetry reports that a step is being retried
+func (s *JSONWriter) StepWithRetry(loopNum, stepNum, totalSteps int, stepName string, attempt, maxRetries int) {
+	s.write(ProgressRecord{
+		Loop:       loopNum,
+		StepIndex:  stepNum,
+		StepTotal:  totalSteps,
+		Step:       stepName,
+		State:      StateRetrying,
+		Attempt:    attempt,
+		MaxRetries: maxRetries,
+	})
+}
+
+// Complete reports that a loop iteration finished
+func (s *JSONWriter) Complete(loopNum, totalSteps int) {
+	s.write(ProgressRecord{Loop: loopNum, StepTotal: totalSteps, State: StateComplete})
+}
+
+// Error reports that a step failed
+func (s *JSONWriter) Error(loopNum, stepNum, totalSteps int, stepName string, err error) {
+	rec := ProgressRecord{Loop: loopNum, StepIndex: stepNum, StepTotal: totalSteps, Step: stepName, State: StateError}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	s.write(rec)
+}
+
+// CircuitOpen reports that a step was skipped by its circuit breaker
+func (s *JSONWriter) CircuitOpen(loopNum, stepNum, totalSteps int, stepName string) {
+	s.write(ProgressRecord{Loop: loopNum, StepIndex: stepNum, StepTotal: totalSteps, Step: stepName, State: StateCircuitOpen})
+}
+
+// TasksDone reports the tasks that became done during a loop iteration
+func (s *JSONWriter) TasksDone(loopNum int, taskIDs []string) {
+	s.write(ProgressRecord{Loop: loopNum, State: StateTasksDone, DoneTaskIDs: taskIDs})
+}
+
+// StepOutput reports a line of output from a running step. Output records
+// carry no task counts: a step can print thousands of lines, and prd.json
+// only changes between steps.
+func (s *JSONWriter) StepOutput(loopNum int, stepName, line string) {
+	s.emit(ProgressRecord{Loop: loopNum, Step: stepName, State: StateOutput, Output: line})
+}
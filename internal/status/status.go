@@ -37,6 +37,7 @@ type Writer struct {
 	w            io.Writer
 	mu           sync.Mutex
 	linesWritten int
+	lastLines    []string // Status lines drawn by the last Update
 	startTime    time.Time
 }
 
@@ -86,6 +87,28 @@ func (s *Writer) Update(lines ...string) {
 		fmt.Fprintln(s.w, line)
 	}
 	s.linesWritten = len(lines)
+	s.lastLines = lines
+}
+
+// StepOutput prints a line of step output above the status, which is
+// redrawn below it
+func (s *Writer) StepOutput(loopNum int, stepName, line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	redraw := s.lastLines
+	if s.linesWritten == 0 {
+		redraw = nil // status was replaced by persistent output
+	}
+	for i := 0; i < s.linesWritten; i++ {
+		fmt.Fprint(s.w, moveUp+clearLine)
+	}
+	fmt.Fprint(s.w, moveToCol0)
+	fmt.Fprintf(s.w, "%s  %s%s\n", dim, line, reset)
+	for _, l := range redraw {
+		fmt.Fprintln(s.w, l)
+	}
+	s.linesWritten = len(redraw)
 }
 
 // progressBar generates a progress bar string
@@ -187,6 +210,18 @@ func (s *Writer) Error(loopNum, stepNum, totalSteps int, stepName string, err er
 	s.linesWritten = 0 // don't clear error messages
 }
 
+// TasksDone prints the tasks that became done during a loop iteration
+func (s *Writer) TasksDone(loopNum int, taskIDs []string) {
+	s.Clear()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Print as a persistent line so completions stay in the scrollback
+	fmt.Fprintf(s.w, "%s✓ Loop %d done: %s%s\n", green, loopNum, strings.Join(taskIDs, ", "), reset)
+
+	s.linesWritten = 0
+}
+
 // Waiting shows waiting status between loops
 func (s *Writer) Waiting(loopNum, totalSteps int) {
 	bar := progressBar(totalSteps, totalSteps)
@@ -0,0 +1,56 @@
+package tracker
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader is the column order ExportCSV writes.
+var csvHeader = []string{
+	"run_id", "started_at", "model", "loops",
+	"input_tokens", "output_tokens", "total_tokens", "cost_usd", "tasks_completed",
+}
+
+// ExportCSV writes metrics_history.jsonl as CSV, one row per run, oldest
+// first. An empty or missing history writes just the header.
+func (w *Writer) ExportCSV(out io.Writer) error {
+	records, err := w.LoadHistory()
+	if err != nil {
+		return err
+	}
+	return WriteHistoryCSV(out, records)
+}
+
+// WriteHistoryCSV writes records as CSV with a header row. started_at is
+// derived from completion time and elapsed seconds, and left empty for
+// records that didn't store how long they ran.
+func WriteHistoryCSV(out io.Writer, records []HistoryRecord) error {
+	cw := csv.NewWriter(out)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		startedAt := ""
+		if !r.CompletedAt.IsZero() && r.ElapsedSec > 0 {
+			startedAt = r.CompletedAt.Add(-time.Duration(r.ElapsedSec) * time.Second).UTC().Format(time.RFC3339)
+		}
+		row := []string{
+			r.RunID,
+			startedAt,
+			r.Model,
+			strconv.Itoa(r.Loops),
+			strconv.Itoa(r.InputTokens),
+			strconv.Itoa(r.OutputTokens),
+			strconv.Itoa(r.TotalTokens),
+			strconv.FormatFloat(r.CostUSD, 'f', -1, 64),
+			strconv.Itoa(r.TasksCompleted),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,41 @@
+package tracker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportCSV(t *testing.T) {
+	w := NewWriter(t.TempDir())
+
+	var empty strings.Builder
+	if err := w.ExportCSV(&empty); err != nil {
+		t.Fatalf("ExportCSV(no history) error: %v", err)
+	}
+	header := "run_id,started_at,model,loops,input_tokens,output_tokens,total_tokens,cost_usd,tasks_completed\n"
+	if empty.String() != header {
+		t.Errorf("ExportCSV(no history) = %q, want just the header", empty.String())
+	}
+
+	completed := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+	for _, r := range []HistoryRecord{
+		{RunID: "run-1", CompletedAt: completed, ElapsedSec: 1800, Model: "sonnet", Loops: 4, InputTokens: 1200, OutputTokens: 300, TotalTokens: 1500, CostUSD: 0.42, TasksCompleted: 3},
+		{RunID: "run-2", CompletedAt: completed, Model: "opus, 4", Loops: 1, TotalTokens: 10},
+	} {
+		if err := w.AppendHistory(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var sb strings.Builder
+	if err := w.ExportCSV(&sb); err != nil {
+		t.Fatalf("ExportCSV() error: %v", err)
+	}
+	want := header +
+		"run-1,2026-03-04T10:00:00Z,sonnet,4,1200,300,1500,0.42,3\n" +
+		"run-2,,\"opus, 4\",1,0,0,10,0,0\n"
+	if sb.String() != want {
+		t.Errorf("ExportCSV() =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
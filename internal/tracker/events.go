@@ -0,0 +1,51 @@
+package tracker
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Event types written to events.jsonl.
+const (
+	EventLoopStart   = "loop_start"
+	EventStepStart   = "step_start"
+	EventStepSuccess = "step_success"
+	EventStepError   = "step_error"
+	EventRetry       = "retry"
+	EventCircuitOpen = "circuit_open"
+	EventTaskFailed  = "task_failed"
+	EventTasksDone   = "tasks_done"
+	EventComplete    = "complete"
+	EventInterrupted = "interrupted"
+)
+
+// Event is one line of the machine-readable run log.
+type Event struct {
+	Time   time.Time      `json:"ts"`
+	RunID  string         `json:"run_id,omitempty"`
+	Type   string         `json:"type"`
+	Loop   int            `json:"loop"`
+	Step   string         `json:"step,omitempty"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// AppendEvent appends e as a single JSON line to events.jsonl.
+func (w *Writer) AppendEvent(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(w.EventsPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
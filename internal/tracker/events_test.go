@@ -0,0 +1,45 @@
+package tracker
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestAppendEvent(t *testing.T) {
+	w := NewWriter(t.TempDir())
+
+	if err := w.AppendEvent(Event{Type: EventLoopStart, Loop: 1, RunID: "r1"}); err != nil {
+		t.Fatalf("AppendEvent failed: %v", err)
+	}
+	if err := w.AppendEvent(Event{Type: EventStepError, Loop: 1, Step: "claude", Fields: map[string]any{"error": "boom"}}); err != nil {
+		t.Fatalf("AppendEvent failed: %v", err)
+	}
+
+	f, err := os.Open(w.EventsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var events []Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", sc.Text(), err)
+		}
+		events = append(events, e)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Type != EventLoopStart || events[0].RunID != "r1" || events[0].Time.IsZero() {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[1].Step != "claude" || events[1].Fields["error"] != "boom" {
+		t.Errorf("unexpected second event: %+v", events[1])
+	}
+}
@@ -0,0 +1,104 @@
+package tracker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryRecord is a compact summary of one completed run in metrics_history.jsonl.
+type HistoryRecord struct {
+	RunID          string    `json:"run_id"`
+	CompletedAt    time.Time `json:"completed_at"`
+	Model          string    `json:"model,omitempty"`
+	Loops          int       `json:"loops"`
+	InputTokens    int       `json:"input_tokens"`
+	OutputTokens   int       `json:"output_tokens"`
+	TotalTokens    int       `json:"total_tokens"`
+	CostUSD        float64   `json:"cost_usd,omitempty"`
+	TasksCompleted int       `json:"tasks_completed"`
+	ElapsedSec     int64     `json:"elapsed_sec,omitempty"`
+}
+
+// AppendHistory appends r as a single JSON line to metrics_history.jsonl.
+func (w *Writer) AppendHistory(r HistoryRecord) error {
+	if r.CompletedAt.IsZero() {
+		r.CompletedAt = time.Now()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(w.HistoryPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadHistory reads all records from metrics_history.jsonl, oldest first.
+// A missing file returns no records; malformed lines are skipped.
+func (w *Writer) LoadHistory() ([]HistoryRecord, error) {
+	f, err := os.Open(w.HistoryPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []HistoryRecord
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r HistoryRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, sc.Err()
+}
+
+// ParseWindow parses a history window such as "90m", "24h" or "7d". It
+// accepts Go durations plus whole days with a "d" suffix.
+func ParseWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q (use e.g. \"90m\", \"24h\", \"7d\")", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid window %q (use e.g. \"90m\", \"24h\", \"7d\")", s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("window %q must be positive", s)
+	}
+	return d, nil
+}
+
+// FilterHistorySince returns the records completed at or after since,
+// keeping their order.
+func FilterHistorySince(records []HistoryRecord, since time.Time) []HistoryRecord {
+	var out []HistoryRecord
+	for _, r := range records {
+		if !r.CompletedAt.Before(since) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package tracker
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendHistoryAccumulates(t *testing.T) {
+	w := NewWriter(t.TempDir())
+
+	records := []HistoryRecord{
+		{RunID: "run-1", Model: "sonnet", Loops: 3, TotalTokens: 100, CostUSD: 0.25, TasksCompleted: 1},
+		{RunID: "run-2", Model: "opus", Loops: 5, TotalTokens: 400, CostUSD: 1.5, TasksCompleted: 2},
+		{RunID: "run-3", Model: "sonnet", Loops: 1, TotalTokens: 50, TasksCompleted: 0},
+	}
+	for i, r := range records {
+		if err := w.AppendHistory(r); err != nil {
+			t.Fatalf("AppendHistory failed: %v", err)
+		}
+
+		got, err := w.LoadHistory()
+		if err != nil {
+			t.Fatalf("LoadHistory failed: %v", err)
+		}
+		if len(got) != i+1 {
+			t.Fatalf("after %d appends expected %d records, got %d", i+1, i+1, len(got))
+		}
+		for j := 0; j <= i; j++ {
+			if got[j].RunID != records[j].RunID || got[j].TotalTokens != records[j].TotalTokens || got[j].Model != records[j].Model {
+				t.Errorf("record %d changed: got %+v, want %+v", j, got[j], records[j])
+			}
+			if got[j].CompletedAt.IsZero() {
+				t.Errorf("record %d missing completed_at", j)
+			}
+		}
+	}
+
+	data, err := os.ReadFile(w.HistoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != len(records) {
+		t.Errorf("expected %d lines, got %d", len(records), lines)
+	}
+}
+
+func TestLoadHistorySkipsMalformedLines(t *testing.T) {
+	w := NewWriter(t.TempDir())
+
+	if got, err := w.LoadHistory(); err != nil || got != nil {
+		t.Fatalf("expected no history for missing file, got %v, %v", got, err)
+	}
+
+	content := `{"run_id":"a","loops":1}` + "\n" + `not json` + "\n" + `{"run_id":"b","loops":2}` + "\n"
+	if err := os.WriteFile(w.HistoryPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := w.LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	if len(got) != 2 || got[0].RunID != "a" || got[1].RunID != "b" {
+		t.Errorf("unexpected history: %+v", got)
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"24h", 24 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{" 1h30m ", 90 * time.Minute, false},
+		{"", 0, true},
+		{"1.5d", 0, true},
+		{"d", 0, true},
+		{"0h", 0, true},
+		{"-2d", 0, true},
+		{"week", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWindow(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseWindow(%q) = %v, %v; want %v (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFilterHistorySince(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	records := []HistoryRecord{
+		{RunID: "old", CompletedAt: now.Add(-48 * time.Hour)},
+		{RunID: "edge", CompletedAt: now.Add(-24 * time.Hour)},
+		{RunID: "recent", CompletedAt: now.Add(-time.Hour)},
+	}
+	got := FilterHistorySince(records, now.Add(-24*time.Hour))
+	if len(got) != 2 || got[0].RunID != "edge" || got[1].RunID != "recent" {
+		t.Errorf("FilterHistorySince() = %+v, want edge and recent", got)
+	}
+	if got := FilterHistorySince(records, now); len(got) != 0 {
+		t.Errorf("expected no records after now, got %+v", got)
+	}
+}
@@ -13,15 +13,57 @@ type Lock struct {
 	PID       int       `json:"pid"`
 	StartedAt time.Time `json:"started_at"`
 	RunID     string    `json:"run_id"`
+	Host      string    `json:"host,omitempty"` // os.Hostname of the holder; empty in locks from older versions
 }
 
 var ErrLockHeld = errors.New("ralph lock is held")
 
+// LockHeldError describes the holder of a lock AcquireLock couldn't take.
+// It matches ErrLockHeld with errors.Is.
+type LockHeldError struct {
+	Lock      *Lock // nil when the lock file can't be parsed
+	Stale     bool  // the holder is no longer running, or the lock has been unreadable past UnreadableLockGrace
+	OtherHost bool  // the lock was taken on another host, so its PID can't be checked here
+}
+
+func (e *LockHeldError) Error() string {
+	switch {
+	case e.Lock == nil && e.Stale:
+		return fmt.Sprintf("%s (lock file exists but can't be read); rerun with -force to clear it", ErrLockHeld)
+	case e.Lock == nil:
+		return fmt.Sprintf("%s (lock file exists but can't be read yet)", ErrLockHeld)
+	case e.OtherHost:
+		return fmt.Sprintf("%s by pid %d on host %s (run_id=%s); rerun with -force if that run has stopped", ErrLockHeld, e.Lock.PID, e.Lock.Host, e.Lock.RunID)
+	case e.Stale:
+		return fmt.Sprintf("%s by pid %d (run_id=%s), which is no longer running; rerun with -force to clear it", ErrLockHeld, e.Lock.PID, e.Lock.RunID)
+	default:
+		return fmt.Sprintf("%s by pid %d (run_id=%s)", ErrLockHeld, e.Lock.PID, e.Lock.RunID)
+	}
+}
+
+func (e *LockHeldError) Is(target error) bool { return target == ErrLockHeld }
+
+// AcquireLock takes the lock for runID. A lock left by a dead process on this
+// host is removed and the acquire retried once; any other existing lock is
+// reported as a *LockHeldError.
 func (w *Writer) AcquireLock(runID string) (func() error, error) {
+	release, err := w.acquireLock(runID)
+	var held *LockHeldError
+	if errors.As(err, &held) && held.Stale && held.Lock != nil && !held.OtherHost {
+		// Process is dead, remove stale lock and retry once
+		if removeErr := os.Remove(w.LockPath); removeErr == nil || os.IsNotExist(removeErr) {
+			return w.acquireLock(runID)
+		}
+	}
+	return release, err
+}
+
+func (w *Writer) acquireLock(runID string) (func() error, error) {
 	pid := os.Getpid()
+	host, _ := os.Hostname()
 
 	// Try to create lock file exclusively (O_EXCL fails if file exists)
-	l := Lock{PID: pid, StartedAt: time.Now(), RunID: runID}
+	l := Lock{PID: pid, StartedAt: time.Now(), RunID: runID, Host: host}
 	data, err := json.MarshalIndent(l, "", "    ")
 	if err != nil {
 		return nil, err
@@ -30,20 +72,7 @@ func (w *Writer) AcquireLock(runID string) (func() error, error) {
 	f, err := os.OpenFile(w.LockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
 	if err != nil {
 		if os.IsExist(err) {
-			// Lock file exists - check if stale
-			if b, readErr := os.ReadFile(w.LockPath); readErr == nil {
-				var existing Lock
-				if json.Unmarshal(b, &existing) == nil && existing.PID > 0 {
-					if processAlive(existing.PID) {
-						return nil, fmt.Errorf("%w by pid %d (run_id=%s)", ErrLockHeld, existing.PID, existing.RunID)
-					}
-					// Process is dead, remove stale lock and retry once
-					if removeErr := os.Remove(w.LockPath); removeErr == nil {
-						return w.AcquireLock(runID)
-					}
-				}
-			}
-			return nil, fmt.Errorf("%w (lock file exists)", ErrLockHeld)
+			return nil, w.lockHeld()
 		}
 		return nil, err
 	}
@@ -70,8 +99,65 @@ func (w *Writer) AcquireLock(runID string) (func() error, error) {
 	return release, nil
 }
 
-func processAlive(pid int) bool {
-	// On unix, signal 0 checks existence/permission.
+// UnreadableLockGrace is how long an empty or unparsable lock file counts as
+// held: AcquireLock creates the file before writing it, so a racing run can
+// briefly see it that way.
+const UnreadableLockGrace = 10 * time.Second
+
+// lockHeld describes the existing lock file's holder. A lock from another
+// host is never stale: its PID means nothing here.
+func (w *Writer) lockHeld() *LockHeldError {
+	existing, err := w.LoadLock()
+	if err != nil || existing == nil || existing.PID <= 0 {
+		info, statErr := os.Stat(w.LockPath)
+		stale := statErr != nil || time.Since(info.ModTime()) > UnreadableLockGrace
+		return &LockHeldError{Stale: stale}
+	}
+	if host, _ := os.Hostname(); existing.Host != "" && host != "" && existing.Host != host {
+		return &LockHeldError{Lock: existing, OtherHost: true}
+	}
+	return &LockHeldError{Lock: existing, Stale: !ProcessAlive(existing.PID)}
+}
+
+// ClearStaleLock removes the lock file if its holder is no longer running,
+// it was taken on another host, or it can't be read. A lock held by a live
+// process on this host is left alone and reported as a *LockHeldError.
+func (w *Writer) ClearStaleLock() error {
+	if _, err := os.Stat(w.LockPath); os.IsNotExist(err) {
+		return nil
+	}
+	if held := w.lockHeld(); !held.Stale && !held.OtherHost {
+		return held
+	}
+	if err := os.Remove(w.LockPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// LoadLock reads the current lock file. Returns nil if no lock is held.
+func (w *Writer) LoadLock() (*Lock, error) {
+	b, err := os.ReadFile(w.LockPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var l Lock
+	if err := json.Unmarshal(b, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	return &l, nil
+}
+
+// ProcessAlive reports whether a process with the given PID exists.
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// On unix, signal 0 checks existence/permission. EPERM means the
+	// process exists but belongs to another user.
 	err := syscall.Kill(pid, 0)
-	return err == nil
+	return err == nil || errors.Is(err, syscall.EPERM)
 }
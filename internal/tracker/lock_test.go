@@ -1,6 +1,14 @@
 package tracker
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestAcquireLockBlocksSecondAcquire(t *testing.T) {
 	dir := t.TempDir()
@@ -25,3 +33,147 @@ func TestAcquireLockBlocksSecondAcquire(t *testing.T) {
 		t.Fatalf("expected AcquireLock after release to succeed, got: %v", err)
 	}
 }
+
+func TestLoadLock(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir)
+
+	l, err := w.LoadLock()
+	if err != nil {
+		t.Fatalf("LoadLock error: %v", err)
+	}
+	if l != nil {
+		t.Fatalf("expected nil lock before acquire, got %+v", l)
+	}
+
+	release, err := w.AcquireLock("run-1")
+	if err != nil {
+		t.Fatalf("AcquireLock error: %v", err)
+	}
+	defer func() { _ = release() }()
+
+	l, err = w.LoadLock()
+	if err != nil {
+		t.Fatalf("LoadLock error: %v", err)
+	}
+	if l == nil || l.RunID != "run-1" || l.PID <= 0 {
+		t.Fatalf("unexpected lock: %+v", l)
+	}
+}
+
+// deadPID returns the PID of a process that has already exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot start a process: %v", err)
+	}
+	pid := cmd.ProcessState.Pid()
+	if ProcessAlive(pid) {
+		t.Skipf("pid %d was reused", pid)
+	}
+	return pid
+}
+
+func TestAcquireLockClearsStaleLock(t *testing.T) {
+	host, _ := os.Hostname()
+	for _, lockHost := range []string{host, ""} {
+		w := NewWriter(t.TempDir())
+		stale := Lock{PID: deadPID(t), StartedAt: time.Now().Add(-time.Hour), RunID: "crashed", Host: lockHost}
+		data, _ := json.Marshal(stale)
+		if err := os.WriteFile(w.LockPath, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		release, err := w.AcquireLock("next")
+		if err != nil {
+			t.Fatalf("host %q: AcquireLock over a dead local holder: %v", lockHost, err)
+		}
+		if l, _ := w.LoadLock(); l == nil || l.RunID != "next" || l.PID != os.Getpid() || l.Host != host {
+			t.Errorf("host %q: lock = %+v, want run next held by this process on %q", lockHost, l, host)
+		}
+		_ = release()
+	}
+}
+
+func TestAcquireLockReportsOtherHostLock(t *testing.T) {
+	w := NewWriter(t.TempDir())
+	remote := Lock{PID: deadPID(t), StartedAt: time.Now().Add(-time.Hour), RunID: "remote", Host: "other-host.invalid"}
+	data, _ := json.Marshal(remote)
+	if err := os.WriteFile(w.LockPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := w.AcquireLock("next")
+	var held *LockHeldError
+	if !errors.As(err, &held) || !held.OtherHost || held.Stale || held.Lock == nil || held.Lock.RunID != "remote" {
+		t.Fatalf("expected other-host LockHeldError, got %v", err)
+	}
+	if !errors.Is(err, ErrLockHeld) {
+		t.Error("LockHeldError should match ErrLockHeld")
+	}
+	if !strings.Contains(err.Error(), "other-host.invalid") || !strings.Contains(err.Error(), "-force") {
+		t.Errorf("error %q should name the host and -force", err)
+	}
+	if _, statErr := os.Stat(w.LockPath); statErr != nil {
+		t.Fatalf("another host's lock should be left for -force to clear: %v", statErr)
+	}
+
+	if err := w.ClearStaleLock(); err != nil {
+		t.Fatalf("ClearStaleLock: %v", err)
+	}
+	release, err := w.AcquireLock("next")
+	if err != nil {
+		t.Fatalf("AcquireLock after clearing: %v", err)
+	}
+	_ = release()
+}
+
+func TestAcquireLockReportsUnreadableLockAsStale(t *testing.T) {
+	for _, content := range []string{"garbage", ""} {
+		w := NewWriter(t.TempDir())
+		if err := os.WriteFile(w.LockPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// Freshly created: another run may still be writing it.
+		_, err := w.AcquireLock("next")
+		var held *LockHeldError
+		if !errors.As(err, &held) || held.Stale || held.Lock != nil {
+			t.Fatalf("lock %q: expected a held LockHeldError without a lock, got %v", content, err)
+		}
+		if err := w.ClearStaleLock(); !errors.Is(err, ErrLockHeld) {
+			t.Fatalf("lock %q: ClearStaleLock on a fresh lock = %v, want ErrLockHeld", content, err)
+		}
+
+		old := time.Now().Add(-2 * UnreadableLockGrace)
+		if err := os.Chtimes(w.LockPath, old, old); err != nil {
+			t.Fatal(err)
+		}
+		_, err = w.AcquireLock("next")
+		if !errors.As(err, &held) || !held.Stale || held.Lock != nil {
+			t.Fatalf("lock %q: expected stale LockHeldError without a lock, got %v", content, err)
+		}
+	}
+}
+
+func TestClearStaleLockKeepsLiveLock(t *testing.T) {
+	w := NewWriter(t.TempDir())
+	release, err := w.AcquireLock("live")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = release() }()
+
+	_, err = w.AcquireLock("other")
+	var held *LockHeldError
+	if !errors.As(err, &held) || held.Stale || held.Lock.PID != os.Getpid() {
+		t.Fatalf("expected live LockHeldError, got %v", err)
+	}
+	if err := w.ClearStaleLock(); !errors.Is(err, ErrLockHeld) {
+		t.Fatalf("ClearStaleLock on a live lock = %v, want ErrLockHeld", err)
+	}
+	if _, err := os.Stat(w.LockPath); err != nil {
+		t.Errorf("live lock was removed: %v", err)
+	}
+}
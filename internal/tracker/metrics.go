@@ -7,25 +7,29 @@ import (
 )
 
 type RunMetrics struct {
-	StartedAt         time.Time  `json:"started_at"`
-	UpdatedAt         time.Time  `json:"updated_at"`
-	CompletedAt       *time.Time `json:"completed_at,omitempty"`
-	TotalClaudeCalls  int        `json:"total_claude_calls"`
-	TotalTurns        int        `json:"total_turns"`
-	InputTokens       int        `json:"input_tokens"`
-	OutputTokens      int        `json:"output_tokens"`
-	TotalTokens       int        `json:"total_tokens"`
-	TotalCostUSD      float64    `json:"total_cost_usd,omitempty"`
-	LastRunID         string     `json:"last_run_id,omitempty"`
-	LastClaudeSession string     `json:"last_claude_session,omitempty"`
+	StartedAt           time.Time  `json:"started_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	CompletedAt         *time.Time `json:"completed_at,omitempty"`
+	TotalClaudeCalls    int        `json:"total_claude_calls"`
+	TotalTurns          int        `json:"total_turns"`
+	InputTokens         int        `json:"input_tokens"`
+	OutputTokens        int        `json:"output_tokens"`
+	TotalTokens         int        `json:"total_tokens"`
+	CacheCreationTokens int        `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadTokens     int        `json:"cache_read_input_tokens,omitempty"`
+	TotalCostUSD        float64    `json:"total_cost_usd,omitempty"`
+	LastRunID           string     `json:"last_run_id,omitempty"`
+	LastClaudeSession   string     `json:"last_claude_session,omitempty"`
 }
 
 type UsageDelta struct {
-	InputTokens  int
-	OutputTokens int
-	TotalTokens  int
-	CostUSD      float64
-	Turns        int
+	InputTokens         int
+	OutputTokens        int
+	TotalTokens         int
+	CacheCreationTokens int
+	CacheReadTokens     int
+	CostUSD             float64
+	Turns               int
 }
 
 func (w *Writer) LoadMetrics() (*RunMetrics, error) {
@@ -78,6 +82,8 @@ func (w *Writer) AddUsage(runID string, delta UsageDelta) {
 	m.InputTokens += delta.InputTokens
 	m.OutputTokens += delta.OutputTokens
 	m.TotalTokens += delta.TotalTokens
+	m.CacheCreationTokens += delta.CacheCreationTokens
+	m.CacheReadTokens += delta.CacheReadTokens
 	m.TotalCostUSD += delta.CostUSD
 	m.UpdatedAt = time.Now()
 	m.LastRunID = runID
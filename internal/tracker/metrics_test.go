@@ -12,7 +12,7 @@ func TestMetricsAccumulateAndPersist(t *testing.T) {
 	w := NewWriter(dir)
 
 	w.AddUsage("run1", UsageDelta{InputTokens: 1, OutputTokens: 2, TotalTokens: 3, CostUSD: 0.01})
-	w.AddUsage("run1", UsageDelta{InputTokens: 10, OutputTokens: 20, TotalTokens: 30, CostUSD: 0.02})
+	w.AddUsage("run1", UsageDelta{InputTokens: 10, OutputTokens: 20, TotalTokens: 30, CacheCreationTokens: 4, CacheReadTokens: 5, CostUSD: 0.02})
 
 	b, err := os.ReadFile(filepath.Join(dir, "run_metrics.json"))
 	if err != nil {
@@ -31,4 +31,7 @@ func TestMetricsAccumulateAndPersist(t *testing.T) {
 	if m.InputTokens != 11 || m.OutputTokens != 22 {
 		t.Fatalf("unexpected split: in=%d out=%d", m.InputTokens, m.OutputTokens)
 	}
+	if m.CacheCreationTokens != 4 || m.CacheReadTokens != 5 {
+		t.Fatalf("unexpected cache tokens: created=%d read=%d", m.CacheCreationTokens, m.CacheReadTokens)
+	}
 }
@@ -0,0 +1,54 @@
+package tracker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// promMetric is one sample in the Prometheus textfile.
+type promMetric struct {
+	name  string
+	help  string
+	value string
+}
+
+// FormatPrometheus renders m in the Prometheus text exposition format, one
+// counter per metric, each labeled with runID and model. loops is the number
+// of loop iterations the run has completed.
+func FormatPrometheus(m RunMetrics, runID, model string, loops int) []byte {
+	labels := fmt.Sprintf(`{run_id="%s",model="%s"}`, escapePromLabel(runID), escapePromLabel(model))
+	metrics := []promMetric{
+		{"ralph_total_claude_calls", "Claude calls made by ralph in this project.", strconv.Itoa(m.TotalClaudeCalls)},
+		{"ralph_total_tokens", "Tokens used by Claude calls (input + output).", strconv.Itoa(m.TotalTokens)},
+		{"ralph_total_cost_usd", "Estimated Claude cost in US dollars.", strconv.FormatFloat(m.TotalCostUSD, 'g', -1, 64)},
+		{"ralph_loops_total", "Loop iterations run by the current run.", strconv.Itoa(loops)},
+	}
+
+	var b strings.Builder
+	for _, pm := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n", pm.name, pm.help)
+		fmt.Fprintf(&b, "# TYPE %s counter\n", pm.name)
+		fmt.Fprintf(&b, "%s%s %s\n", pm.name, labels, pm.value)
+	}
+	return []byte(b.String())
+}
+
+// escapePromLabel escapes a label value: backslash, double quote and newline.
+func escapePromLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// WritePrometheus writes the current run metrics to .ralph/metrics.prom for
+// a node_exporter textfile collector. The file is replaced atomically so a
+// scrape never reads a partial file. Missing metrics write zeros.
+func (w *Writer) WritePrometheus(runID, model string, loops int) error {
+	m, err := w.LoadMetrics()
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		m = &RunMetrics{}
+	}
+	return writeFileAtomic(w.PrometheusPath, FormatPrometheus(*m, runID, model, loops))
+}
@@ -0,0 +1,80 @@
+package tracker
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFormatPrometheus(t *testing.T) {
+	m := RunMetrics{
+		TotalClaudeCalls: 12,
+		InputTokens:      90000,
+		OutputTokens:     10500,
+		TotalTokens:      100500,
+		TotalCostUSD:     3.275,
+	}
+	got := string(FormatPrometheus(m, "20260310-130000-abc", "claude-sonnet-4-5", 7))
+	want := `# HELP ralph_total_claude_calls Claude calls made by ralph in this project.
+# TYPE ralph_total_claude_calls counter
+ralph_total_claude_calls{run_id="20260310-130000-abc",model="claude-sonnet-4-5"} 12
+# HELP ralph_total_tokens Tokens used by Claude calls (input + output).
+# TYPE ralph_total_tokens counter
+ralph_total_tokens{run_id="20260310-130000-abc",model="claude-sonnet-4-5"} 100500
+# HELP ralph_total_cost_usd Estimated Claude cost in US dollars.
+# TYPE ralph_total_cost_usd counter
+ralph_total_cost_usd{run_id="20260310-130000-abc",model="claude-sonnet-4-5"} 3.275
+# HELP ralph_loops_total Loop iterations run by the current run.
+# TYPE ralph_loops_total counter
+ralph_loops_total{run_id="20260310-130000-abc",model="claude-sonnet-4-5"} 7
+`
+	if got != want {
+		t.Errorf("FormatPrometheus =\n%s\nwant\n%s", got, want)
+	}
+
+	// Every sample line must be `name{labels} value` with a preceding TYPE.
+	typed := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if strings.HasPrefix(line, "# TYPE ") {
+			typed[strings.Fields(line)[2]] = true
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, rest, ok := strings.Cut(line, "{")
+		if !ok || !typed[name] || !strings.Contains(rest, "} ") {
+			t.Errorf("malformed sample line %q", line)
+		}
+	}
+}
+
+func TestFormatPrometheusEscapesLabels(t *testing.T) {
+	got := string(FormatPrometheus(RunMetrics{}, `a"b\c`+"\nd", "", 0))
+	if !strings.Contains(got, `ralph_loops_total{run_id="a\"b\\c\nd",model=""} 0`) {
+		t.Errorf("labels not escaped:\n%s", got)
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	w := NewWriter(t.TempDir())
+	if err := w.SaveMetrics(&RunMetrics{TotalClaudeCalls: 2, TotalTokens: 300}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WritePrometheus("run-1", "sonnet", 3); err != nil {
+		t.Fatalf("WritePrometheus: %v", err)
+	}
+	data, err := os.ReadFile(w.PrometheusPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`ralph_total_claude_calls{run_id="run-1",model="sonnet"} 2`,
+		`ralph_total_tokens{run_id="run-1",model="sonnet"} 300`,
+		`ralph_loops_total{run_id="run-1",model="sonnet"} 3`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("metrics.prom missing %q:\n%s", want, data)
+		}
+	}
+}
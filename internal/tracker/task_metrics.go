@@ -0,0 +1,62 @@
+package tracker
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+)
+
+// UnattributedTaskID is the task_metrics.json key for usage from Claude calls
+// made while no task with an ID was current.
+const UnattributedTaskID = "-"
+
+// TaskMetrics is the usage attributed to one task across all loops.
+type TaskMetrics struct {
+	ClaudeCalls  int       `json:"claude_calls"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	TotalTokens  int       `json:"total_tokens"`
+	CostUSD      float64   `json:"cost_usd,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// LoadTaskMetrics reads task_metrics.json, keyed by task ID. A missing or
+// corrupted file returns an empty map.
+func (w *Writer) LoadTaskMetrics() (map[string]TaskMetrics, error) {
+	out := map[string]TaskMetrics{}
+	b, err := os.ReadFile(w.TaskMetricsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return out, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &out); err != nil || out == nil {
+		// Corrupted task metrics: start over rather than fail the run.
+		return map[string]TaskMetrics{}, nil
+	}
+	return out, nil
+}
+
+// AddTaskUsage adds delta to the totals for taskID. An empty taskID is
+// recorded under UnattributedTaskID.
+func (w *Writer) AddTaskUsage(taskID string, delta UsageDelta) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		taskID = UnattributedTaskID
+	}
+	all, err := w.LoadTaskMetrics()
+	if err != nil {
+		return
+	}
+	m := all[taskID]
+	m.ClaudeCalls++
+	m.InputTokens += delta.InputTokens
+	m.OutputTokens += delta.OutputTokens
+	m.TotalTokens += delta.TotalTokens
+	m.CostUSD += delta.CostUSD
+	m.UpdatedAt = time.Now()
+	all[taskID] = m
+	_ = writeJSONAtomic(w.TaskMetricsPath, all)
+}
@@ -0,0 +1,60 @@
+package tracker
+
+import (
+	"os"
+	"testing"
+)
+
+func TestAddTaskUsageAccumulates(t *testing.T) {
+	w := NewWriter(t.TempDir())
+
+	// Two loops on T001, one on T002, one with no current task.
+	w.AddTaskUsage("T001", UsageDelta{InputTokens: 10, OutputTokens: 5, TotalTokens: 15, CostUSD: 0.10})
+	w.AddTaskUsage("T001", UsageDelta{InputTokens: 20, OutputTokens: 10, TotalTokens: 30, CostUSD: 0.25})
+	w.AddTaskUsage("T002", UsageDelta{InputTokens: 1, OutputTokens: 1, TotalTokens: 2, CostUSD: 0.01})
+	w.AddTaskUsage("  ", UsageDelta{TotalTokens: 7})
+
+	got, err := w.LoadTaskMetrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", got)
+	}
+	t1 := got["T001"]
+	if t1.ClaudeCalls != 2 || t1.InputTokens != 30 || t1.OutputTokens != 15 || t1.TotalTokens != 45 {
+		t.Errorf("T001 = %+v, want 2 calls, 30 in, 15 out, 45 total", t1)
+	}
+	if t1.CostUSD < 0.349 || t1.CostUSD > 0.351 {
+		t.Errorf("T001 cost = %v, want 0.35", t1.CostUSD)
+	}
+	if t1.UpdatedAt.IsZero() {
+		t.Error("T001 updated_at not set")
+	}
+	if t2 := got["T002"]; t2.ClaudeCalls != 1 || t2.TotalTokens != 2 {
+		t.Errorf("T002 = %+v, want 1 call, 2 tokens", t2)
+	}
+	if none := got[UnattributedTaskID]; none.ClaudeCalls != 1 || none.TotalTokens != 7 {
+		t.Errorf("unattributed = %+v, want 1 call, 7 tokens", none)
+	}
+}
+
+func TestLoadTaskMetricsMissingOrCorrupt(t *testing.T) {
+	w := NewWriter(t.TempDir())
+	got, err := w.LoadTaskMetrics()
+	if err != nil || len(got) != 0 {
+		t.Fatalf("missing file: got %+v, %v", got, err)
+	}
+
+	if err := os.WriteFile(w.TaskMetricsPath, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = w.LoadTaskMetrics()
+	if err != nil || len(got) != 0 {
+		t.Fatalf("corrupt file: got %+v, %v", got, err)
+	}
+	w.AddTaskUsage("T001", UsageDelta{TotalTokens: 3})
+	if got, _ := w.LoadTaskMetrics(); got["T001"].TotalTokens != 3 {
+		t.Errorf("expected corrupt file to be replaced, got %+v", got)
+	}
+}
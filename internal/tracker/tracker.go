@@ -9,18 +9,26 @@ import (
 )
 
 type Writer struct {
-	Dir          string
-	RunStatePath string
-	LockPath     string
-	MetricsPath  string
+	Dir             string
+	RunStatePath    string
+	LockPath        string
+	MetricsPath     string
+	TaskMetricsPath string
+	EventsPath      string
+	HistoryPath     string
+	PrometheusPath  string
 }
 
 func NewWriter(dir string) *Writer {
 	return &Writer{
-		Dir:          dir,
-		RunStatePath: filepath.Join(dir, "run_state.json"),
-		LockPath:     filepath.Join(dir, ".ralph_lock"),
-		MetricsPath:  filepath.Join(dir, "run_metrics.json"),
+		Dir:             dir,
+		RunStatePath:    filepath.Join(dir, "run_state.json"),
+		LockPath:        filepath.Join(dir, ".ralph_lock"),
+		MetricsPath:     filepath.Join(dir, "run_metrics.json"),
+		TaskMetricsPath: filepath.Join(dir, "task_metrics.json"),
+		EventsPath:      filepath.Join(dir, "events.jsonl"),
+		HistoryPath:     filepath.Join(dir, "metrics_history.jsonl"),
+		PrometheusPath:  filepath.Join(dir, "metrics.prom"),
 	}
 }
 
@@ -33,7 +41,12 @@ func writeJSONAtomic(path string, v any) error {
 	if err != nil {
 		return err
 	}
+	return writeFileAtomic(path, data)
+}
 
+// writeFileAtomic writes data to a temp file and renames it over path, so
+// readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
 	tmp := fmt.Sprintf("%s.tmp.%d", path, time.Now().UnixNano())
 	f, err := os.Create(tmp)
 	if err != nil {
@@ -31,9 +31,14 @@ func ParseClaudeUsageFromOutput(output string) (UsageDelta, bool) {
 	// Claude uses prompt caching, so input tokens are split across multiple fields.
 	// Sum them all to get the true input token count.
 	input := findInt(v, []string{"input_tokens", "prompt_tokens"})
-	cacheCreation := findInt(v, []string{"cache_creation_input_tokens"})
+	cacheCreation, ok := findNumber(v, []string{"cache_creation_input_tokens"})
+	if !ok {
+		// Newer CLI versions may only report the per-TTL breakdown under
+		// usage.cache_creation.
+		cacheCreation = cacheCreationBreakdown(v)
+	}
 	cacheRead := findInt(v, []string{"cache_read_input_tokens"})
-	input += cacheCreation + cacheRead
+	input += int(cacheCreation) + cacheRead
 
 	out := findInt(v, []string{"output_tokens", "completion_tokens"})
 	total := findInt(v, []string{"total_tokens", "tokens"})
@@ -52,13 +57,61 @@ func ParseClaudeUsageFromOutput(output string) (UsageDelta, bool) {
 	hasAnyKey := hasAnyKey(v, []string{
 		"input_tokens", "prompt_tokens", "output_tokens", "completion_tokens",
 		"total_tokens", "tokens", "total_cost", "cost", "total_cost_usd", "cost_usd",
-		"cache_creation_input_tokens", "cache_read_input_tokens",
+		"cache_creation_input_tokens", "cache_read_input_tokens", "cache_creation",
 	})
 	if !hasAnyKey {
 		return UsageDelta{}, false
 	}
 
-	return UsageDelta{InputTokens: input, OutputTokens: out, TotalTokens: total, CostUSD: cost, Turns: turns}, true
+	return UsageDelta{
+		InputTokens:         input,
+		OutputTokens:        out,
+		TotalTokens:         total,
+		CacheCreationTokens: int(cacheCreation),
+		CacheReadTokens:     cacheRead,
+		CostUSD:             cost,
+		Turns:               turns,
+	}, true
+}
+
+// cacheCreationBreakdown sums the ephemeral_*_input_tokens counts in the
+// first cache_creation object found in v.
+func cacheCreationBreakdown(v any) float64 {
+	var walk func(any) (map[string]any, bool)
+	walk = func(x any) (map[string]any, bool) {
+		switch t := x.(type) {
+		case map[string]any:
+			if cc, ok := t["cache_creation"].(map[string]any); ok {
+				return cc, true
+			}
+			for _, vv := range t {
+				if cc, ok := walk(vv); ok {
+					return cc, true
+				}
+			}
+		case []any:
+			for _, vv := range t {
+				if cc, ok := walk(vv); ok {
+					return cc, true
+				}
+			}
+		}
+		return nil, false
+	}
+
+	cc, ok := walk(v)
+	if !ok {
+		return 0
+	}
+	var sum float64
+	for k, vv := range cc {
+		if strings.HasPrefix(k, "ephemeral_") && strings.HasSuffix(k, "_input_tokens") {
+			if n, ok := toFloat(vv); ok {
+				sum += n
+			}
+		}
+	}
+	return sum
 }
 
 // extractLastJSON finds and parses the last valid JSON object in the text.
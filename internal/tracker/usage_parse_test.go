@@ -32,3 +32,50 @@ func TestParseClaudeUsageWithCacheTokens(t *testing.T) {
 		t.Fatalf("expected output tokens 931, got %d", d.OutputTokens)
 	}
 }
+
+func TestParseClaudeUsageCacheBreakdown(t *testing.T) {
+	tests := []struct {
+		name         string
+		out          string
+		wantInput    int
+		wantCreation int
+		wantRead     int
+	}{
+		{
+			name:         "real result with nested cache_creation",
+			out:          `{"type":"result","subtype":"success","num_turns":7,"total_cost_usd":0.112,"usage":{"input_tokens":2,"cache_creation_input_tokens":6843,"cache_read_input_tokens":91983,"output_tokens":931,"server_tool_use":{"web_search_requests":0},"service_tier":"standard","cache_creation":{"ephemeral_1h_input_tokens":0,"ephemeral_5m_input_tokens":6843}},"modelUsage":{"claude-sonnet-4-5":{"inputTokens":2,"cacheReadInputTokens":91983,"cacheCreationInputTokens":6843}}}`,
+			wantInput:    2 + 6843 + 91983,
+			wantCreation: 6843,
+			wantRead:     91983,
+		},
+		{
+			name:         "only the per-TTL breakdown",
+			out:          `{"usage":{"input_tokens":5,"cache_read_input_tokens":100,"output_tokens":10,"cache_creation":{"ephemeral_1h_input_tokens":30,"ephemeral_5m_input_tokens":20}}}`,
+			wantInput:    5 + 50 + 100,
+			wantCreation: 50,
+			wantRead:     100,
+		},
+		{
+			name:      "no caching",
+			out:       `{"usage":{"input_tokens":12,"output_tokens":34}}`,
+			wantInput: 12,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := ParseClaudeUsageFromOutput(tt.out)
+			if !ok {
+				t.Fatalf("expected ok")
+			}
+			if d.InputTokens != tt.wantInput {
+				t.Errorf("input tokens = %d, want %d", d.InputTokens, tt.wantInput)
+			}
+			if d.CacheCreationTokens != tt.wantCreation {
+				t.Errorf("cache creation tokens = %d, want %d", d.CacheCreationTokens, tt.wantCreation)
+			}
+			if d.CacheReadTokens != tt.wantRead {
+				t.Errorf("cache read tokens = %d, want %d", d.CacheReadTokens, tt.wantRead)
+			}
+		})
+	}
+}